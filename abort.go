@@ -14,6 +14,8 @@ import (
 
 type abortCmd struct{}
 
+func (*abortCmd) locksRepository() {}
+
 func (*abortCmd) Help() string {
 	return text.Dedent(`
 		Cancels an ongoing git-spice operation that was interrupted by
@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/url"
 	"slices"
 	"strings"
 
@@ -22,6 +23,7 @@ type authCmd struct {
 	Logout authLogoutCmd `cmd:"" help:"Log out of a service"`
 
 	Forge string `help:"Name of the forge to log into" placeholder:"NAME" predictor:"forges"`
+	Host  string `released:"unreleased" help:"URL of the forge host to log into if the forge has multiple hosts configured" placeholder:"URL"`
 }
 
 // AfterApply makes the Forge available to all subcommands.
@@ -37,10 +39,57 @@ func (c *authCmd) AfterApply(
 		return err
 	}
 
+	if c.Host != "" {
+		f, err = resolveForgeHost(f, c.Host)
+		if err != nil {
+			return err
+		}
+	}
+
 	kctx.BindTo(f, (*forge.Forge)(nil))
 	return nil
 }
 
+// resolveForgeHost returns the Forge for the given host
+// from among the hosts configured for f.
+//
+// host may be a full URL or just a host name.
+func resolveForgeHost(f forge.Forge, host string) (forge.Forge, error) {
+	var available []string
+	for _, h := range forge.Hosts(f) {
+		hf, ok := h.(forge.MultiHostForge)
+		if !ok {
+			continue
+		}
+
+		u, err := url.Parse(hf.URL())
+		if err != nil {
+			continue
+		}
+		if strings.TrimRight(host, "/") == hf.URL() || host == u.Host {
+			return h, nil
+		}
+		available = append(available, hf.URL())
+	}
+
+	if len(available) == 0 {
+		return nil, fmt.Errorf("%s: forge does not support multiple hosts", f.ID())
+	}
+	return nil, fmt.Errorf("%s: host %q is not configured: expected one of: %s",
+		f.ID(), host, strings.Join(available, ", "))
+}
+
+// forgeHostName returns a human-readable name for the given Forge.
+// If the forge is configured with multiple hosts,
+// this includes the URL of the host.
+func forgeHostName(f forge.Forge) string {
+	hf, ok := f.(forge.MultiHostForge)
+	if !ok || len(hf.Hosts()) < 2 {
+		return f.ID()
+	}
+	return fmt.Sprintf("%s (%s)", f.ID(), hf.URL())
+}
+
 // resolveForge resolves a forge by name.
 // If name is unset, it will attempt to guess the forge based on the current
 // repository's remote URL.
@@ -105,6 +154,7 @@ func guessCurrentForge(ctx context.Context, forges *forge.Registry, log *silog.L
 	if err != nil {
 		return nil, nil, errors.New("not in a Git repository")
 	}
+	defer func() { _ = repo.Close() }()
 
 	// If the repository is already initialized with gs,
 	// and a remote is configured, use the forge for that remote.
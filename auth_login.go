@@ -46,7 +46,7 @@ func (cmd *authLoginCmd) Run(
 ) error {
 	if _, err := f.LoadAuthenticationToken(stash); err == nil && !cmd.Refresh {
 		log.Errorf("Use --refresh to force a refresh of the authentication token")
-		return fmt.Errorf("%s: already logged in", f.ID())
+		return fmt.Errorf("%s: already logged in", forgeHostName(f))
 	}
 
 	secret, err := f.AuthenticationFlow(ctx, view)
@@ -58,6 +58,6 @@ func (cmd *authLoginCmd) Run(
 		return err
 	}
 
-	log.Infof("%s: successfully logged in", f.ID())
+	log.Infof("%s: successfully logged in", forgeHostName(f))
 	return nil
 }
@@ -28,6 +28,6 @@ func (cmd *authLogoutCmd) Run(
 	}
 
 	// TOOD: Forges should present friendly names in addition to IDs.
-	log.Infof("%s: logged out", f.ID())
+	log.Infof("%s: logged out", forgeHostName(f))
 	return nil
 }
@@ -1,32 +1,123 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"go.abhg.dev/gs/internal/forge"
 	"go.abhg.dev/gs/internal/secret"
 	"go.abhg.dev/gs/internal/silog"
+	"go.abhg.dev/gs/internal/text"
 )
 
-type authStatusCmd struct{}
+type authStatusCmd struct {
+	Quota bool `released:"unreleased" help:"Report the remaining API quota for each host"`
+}
 
 func (*authStatusCmd) Help() string {
-	return `Exits with a non-zero code if not logged in.`
+	return text.Dedent(`
+		If the forge is configured with multiple hosts,
+		the status of each host is listed.
+
+		Use --quota to also list the remaining API quota
+		for each host that you're logged in to.
+		This requires network access.
+
+		Exits with a non-zero code if not logged in
+		to the selected host.
+	`)
 }
 
 func (cmd *authStatusCmd) Run(
+	ctx context.Context,
 	stash secret.Stash,
 	log *silog.Logger,
 	f forge.Forge,
 ) error {
-	if _, err := f.LoadAuthenticationToken(stash); err != nil {
-		if errors.Is(err, secret.ErrNotFound) {
-			return fmt.Errorf("%s: not logged in", f.ID())
+	hosts := forge.Hosts(f)
+	if len(hosts) < 2 {
+		hosts = []forge.Forge{f}
+	}
+
+	var selectedErr error
+	for _, h := range hosts {
+		name := forgeHostName(h)
+		tok, err := h.LoadAuthenticationToken(stash)
+		switch {
+		case err == nil:
+			log.Infof("%s: currently logged in", name)
+			if cmd.Quota {
+				logAPIQuotas(ctx, log, name, h, tok)
+			}
+
+		case errors.Is(err, secret.ErrNotFound):
+			err = fmt.Errorf("%s: not logged in", name)
+			if !sameForgeHost(h, f) {
+				log.Warnf("%v", err)
+			}
+
+		default:
+			err = fmt.Errorf("%s: load authentication token: %w", name, err)
+			if !sameForgeHost(h, f) {
+				log.Warnf("%v", err)
+			}
+		}
+
+		if sameForgeHost(h, f) {
+			selectedErr = err
+		}
+	}
+
+	return selectedErr
+}
+
+// logAPIQuotas logs the remaining API quota for a host
+// if the forge reports it.
+// Failure to check the quota is not fatal.
+func logAPIQuotas(
+	ctx context.Context,
+	log *silog.Logger,
+	name string,
+	f forge.Forge,
+	tok forge.AuthenticationToken,
+) {
+	reporter, ok := f.(forge.QuotaReporter)
+	if !ok {
+		return
+	}
+
+	quotas, err := reporter.APIQuotas(ctx, tok)
+	if err != nil {
+		log.Warnf("%s: could not check API quota: %v", name, err)
+		return
+	}
+
+	for _, q := range quotas {
+		api := "API"
+		if q.Resource != "" {
+			api = q.Resource + " API"
 		}
-		return fmt.Errorf("load authentication token: %w", err)
+
+		msg := fmt.Sprintf("%s: %s: %d of %d requests remaining", name, api, q.Remaining, q.Limit)
+		if !q.Reset.IsZero() {
+			msg += fmt.Sprintf(", resets at %v", q.Reset.Local().Format(time.Kitchen))
+		}
+		log.Info(msg)
+	}
+}
+
+// sameForgeHost reports whether a and b are Forges for the same host.
+func sameForgeHost(a, b forge.Forge) bool {
+	if a.ID() != b.ID() {
+		return false
 	}
 
-	log.Infof("%s: currently logged in", f.ID())
-	return nil
+	ah, aok := a.(forge.MultiHostForge)
+	bh, bok := b.(forge.MultiHostForge)
+	if !aok || !bok {
+		return aok == bok
+	}
+	return ah.URL() == bh.URL()
 }
@@ -34,12 +34,16 @@ type branchCmd struct {
 	Onto    branchOntoCmd    `cmd:"" aliases:"on" help:"Move a branch onto another branch"`
 
 	// Inspection
-	Diff branchDiffCmd `cmd:"" aliases:"di" help:"Show diff between a branch and its base"`
+	Diff      branchDiffCmd      `cmd:"" aliases:"di" help:"Show diff between a branch and its base"`
+	History   branchHistoryCmd   `cmd:"" released:"unreleased" help:"List pushed versions of a branch"`
+	Interdiff branchInterdiffCmd `cmd:"" released:"unreleased" help:"Compare pushed versions of a branch"`
 
 	// Pull request management
-	Submit  branchSubmitCmd  `cmd:"" aliases:"s" help:"Submit a branch"`
-	Reviews branchReviewsCmd `cmd:"" help:"Summarize open PR review threads"`
-	Checks  branchChecksCmd  `cmd:"" help:"Summarize CI checks for the PR"`
+	Submit     branchSubmitCmd     `cmd:"" aliases:"s" help:"Submit a branch"`
+	EditChange branchEditChangeCmd `cmd:"" released:"unreleased" help:"Update the labels, reviewers, and other metadata of a submitted branch"`
+	Owners     branchOwnersCmd     `cmd:"" released:"unreleased" help:"Configure code owner reviewers for a branch"`
+	Reviews    branchReviewsCmd    `cmd:"" help:"Summarize open PR review threads"`
+	Checks     branchChecksCmd     `cmd:"" help:"Summarize CI checks for the PR"`
 }
 
 // BranchPromptConfig defines configuration for the branch tree prompt
@@ -3,12 +3,17 @@ package main
 import (
 	"context"
 	"encoding"
+	"errors"
 	"fmt"
 	"strings"
 
+	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/forge/forgecache"
 	"go.abhg.dev/gs/internal/git"
 	"go.abhg.dev/gs/internal/handler/checkout"
+	"go.abhg.dev/gs/internal/secret"
 	"go.abhg.dev/gs/internal/silog"
+	"go.abhg.dev/gs/internal/spice/state"
 	"go.abhg.dev/gs/internal/text"
 	"go.abhg.dev/gs/internal/ui"
 )
@@ -76,6 +81,9 @@ type branchCheckoutCmd struct {
 
 	Untracked bool   `short:"u" config:"branchCheckout.showUntracked" help:"Show untracked branches if one isn't supplied"`
 	Branch    string `arg:"" optional:"" help:"Name of the branch to checkout" predictor:"branches"`
+
+	Change   string `placeholder:"NUM|URL" released:"unreleased" help:"Check out a change from the forge, along with the open changes below it"`
+	ReadOnly bool   `released:"unreleased" help:"With --change, mark the checked out branches as read-only so they are not restacked"`
 }
 
 func (*branchCheckoutCmd) Help() string {
@@ -87,6 +95,13 @@ func (*branchCheckoutCmd) Help() string {
 		Use --detach to detach HEAD to the commit of the selected branch.
 		Use -n to print the selected branch name to stdout
 		without checking it out.
+
+		Use --change to check out someone else's change
+		by its number or URL.
+		The change and the open changes it's stacked on
+		are fetched into local branches and tracked with git-spice.
+		Use --read-only with --change to prevent restacking these branches.
+		Run the command again without --read-only to allow it.
 	`)
 }
 
@@ -100,6 +115,16 @@ func (cmd *branchCheckoutCmd) AfterApply(
 	wt *git.Worktree,
 	branchPrompt *branchPrompter,
 ) error {
+	if cmd.Change != "" {
+		if cmd.Branch != "" {
+			return errors.New("cannot use --change with a branch name")
+		}
+		return nil
+	}
+	if cmd.ReadOnly {
+		return errors.New("--read-only can only be used with --change")
+	}
+
 	if cmd.Branch == "" {
 		if !ui.Interactive(view) {
 			return fmt.Errorf("cannot proceed without a branch name: %w", errNoPrompt)
@@ -144,8 +169,25 @@ func (cmd *branchCheckoutCmd) Run(
 	ctx context.Context,
 	log *silog.Logger,
 	view ui.View,
+	repo *git.Repository,
+	store *state.Store,
+	stash secret.Stash,
+	cache *forgecache.Cache,
+	forges *forge.Registry,
 	handler CheckoutHandler,
 ) error {
+	if cmd.Change != "" {
+		branch, err := cmd.fetchChange(ctx, log, view, repo, store, stash, cache, forges)
+		if err != nil {
+			return err
+		}
+
+		return handler.CheckoutBranch(ctx, &checkout.Request{
+			Branch:  branch,
+			Options: &cmd.Options,
+		})
+	}
+
 	mode := cmd.TrackUntracked
 
 	// Translate the deprecated option if set.
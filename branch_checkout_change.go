@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/forge/forgecache"
+	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/secret"
+	"go.abhg.dev/gs/internal/silog"
+	"go.abhg.dev/gs/internal/spice/state"
+	"go.abhg.dev/gs/internal/ui"
+)
+
+// changeBranch is a branch that holds a change being checked out.
+type changeBranch struct {
+	Name   string
+	Base   string
+	Change *forge.FindChangeItem
+}
+
+// fetchChange fetches the change requested with --change
+// and the open changes it's stacked on into local branches,
+// and tracks them with git-spice.
+//
+// It returns the name of the branch for the requested change.
+func (cmd *branchCheckoutCmd) fetchChange(
+	ctx context.Context,
+	log *silog.Logger,
+	view ui.View,
+	repo *git.Repository,
+	store *state.Store,
+	stash secret.Stash,
+	cache *forgecache.Cache,
+	forges *forge.Registry,
+) (string, error) {
+	remote, err := ensureRemote(ctx, repo, store, log, view)
+	if err != nil {
+		return "", fmt.Errorf("get remote: %w", err)
+	}
+	forgeRepo, err := openRemoteRepository(ctx, log, stash, cache, forges, repo, remote)
+	if err != nil {
+		return "", fmt.Errorf("open remote repository: %w", err)
+	}
+
+	parser, ok := forgeRepo.(forge.ChangeIDParser)
+	if !ok {
+		return "", fmt.Errorf("forge %q does not support checking out changes", forgeRepo.Forge().ID())
+	}
+	id, err := parser.ParseChangeID(cmd.Change)
+	if err != nil {
+		return "", fmt.Errorf("parse change: %w", err)
+	}
+
+	branches, err := changeStack(ctx, forgeRepo, store.Trunk(), id)
+	if err != nil {
+		return "", err
+	}
+
+	refspecs := make([]git.Refspec, len(branches))
+	for i, b := range branches {
+		refspecs[i] = git.Refspec("+refs/heads/" + b.Name + ":refs/remotes/" + remote + "/" + b.Name)
+	}
+	if err := repo.Fetch(ctx, git.FetchOptions{
+		Remote:   remote,
+		Refspecs: refspecs,
+	}); err != nil {
+		return "", fmt.Errorf("fetch changes: %w", err)
+	}
+
+	// Create branches bottom-up so that each base exists
+	// by the time a branch above it is tracked.
+	tx := store.BeginBranchTx()
+	for _, b := range slices.Backward(branches) {
+		if err := createChangeBranch(ctx, log, repo, b); err != nil {
+			return "", err
+		}
+
+		if err := repo.SetBranchUpstream(ctx, b.Name, remote+"/"+b.Name); err != nil {
+			log.Warn("Could not set upstream branch", "branch", b.Name, "error", err)
+		}
+
+		baseHash, err := repo.PeelToCommit(ctx, b.Base)
+		if err != nil {
+			return "", fmt.Errorf("resolve base %v: %w", b.Base, err)
+		}
+
+		md, err := forgeRepo.NewChangeMetadata(ctx, b.Change.ID)
+		if err != nil {
+			return "", fmt.Errorf("get change metadata for %v: %w", b.Change.ID, err)
+		}
+		mdJSON, err := forgeRepo.Forge().MarshalChangeMetadata(md)
+		if err != nil {
+			return "", fmt.Errorf("marshal change metadata: %w", err)
+		}
+
+		upstream := b.Name
+		if err := tx.Upsert(ctx, state.UpsertRequest{
+			Name:           b.Name,
+			Base:           b.Base,
+			BaseHash:       baseHash,
+			ChangeMetadata: mdJSON,
+			ChangeForge:    md.ForgeID(),
+			UpstreamBranch: &upstream,
+			ReadOnly:       &cmd.ReadOnly,
+		}); err != nil {
+			return "", fmt.Errorf("track %v: %w", b.Name, err)
+		}
+
+		log.Infof("%v: tracking %v with base %v", b.Name, b.Change.ID, b.Base)
+	}
+
+	if err := tx.Commit(ctx, "checkout "+branches[0].Change.ID.String()); err != nil {
+		return "", fmt.Errorf("update state: %w", err)
+	}
+
+	return branches[0].Name, nil
+}
+
+// changeStack finds the change with the given ID,
+// and the open changes below it down to trunk.
+//
+// The returned branches are ordered top-down,
+// starting with the branch for the requested change.
+func changeStack(
+	ctx context.Context,
+	forgeRepo forge.Repository,
+	trunk string,
+	id forge.ChangeID,
+) ([]changeBranch, error) {
+	top, err := forgeRepo.FindChangeByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("find change %v: %w", id, err)
+	}
+	if top.State != forge.ChangeOpen {
+		return nil, fmt.Errorf("change %v is %v", id, top.State)
+	}
+	if top.HeadName == "" {
+		return nil, fmt.Errorf("forge %q did not report the branch for change %v", forgeRepo.Forge().ID(), id)
+	}
+	if top.HeadName == trunk {
+		return nil, fmt.Errorf("change %v is proposed from trunk", id)
+	}
+
+	branches := []changeBranch{{
+		Name:   top.HeadName,
+		Base:   top.BaseName,
+		Change: top,
+	}}
+	seen := map[string]struct{}{top.HeadName: {}}
+	for base := top.BaseName; base != trunk; {
+		if _, ok := seen[base]; ok {
+			return nil, fmt.Errorf("changes for %v form a cycle", base)
+		}
+		seen[base] = struct{}{}
+
+		changes, err := forgeRepo.FindChangesByBranch(ctx, base, forge.FindChangesOptions{
+			State: forge.ChangeOpen,
+			Limit: 1,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("find changes for %v: %w", base, err)
+		}
+		if len(changes) == 0 {
+			return nil, fmt.Errorf("no open change found for base branch %v", base)
+		}
+
+		c := changes[0]
+		branches = append(branches, changeBranch{
+			Name:   base,
+			Base:   c.BaseName,
+			Change: c,
+		})
+		base = c.BaseName
+	}
+
+	return branches, nil
+}
+
+// createChangeBranch creates a local branch at the head of a change.
+//
+// An existing branch is fast-forwarded to the head of the change.
+// It's an error if the branch has commits that aren't in the change.
+func createChangeBranch(
+	ctx context.Context,
+	log *silog.Logger,
+	repo *git.Repository,
+	b changeBranch,
+) error {
+	head := b.Change.HeadHash
+	if _, err := repo.PeelToCommit(ctx, head.String()); err != nil {
+		return fmt.Errorf("%v: head %v of %v was not fetched: %w", b.Name, head.Short(), b.Change.ID, err)
+	}
+
+	if !repo.BranchExists(ctx, b.Name) {
+		if err := repo.CreateBranch(ctx, git.CreateBranchRequest{
+			Name: b.Name,
+			Head: head.String(),
+		}); err != nil {
+			return fmt.Errorf("create branch %v: %w", b.Name, err)
+		}
+		return nil
+	}
+
+	current, err := repo.PeelToCommit(ctx, b.Name)
+	if err != nil {
+		return fmt.Errorf("resolve branch %v: %w", b.Name, err)
+	}
+	switch {
+	case current == head:
+		return nil
+	case !repo.IsAncestor(ctx, current, head):
+		return fmt.Errorf("%v: branch already exists and has commits that aren't in %v", b.Name, b.Change.ID)
+	}
+
+	if err := repo.Fetch(ctx, git.FetchOptions{
+		Remote:   ".", // local repository
+		Refspecs: []git.Refspec{git.Refspec(head.String() + ":refs/heads/" + b.Name)},
+	}); err != nil {
+		return fmt.Errorf("update branch %v: %w", b.Name, err)
+	}
+	log.Infof("%v: updated to %v", b.Name, head.Short())
+	return nil
+}
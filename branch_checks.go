@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/forge/forgecache"
 	"go.abhg.dev/gs/internal/git"
 	"go.abhg.dev/gs/internal/secret"
 	"go.abhg.dev/gs/internal/silog"
@@ -41,28 +42,59 @@ func (c *branchChecksCmd) Run(
 	repo *git.Repository,
 	store *state.Store,
 	stash secret.Stash,
+	cache *forgecache.Cache,
 	forges *forge.Registry,
 ) error {
+	checks, err := c.listChecks(ctx, log, view, wt, repo, store, stash, cache, forges)
+	if err != nil {
+		return err
+	}
+
+	if len(checks) == 0 {
+		if c.IncludePassing {
+			fmt.Fprintln(view, "no checks found")
+		} else {
+			fmt.Fprintln(view, "no failing checks")
+		}
+		return nil
+	}
+
+	printChecksSummary(view, checks)
+	return nil
+}
+
+// listChecks fetches the check runs for the branch's change.
+func (c *branchChecksCmd) listChecks(
+	ctx context.Context,
+	log *silog.Logger,
+	view ui.View,
+	wt *git.Worktree,
+	repo *git.Repository,
+	store *state.Store,
+	stash secret.Stash,
+	cache *forgecache.Cache,
+	forges *forge.Registry,
+) ([]*forge.ChangeCheckItem, error) {
 	if c.Branch == "" {
 		currentBranch, err := wt.CurrentBranch(ctx)
 		if err != nil {
-			return fmt.Errorf("get current branch: %w", err)
+			return nil, fmt.Errorf("get current branch: %w", err)
 		}
 		c.Branch = currentBranch
 	}
 
 	remote, err := ensureRemote(ctx, repo, store, log, view)
 	if err != nil {
-		return fmt.Errorf("get remote: %w", err)
+		return nil, fmt.Errorf("get remote: %w", err)
 	}
-	forgeRepo, err := openRemoteRepository(ctx, log, stash, forges, repo, remote)
+	forgeRepo, err := openRemoteRepository(ctx, log, stash, cache, forges, repo, remote)
 	if err != nil {
-		return fmt.Errorf("open remote repository: %w", err)
+		return nil, fmt.Errorf("open remote repository: %w", err)
 	}
 
 	checker, ok := forgeRepo.(forge.ChangeChecksLister)
 	if !ok {
-		return fmt.Errorf(
+		return nil, fmt.Errorf(
 			"forge %q does not support CI check listing",
 			forgeRepo.Forge().ID(),
 		)
@@ -72,10 +104,10 @@ func (c *branchChecksCmd) Run(
 		ctx, c.Branch, forge.FindChangesOptions{Limit: 1},
 	)
 	if err != nil {
-		return fmt.Errorf("find changes for branch %q: %w", c.Branch, err)
+		return nil, fmt.Errorf("find changes for branch %q: %w", c.Branch, err)
 	}
 	if len(changes) == 0 {
-		return fmt.Errorf("no open pull request found for branch %q", c.Branch)
+		return nil, fmt.Errorf("no open pull request found for branch %q", c.Branch)
 	}
 	prID := changes[0].ID
 
@@ -85,22 +117,11 @@ func (c *branchChecksCmd) Run(
 		&forge.ListChangeChecksOptions{OnlyFailing: !c.IncludePassing},
 	) {
 		if err != nil {
-			return fmt.Errorf("list change checks: %w", err)
+			return nil, fmt.Errorf("list change checks: %w", err)
 		}
 		checks = append(checks, it)
 	}
-
-	if len(checks) == 0 {
-		if c.IncludePassing {
-			fmt.Fprintln(view, "no checks found")
-		} else {
-			fmt.Fprintln(view, "no failing checks")
-		}
-		return nil
-	}
-
-	printChecksSummary(view, checks)
-	return nil
+	return checks, nil
 }
 
 // printChecksSummary writes a one-line-per-check summary, grouped by
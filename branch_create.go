@@ -5,8 +5,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
+	"unicode/utf8"
 
 	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/hook"
 	"go.abhg.dev/gs/internal/silog"
 	"go.abhg.dev/gs/internal/spice"
 	"go.abhg.dev/gs/internal/spice/state"
@@ -20,6 +23,7 @@ type branchCreateConfig struct {
 
 type branchCreateCmd struct {
 	branchCreateConfig
+	branchNameConfig
 
 	Name string `arg:"" optional:"" help:"Name of the new branch"`
 
@@ -31,12 +35,16 @@ type branchCreateCmd struct {
 	Message     string `short:"m" xor:"commit-message-source" placeholder:"MSG" help:"Commit message"`
 	MessageFile string `short:"F" xor:"commit-message-source" placeholder:"FILE" help:"Read the commit message from the given file."`
 
+	Issue string `placeholder:"ISSUE" released:"unreleased" help:"Issue tracker reference to link the branch to"`
+
 	NoVerify bool `help:"Bypass pre-commit and commit-msg hooks."`
 	Signoff  bool `config:"commit.signoff" help:"Add Signed-off-by trailer to the commit message"`
 
 	Commit bool `negatable:"" default:"true" config:"branchCreate.commit" help:"Commit staged changes to the new branch, or create an empty commit"`
 }
 
+func (*branchCreateCmd) locksRepository() {}
+
 func (*branchCreateCmd) Help() string {
 	return text.Dedent(`
 		Staged changes will be committed to the new branch.
@@ -46,12 +54,21 @@ func (*branchCreateCmd) Help() string {
 		Use --no-commit to create the branch without committing.
 		-m/--message and -F/--file always imply --commit.
 
+		Use --issue to link the branch to an issue in an issue tracker.
+		If not provided and the 'spice.branchName.ticketPattern'
+		configuration option is set, the issue is inferred from the branch name.
+		Submitted change requests will reference the issue.
+
 		If a branch name is not provided,
 		it will be generated from the commit message.
 		If the 'spice.branchCreate.prefix' configuration option is set,
 		branch names will be prefixed with its value.
 		If the 'spice.branchCreate.generatedBranchNameLimit' configuration option is set,
 		auto-generated branch names will be truncated to that length at word boundaries (defaults to 32).
+		Use the 'spice.branchName.template' configuration option
+		to generate names with other information,
+		like the ticket ID or the user name.
+		All branch names must satisfy the 'spice.branchName.*' naming policy.
 
 		The new branch will use the current branch as its base.
 		Use --target to specify a different base branch.
@@ -103,6 +120,7 @@ func (cmd *branchCreateCmd) Run(
 	store *state.Store,
 	svc *spice.Service,
 	restackHandler RestackHandler,
+	hooks *hook.Runner,
 ) (err error) {
 	// If a message is specified, automatically enable commits
 	if cmd.Message != "" || cmd.MessageFile != "" {
@@ -122,12 +140,22 @@ func (cmd *branchCreateCmd) Run(
 		}
 	}
 
-	// If a branch name was specified, verify it's unused.
+	namePolicy, err := cmd.Policy()
+	if err != nil {
+		return err
+	}
+
+	// If a branch name was specified, verify it's unused
+	// and that it follows the naming policy.
 	// We do this before any changes to the working tree or index.
 	if cmd.Name != "" {
 		if repo.BranchExists(ctx, cmd.Name) {
 			return fmt.Errorf("branch already exists: %v", cmd.Name)
 		}
+
+		if err := namePolicy.Validate(cmd.Prefix + cmd.Name); err != nil {
+			return err
+		}
 	}
 
 	baseName := cmd.Target
@@ -237,24 +265,40 @@ func (cmd *branchCreateCmd) Run(
 		if cmd.Name == "" {
 			// Branch name was not specified.
 			// Generate one from the commit message.
-			subject, err := repo.CommitSubject(ctx, commitHash.String())
+			commit, err := repo.ReadCommit(ctx, commitHash.String())
+			if err != nil {
+				return fmt.Errorf("read commit: %w", err)
+			}
+
+			vars, err := cmd.TemplateVars(ctx, svc, trunk, baseName, commit.Message())
 			if err != nil {
-				return fmt.Errorf("get commit subject: %w", err)
+				return err
 			}
 
-			msgName := spice.GenerateBranchName(subject, cmd.GeneratedBranchNameLimit)
+			prefixLen := utf8.RuneCountInString(cmd.Prefix)
+			msgName := namePolicy.Generate(vars, cmd.GeneratedBranchNameLimit, prefixLen)
+			if msgName == "" {
+				return errors.New("could not generate a branch name from the commit message")
+			}
 			current := cmd.Prefix + msgName
 
 			// If the auto-generated branch name already exists,
 			// append a number to it until we find an unused name.
+			// The name is shortened further if needed to fit the number.
 			for num := 2; repo.BranchExists(ctx, current); num++ {
-				current = fmt.Sprintf("%s%s-%d", cmd.Prefix, msgName, num)
+				suffix := "-" + strconv.Itoa(num)
+				name := namePolicy.Generate(vars, cmd.GeneratedBranchNameLimit, prefixLen+len(suffix))
+				current = cmd.Prefix + name + suffix
 			}
 
 			cmd.Name = current
 			generatedName = true
 			log.Debug("Branch name generated from commit",
 				"name", cmd.Name, "commit", commitHash)
+
+			if err := namePolicy.Validate(cmd.Name); err != nil {
+				return fmt.Errorf("generated %w", err)
+			}
 		}
 	}
 
@@ -264,6 +308,24 @@ func (cmd *branchCreateCmd) Run(
 		branchName = cmd.Prefix + cmd.Name
 	}
 
+	// If an issue wasn't specified,
+	// try to infer it from the branch name.
+	// Only do this for explicitly configured patterns:
+	// the default is too loose for arbitrary branch names.
+	issue := cmd.Issue
+	if issue == "" && namePolicy.TicketPattern != nil {
+		issue = namePolicy.FindTicket(branchName)
+		if issue != "" {
+			log.Debug("Inferred issue from branch name",
+				"branch", branchName, "issue", issue)
+		}
+	}
+
+	var issueReq *string
+	if issue != "" {
+		issueReq = &issue
+	}
+
 	// Start the transaction and make sure it would work
 	// before actually creating the branch.
 	// This way, if the transaction would've failed anyway
@@ -275,6 +337,7 @@ func (cmd *branchCreateCmd) Run(
 		Name:            branchName,
 		Base:            baseName,
 		BaseHash:        baseHash,
+		Issue:           issueReq,
 		MergedDownstack: newMergedDownstack,
 	}); err != nil {
 		return fmt.Errorf("add branch %v with base %v: %w", branchName, baseName, err)
@@ -321,6 +384,10 @@ func (cmd *branchCreateCmd) Run(
 		return fmt.Errorf("update branch state: %w", err)
 	}
 
+	if err := hooks.Run(ctx, hook.BranchCreated, []string{branchName}); err != nil {
+		return err
+	}
+
 	if cmd.Below || cmd.Insert {
 		return restackHandler.RestackUpstack(ctx, branchName, nil)
 	}
@@ -18,6 +18,8 @@ type branchDeleteCmd struct {
 	Branches []string `arg:"" optional:"" help:"Names of the branches to delete" predictor:"branches"`
 }
 
+func (*branchDeleteCmd) locksRepository() {}
+
 func (*branchDeleteCmd) Help() string {
 	return text.Dedent(`
 		The deleted branches and their commits are removed from the stack.
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.abhg.dev/gs/internal/changemeta"
+	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/forge/forgecache"
+	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/secret"
+	"go.abhg.dev/gs/internal/silog"
+	"go.abhg.dev/gs/internal/spice"
+	"go.abhg.dev/gs/internal/spice/state"
+	"go.abhg.dev/gs/internal/text"
+	"go.abhg.dev/gs/internal/ui"
+)
+
+type branchEditChangeCmd struct {
+	Branch string `placeholder:"NAME" help:"Branch whose change to edit" predictor:"trackedBranches"`
+
+	Labels    *string `placeholder:"LABELS" help:"Comma-separated labels the change should have. Pass an empty string to remove all labels."`
+	Reviewers *string `placeholder:"REVIEWERS" help:"Comma-separated reviewers the change should have. Pass an empty string to remove all reviewers."`
+	Assignees *string `placeholder:"ASSIGNEES" help:"Comma-separated assignees the change should have. Pass an empty string to remove all assignees."`
+	Projects  *string `placeholder:"PROJECTS" help:"Comma-separated projects the change should belong to. Pass an empty string to remove it from all projects."`
+	Milestone *string `placeholder:"TITLE" help:"Milestone the change should have. Pass an empty string to remove the milestone."`
+}
+
+func (*branchEditChangeCmd) Help() string {
+	return text.Dedent(`
+		Updates the labels, reviewers, assignees, milestone, and projects
+		of a submitted branch's change to match the desired state.
+		Values that the change has but that aren't desired are removed.
+
+		The desired state is read from a fenced code block
+		with the info string "git-spice" in the branch's commit messages.
+		If multiple commits have such a block,
+		the most recent commit wins.
+		For example:
+
+			` + "```git-spice" + `
+			labels: [bug, ui]
+			reviewers: [alice]
+			milestone: v1.0
+			` + "```" + `
+
+		Flags override the fields of the block.
+		Fields that are neither in the block nor set with flags
+		are left unchanged.
+		Use --branch to target a different branch.
+	`)
+}
+
+func (cmd *branchEditChangeCmd) AfterApply(ctx context.Context, wt *git.Worktree) error {
+	if cmd.Branch == "" {
+		var err error
+		cmd.Branch, err = wt.CurrentBranch(ctx)
+		if err != nil {
+			return fmt.Errorf("get current branch: %w", err)
+		}
+	}
+	return nil
+}
+
+func (cmd *branchEditChangeCmd) Run(
+	ctx context.Context,
+	log *silog.Logger,
+	view ui.View,
+	repo *git.Repository,
+	store *state.Store,
+	svc *spice.Service,
+	stash secret.Stash,
+	cache *forgecache.Cache,
+	forges *forge.Registry,
+) error {
+	b, err := svc.LookupBranch(ctx, cmd.Branch)
+	if err != nil {
+		if errors.Is(err, state.ErrNotExist) {
+			return fmt.Errorf("branch not tracked: %s", cmd.Branch)
+		}
+		return fmt.Errorf("get branch: %w", err)
+	}
+	if b.Change == nil {
+		return fmt.Errorf("%v: branch has not been submitted", cmd.Branch)
+	}
+
+	spec, err := branchChangeSpec(ctx, repo, b)
+	if err != nil {
+		return err
+	}
+	spec.Override(cmd.flagSpec())
+	if spec.IsZero() {
+		return fmt.Errorf("%v: nothing to edit: use flags or a %q block in a commit message",
+			cmd.Branch, changemeta.InfoString)
+	}
+
+	remote, err := ensureRemote(ctx, repo, store, log, view)
+	if err != nil {
+		return fmt.Errorf("get remote: %w", err)
+	}
+	remoteRepo, err := openRemoteRepository(ctx, log, stash, cache, forges, repo, remote)
+	if err != nil {
+		return fmt.Errorf("open remote repository: %w", err)
+	}
+
+	changeID := b.Change.ChangeID()
+	change, err := remoteRepo.FindChangeByID(ctx, changeID)
+	if err != nil {
+		return fmt.Errorf("find change %v: %w", changeID, err)
+	}
+
+	current := changemeta.Current{
+		Labels:    change.Labels,
+		Reviewers: change.Reviewers,
+		Assignees: change.Assignees,
+		Milestone: change.Milestone,
+	}
+	if spec.Projects != nil {
+		lister, ok := remoteRepo.(forge.ChangeProjectLister)
+		if !ok {
+			return fmt.Errorf("forge %q does not support projects", remoteRepo.Forge().ID())
+		}
+
+		current.Projects, err = lister.ListChangeProjects(ctx, changeID)
+		if err != nil {
+			return fmt.Errorf("list projects of %v: %w", changeID, err)
+		}
+	}
+
+	opts := changemeta.Reconcile(spec, &current)
+	if isZeroEdit(opts) {
+		log.Infof("%v: %v is up to date", cmd.Branch, changeID)
+		return nil
+	}
+
+	if err := remoteRepo.EditChange(ctx, changeID, opts); err != nil {
+		return fmt.Errorf("edit change %v: %w", changeID, err)
+	}
+	log.Infof("%v: updated %v", cmd.Branch, changeID)
+	return nil
+}
+
+// branchChangeSpec reads the desired change metadata
+// from the most recent commit in the branch that declares it.
+// It returns an empty spec if no commit does.
+func branchChangeSpec(
+	ctx context.Context,
+	repo *git.Repository,
+	b *spice.LookupBranchResponse,
+) (*changemeta.Spec, error) {
+	// Commit messages are listed newest first.
+	msgs, err := repo.CommitMessageRange(ctx, b.Head.String(), b.BaseHash.String())
+	if err != nil {
+		return nil, fmt.Errorf("list commits: %w", err)
+	}
+
+	for _, msg := range msgs {
+		spec, err := changemeta.ParseMessage(msg.Body)
+		if err != nil {
+			return nil, fmt.Errorf("commit %q: %w", msg.Subject, err)
+		}
+		if spec != nil {
+			return spec, nil
+		}
+	}
+	return new(changemeta.Spec), nil
+}
+
+// flagSpec returns the change metadata requested with flags.
+func (cmd *branchEditChangeCmd) flagSpec() *changemeta.Spec {
+	return &changemeta.Spec{
+		Labels:    splitFlagList(cmd.Labels),
+		Reviewers: splitFlagList(cmd.Reviewers),
+		Assignees: splitFlagList(cmd.Assignees),
+		Projects:  splitFlagList(cmd.Projects),
+		Milestone: cmd.Milestone,
+	}
+}
+
+// splitFlagList splits a comma-separated flag value.
+// It returns nil if the flag wasn't set,
+// and an empty list if it was set to an empty string.
+func splitFlagList(s *string) *[]string {
+	if s == nil {
+		return nil
+	}
+
+	values := []string{}
+	for v := range strings.SplitSeq(*s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return &values
+}
+
+func isZeroEdit(opts forge.EditChangeOptions) bool {
+	return len(opts.AddLabels) == 0 &&
+		len(opts.RemoveLabels) == 0 &&
+		len(opts.AddReviewers) == 0 &&
+		len(opts.RemoveReviewers) == 0 &&
+		len(opts.AddAssignees) == 0 &&
+		len(opts.RemoveAssignees) == 0 &&
+		len(opts.AddProjects) == 0 &&
+		len(opts.RemoveProjects) == 0 &&
+		opts.Milestone == nil
+}
@@ -18,6 +18,8 @@ type branchFoldCmd struct {
 	Branch string `placeholder:"NAME" help:"Name of the branch" predictor:"trackedBranches"`
 }
 
+func (*branchFoldCmd) locksRepository() {}
+
 func (*branchFoldCmd) Help() string {
 	return text.Dedent(`
 		Commits from the current branch will be merged into its base
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alecthomas/kong"
+	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/silog"
+	"go.abhg.dev/gs/internal/spice/state"
+	"go.abhg.dev/gs/internal/text"
+)
+
+type branchHistoryCmd struct {
+	Branch string `placeholder:"NAME" help:"Branch to list versions of" predictor:"trackedBranches"`
+}
+
+func (*branchHistoryCmd) Help() string {
+	return text.Dedent(`
+		Lists the versions of a branch that were pushed
+		by 'gs branch submit' and other submit commands,
+		oldest first.
+		Each version is listed with the time it was pushed
+		and the number of commits in it.
+
+		Use 'gs branch interdiff' to compare two versions.
+		Use --branch to target a different branch.
+	`)
+}
+
+func (cmd *branchHistoryCmd) AfterApply(ctx context.Context, wt *git.Worktree) error {
+	if cmd.Branch == "" {
+		var err error
+		cmd.Branch, err = wt.CurrentBranch(ctx)
+		if err != nil {
+			return fmt.Errorf("get current branch: %w", err)
+		}
+	}
+	return nil
+}
+
+func (cmd *branchHistoryCmd) Run(
+	ctx context.Context,
+	kctx *kong.Context,
+	log *silog.Logger,
+	repo *git.Repository,
+	store *state.Store,
+) error {
+	versions, err := store.LoadBranchHistory(ctx, cmd.Branch)
+	if err != nil {
+		return fmt.Errorf("load history: %w", err)
+	}
+	if len(versions) == 0 {
+		log.Infof("%v: no pushed versions recorded", cmd.Branch)
+		return nil
+	}
+
+	head, err := repo.PeelToCommit(ctx, cmd.Branch)
+	if err != nil {
+		head = "" // branch was deleted locally
+	}
+
+	for i, v := range versions {
+		var commits string
+		if err := checkVersionExists(ctx, repo, i+1, v); err != nil {
+			log.Debug("Version is not available", "error", err)
+			commits = "garbage collected"
+		} else {
+			n, err := repo.CountCommits(ctx,
+				git.CommitRangeFrom(v.Span.Head).ExcludeFrom(v.Span.Base))
+			if err != nil {
+				log.Warn("Could not count commits", "version", i+1, "error", err)
+			}
+			commits = fmt.Sprintf("%d commit(s)", n)
+		}
+
+		var suffix string
+		if v.Span.Head == head {
+			suffix = " (current)"
+		}
+
+		_, err = fmt.Fprintf(kctx.Stdout, "v%d  %v  %v  %v%v\n",
+			i+1, v.Span.Head.Short(), v.Time.Format("2006-01-02 15:04"),
+			commits, suffix)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
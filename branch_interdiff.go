@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/alecthomas/kong"
+	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/spice/state"
+	"go.abhg.dev/gs/internal/text"
+)
+
+type branchInterdiffCmd struct {
+	Branch   string `placeholder:"NAME" help:"Branch to compare versions of" predictor:"trackedBranches"`
+	Versions string `arg:"" optional:"" placeholder:"vN..vM" help:"Versions to compare. Defaults to the last two versions."`
+}
+
+func (*branchInterdiffCmd) Help() string {
+	return text.Dedent(`
+		Compares two pushed versions of a branch with 'git range-diff'.
+		Versions are numbered from 1 in the order they were pushed.
+		Use 'gs branch history' to list them.
+
+		By default, the last two versions are compared.
+		Specify versions as 'vN..vM' to compare vN with vM,
+		or as 'vN' to compare vN with the latest version.
+
+		Use --branch to target a different branch.
+	`)
+}
+
+func (cmd *branchInterdiffCmd) AfterApply(ctx context.Context, wt *git.Worktree) error {
+	if cmd.Branch == "" {
+		var err error
+		cmd.Branch, err = wt.CurrentBranch(ctx)
+		if err != nil {
+			return fmt.Errorf("get current branch: %w", err)
+		}
+	}
+	return nil
+}
+
+func (cmd *branchInterdiffCmd) Run(
+	ctx context.Context,
+	kctx *kong.Context,
+	repo *git.Repository,
+	store *state.Store,
+) error {
+	versions, err := store.LoadBranchHistory(ctx, cmd.Branch)
+	if err != nil {
+		return fmt.Errorf("load history: %w", err)
+	}
+
+	from, to, err := parseVersionRange(cmd.Versions, len(versions))
+	if err != nil {
+		return err
+	}
+
+	for _, n := range []int{from, to} {
+		if err := checkVersionExists(ctx, repo, n, versions[n-1]); err != nil {
+			return err
+		}
+	}
+
+	return repo.RangeDiff(ctx, git.RangeDiffRequest{
+		Old:    versions[from-1].Span,
+		New:    versions[to-1].Span,
+		Stdout: kctx.Stdout,
+	})
+}
+
+// checkVersionExists verifies that the commits of version n of a branch
+// are still present in the repository.
+// Commits of old versions may be garbage collected
+// once nothing refers to them.
+func checkVersionExists(ctx context.Context, repo *git.Repository, n int, v state.BranchVersion) error {
+	for _, hash := range []git.Hash{v.Span.Base, v.Span.Head} {
+		if _, err := repo.PeelToCommit(ctx, hash.String()); err != nil {
+			return fmt.Errorf("v%d: commit %v is no longer in the repository; it may have been garbage collected", n, hash.Short())
+		}
+	}
+	return nil
+}
+
+// parseVersionRange parses a range of versions in the form "vN..vM" or "vN"
+// given the number of the latest version.
+// The "v" prefix is optional.
+// An empty string refers to the last two versions.
+func parseVersionRange(s string, latest int) (from, to int, err error) {
+	if latest < 2 {
+		return 0, 0, fmt.Errorf("need at least two pushed versions to compare, have %d", latest)
+	}
+	if s == "" {
+		return latest - 1, latest, nil
+	}
+
+	fromStr, toStr, ok := strings.Cut(s, "..")
+	from, err = parseVersion(fromStr, latest)
+	if err != nil {
+		return 0, 0, err
+	}
+	to = latest
+	if ok {
+		to, err = parseVersion(toStr, latest)
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+
+	if from == to {
+		return 0, 0, errors.New("cannot compare a version with itself")
+	}
+	return from, to, nil
+}
+
+func parseVersion(s string, latest int) (int, error) {
+	n, err := strconv.Atoi(strings.TrimPrefix(s, "v"))
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid version %q: expected vN", s)
+	}
+	if n > latest {
+		return 0, fmt.Errorf("version %v does not exist: latest version is v%d", s, latest)
+	}
+	return n, nil
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.abhg.dev/gs/internal/spice"
+)
+
+// branchNameConfig configures the branch naming policy.
+// It's embedded into all commands that create or rename branches.
+type branchNameConfig struct {
+	BranchNameTemplate      string `hidden:"" config:"branchName.template" help:"Template for auto-generated branch names"`
+	BranchNameTicketPattern string `hidden:"" config:"branchName.ticketPattern" help:"Regular expression matching ticket IDs"`
+	BranchNameMaxLength     int    `hidden:"" config:"branchName.maxLength" help:"Maximum length of branch names"`
+	BranchNameAllowedChars  string `hidden:"" config:"branchName.allowedChars" help:"Characters allowed in branch names"`
+	BranchNameRequireTicket bool   `hidden:"" config:"branchName.requireTicket" help:"Require a ticket ID in branch names"`
+	BranchNameUser          string `hidden:"" config:"branchName.user" help:"User name for branch name templates"`
+
+	GitUserEmail string `hidden:"" config:"@user.email"`
+}
+
+// Policy builds a branch naming policy from the configuration.
+func (c *branchNameConfig) Policy() (*spice.BranchNamePolicy, error) {
+	if err := spice.ParseBranchNameTemplate(c.BranchNameTemplate); err != nil {
+		return nil, fmt.Errorf("spice.branchName.template: %w", err)
+	}
+
+	policy := spice.BranchNamePolicy{
+		Template:      c.BranchNameTemplate,
+		MaxLength:     c.BranchNameMaxLength,
+		RequireTicket: c.BranchNameRequireTicket,
+	}
+
+	if c.BranchNameTicketPattern != "" {
+		re, err := regexp.Compile(c.BranchNameTicketPattern)
+		if err != nil {
+			return nil, fmt.Errorf("spice.branchName.ticketPattern: %w", err)
+		}
+		policy.TicketPattern = re
+	}
+
+	if c.BranchNameAllowedChars != "" {
+		re, err := regexp.Compile("^[" + c.BranchNameAllowedChars + "]$")
+		if err != nil {
+			return nil, fmt.Errorf("spice.branchName.allowedChars: %w", err)
+		}
+		policy.AllowedChars = re
+	}
+
+	return &policy, nil
+}
+
+// user returns the value of the {user} template variable.
+//
+// This is spice.branchName.user if set,
+// or the local part of the user's email address,
+// or the $USER environment variable.
+func (c *branchNameConfig) user() string {
+	email, _, _ := strings.Cut(c.GitUserEmail, "@")
+	return cmp.Or(c.BranchNameUser, email, os.Getenv("USER"))
+}
+
+// TemplateVars returns variables for the branch name template
+// for a branch that will be created on top of base.
+func (c *branchNameConfig) TemplateVars(
+	ctx context.Context,
+	svc *spice.Service,
+	trunk, base, message string,
+) (spice.BranchNameVars, error) {
+	vars := spice.BranchNameVars{
+		User:    c.user(),
+		Date:    time.Now().Format(time.DateOnly),
+		Message: message,
+	}
+
+	// A new branch on top of trunk starts a new stack
+	// so there's no stack name for it yet.
+	if base != trunk {
+		bottom, err := svc.FindBottom(ctx, base)
+		if err != nil {
+			return vars, fmt.Errorf("find bottom of stack: %w", err)
+		}
+		vars.Stack = bottom
+	}
+
+	return vars, nil
+}
@@ -20,6 +20,8 @@ type branchOntoCmd struct {
 	Onto   string `arg:"" optional:"" help:"Destination branch" predictor:"trackedBranches"`
 }
 
+func (*branchOntoCmd) locksRepository() {}
+
 func (*branchOntoCmd) Help() string {
 	return text.Dedent(`
 		Commits of the current branch
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/alecthomas/kong"
+	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/iterutil"
+	"go.abhg.dev/gs/internal/silog"
+	"go.abhg.dev/gs/internal/spice/state"
+	"go.abhg.dev/gs/internal/text"
+)
+
+type branchOwnersCmd struct {
+	Branch string `placeholder:"NAME" help:"Branch to configure" predictor:"trackedBranches"`
+
+	Enable  bool     `xor:"enable-disable" help:"Request reviews from code owners for this branch"`
+	Disable bool     `xor:"enable-disable" help:"Don't request reviews from code owners for this branch"`
+	Add     []string `placeholder:"REVIEWER" help:"Also request reviews from these users. Pass multiple times or separate with commas."`
+	Skip    []string `placeholder:"OWNER" help:"Don't request reviews from these code owners. Pass multiple times or separate with commas."`
+	Reset   bool     `help:"Remove all overrides for the branch before applying other flags"`
+}
+
+func (*branchOwnersCmd) Help() string {
+	return text.Dedent(`
+		Overrides how reviewers are requested from code owners
+		when the branch is submitted with --codeowners
+		or with spice.submit.codeowners set.
+
+		Use --enable or --disable to override spice.submit.codeowners
+		for the branch.
+		Use --add to request reviews from more users,
+		and --skip to leave out users or teams listed in CODEOWNERS.
+		Use --reset to remove all overrides.
+
+		Without any flags, the current overrides are printed.
+		Use --branch to target a different branch.
+	`)
+}
+
+func (cmd *branchOwnersCmd) AfterApply(ctx context.Context, wt *git.Worktree) error {
+	if cmd.Branch == "" {
+		var err error
+		cmd.Branch, err = wt.CurrentBranch(ctx)
+		if err != nil {
+			return fmt.Errorf("get current branch: %w", err)
+		}
+	}
+	return nil
+}
+
+func (cmd *branchOwnersCmd) Run(
+	ctx context.Context,
+	kctx *kong.Context,
+	log *silog.Logger,
+	store *state.Store,
+) error {
+	owners, err := store.LoadBranchOwners(ctx, cmd.Branch)
+	if err != nil {
+		return fmt.Errorf("load overrides: %w", err)
+	}
+
+	modified := cmd.Enable || cmd.Disable || len(cmd.Add) > 0 || len(cmd.Skip) > 0
+	if !modified && !cmd.Reset {
+		return printBranchOwners(kctx, log, cmd.Branch, owners)
+	}
+
+	if owners == nil || cmd.Reset {
+		owners = new(state.BranchOwners)
+	}
+	if cmd.Enable || cmd.Disable {
+		enabled := cmd.Enable
+		owners.Enabled = &enabled
+	}
+	owners.Add = slices.Collect(iterutil.Uniq(owners.Add, cmd.Add))
+	owners.Skip = slices.Collect(iterutil.Uniq(owners.Skip, cmd.Skip))
+
+	if owners.Enabled == nil && len(owners.Add) == 0 && len(owners.Skip) == 0 {
+		if err := store.ClearBranchOwners(ctx, cmd.Branch); err != nil {
+			return fmt.Errorf("clear overrides: %w", err)
+		}
+		log.Infof("%v: removed code owner overrides", cmd.Branch)
+		return nil
+	}
+
+	if err := store.SaveBranchOwners(ctx, cmd.Branch, owners); err != nil {
+		return fmt.Errorf("save overrides: %w", err)
+	}
+	log.Infof("%v: updated code owner overrides", cmd.Branch)
+	return nil
+}
+
+func printBranchOwners(kctx *kong.Context, log *silog.Logger, branch string, owners *state.BranchOwners) error {
+	if owners == nil {
+		log.Infof("%v: no code owner overrides", branch)
+		return nil
+	}
+
+	enabled := "default"
+	if owners.Enabled != nil {
+		enabled = fmt.Sprint(*owners.Enabled)
+	}
+
+	_, err := fmt.Fprintf(kctx.Stdout, "enabled: %v\nadd: %v\nskip: %v\n",
+		enabled, strings.Join(owners.Add, ", "), strings.Join(owners.Skip, ", "))
+	return err
+}
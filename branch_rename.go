@@ -14,10 +14,14 @@ import (
 )
 
 type branchRenameCmd struct {
+	branchNameConfig
+
 	OldName string `arg:"" predictor:"branches" optional:"" help:"Old name of the branch"`
 	NewName string `arg:"" optional:"" help:"New name of the branch"`
 }
 
+func (*branchRenameCmd) locksRepository() {}
+
 func (*branchRenameCmd) Help() string {
 	return text.Dedent(`
 		The following usage modes are supported:
@@ -31,6 +35,8 @@ func (*branchRenameCmd) Help() string {
 			# Rename current branch interactively
 			gs branch rename
 
+		The new name must satisfy the 'spice.branchName.*' naming policy.
+
 		If a branch was renamed outside of 'gs',
 		for example with 'git branch -m',
 		the branch tracking information will be out of date.
@@ -53,6 +59,11 @@ func (cmd *branchRenameCmd) Run(
 		oldName, newName = "", oldName
 	}
 
+	namePolicy, err := cmd.Policy()
+	if err != nil {
+		return err
+	}
+
 	if oldName == "" {
 		oldName, err = wt.CurrentBranch(ctx)
 		if err != nil {
@@ -69,7 +80,7 @@ func (cmd *branchRenameCmd) Run(
 				if strings.TrimSpace(s) == "" {
 					return errors.New("branch name cannot be empty")
 				}
-				return nil
+				return namePolicy.Validate(s)
 			})
 
 		if err := ui.Run(view, prompt); err != nil {
@@ -80,6 +91,10 @@ func (cmd *branchRenameCmd) Run(
 	must.NotBeBlankf(oldName, "old branch name must be set")
 	must.NotBeBlankf(newName, "new branch name must be set")
 
+	if err := namePolicy.Validate(newName); err != nil {
+		return err
+	}
+
 	if err := svc.RenameBranch(ctx, oldName, newName); err != nil {
 		return fmt.Errorf("rename branch: %w", err)
 	}
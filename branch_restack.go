@@ -12,6 +12,8 @@ type branchRestackCmd struct {
 	Branch string `placeholder:"NAME" help:"Branch to restack" predictor:"trackedBranches"`
 }
 
+func (*branchRestackCmd) locksRepository() {}
+
 func (*branchRestackCmd) Help() string {
 	return text.Dedent(`
 		The current branch will be rebased onto its base,
@@ -3,9 +3,12 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
 	"strings"
 
+	"github.com/alecthomas/kong"
 	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/forge/forgecache"
 	"go.abhg.dev/gs/internal/git"
 	"go.abhg.dev/gs/internal/review"
 	"go.abhg.dev/gs/internal/secret"
@@ -24,6 +27,8 @@ type branchReviewsCmd struct {
 
 	IncludeResolved bool   `help:"Include resolved threads"`
 	BotAllowlist    string `help:"Comma-separated bot logins to include" default:"copilot,claude,codex,github-advanced-security,copilot-pull-request-reviewer"`
+
+	Format string `enum:"text,sarif,quickfix,json" default:"text" released:"unreleased" help:"Output format. One of 'text', 'sarif', 'quickfix', or 'json'."`
 }
 
 func (*branchReviewsCmd) Help() string {
@@ -42,42 +47,88 @@ are also filtered unless --include-resolved is set.
 Bot comments are filtered to a small allowlist by default (Copilot,
 Claude, Codex, GitHub Advanced Security). Override with
 --bot-allowlist=name1,name2 (empty allowlist excludes all bots).
+
+Use --format to write threads to stdout for use with other tools:
+'sarif' for SARIF 2.1.0, 'quickfix' for "file:line: message" lines
+that editors like Vim can load, or 'json' for a stream of JSON objects.
+These formats also include annotations reported by CI checks,
+and locations are adjusted to match local files
+if the branch has changed since it was submitted.
 `
 }
 
 func (c *branchReviewsCmd) Run(
 	ctx context.Context,
+	kctx *kong.Context,
 	log *silog.Logger,
 	view ui.View,
 	wt *git.Worktree,
 	repo *git.Repository,
 	store *state.Store,
 	stash secret.Stash,
+	cache *forgecache.Cache,
 	forges *forge.Registry,
 ) error {
+	items, err := c.listItems(ctx, log, view, wt, repo, store, stash, cache, forges)
+	if err != nil {
+		return err
+	}
+
+	if c.Format != "text" {
+		return writeReviewItems(kctx.Stdout, c.Format, items)
+	}
+
+	if len(items) == 0 {
+		fmt.Fprintln(view, "no open review threads")
+		return nil
+	}
+
+	review.PrintSummary(view, items)
+	return nil
+}
+
+// listItems fetches the open review threads for the branch's PR.
+//
+// If exporting to a format other than text,
+// it also fetches check annotations,
+// and remaps locations to the local version of the branch.
+func (c *branchReviewsCmd) listItems(
+	ctx context.Context,
+	log *silog.Logger,
+	view ui.View,
+	wt *git.Worktree,
+	repo *git.Repository,
+	store *state.Store,
+	stash secret.Stash,
+	cache *forgecache.Cache,
+	forges *forge.Registry,
+) ([]*review.Item, error) {
 	// Resolve branch name.
-	if c.Branch == "" {
-		currentBranch, err := wt.CurrentBranch(ctx)
-		if err != nil {
-			return fmt.Errorf("get current branch: %w", err)
+	currentBranch, err := wt.CurrentBranch(ctx)
+	if err != nil {
+		if c.Branch == "" {
+			return nil, fmt.Errorf("get current branch: %w", err)
 		}
+		currentBranch = "" // detached HEAD
+	}
+	if c.Branch == "" {
 		c.Branch = currentBranch
 	}
 
 	// Open the forge repository.
 	remote, err := ensureRemote(ctx, repo, store, log, view)
 	if err != nil {
-		return fmt.Errorf("get remote: %w", err)
+		return nil, fmt.Errorf("get remote: %w", err)
 	}
-	forgeRepo, err := openRemoteRepository(ctx, log, stash, forges, repo, remote)
+	forgeRepo, err := openRemoteRepository(ctx, log, stash, cache, forges, repo, remote)
 	if err != nil {
-		return fmt.Errorf("open remote repository: %w", err)
+		return nil, fmt.Errorf("open remote repository: %w", err)
 	}
 
 	// Capability check: forge must support review threads.
 	threader, ok := forgeRepo.(forge.ReviewThreadLister)
 	if !ok {
-		return fmt.Errorf(
+		return nil, fmt.Errorf(
 			"forge %q does not support review thread listing",
 			forgeRepo.Forge().ID(),
 		)
@@ -88,10 +139,10 @@ func (c *branchReviewsCmd) Run(
 		ctx, c.Branch, forge.FindChangesOptions{Limit: 1},
 	)
 	if err != nil {
-		return fmt.Errorf("find changes for branch %q: %w", c.Branch, err)
+		return nil, fmt.Errorf("find changes for branch %q: %w", c.Branch, err)
 	}
 	if len(changes) == 0 {
-		return fmt.Errorf("no open pull request found for branch %q", c.Branch)
+		return nil, fmt.Errorf("no open pull request found for branch %q", c.Branch)
 	}
 	prID := changes[0].ID
 
@@ -122,7 +173,7 @@ func (c *branchReviewsCmd) Run(
 		},
 	) {
 		if err != nil {
-			return fmt.Errorf("list review threads: %w", err)
+			return nil, fmt.Errorf("list review threads: %w", err)
 		}
 		threads = append(threads, thread)
 	}
@@ -131,14 +182,82 @@ func (c *branchReviewsCmd) Run(
 	// posted an "Addressed in <sha>" reply more recently than the
 	// reviewer). Read-only — no deferred-state file involved.
 	items := review.PipelineForThreads(ctx, threads, viewerLogin)
+	if c.Format == "text" {
+		return items, nil
+	}
 
-	if len(items) == 0 {
-		fmt.Fprintln(view, "no open review threads")
-		return nil
+	if checker, ok := forgeRepo.(forge.ChangeChecksLister); ok {
+		var checks []*forge.ChangeCheckItem
+		for check, err := range checker.ListChangeChecks(
+			ctx, prID,
+			&forge.ListChangeChecksOptions{IncludeAnnotations: true},
+		) {
+			if err != nil {
+				return nil, fmt.Errorf("list change checks: %w", err)
+			}
+			checks = append(checks, check)
+		}
+		items = append(items, review.ItemsForChecks(checks)...)
 	}
 
-	review.PrintSummary(view, items)
-	return nil
+	for _, it := range items {
+		it.Branch = c.Branch
+	}
+
+	// Locations refer to the submitted version of the branch.
+	// Map them to the local version if it has moved on.
+	lineMap, err := c.localLineMap(ctx, wt, repo, changes[0].HeadHash, c.Branch == currentBranch)
+	if err != nil {
+		log.Warnf("%v: Could not match locations to local files: %v", c.Branch, err)
+	} else if lineMap != nil {
+		review.Remap(items, lineMap)
+	}
+
+	return items, nil
+}
+
+// localLineMap returns a LineMap from the submitted version of the branch
+// to its local version:
+// the working tree if the branch is checked out,
+// or the branch's head commit otherwise.
+// It returns nil if the two are the same.
+func (c *branchReviewsCmd) localLineMap(
+	ctx context.Context,
+	wt *git.Worktree,
+	repo *git.Repository,
+	submitted git.Hash,
+	checkedOut bool,
+) (*git.LineMap, error) {
+	if _, err := repo.PeelToCommit(ctx, submitted.String()); err != nil {
+		return nil, fmt.Errorf("submitted commit %v is not available locally", submitted.Short())
+	}
+
+	if checkedOut {
+		return wt.DiffLineMap(ctx, submitted.String())
+	}
+
+	head, err := repo.PeelToCommit(ctx, c.Branch)
+	if err != nil {
+		return nil, fmt.Errorf("resolve branch: %w", err)
+	}
+	if head == submitted {
+		return nil, nil
+	}
+	return repo.DiffLineMap(ctx, submitted.String(), head.String())
+}
+
+// writeReviewItems writes review items to w in the given format.
+func writeReviewItems(w io.Writer, format string, items []*review.Item) error {
+	switch format {
+	case "sarif":
+		return review.WriteSARIF(w, items)
+	case "quickfix":
+		return review.WriteQuickfix(w, items)
+	case "json":
+		return review.WriteJSON(w, items)
+	default:
+		return fmt.Errorf("unknown format: %q", format)
+	}
 }
 
 // parseCSV splits a comma-separated string into trimmed, non-empty tokens.
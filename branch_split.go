@@ -21,8 +21,12 @@ type branchSplitCmd struct {
 	Branch string `placeholder:"NAME" help:"Branch to split commits of."`
 
 	Prefix string `default:"" config:"branchCreate.prefix" hidden:""`
+
+	branchNameConfig
 }
 
+func (*branchSplitCmd) locksRepository() {}
+
 func (*branchSplitCmd) Help() string {
 	return text.Dedent(`
 		Splits the current branch into two or more branches
@@ -59,6 +63,8 @@ func (*branchSplitCmd) Help() string {
 
 		A split at commit 2 using the branch name "A"
 		would require a new name to be provided for commit 3.
+
+		New branch names must satisfy the 'spice.branchName.*' naming policy.
 	`)
 }
 
@@ -85,9 +91,15 @@ func (cmd *branchSplitCmd) Run(
 		cmd.Branch = currentBranch
 	}
 
+	namePolicy, err := cmd.Policy()
+	if err != nil {
+		return err
+	}
+
 	result, err := splitHandler.SplitBranch(ctx, &split.BranchRequest{
-		Branch:  cmd.Branch,
-		Options: &cmd.Options,
+		Branch:       cmd.Branch,
+		Options:      &cmd.Options,
+		ValidateName: namePolicy.Validate,
 		SelectCommits: func(ctx context.Context, branchCommits []git.CommitDetail) ([]split.Point, error) {
 			if !ui.Interactive(view) {
 				return nil, fmt.Errorf("use --at to split non-interactively: %w", ui.ErrPrompt)
@@ -143,6 +155,9 @@ func (cmd *branchSplitCmd) Run(
 						if value != cmd.Branch && repo.BranchExists(ctx, value) {
 							return fmt.Errorf("branch name already taken: %v", value)
 						}
+						if value != cmd.Branch {
+							return namePolicy.Validate(value)
+						}
 						return nil
 					}).
 					WithValue(value)
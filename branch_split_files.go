@@ -19,6 +19,8 @@ import (
 )
 
 type branchSplitFilesCmd struct {
+	branchNameConfig
+
 	Branch string `arg:"" optional:"" help:"Branch to split (default: current branch)"`
 
 	// Non-interactive mode for scripting/testing.
@@ -30,6 +32,8 @@ type branchSplitFilesCmd struct {
 	Delete bool `help:"Delete original branch (non-interactive)"`
 }
 
+func (*branchSplitFilesCmd) locksRepository() {}
+
 // splitFilesPoint represents a file group specification for splitting.
 type splitFilesPoint struct {
 	Files   []string // pipe-separated file paths
@@ -90,6 +94,8 @@ func (*branchSplitFilesCmd) Help() string {
 		Files are separated by pipes (|), followed by branch name and message.
 		Use --keep to keep the original branch at the top of the stack,
 		or --delete to remove it after splitting.
+
+		New branch names must satisfy the 'spice.branchName.*' naming policy.
 	`)
 }
 
@@ -122,6 +128,11 @@ func (cmd *branchSplitFilesCmd) Run(
 		return errors.New("cannot split trunk branch")
 	}
 
+	namePolicy, err := cmd.Policy()
+	if err != nil {
+		return err
+	}
+
 	branchInfo, err := svc.LookupBranch(ctx, targetBranch)
 	if err != nil {
 		return fmt.Errorf("lookup branch %q: %w", targetBranch, err)
@@ -179,13 +190,13 @@ func (cmd *branchSplitFilesCmd) Run(
 	var groups []fileGroup
 	if len(cmd.At) > 0 {
 		// Non-interactive mode.
-		groups, err = cmd.parseNonInteractiveGroups(ctx, repo, targetBranch, files)
+		groups, err = cmd.parseNonInteractiveGroups(ctx, repo, namePolicy, targetBranch, files)
 		if err != nil {
 			return err
 		}
 	} else {
 		// Interactive mode.
-		groups, err = cmd.selectFilesInteractively(ctx, view, targetBranch, files)
+		groups, err = cmd.selectFilesInteractively(ctx, view, namePolicy, targetBranch, files)
 		if err != nil {
 			return err
 		}
@@ -293,6 +304,7 @@ func (cmd *branchSplitFilesCmd) getChangedFiles(
 func (cmd *branchSplitFilesCmd) parseNonInteractiveGroups(
 	ctx context.Context,
 	repo *git.Repository,
+	namePolicy *spice.BranchNamePolicy,
 	originalBranch string,
 	allFiles []widget.FileEntry,
 ) ([]fileGroup, error) {
@@ -327,6 +339,11 @@ func (cmd *branchSplitFilesCmd) parseNonInteractiveGroups(
 		if at.Branch != originalBranch && repo.BranchExists(ctx, at.Branch) {
 			return nil, fmt.Errorf("--at[%d]: branch %q already exists", i, at.Branch)
 		}
+		if at.Branch != originalBranch {
+			if err := namePolicy.Validate(at.Branch); err != nil {
+				return nil, fmt.Errorf("--at[%d]: %w", i, err)
+			}
+		}
 
 		groups = append(groups, fileGroup{
 			files:   groupFiles,
@@ -370,6 +387,7 @@ func (cmd *branchSplitFilesCmd) parseNonInteractiveGroups(
 func (cmd *branchSplitFilesCmd) selectFilesInteractively(
 	_ context.Context,
 	view ui.View,
+	namePolicy *spice.BranchNamePolicy,
 	originalBranch string,
 	allFiles []widget.FileEntry,
 ) ([]fileGroup, error) {
@@ -414,6 +432,9 @@ func (cmd *branchSplitFilesCmd) selectFilesInteractively(
 				if usedBranchNames[name] {
 					return fmt.Errorf("branch name %q already used in this split", name)
 				}
+				if name != originalBranch {
+					return namePolicy.Validate(name)
+				}
 				return nil
 			}).
 			WithOptions([]string{suggestedName})
@@ -15,6 +15,8 @@ type branchSquashCmd struct {
 	Branch string `released:"v0.16.0" help:"Branch to squash. Defaults to current branch." predictor:"trackedBranches" placeholder:"NAME"`
 }
 
+func (*branchSquashCmd) locksRepository() {}
+
 func (*branchSquashCmd) Help() string {
 	return text.Dedent(`
 		Squash all commits in the current branch into a single commit
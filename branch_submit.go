@@ -52,6 +52,9 @@ and skip those that would create new CRs.
 Use --nav-comment=false to disable navigation comments in CRs,
 or --nav-comment=multiple to post those comments
 only if there are multiple CRs in the stack.
+
+Use --offline to queue the submission without contacting the forge,
+and 'gs repo flush' to replay it later.
 `
 
 type branchSubmitCmd struct {
@@ -63,6 +66,8 @@ type branchSubmitCmd struct {
 	Base   string `short:"b" placeholder:"BRANCH" help:"Base branch for the PR (overrides tracked base)"`
 }
 
+func (*branchSubmitCmd) locksRepository() {}
+
 func (*branchSubmitCmd) Help() string {
 	return text.Dedent(`
 		A Change Request is created for the current branch,
@@ -93,6 +98,9 @@ func (*branchSubmitCmd) Help() string {
 		Use --nav-comment=false to disable navigation comments in CRs,
 		or --nav-comment=multiple to post those comments
 		only if there are multiple CRs in the stack.
+
+		Use --offline to queue the submission without contacting the forge,
+		and 'gs repo flush' to replay it later.
 	`)
 }
 
@@ -100,6 +108,7 @@ func (*branchSubmitCmd) Help() string {
 type SubmitHandler interface {
 	Submit(ctx context.Context, req *submit.Request) error
 	SubmitBatch(ctx context.Context, req *submit.BatchRequest) error
+	Flush(ctx context.Context, req *submit.FlushRequest) error
 }
 
 func (cmd *branchSubmitCmd) Run(
@@ -14,6 +14,8 @@ type branchTrackCmd struct {
 	Branch string `arg:"" optional:"" help:"Name of the branch to track" predictor:"branches"`
 }
 
+func (*branchTrackCmd) locksRepository() {}
+
 func (*branchTrackCmd) Help() string {
 	return text.Dedent(`
 		A branch must be tracked to be able to run gs operations on it.
@@ -15,6 +15,8 @@ type branchUntrackCmd struct {
 	Branch string `arg:"" optional:"" help:"Name of the branch to untrack. Defaults to current." predictor:"branches"`
 }
 
+func (*branchUntrackCmd) locksRepository() {}
+
 func (*branchUntrackCmd) Help() string {
 	return text.Dedent(`
 		The current branch is deleted from git-spice's data store
@@ -10,7 +10,10 @@ import (
 	"strings"
 
 	"go.abhg.dev/gs/internal/claude"
+	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/forge/forgecache"
 	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/secret"
 	"go.abhg.dev/gs/internal/silog"
 	"go.abhg.dev/gs/internal/spice"
 	"go.abhg.dev/gs/internal/spice/state"
@@ -19,11 +22,16 @@ import (
 )
 
 type claudeReviewCmd struct {
-	From      string `help:"Start of the range to review (defaults to trunk)"`
-	To        string `help:"End of the range to review (defaults to current branch)"`
-	PerBranch bool   `help:"Review each branch individually, then provide an overall summary"`
-	Title     string `help:"Title for the review (defaults to branch name or range)"`
-	Fix       bool   `help:"After review, prompt to apply suggested fixes"`
+	From       string `xor:"from-post" help:"Start of the range to review (defaults to trunk)"`
+	To         string `help:"End of the range to review (defaults to current branch)"`
+	PerBranch  bool   `help:"Review each branch individually, then provide an overall summary"`
+	Title      string `help:"Title for the review (defaults to branch name or range)"`
+	Fix        bool   `help:"After review, prompt to apply suggested fixes"`
+	Structured bool   `released:"unreleased" help:"Report findings with the file and lines they refer to"`
+	Post       bool   `xor:"from-post" released:"unreleased" help:"Post findings as a pending review on the branch's change. Implies --structured."`
+
+	SinceLastReview bool `released:"unreleased" help:"Review only changes made since the last review of each branch"`
+	NoCache         bool `released:"unreleased" help:"Don't reuse responses from earlier reviews of the same changes"`
 }
 
 func (*claudeReviewCmd) Help() string {
@@ -36,10 +44,34 @@ func (*claudeReviewCmd) Help() string {
 		The --per-branch flag reviews each branch in the stack individually,
 		then provides an overall summary of the entire stack.
 
+		The --structured flag asks for findings as structured data
+		and lists each finding with the file and lines it refers to.
+		Findings that don't refer to lines added by the diff
+		are listed separately.
+
+		The --post flag posts structured findings
+		as a pending review with inline comments
+		on the change submitted for the branch.
+		The branch is reviewed against its base,
+		and the review stays pending until you submit it on the forge.
+		With --per-branch, findings are posted to each branch's change.
+
+		Claude's responses are cached in the git-spice state,
+		so reviewing unchanged code again reuses the earlier review.
+		Use --no-cache to always request a new review.
+
+		The --since-last-review flag reviews only changes made to a branch
+		since it was last reviewed.
+		If the branch was restacked since then,
+		changes from its new base are left out.
+		This requires at least Git 2.40.
+
 		Example usage:
 		  gs claude review                      # Review current branch against trunk
 		  gs claude review --from main --to feature
 		  gs claude review --per-branch         # Review each branch in stack
+		  gs claude review --post               # Post findings to the current branch's change
+		  gs claude review --since-last-review  # Review only changes since the last review
 	`)
 }
 
@@ -51,12 +83,21 @@ func (cmd *claudeReviewCmd) Run(
 	wt *git.Worktree,
 	store *state.Store,
 	svc *spice.Service,
+	stash secret.Stash,
+	cache *forgecache.Cache,
+	forges *forge.Registry,
 ) error {
 	// Initialize Claude client.
-	client := claude.NewClient(nil)
-	if !client.IsAvailable() {
+	claudeClient := claude.NewClient(nil)
+	if !claudeClient.IsAvailable() {
 		return errors.New("claude CLI not found; please install it from https://claude.ai/download")
 	}
+	client := &claudeReviewClient{
+		Client:  claudeClient,
+		log:     log,
+		store:   store,
+		noCache: cmd.NoCache,
+	}
 
 	// Load configuration.
 	cfg, err := claude.LoadConfig(claude.DefaultConfigPath())
@@ -80,6 +121,25 @@ func (cmd *claudeReviewCmd) Run(
 		toRef = currentBranch
 	}
 
+	// Posted findings must refer to lines in the branch's change,
+	// so review only the branch's own changes.
+	var poster *claudeReviewPoster
+	if cmd.Post {
+		cmd.Structured = true
+		if !cmd.PerBranch {
+			branch, err := svc.LookupBranch(ctx, toRef)
+			if err != nil {
+				return fmt.Errorf("lookup branch %v: %w", toRef, err)
+			}
+			fromRef = branch.Base
+		}
+
+		poster, err = newClaudeReviewPoster(ctx, log, view, repo, store, stash, cache, forges)
+		if err != nil {
+			return err
+		}
+	}
+
 	title := cmd.Title
 	if title == "" {
 		if fromRef == store.Trunk() {
@@ -90,10 +150,10 @@ func (cmd *claudeReviewCmd) Run(
 	}
 
 	if cmd.PerBranch {
-		return cmd.runPerBranch(ctx, log, view, repo, svc, store, client, cfg, fromRef, toRef, title)
+		return cmd.runPerBranch(ctx, log, view, repo, svc, store, client, cfg, poster, fromRef, toRef, title)
 	}
 
-	return cmd.runOverall(ctx, log, view, repo, client, cfg, fromRef, toRef, title)
+	return cmd.runOverall(ctx, log, view, repo, store, client, cfg, poster, fromRef, toRef, title)
 }
 
 func (cmd *claudeReviewCmd) runOverall(
@@ -101,22 +161,34 @@ func (cmd *claudeReviewCmd) runOverall(
 	log *silog.Logger,
 	view ui.View,
 	repo *git.Repository,
-	client *claude.Client,
+	store *state.Store,
+	client *claudeReviewClient,
 	cfg *claude.Config,
+	poster *claudeReviewPoster,
 	fromRef, toRef, title string,
 ) error {
 	log.Infof("Reviewing changes: %s...%s", fromRef, toRef)
 
-	diffText, err := repo.DiffText(ctx, fromRef, toRef)
+	diff, err := cmd.reviewDiff(ctx, log, repo, store, fromRef, toRef)
 	if err != nil {
 		return fmt.Errorf("get diff: %w", err)
 	}
-	if diffText == "" {
+	if diff.Unchanged {
+		// Record the new commits so that later reviews
+		// compare against them.
+		client.RecordReview(ctx, toRef, diff.Span)
+		log.Infof("%v: No changes since last review", toRef)
+		return nil
+	}
+	if diff.Text == "" {
 		log.Info("No changes to review")
 		return nil
 	}
+	if diff.Incremental {
+		title += " (changes since last review)"
+	}
 
-	result, err := claude.ParseAndFilterDiff(diffText, cfg)
+	result, err := claude.ParseAndFilterDiff(diff.Text, cfg)
 	if err != nil {
 		return fmt.Errorf("parse diff: %w", err)
 	}
@@ -128,14 +200,37 @@ func (cmd *claudeReviewCmd) runOverall(
 		return cmd.handleOverBudget(view, result.Budget)
 	}
 
+	if cmd.Structured {
+		review, err := cmd.sendStructuredReview(ctx, view, client, cfg, title, result)
+		if err != nil {
+			return err
+		}
+		client.RecordReview(ctx, toRef, diff.Span)
+
+		fmt.Fprintln(view, "")
+		fmt.Fprintln(view, "=== Claude Review ===")
+		fmt.Fprintln(view, "")
+		fmt.Fprint(view, review.String())
+
+		if poster != nil {
+			if err := poster.Post(ctx, toRef, review); err != nil {
+				return err
+			}
+		}
+
+		if cmd.Fix && ui.Interactive(view) {
+			return cmd.offerFixes(ctx, view, client.Client, cfg, review.String(), result.FilteredDiff)
+		}
+		return nil
+	}
+
 	prompt := claude.BuildReviewPrompt(cfg, title, result.FilteredDiff)
 
-	fmt.Fprint(view, "Sending to Claude for review... ")
-	response, err := client.SendPromptWithModel(ctx, prompt, cfg.Models.Review)
-	fmt.Fprintln(view, "done")
+	response, err := client.Review(ctx, view, "Sending to Claude for review... ", prompt, cfg.Models.Review)
 	if err != nil {
 		return cmd.handleClaudeError(err)
 	}
+	client.RecordReview(ctx, toRef, diff.Span)
 
 	fmt.Fprintln(view, "")
 	fmt.Fprintln(view, "=== Claude Review ===")
@@ -143,7 +238,7 @@ func (cmd *claudeReviewCmd) runOverall(
 	fmt.Fprintln(view, response)
 
 	if cmd.Fix && ui.Interactive(view) {
-		return cmd.offerFixes(ctx, view, client, cfg, response, result.FilteredDiff)
+		return cmd.offerFixes(ctx, view, client.Client, cfg, response, result.FilteredDiff)
 	}
 
 	return nil
@@ -156,8 +251,9 @@ func (cmd *claudeReviewCmd) runPerBranch(
 	repo *git.Repository,
 	svc *spice.Service,
 	store *state.Store,
-	client *claude.Client,
+	client *claudeReviewClient,
 	cfg *claude.Config,
+	poster *claudeReviewPoster,
 	fromRef, toRef, title string,
 ) error {
 	graph, err := svc.BranchGraph(ctx, nil)
@@ -168,7 +264,10 @@ func (cmd *claudeReviewCmd) runPerBranch(
 	pathResult := collectBranchPath(graph, store.Trunk(), toRef)
 	if len(pathResult.Branches) == 0 {
 		log.Info("No tracked branches found in range")
-		return cmd.runOverall(ctx, log, view, repo, client, cfg, fromRef, toRef, title)
+		if poster != nil {
+			return fmt.Errorf("%v is not a tracked branch", toRef)
+		}
+		return cmd.runOverall(ctx, log, view, repo, store, client, cfg, poster, fromRef, toRef, title)
 	}
 	if pathResult.Incomplete {
 		log.Warn("Branch path incomplete; branch not found in graph",
@@ -181,7 +280,7 @@ func (cmd *claudeReviewCmd) runPerBranch(
 	var reviews []string
 	for _, branch := range pathResult.Branches {
 		result, err := cmd.reviewSingleBranch(
-			ctx, log, view, repo, graph, store, client, cfg, branch,
+			ctx, log, view, repo, graph, store, client, cfg, poster, branch,
 		)
 		if err != nil {
 			return err
@@ -220,8 +319,9 @@ func (cmd *claudeReviewCmd) reviewSingleBranch(
 	repo *git.Repository,
 	graph *spice.BranchGraph,
 	store *state.Store,
-	client *claude.Client,
+	client *claudeReviewClient,
 	cfg *claude.Config,
+	poster *claudeReviewPoster,
 	branch string,
 ) (branchReviewResult, error) {
 	info, ok := graph.Lookup(branch)
@@ -236,16 +336,25 @@ func (cmd *claudeReviewCmd) reviewSingleBranch(
 	}
 	log.Infof("Reviewing branch: %s (base: %s)", branch, base)
 
-	diffText, err := repo.DiffText(ctx, base, branch)
+	diff, err := cmd.reviewDiff(ctx, log, repo, store, base, branch)
 	if err != nil {
 		return branchReviewResult{}, fmt.Errorf("get diff for %s: %w", branch, err)
 	}
-	if diffText == "" {
+	if diff.Unchanged {
+		client.RecordReview(ctx, branch, diff.Span)
+		log.Infof("Branch %s has no changes since last review", branch)
+		return branchReviewResult{Reviewed: false}, nil
+	}
+	if diff.Text == "" {
 		log.Infof("Branch %s has no changes", branch)
 		return branchReviewResult{Reviewed: false}, nil
 	}
+	title := branch
+	if diff.Incremental {
+		title += " (changes since last review)"
+	}
 
-	result, err := claude.ParseAndFilterDiff(diffText, cfg)
+	result, err := claude.ParseAndFilterDiff(diff.Text, cfg)
 	if err != nil {
 		return branchReviewResult{}, fmt.Errorf("parse diff for %s: %w", branch, err)
 	}
@@ -260,14 +369,37 @@ func (cmd *claudeReviewCmd) reviewSingleBranch(
 		)
 	}
 
-	prompt := claude.BuildReviewPrompt(cfg, branch, result.FilteredDiff)
+	if cmd.Structured {
+		review, err := cmd.sendStructuredReview(ctx, view, client, cfg, title, result)
+		if err != nil {
+			return branchReviewResult{}, err
+		}
+		client.RecordReview(ctx, branch, diff.Span)
+
+		fmt.Fprintln(view, "")
+		fmt.Fprintf(view, "=== Review: %s ===\n", branch)
+		fmt.Fprintln(view, "")
+		fmt.Fprint(view, review.String())
+
+		if poster != nil {
+			if err := poster.Post(ctx, branch, review); err != nil {
+				return branchReviewResult{}, err
+			}
+		}
+
+		return branchReviewResult{
+			Content:  fmt.Sprintf("## Branch: %s\n\n%s", branch, review.String()),
+			Reviewed: true,
+		}, nil
+	}
+
+	prompt := claude.BuildReviewPrompt(cfg, title, result.FilteredDiff)
 
-	fmt.Fprint(view, "Reviewing... ")
-	response, err := client.SendPromptWithModel(ctx, prompt, cfg.Models.Review)
-	fmt.Fprintln(view, "done")
+	response, err := client.Review(ctx, view, "Reviewing... ", prompt, cfg.Models.Review)
 	if err != nil {
 		return branchReviewResult{}, cmd.handleClaudeError(err)
 	}
+	client.RecordReview(ctx, branch, diff.Span)
 
 	fmt.Fprintln(view, "")
 	fmt.Fprintf(view, "=== Review: %s ===\n", branch)
@@ -284,12 +416,10 @@ func (cmd *claudeReviewCmd) reviewSingleBranch(
 func (cmd *claudeReviewCmd) generateStackSummary(
 	ctx context.Context,
 	view ui.View,
-	client *claude.Client,
+	client *claudeReviewClient,
 	cfg *claude.Config,
 	reviews []string,
 ) error {
-	fmt.Fprint(view, "Generating stack summary... ")
-
 	// Build stack summary with separator.
 	var summary strings.Builder
 	for i, review := range reviews {
@@ -300,8 +430,7 @@ func (cmd *claudeReviewCmd) generateStackSummary(
 	}
 
 	prompt := claude.BuildStackReviewPrompt(cfg, summary.String())
-	response, err := client.SendPromptWithModel(ctx, prompt, cfg.Models.Review)
-	fmt.Fprintln(view, "done")
+	response, err := client.Review(ctx, view, "Generating stack summary... ", prompt, cfg.Models.Review)
 	if err != nil {
 		return cmd.handleClaudeError(err)
 	}
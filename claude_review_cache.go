@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"go.abhg.dev/gs/internal/claude"
+	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/silog"
+	"go.abhg.dev/gs/internal/spice/state"
+	"go.abhg.dev/gs/internal/ui"
+)
+
+// claudeReviewClient sends review prompts to Claude,
+// reusing responses to identical prompts from earlier reviews.
+//
+// The prompt includes the filtered diff,
+// so a cached response is only reused if the reviewed code is unchanged.
+type claudeReviewClient struct {
+	*claude.Client
+
+	log     *silog.Logger
+	store   *state.Store
+	noCache bool // don't read cached responses
+}
+
+// Review sends a review prompt to Claude with the given model,
+// or returns the cached response to the same prompt and model.
+// status is printed to the view before waiting for a response.
+//
+// Failures to read or write the cache are logged and otherwise ignored.
+func (c *claudeReviewClient) Review(
+	ctx context.Context,
+	view ui.View,
+	status, prompt, model string,
+) (string, error) {
+	key := claude.PromptKey(prompt, model)
+	if !c.noCache {
+		response, ok, err := c.store.LoadCachedReview(ctx, key)
+		if err != nil {
+			c.log.Warn("Could not read cached review", "error", err)
+		} else if ok {
+			fmt.Fprintln(view, status+"cached")
+			return response, nil
+		}
+	}
+
+	fmt.Fprint(view, status)
+	response, err := c.SendPromptWithModel(ctx, prompt, model)
+	fmt.Fprintln(view, "done")
+	if err != nil {
+		return "", err
+	}
+
+	if err := c.store.SaveCachedReview(ctx, key, response); err != nil {
+		c.log.Warn("Could not cache review", "error", err)
+	}
+	return response, nil
+}
+
+// RecordReview records that the given commits of a branch were reviewed
+// for use with --since-last-review.
+func (c *claudeReviewClient) RecordReview(ctx context.Context, branch string, span git.CommitSpan) {
+	if err := c.store.SaveLastReview(ctx, branch, span); err != nil {
+		c.log.Warn("Could not record review", "branch", branch, "error", err)
+	}
+}
+
+// claudeReviewDiff is the diff to review for a range of commits.
+type claudeReviewDiff struct {
+	// Text is the diff to review.
+	Text string
+
+	// Span is the range of commits being reviewed.
+	// Base is the merge base of the range.
+	Span git.CommitSpan
+
+	// Incremental reports whether Text holds only changes
+	// made since the last review.
+	Incremental bool
+
+	// Unchanged reports whether the commits were already reviewed
+	// and there's nothing new to review.
+	Unchanged bool
+}
+
+// reviewDiff computes the diff to review between from and to.
+//
+// With --since-last-review, if to was reviewed before,
+// only changes made since that review are included.
+// Changes brought in by a new base are left out of that diff.
+func (cmd *claudeReviewCmd) reviewDiff(
+	ctx context.Context,
+	log *silog.Logger,
+	repo *git.Repository,
+	store *state.Store,
+	from, to string,
+) (*claudeReviewDiff, error) {
+	base, err := repo.MergeBase(ctx, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("find merge base: %w", err)
+	}
+	head, err := repo.PeelToCommit(ctx, to)
+	if err != nil {
+		return nil, fmt.Errorf("resolve %v: %w", to, err)
+	}
+	span := git.CommitSpan{Base: base, Head: head}
+
+	if cmd.SinceLastReview {
+		last, err := store.LoadLastReview(ctx, to)
+		if err != nil {
+			return nil, fmt.Errorf("load last review: %w", err)
+		}
+
+		switch {
+		case last == nil:
+			log.Infof("%v: No earlier review found. Reviewing all changes.", to)
+		case *last == span:
+			return &claudeReviewDiff{Span: span, Unchanged: true}, nil
+		default:
+			text, ok := interdiff(ctx, log, repo, *last, span)
+			if ok {
+				return &claudeReviewDiff{
+					Text:        text,
+					Span:        span,
+					Incremental: true,
+					Unchanged:   text == "",
+				}, nil
+			}
+			log.Warnf("%v: Reviewing all changes instead.", to)
+		}
+	}
+
+	text, err := repo.DiffText(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return &claudeReviewDiff{Text: text, Span: span}, nil
+}
+
+// interdiff returns the changes made between two versions of a branch,
+// leaving out changes made to its base.
+//
+// If the base changed, the old version of the branch is first rebased
+// onto the new base in-memory, and the result is compared with the new version.
+// It reports false if this isn't possible.
+func interdiff(
+	ctx context.Context,
+	log *silog.Logger,
+	repo *git.Repository,
+	prev, next git.CommitSpan,
+) (string, bool) {
+	if _, err := repo.PeelToCommit(ctx, prev.Head.String()); err != nil {
+		log.Warnf("Previously reviewed commit %v is no longer available.", prev.Head.Short())
+		return "", false
+	}
+
+	oldHead := prev.Head.String()
+	if prev.Base != next.Base {
+		tree, err := repo.MergeTree(ctx, git.MergeTreeRequest{
+			MergeBase: prev.Base.String(),
+			Branch1:   next.Base.String(),
+			Branch2:   prev.Head.String(),
+		})
+		if err != nil {
+			log.Warnf("Could not rebase previously reviewed changes onto the new base: %v", err)
+			return "", false
+		}
+		oldHead = tree.String()
+	}
+
+	text, err := repo.DiffTextDirect(ctx, oldHead, next.Head.String())
+	if err != nil {
+		log.Warnf("Could not diff against previously reviewed changes: %v", err)
+		return "", false
+	}
+	return text, true
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.abhg.dev/gs/internal/claude"
+	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/forge/forgecache"
+	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/secret"
+	"go.abhg.dev/gs/internal/silog"
+	"go.abhg.dev/gs/internal/spice/state"
+	"go.abhg.dev/gs/internal/ui"
+)
+
+// structuredReview is the result of a structured review.
+type structuredReview struct {
+	// Findings refer to lines added by the reviewed diff.
+	Findings []*claude.Finding
+
+	// Rejected are findings that don't refer to added lines.
+	Rejected []*claude.RejectedFinding
+}
+
+// String formats the findings for display,
+// one finding per paragraph.
+func (r *structuredReview) String() string {
+	var sb strings.Builder
+	if len(r.Findings) == 0 {
+		sb.WriteString("No problems found.\n")
+	}
+	for _, f := range r.Findings {
+		writeFinding(&sb, f, "")
+	}
+
+	if len(r.Rejected) > 0 {
+		sb.WriteString("\nFindings outside the diff:\n")
+		for _, rf := range r.Rejected {
+			writeFinding(&sb, rf.Finding, rf.Reason)
+		}
+	}
+	return sb.String()
+}
+
+// writeFinding writes a finding as "file:lines: severity: message"
+// with following lines of the message indented.
+func writeFinding(sb *strings.Builder, f *claude.Finding, note string) {
+	msg := strings.TrimSpace(f.Message)
+	if note != "" {
+		msg += " (" + note + ")"
+	}
+
+	fmt.Fprintf(sb, "%s: %s: ", f.Location(), f.Severity)
+	for i, line := range strings.Split(msg, "\n") {
+		if i > 0 {
+			sb.WriteString("    ")
+		}
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+}
+
+// sendStructuredReview asks Claude to review a diff
+// and validates the findings against it.
+func (cmd *claudeReviewCmd) sendStructuredReview(
+	ctx context.Context,
+	view ui.View,
+	client *claudeReviewClient,
+	cfg *claude.Config,
+	title string,
+	diff *claude.FilteredDiffResult,
+) (*structuredReview, error) {
+	// Number lines so that findings refer to exact lines.
+	prompt := claude.BuildStructuredReviewPrompt(cfg, title, claude.NumberDiffLines(diff.Files))
+
+	response, err := client.Review(ctx, view, "Sending to Claude for review... ", prompt, cfg.Models.Review)
+	if err != nil {
+		return nil, cmd.handleClaudeError(err)
+	}
+
+	findings, err := claude.ParseFindings(response)
+	if err != nil {
+		return nil, fmt.Errorf("parse review findings: %w", err)
+	}
+
+	valid, rejected := claude.ValidateFindings(findings, diff.Files)
+	return &structuredReview{
+		Findings: valid,
+		Rejected: rejected,
+	}, nil
+}
+
+// claudeReviewPoster posts structured review findings
+// as pending reviews on the changes of reviewed branches.
+type claudeReviewPoster struct {
+	log       *silog.Logger
+	repo      *git.Repository
+	forgeRepo forge.Repository
+	poster    forge.ReviewPoster
+}
+
+func newClaudeReviewPoster(
+	ctx context.Context,
+	log *silog.Logger,
+	view ui.View,
+	repo *git.Repository,
+	store *state.Store,
+	stash secret.Stash,
+	cache *forgecache.Cache,
+	forges *forge.Registry,
+) (*claudeReviewPoster, error) {
+	remote, err := ensureRemote(ctx, repo, store, log, view)
+	if err != nil {
+		return nil, fmt.Errorf("get remote: %w", err)
+	}
+	forgeRepo, err := openRemoteRepository(ctx, log, stash, cache, forges, repo, remote)
+	if err != nil {
+		return nil, fmt.Errorf("open remote repository: %w", err)
+	}
+
+	poster, ok := forgeRepo.(forge.ReviewPoster)
+	if !ok {
+		return nil, fmt.Errorf("forge %q does not support posting reviews", forgeRepo.Forge().ID())
+	}
+
+	return &claudeReviewPoster{
+		log:       log,
+		repo:      repo,
+		forgeRepo: forgeRepo,
+		poster:    poster,
+	}, nil
+}
+
+// Post posts the findings of a review of a branch
+// as a pending review on the branch's change.
+// Findings outside the diff are listed in the review's summary.
+func (p *claudeReviewPoster) Post(ctx context.Context, branch string, review *structuredReview) error {
+	if len(review.Findings) == 0 && len(review.Rejected) == 0 {
+		p.log.Infof("%v: No findings to post", branch)
+		return nil
+	}
+
+	changes, err := p.forgeRepo.FindChangesByBranch(ctx, branch, forge.FindChangesOptions{Limit: 1})
+	if err != nil {
+		return fmt.Errorf("find changes for branch %q: %w", branch, err)
+	}
+	if len(changes) == 0 {
+		return fmt.Errorf("no open change found for branch %q", branch)
+	}
+	change := changes[0]
+
+	// Comments are placed by line number,
+	// so they only line up if the change is up to date.
+	if head, err := p.repo.PeelToCommit(ctx, branch); err == nil && head != change.HeadHash {
+		p.log.Warnf("%v: Local branch does not match %v. Comments may be placed on the wrong lines.", branch, change.ID)
+		p.log.Warnf("Submit the branch before posting to avoid this.")
+	}
+
+	req := forge.PostReviewRequest{
+		Comments: make([]*forge.ReviewComment, len(review.Findings)),
+	}
+	for i, f := range review.Findings {
+		req.Comments[i] = &forge.ReviewComment{
+			Path:      f.File,
+			StartLine: f.StartLine,
+			EndLine:   f.EndLine,
+			Body:      fmt.Sprintf("**%s**: %s", f.Severity, strings.TrimSpace(f.Message)),
+		}
+	}
+	if len(review.Rejected) > 0 {
+		var body strings.Builder
+		body.WriteString("Findings outside the diff:\n\n")
+		for _, rf := range review.Rejected {
+			f := rf.Finding
+			fmt.Fprintf(&body, "- `%s` **%s**: %s\n", f.Location(), f.Severity, strings.TrimSpace(f.Message))
+		}
+		req.Body = body.String()
+	}
+
+	if err := p.poster.PostPendingReview(ctx, change.ID, &req); err != nil {
+		return fmt.Errorf("post review on %v: %w", change.ID, err)
+	}
+
+	p.log.Infof("%v: Posted pending review on %v", branch, change.URL)
+	p.log.Infof("Submit the review from the change's page to publish it.")
+	return nil
+}
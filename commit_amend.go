@@ -7,6 +7,7 @@ import (
 
 	"go.abhg.dev/gs/internal/git"
 	"go.abhg.dev/gs/internal/handler/restack"
+	"go.abhg.dev/gs/internal/hook"
 	"go.abhg.dev/gs/internal/silog"
 	"go.abhg.dev/gs/internal/spice"
 	"go.abhg.dev/gs/internal/spice/state"
@@ -27,6 +28,8 @@ type commitAmendCmd struct {
 	Signoff  bool `config:"commit.signoff" help:"Add Signed-off-by trailer to the commit message"`
 }
 
+func (*commitAmendCmd) locksRepository() {}
+
 func (*commitAmendCmd) Help() string {
 	return text.Dedent(`
 		Staged changes are amended into the topmost commit.
@@ -60,6 +63,7 @@ func (cmd *commitAmendCmd) Run(
 	store *state.Store,
 	svc *spice.Service,
 	restackHandler RestackHandler,
+	hooks *hook.Runner,
 ) error {
 	var detachedHead bool
 	currentBranch, err := wt.CurrentBranch(ctx)
@@ -126,7 +130,7 @@ func (cmd *commitAmendCmd) Run(
 					MessageFile:        cmd.MessageFile,
 					Signoff:            cmd.Signoff,
 					Commit:             true,
-				}).Run(ctx, log, repo, wt, store, svc, restackHandler)
+				}).Run(ctx, log, repo, wt, store, svc, restackHandler, hooks)
 			}
 		}
 	}
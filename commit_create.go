@@ -24,6 +24,8 @@ type commitCreateCmd struct {
 	Signoff       bool   `config:"commit.signoff" help:"Add Signed-off-by trailer to the commit message"`
 }
 
+func (*commitCreateCmd) locksRepository() {}
+
 func (*commitCreateCmd) Help() string {
 	return text.Dedent(`
 		Staged changes are committed to the current branch.
@@ -27,6 +27,8 @@ type commitFixupCmd struct {
 	Commit string `arg:"" optional:"" help:"The commit to fixup. Must be reachable from the HEAD commit."`
 }
 
+func (*commitFixupCmd) locksRepository() {}
+
 func (cmd *commitFixupCmd) Help() string {
 	return text.Dedent(`
 		Apply staged uncommited changes to another commit
@@ -24,6 +24,8 @@ type commitPickCmd struct {
 	From   string `placeholder:"NAME" predictor:"trackedBranches" help:"Branch whose upstack commits will be considered."`
 }
 
+func (*commitPickCmd) locksRepository() {}
+
 func (*commitPickCmd) Help() string {
 	return text.Dedent(`
 		Apply the changes introduced by a commit to the current branch
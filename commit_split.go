@@ -20,6 +20,8 @@ type commitSplitCmd struct {
 	Commit      string `arg:"" optional:"" help:"Commit to split (default: HEAD)."`
 }
 
+func (*commitSplitCmd) locksRepository() {}
+
 func (*commitSplitCmd) Help() string {
 	return text.Dedent(`
 		Interactively select hunks from a commit
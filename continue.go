@@ -17,6 +17,8 @@ type continueCmd struct {
 	Edit bool `default:"true" negatable:"" config:"continue.edit" help:"Whether to open an editor to edit the commit message."`
 }
 
+func (*continueCmd) locksRepository() {}
+
 func (*continueCmd) Help() string {
 	return text.Dedent(`
 		Continues an ongoing git-spice operation interrupted by
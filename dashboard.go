@@ -0,0 +1,331 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/alecthomas/kong"
+	"go.abhg.dev/gs/internal/dashboard"
+	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/forge/forgecache"
+	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/handler/list"
+	"go.abhg.dev/gs/internal/secret"
+	"go.abhg.dev/gs/internal/silog"
+	"go.abhg.dev/gs/internal/spice"
+	"go.abhg.dev/gs/internal/spice/state"
+	"go.abhg.dev/gs/internal/text"
+	"go.abhg.dev/gs/internal/ui"
+)
+
+type dashboardCmd struct {
+	Addr    string        `default:"127.0.0.1:0" placeholder:"HOST:PORT" help:"Address to listen on. Must be a loopback address. Uses a random port by default."`
+	Refresh time.Duration `default:"1m" config:"dashboard.refresh" help:"How often the page reloads itself. Set to 0 to disable."`
+	Open    bool          `help:"Open the dashboard in a web browser"`
+
+	// output receives messages written to the view by actions.
+	output bytes.Buffer
+}
+
+var _ viewBuilder = (*dashboardCmd)(nil)
+
+func (*dashboardCmd) Help() string {
+	return text.Dedent(`
+		Serves a web page on localhost showing all tracked stacks
+		with the state, review decision, failing CI checks,
+		and unresolved review threads of their Change Requests.
+		The page reloads itself periodically.
+
+		Branches may be restacked and submitted from the page.
+		These run the same operations as 'gs upstack restack'
+		and 'gs branch submit --fill'.
+
+		The dashboard runs until interrupted with Ctrl-C.
+		Use --open to open it in a web browser.
+	`)
+}
+
+func (cmd *dashboardCmd) buildView(context.Context, *silog.Logger) (ui.View, error) {
+	// Actions can't prompt for input,
+	// and messages they report are shown on the page.
+	return &ui.FileView{W: io.MultiWriter(os.Stderr, &cmd.output)}, nil
+}
+
+func (cmd *dashboardCmd) Run(
+	ctx context.Context,
+	kctx *kong.Context,
+	log *silog.Logger,
+	config *spice.Config,
+	repo *git.Repository,
+) error {
+	host, _, err := net.SplitHostPort(cmd.Addr)
+	if err != nil {
+		return fmt.Errorf("bad address %q: %w", cmd.Addr, err)
+	}
+	if !dashboard.IsLoopback(host) {
+		return fmt.Errorf("address %q is not a loopback address: the dashboard must only be served locally", cmd.Addr)
+	}
+
+	// Stop long-lived Git processes when the server shuts down.
+	defer func() {
+		if err := repo.Close(); err != nil {
+			log.Warn("Could not close repository", "error", err)
+		}
+	}()
+
+	// Reuse forge clients between page loads.
+	_openedRepositories = new(openedRepositories)
+
+	srv := &server{
+		kctx:   kctx,
+		log:    log,
+		config: config,
+		repo:   repo,
+	}
+	kctx.Stdout = &srv.stdout
+	if err := new(logCmd).AfterApply(kctx); err != nil {
+		return fmt.Errorf("bind log handlers: %w", err)
+	}
+
+	handler := &dashboard.Handler{
+		Log:     log,
+		Backend: &dashboardBackend{srv: srv, log: log, output: &cmd.output},
+		Token:   rand.Text(),
+		Refresh: cmd.Refresh,
+	}
+
+	ln, err := net.Listen("tcp", cmd.Addr)
+	if err != nil {
+		return fmt.Errorf("listen: %w", err)
+	}
+
+	httpServer := &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	wg.Go(func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+		defer cancel()
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			log.Warn("Could not stop dashboard cleanly", "error", err)
+		}
+	})
+
+	url := "http://" + ln.Addr().String() + "/"
+	log.Infof("Serving dashboard at %v", url)
+	log.Info("Press Ctrl-C to stop.")
+	if cmd.Open {
+		if err := _browserLauncher.OpenURL(url); err != nil {
+			log.Warn("Could not open browser", "error", err)
+		}
+	}
+
+	if err := httpServer.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("serve dashboard: %w", err)
+	}
+	return nil
+}
+
+// dashboardBackend loads the dashboard and runs its actions
+// with the same commands used on the command line.
+type dashboardBackend struct {
+	srv    *server       // required
+	log    *silog.Logger // required
+	output *bytes.Buffer // required
+}
+
+var _ dashboard.Backend = (*dashboardBackend)(nil)
+
+func (b *dashboardBackend) Restack(ctx context.Context, branch string) (string, error) {
+	cmd := &upstackRestackCmd{}
+	return b.run(ctx, "restack", cmd, func() {
+		cmd.Branch = branch
+	})
+}
+
+func (b *dashboardBackend) Submit(ctx context.Context, branch string) (string, error) {
+	cmd := &branchSubmitCmd{}
+	return b.run(ctx, "submit", cmd, func() {
+		cmd.Branch = branch
+		cmd.Fill = true // there's no one to prompt
+	})
+}
+
+func (b *dashboardBackend) run(ctx context.Context, method string, cmd any, override func()) (string, error) {
+	b.output.Reset()
+	_, err := b.srv.capture(ctx, method, cmd, nil, override)
+	return b.output.String(), err
+}
+
+func (b *dashboardBackend) Load(ctx context.Context) (*dashboard.Page, error) {
+	var page *dashboard.Page
+	err := b.srv.call(func(
+		wt *git.Worktree,
+		repo *git.Repository,
+		store *state.Store,
+		stash secret.Stash,
+		cache *forgecache.Cache,
+		forges *forge.Registry,
+		listHandler ListHandler,
+	) (*dashboard.Page, error) {
+		return b.load(ctx, wt, repo, store, stash, cache, forges, listHandler)
+	}, &page)
+	return page, err
+}
+
+func (b *dashboardBackend) load(
+	ctx context.Context,
+	wt *git.Worktree,
+	repo *git.Repository,
+	store *state.Store,
+	stash secret.Stash,
+	cache *forgecache.Cache,
+	forges *forge.Registry,
+	listHandler ListHandler,
+) (*dashboard.Page, error) {
+	currentBranch, err := wt.CurrentBranch(ctx)
+	if err != nil {
+		currentBranch = "" // may be detached
+	}
+
+	res, err := listHandler.ListBranches(ctx, &list.BranchesRequest{
+		Branch:  currentBranch,
+		Options: &list.Options{All: true},
+		Include: list.IncludeChangeURL | list.IncludeChangeDetails | list.IncludePushStatus,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	page := &dashboard.Page{
+		Trunk:     store.Trunk(),
+		UpdatedAt: time.Now(),
+	}
+
+	// changeIDs[i] is the ID of changes[i].
+	var (
+		changes   []*dashboard.Change
+		changeIDs []forge.ChangeID
+	)
+	branches := make([]*dashboard.Branch, len(res.Branches))
+	for idx, item := range res.Branches {
+		branch := &dashboard.Branch{
+			Name:         item.Name,
+			Current:      item.Name == currentBranch,
+			Worktree:     item.Worktree,
+			NeedsRestack: item.NeedsRestack,
+		}
+		if item.PushStatus != nil {
+			branch.NeedsPush = item.PushStatus.NeedsPush
+		}
+		if item.ChangeID != nil {
+			branch.Change = &dashboard.Change{
+				ID:             item.ChangeID.String(),
+				URL:            item.ChangeURL,
+				State:          item.ChangeState,
+				Draft:          item.ChangeDraft,
+				ReviewDecision: item.ChangeReviewDecision,
+			}
+
+			// Checks and reviews don't matter for finished changes.
+			if item.ChangeState == forge.ChangeOpen {
+				changes = append(changes, branch.Change)
+				changeIDs = append(changeIDs, item.ChangeID)
+			}
+		}
+		branches[idx] = branch
+	}
+	for idx, item := range res.Branches {
+		for _, above := range item.Aboves {
+			branches[idx].Aboves = append(branches[idx].Aboves, branches[above])
+		}
+	}
+	page.Stacks = branches[res.TrunkIdx].Aboves
+
+	if len(changes) == 0 {
+		return page, nil
+	}
+
+	remote, err := store.Remote()
+	if err != nil {
+		page.Warnings = append(page.Warnings, fmt.Sprintf("Could not load checks and reviews: %v", err))
+		return page, nil
+	}
+	forgeRepo, err := openRemoteRepositorySilent(ctx, b.log, stash, cache, forges, repo, remote)
+	if err != nil {
+		page.Warnings = append(page.Warnings, fmt.Sprintf("Could not load checks and reviews: %v", err))
+		return page, nil
+	}
+
+	var (
+		wg         sync.WaitGroup
+		warningsMu sync.Mutex
+	)
+	warn := func(format string, args ...any) {
+		warningsMu.Lock()
+		defer warningsMu.Unlock()
+		page.Warnings = append(page.Warnings, fmt.Sprintf(format, args...))
+	}
+
+	checker, _ := forgeRepo.(forge.ChangeChecksLister)
+	threader, _ := forgeRepo.(forge.ReviewThreadLister)
+	for idx, change := range changes {
+		id := changeIDs[idx]
+
+		if checker != nil {
+			wg.Go(func() {
+				for item, err := range checker.ListChangeChecks(ctx, id, &forge.ListChangeChecksOptions{
+					OnlyFailing: true,
+				}) {
+					if err != nil {
+						warn("Could not list checks for %v: %v", change.ID, err)
+						return
+					}
+					change.FailingChecks = append(change.FailingChecks, &dashboard.Check{
+						Name:       item.Name,
+						Conclusion: item.Conclusion,
+						URL:        item.URL,
+					})
+				}
+			})
+		}
+
+		if threader != nil {
+			wg.Go(func() {
+				for item, err := range threader.ListReviewThreads(ctx, id, &forge.ListReviewThreadsOptions{}) {
+					if err != nil {
+						warn("Could not list review threads for %v: %v", change.ID, err)
+						return
+					}
+					change.OpenThreads = append(change.OpenThreads, &dashboard.Thread{
+						Author: item.Author,
+						File:   item.File,
+						Line:   item.LineRange[0],
+						Body:   item.Body,
+						URL:    item.URL,
+					})
+				}
+			})
+		}
+	}
+	wg.Wait()
+	slices.Sort(page.Warnings)
+
+	return page, nil
+}
@@ -21,6 +21,8 @@ type downstackEditCmd struct {
 	Branch string `placeholder:"NAME" help:"Branch to edit from. Defaults to current branch." predictor:"trackedBranches"`
 }
 
+func (*downstackEditCmd) locksRepository() {}
+
 func (*downstackEditCmd) Help() string {
 	return text.Dedent(`
 		An editor opens with a list of branches in-order,
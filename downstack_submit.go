@@ -21,6 +21,8 @@ type downstackSubmitCmd struct {
 	Branch string `placeholder:"NAME" help:"Branch to start at" predictor:"trackedBranches"`
 }
 
+func (*downstackSubmitCmd) locksRepository() {}
+
 func (*downstackSubmitCmd) Help() string {
 	return text.Dedent(`
 		Change Requests are created or updated
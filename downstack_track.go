@@ -13,6 +13,8 @@ type downstackTrackCmd struct {
 	Branch string `arg:"" optional:"" help:"Name of the branch to start tracking from" predictor:"branches"`
 }
 
+func (*downstackTrackCmd) locksRepository() {}
+
 func (*downstackTrackCmd) Help() string {
 	return text.Dedent(`
 		Track all untracked branches in the downstack of a branch.
@@ -0,0 +1,203 @@
+// Package changemeta declares the desired metadata of a change
+// (labels, reviewers, assignees, milestone, and projects)
+// and reconciles it against the change's current state.
+//
+// Desired metadata may be read from a fenced code block
+// with the info string "git-spice" in a commit message:
+//
+//	```git-spice
+//	labels: [bug, ui]
+//	reviewers: [alice, acme/backend]
+//	milestone: v1.0
+//	```
+//
+// Fields that are omitted are left unchanged.
+// Fields that are set to an empty value are cleared.
+package changemeta
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"go.abhg.dev/gs/internal/forge"
+	"gopkg.in/yaml.v3"
+)
+
+// InfoString is the info string of fenced code blocks
+// in commit messages that hold change metadata.
+const InfoString = "git-spice"
+
+// Spec is the desired metadata for a change.
+//
+// A nil field leaves that metadata unchanged.
+// A non-nil empty field removes all values for that metadata.
+type Spec struct {
+	Labels    *[]string `yaml:"labels"`
+	Reviewers *[]string `yaml:"reviewers"`
+	Assignees *[]string `yaml:"assignees"`
+	Projects  *[]string `yaml:"projects"`
+
+	// Milestone is the title of the desired milestone.
+	// An empty string removes the milestone.
+	Milestone *string `yaml:"milestone"`
+}
+
+// IsZero reports whether the spec leaves all metadata unchanged.
+func (s *Spec) IsZero() bool {
+	return s.Labels == nil &&
+		s.Reviewers == nil &&
+		s.Assignees == nil &&
+		s.Projects == nil &&
+		s.Milestone == nil
+}
+
+// Override replaces fields of s with the fields that are set in o.
+func (s *Spec) Override(o *Spec) {
+	if o.Labels != nil {
+		s.Labels = o.Labels
+	}
+	if o.Reviewers != nil {
+		s.Reviewers = o.Reviewers
+	}
+	if o.Assignees != nil {
+		s.Assignees = o.Assignees
+	}
+	if o.Projects != nil {
+		s.Projects = o.Projects
+	}
+	if o.Milestone != nil {
+		s.Milestone = o.Milestone
+	}
+}
+
+// ParseMessage reads the spec from the "git-spice" fenced code block
+// in a commit message.
+//
+// It returns nil if the message doesn't have such a block.
+// If there are multiple blocks, only the first one is used.
+func ParseMessage(msg string) (*Spec, error) {
+	block, ok := findBlock(msg)
+	if !ok {
+		return nil, nil
+	}
+
+	spec := new(Spec)
+	dec := yaml.NewDecoder(strings.NewReader(block))
+	dec.KnownFields(true)
+	if err := dec.Decode(spec); err != nil {
+		if errors.Is(err, io.EOF) {
+			return spec, nil // empty block
+		}
+		return nil, fmt.Errorf("parse %v block: %w", InfoString, err)
+	}
+	return spec, nil
+}
+
+// findBlock returns the contents of the first fenced code block
+// with the info string [InfoString] in msg.
+func findBlock(msg string) (string, bool) {
+	var (
+		buf     bytes.Buffer
+		fence   string // opening fence if inside the block
+		scanner = bufio.NewScanner(strings.NewReader(msg))
+	)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		if fence == "" {
+			if f := fencePrefix(trimmed); f != "" &&
+				strings.TrimSpace(trimmed[len(f):]) == InfoString {
+				fence = f
+			}
+			continue
+		}
+
+		// The closing fence must be at least as long as the opening fence.
+		if f := fencePrefix(trimmed); f == trimmed && strings.HasPrefix(f, fence) {
+			return buf.String(), true
+		}
+		buf.WriteString(line)
+		buf.WriteByte('\n')
+	}
+
+	// An unterminated block extends to the end of the message.
+	return buf.String(), fence != ""
+}
+
+// fencePrefix returns the code fence at the start of line:
+// three or more backticks or tildes.
+// It returns an empty string if line doesn't start with a fence.
+func fencePrefix(line string) string {
+	if line == "" || (line[0] != '`' && line[0] != '~') {
+		return ""
+	}
+	n := len(line) - len(strings.TrimLeft(line, line[:1]))
+	if n < 3 {
+		return ""
+	}
+	return line[:n]
+}
+
+// Current is the current metadata of a change.
+type Current struct {
+	Labels    []string
+	Reviewers []string
+	Assignees []string
+	Projects  []string
+	Milestone string
+}
+
+// Reconcile returns the edits needed to bring a change
+// from its current metadata to the metadata described by the spec.
+//
+// Users and teams are compared case-insensitively.
+func Reconcile(spec *Spec, cur *Current) forge.EditChangeOptions {
+	var opts forge.EditChangeOptions
+	if spec.Labels != nil {
+		opts.AddLabels, opts.RemoveLabels = diff(cur.Labels, *spec.Labels, equal)
+	}
+	if spec.Reviewers != nil {
+		opts.AddReviewers, opts.RemoveReviewers = diff(cur.Reviewers, *spec.Reviewers, strings.EqualFold)
+	}
+	if spec.Assignees != nil {
+		opts.AddAssignees, opts.RemoveAssignees = diff(cur.Assignees, *spec.Assignees, strings.EqualFold)
+	}
+	if spec.Projects != nil {
+		opts.AddProjects, opts.RemoveProjects = diff(cur.Projects, *spec.Projects, equal)
+	}
+	if spec.Milestone != nil && *spec.Milestone != cur.Milestone {
+		milestone := *spec.Milestone
+		opts.Milestone = &milestone
+	}
+	return opts
+}
+
+// diff returns the values in want that aren't in have,
+// and the values in have that aren't in want.
+func diff(have, want []string, eq func(a, b string) bool) (add, remove []string) {
+	has := func(values []string, v string) bool {
+		return slices.ContainsFunc(values, func(o string) bool {
+			return eq(o, v)
+		})
+	}
+
+	for _, v := range want {
+		if v = strings.TrimSpace(v); v != "" && !has(have, v) && !has(add, v) {
+			add = append(add, v)
+		}
+	}
+	for _, v := range have {
+		if !has(want, v) && !has(remove, v) {
+			remove = append(remove, v)
+		}
+	}
+	return add, remove
+}
+
+func equal(a, b string) bool { return a == b }
@@ -0,0 +1,161 @@
+package changemeta
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/text"
+)
+
+func TestParseMessage(t *testing.T) {
+	ptr := func(s ...string) *[]string {
+		if s == nil {
+			s = []string{}
+		}
+		return &s
+	}
+	str := func(s string) *string { return &s }
+
+	tests := []struct {
+		name string
+		msg  string
+		want *Spec
+	}{
+		{
+			name: "NoBlock",
+			msg:  "Fix a bug\n\nNothing to see here.",
+		},
+		{
+			name: "OtherBlock",
+			msg: text.Dedent(`
+				Fix a bug
+
+				` + "```yaml" + `
+				labels: [bug]
+				` + "```" + `
+			`),
+		},
+		{
+			name: "Full",
+			msg: text.Dedent(`
+				Fix a bug
+
+				Some details.
+
+				` + "```git-spice" + `
+				labels: [bug, ui]
+				reviewers:
+				  - alice
+				  - acme/backend
+				assignees: []
+				milestone: v1.0
+				` + "```" + `
+
+				Trailing text.
+			`),
+			want: &Spec{
+				Labels:    ptr("bug", "ui"),
+				Reviewers: ptr("alice", "acme/backend"),
+				Assignees: ptr(),
+				Milestone: str("v1.0"),
+			},
+		},
+		{
+			name: "TildeFence",
+			msg: text.Dedent(`
+				Subject
+
+				~~~~ git-spice
+				projects: [Roadmap]
+				milestone: ""
+				~~~~
+			`),
+			want: &Spec{
+				Projects:  ptr("Roadmap"),
+				Milestone: str(""),
+			},
+		},
+		{
+			name: "Empty",
+			msg:  "Subject\n\n```git-spice\n```\n",
+			want: &Spec{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMessage(tt.msg)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseMessage_unknownField(t *testing.T) {
+	_, err := ParseMessage("Subject\n\n```git-spice\nlabel: [bug]\n```\n")
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "label")
+}
+
+func TestSpecOverride(t *testing.T) {
+	labels := []string{"bug"}
+	reviewers := []string{"alice"}
+	other := []string{"bob"}
+	milestone := "v1"
+
+	spec := &Spec{Labels: &labels, Reviewers: &reviewers}
+	spec.Override(&Spec{Reviewers: &other, Milestone: &milestone})
+
+	assert.Equal(t, &Spec{
+		Labels:    &labels,
+		Reviewers: &other,
+		Milestone: &milestone,
+	}, spec)
+	assert.False(t, spec.IsZero())
+	assert.True(t, new(Spec).IsZero())
+}
+
+func TestReconcile(t *testing.T) {
+	labels := []string{"bug", "ui", "bug"}
+	reviewers := []string{"Alice", "carol"}
+	assignees := []string{}
+	milestone := ""
+
+	got := Reconcile(&Spec{
+		Labels:    &labels,
+		Reviewers: &reviewers,
+		Assignees: &assignees,
+		Milestone: &milestone,
+	}, &Current{
+		Labels:    []string{"bug", "stale"},
+		Reviewers: []string{"alice", "bob"},
+		Assignees: []string{"dave"},
+		Projects:  []string{"Roadmap"},
+		Milestone: "v1",
+	})
+
+	assert.Equal(t, forge.EditChangeOptions{
+		AddLabels:       []string{"ui"},
+		RemoveLabels:    []string{"stale"},
+		AddReviewers:    []string{"carol"},
+		RemoveReviewers: []string{"bob"},
+		RemoveAssignees: []string{"dave"},
+		Milestone:       &milestone,
+	}, got)
+}
+
+func TestReconcile_upToDate(t *testing.T) {
+	labels := []string{"bug"}
+	milestone := "v1"
+
+	got := Reconcile(&Spec{
+		Labels:    &labels,
+		Milestone: &milestone,
+	}, &Current{
+		Labels:    []string{"bug"},
+		Milestone: "v1",
+	})
+	assert.Zero(t, got)
+}
@@ -62,6 +62,10 @@ type Prompts struct {
 
 	// StackReview is the prompt template for stack review.
 	StackReview string `yaml:"stackReview"`
+
+	// StructuredReview is the prompt template for code review
+	// that reports findings as JSON.
+	StructuredReview string `yaml:"structuredReview"`
 }
 
 // RefineOption is a quick refinement option for user selection.
@@ -106,10 +110,11 @@ func DefaultConfig() *Config {
 			Commit:  ModelHaiku,  // Haiku for fast commit messages
 		},
 		Prompts: Prompts{
-			Review:      defaultReviewPrompt,
-			Summary:     defaultSummaryPrompt,
-			Commit:      defaultCommitPrompt,
-			StackReview: defaultStackReviewPrompt,
+			Review:           defaultReviewPrompt,
+			Summary:          defaultSummaryPrompt,
+			Commit:           defaultCommitPrompt,
+			StackReview:      defaultStackReviewPrompt,
+			StructuredReview: defaultStructuredReviewPrompt,
 		},
 		RefineOptions: []RefineOption{
 			{
@@ -198,6 +203,9 @@ func LoadConfig(path string) (*Config, error) {
 	if fileCfg.Prompts.StackReview != "" {
 		cfg.Prompts.StackReview = fileCfg.Prompts.StackReview
 	}
+	if fileCfg.Prompts.StructuredReview != "" {
+		cfg.Prompts.StructuredReview = fileCfg.Prompts.StructuredReview
+	}
 	if len(fileCfg.RefineOptions) > 0 {
 		cfg.RefineOptions = fileCfg.RefineOptions
 	}
@@ -228,6 +236,9 @@ func (c *Config) Validate() error {
 	if c.Prompts.Commit == "" {
 		return errors.New("prompts.commit must be set")
 	}
+	if c.Prompts.StructuredReview == "" {
+		return errors.New("prompts.structuredReview must be set")
+	}
 
 	// Validate required placeholders in prompts.
 	if err := validatePlaceholders(c.Prompts.Review, "prompts.review", "{diff}"); err != nil {
@@ -239,6 +250,9 @@ func (c *Config) Validate() error {
 	if err := validatePlaceholders(c.Prompts.Commit, "prompts.commit", "{diff}"); err != nil {
 		return err
 	}
+	if err := validatePlaceholders(c.Prompts.StructuredReview, "prompts.structuredReview", "{diff}"); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -316,3 +330,37 @@ Diff:
 
 const defaultStackReviewPrompt = `Review this stack. Per-branch summary, then full stack summary.
 {branches}`
+
+const defaultStructuredReviewPrompt = `Review PR: "{title}"
+
+## Guidelines
+1. Code Quality - readability, naming, structure
+2. Functionality - correctness, edge cases, bugs
+3. Performance - efficiency, memory
+
+Only report problems in lines added by the diff (lines starting with "+").
+Each added and unchanged line in the diff is prefixed with
+its line number in the new version of the file.
+
+## Output
+Output ONLY a JSON array with one object per problem, and nothing else:
+[
+  {
+    "file": "path/to/file.go",
+    "startLine": 12,
+    "endLine": 14,
+    "severity": "error",
+    "message": "What is wrong and how to fix it."
+  }
+]
+
+Rules:
+- file: path of the file in the new version
+- startLine, endLine: inclusive range of added lines
+- severity: "error" for bugs, "warning" for likely problems,
+  "info" for suggestions
+- message: succinct, direct, actionable
+- Output [] if there are no problems
+
+## Diff:
+{diff}`
@@ -22,6 +22,7 @@ func TestLoadConfig(t *testing.T) {
 		assert.NotEmpty(t, cfg.Prompts.Summary)
 		assert.NotEmpty(t, cfg.Prompts.Commit)
 		assert.NotEmpty(t, cfg.Prompts.StackReview)
+		assert.NotEmpty(t, cfg.Prompts.StructuredReview)
 
 		// Check default models.
 		assert.Equal(t, ModelSonnet, cfg.Models.Review)
@@ -43,6 +44,7 @@ prompts:
   summary: "Custom summary prompt"
   commit: "Custom commit prompt"
   stackReview: "Custom stack review prompt"
+  structuredReview: "Custom structured review prompt {diff}"
 refineOptions:
   - label: "Custom option"
     prompt: "Custom instruction"
@@ -59,6 +61,7 @@ refineOptions:
 		assert.Equal(t, "Custom summary prompt", cfg.Prompts.Summary)
 		assert.Equal(t, "Custom commit prompt", cfg.Prompts.Commit)
 		assert.Equal(t, "Custom stack review prompt", cfg.Prompts.StackReview)
+		assert.Equal(t, "Custom structured review prompt {diff}", cfg.Prompts.StructuredReview)
 		require.Len(t, cfg.RefineOptions, 1)
 		assert.Equal(t, "Custom option", cfg.RefineOptions[0].Label)
 	})
@@ -0,0 +1,210 @@
+package claude
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Severity is the severity of a review finding.
+type Severity string
+
+// Supported finding severities.
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// Finding is a single problem reported by a structured review.
+type Finding struct {
+	// File is the path of the file in the new version of the code.
+	File string `json:"file"`
+
+	// StartLine and EndLine are the inclusive range of lines
+	// in the new version of the file that the finding is about.
+	// EndLine is the same as StartLine for single-line findings.
+	StartLine int `json:"startLine"`
+	EndLine   int `json:"endLine"`
+
+	// Severity is how serious the problem is.
+	Severity Severity `json:"severity"`
+
+	// Message describes the problem and how to fix it.
+	Message string `json:"message"`
+}
+
+// Location returns the location of the finding
+// in the form "file:line" or "file:start-end".
+func (f *Finding) Location() string {
+	if f.EndLine == f.StartLine {
+		return fmt.Sprintf("%s:%d", f.File, f.StartLine)
+	}
+	return fmt.Sprintf("%s:%d-%d", f.File, f.StartLine, f.EndLine)
+}
+
+// RejectedFinding is a finding that does not refer to lines
+// added by the reviewed diff.
+type RejectedFinding struct {
+	Finding *Finding
+
+	// Reason explains why the finding was rejected.
+	Reason string
+}
+
+// ParseFindings parses the response to a structured review prompt.
+//
+// The response must hold a JSON array of findings.
+// Text around the array, such as a Markdown code fence, is ignored.
+// Severities are normalized to lowercase,
+// and a missing end line is set to the start line.
+func ParseFindings(response string) ([]*Finding, error) {
+	start := strings.IndexByte(response, '[')
+	end := strings.LastIndexByte(response, ']')
+	if start < 0 || end < start {
+		return nil, errors.New("response does not contain a JSON array")
+	}
+
+	var findings []*Finding
+	if err := json.Unmarshal([]byte(response[start:end+1]), &findings); err != nil {
+		return nil, fmt.Errorf("decode findings: %w", err)
+	}
+
+	for _, f := range findings {
+		f.Severity = Severity(strings.ToLower(strings.TrimSpace(string(f.Severity))))
+		if f.EndLine == 0 {
+			f.EndLine = f.StartLine
+		}
+	}
+	return findings, nil
+}
+
+// ValidateFindings checks that findings refer to lines
+// added by the given diff files.
+// A finding is valid if every line in its range was added.
+//
+// It returns the valid findings and the rejected ones,
+// both in their original order.
+func ValidateFindings(findings []*Finding, files []DiffFile) (valid []*Finding, rejected []*RejectedFinding) {
+	added := make(map[string]map[int]struct{}, len(files))
+	for _, f := range files {
+		added[f.Path] = f.AddedLines()
+	}
+
+	for _, f := range findings {
+		if reason := findingError(f, added); reason != "" {
+			rejected = append(rejected, &RejectedFinding{Finding: f, Reason: reason})
+		} else {
+			valid = append(valid, f)
+		}
+	}
+	return valid, rejected
+}
+
+func findingError(f *Finding, added map[string]map[int]struct{}) string {
+	switch f.Severity {
+	case SeverityError, SeverityWarning, SeverityInfo:
+	default:
+		return fmt.Sprintf("unknown severity %q", f.Severity)
+	}
+
+	if strings.TrimSpace(f.Message) == "" {
+		return "empty message"
+	}
+
+	lines, ok := added[f.File]
+	if !ok {
+		return "file is not part of the diff"
+	}
+	if f.StartLine <= 0 || f.EndLine < f.StartLine {
+		return fmt.Sprintf("invalid line range %d-%d", f.StartLine, f.EndLine)
+	}
+	for n := f.StartLine; n <= f.EndLine; n++ {
+		if _, ok := lines[n]; !ok {
+			return fmt.Sprintf("line %d was not added by the diff", n)
+		}
+	}
+	return ""
+}
+
+// hunkHeaderRegex matches the header of a hunk in a unified diff
+// and captures the starting line in the new version of the file.
+// Example: "@@ -10,6 +12,8 @@ func Foo() {"
+var hunkHeaderRegex = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,\d+)? @@`)
+
+// AddedLines reports the lines in the new version of the file
+// that were added by the diff.
+func (f *DiffFile) AddedLines() map[int]struct{} {
+	lines := make(map[int]struct{})
+	forEachHunkLine(f.Content, func(newLine int, line string) {
+		if strings.HasPrefix(line, "+") {
+			lines[newLine] = struct{}{}
+		}
+	})
+	return lines
+}
+
+// NumberDiffLines reconstructs a diff from the given files
+// with each added and context line prefixed by its line number
+// in the new version of the file.
+// Removed lines are indented to line up with the rest.
+//
+// This lets structured reviews report exact line numbers
+// without counting lines from hunk headers.
+func NumberDiffLines(files []DiffFile) string {
+	var sb strings.Builder
+	for _, f := range files {
+		var inHunk bool
+		newLine := 0
+		for line := range strings.SplitSeq(strings.TrimSuffix(f.Content, "\n"), "\n") {
+			if m := hunkHeaderRegex.FindStringSubmatch(line); m != nil {
+				inHunk = true
+				newLine, _ = strconv.Atoi(m[1])
+				sb.WriteString(line)
+				sb.WriteByte('\n')
+				continue
+			}
+
+			switch {
+			case !inHunk || line == "" || strings.HasPrefix(line, `\`):
+				// File headers and "\ No newline at end of file" markers.
+			case strings.HasPrefix(line, "-"):
+				sb.WriteString("\t")
+			default:
+				sb.WriteString(strconv.Itoa(newLine))
+				sb.WriteString("\t")
+				newLine++
+			}
+			sb.WriteString(line)
+			sb.WriteByte('\n')
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// forEachHunkLine calls fn for each added or context line
+// in the hunks of a single file's diff
+// with its line number in the new version of the file.
+func forEachHunkLine(content string, fn func(newLine int, line string)) {
+	var inHunk bool
+	newLine := 0
+	for line := range strings.SplitSeq(content, "\n") {
+		if m := hunkHeaderRegex.FindStringSubmatch(line); m != nil {
+			inHunk = true
+			newLine, _ = strconv.Atoi(m[1])
+			continue
+		}
+		if !inHunk || line == "" {
+			continue
+		}
+
+		switch line[0] {
+		case '+', ' ':
+			fn(newLine, line)
+			newLine++
+		}
+	}
+}
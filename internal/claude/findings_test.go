@@ -0,0 +1,150 @@
+package claude
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/text"
+)
+
+func TestParseFindings(t *testing.T) {
+	t.Run("Plain", func(t *testing.T) {
+		findings, err := ParseFindings(`[
+			{"file": "foo.go", "startLine": 3, "endLine": 4, "severity": "error", "message": "bad"}
+		]`)
+		require.NoError(t, err)
+		assert.Equal(t, []*Finding{
+			{File: "foo.go", StartLine: 3, EndLine: 4, Severity: SeverityError, Message: "bad"},
+		}, findings)
+	})
+
+	t.Run("CodeFence", func(t *testing.T) {
+		findings, err := ParseFindings(text.Dedent("Here you go:\n" + "```json\n" +
+			`[{"file": "foo.go", "startLine": 3, "severity": "Warning", "message": "hmm"}]` +
+			"\n```\n"))
+		require.NoError(t, err)
+		assert.Equal(t, []*Finding{
+			{File: "foo.go", StartLine: 3, EndLine: 3, Severity: SeverityWarning, Message: "hmm"},
+		}, findings)
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		findings, err := ParseFindings("[]")
+		require.NoError(t, err)
+		assert.Empty(t, findings)
+	})
+
+	t.Run("NoArray", func(t *testing.T) {
+		_, err := ParseFindings("Looks good to me!")
+		assert.ErrorContains(t, err, "does not contain a JSON array")
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := ParseFindings(`[{"file": 42}]`)
+		assert.ErrorContains(t, err, "decode findings")
+	})
+}
+
+const _findingsTestDiff = `diff --git a/foo.go b/foo.go
+index 1234567..abcdefg 100644
+--- a/foo.go
++++ b/foo.go
+@@ -1,4 +1,5 @@
+ package foo
+-
+-func Foo() {}
++
++// Foo does things.
++func Foo() {
++}
+@@ -20,2 +21,3 @@ func Bar() {
+ 	x := 1
++	y := 2
+ 	return
+diff --git a/gone.go b/gone.go
+deleted file mode 100644
+index 1234567..0000000
+--- a/gone.go
++++ /dev/null
+@@ -1,1 +0,0 @@
+-package gone`
+
+func TestDiffFile_AddedLines(t *testing.T) {
+	files, err := ParseDiff(_findingsTestDiff)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+
+	assert.Equal(t, map[int]struct{}{
+		2: {}, 3: {}, 4: {}, 5: {}, 22: {},
+	}, files[0].AddedLines())
+	assert.Empty(t, files[1].AddedLines())
+}
+
+func TestNumberDiffLines(t *testing.T) {
+	files, err := ParseDiff(_findingsTestDiff)
+	require.NoError(t, err)
+
+	assert.Equal(t, text.Dedent(`
+		diff --git a/foo.go b/foo.go
+		index 1234567..abcdefg 100644
+		--- a/foo.go
+		+++ b/foo.go
+		@@ -1,4 +1,5 @@
+		1	 package foo
+			-
+			-func Foo() {}
+		2	+
+		3	+// Foo does things.
+		4	+func Foo() {
+		5	+}
+		@@ -20,2 +21,3 @@ func Bar() {
+		21	 	x := 1
+		22	+	y := 2
+		23	 	return
+		diff --git a/gone.go b/gone.go
+		deleted file mode 100644
+		index 1234567..0000000
+		--- a/gone.go
+		+++ /dev/null
+		@@ -1,1 +0,0 @@
+			-package gone
+	`), NumberDiffLines(files))
+}
+
+func TestValidateFindings(t *testing.T) {
+	files, err := ParseDiff(_findingsTestDiff)
+	require.NoError(t, err)
+
+	give := []*Finding{
+		{File: "foo.go", StartLine: 3, EndLine: 5, Severity: SeverityError, Message: "range"},
+		{File: "foo.go", StartLine: 22, EndLine: 22, Severity: SeverityInfo, Message: "single"},
+		{File: "foo.go", StartLine: 1, EndLine: 2, Severity: SeverityWarning, Message: "context line"},
+		{File: "foo.go", StartLine: 5, EndLine: 3, Severity: SeverityWarning, Message: "backwards"},
+		{File: "gone.go", StartLine: 1, EndLine: 1, Severity: SeverityWarning, Message: "deleted"},
+		{File: "other.go", StartLine: 1, EndLine: 1, Severity: SeverityWarning, Message: "not in diff"},
+		{File: "foo.go", StartLine: 22, EndLine: 22, Severity: "critical", Message: "severity"},
+		{File: "foo.go", StartLine: 22, EndLine: 22, Severity: SeverityInfo, Message: " "},
+	}
+
+	valid, rejected := ValidateFindings(give, files)
+	assert.Equal(t, give[:2], valid)
+
+	reasons := make(map[string]string)
+	for _, r := range rejected {
+		reasons[r.Finding.Message] = r.Reason
+	}
+	assert.Equal(t, map[string]string{
+		"context line": "line 1 was not added by the diff",
+		"backwards":    "invalid line range 5-3",
+		"deleted":      "line 1 was not added by the diff",
+		"not in diff":  "file is not part of the diff",
+		"severity":     `unknown severity "critical"`,
+		" ":            "empty message",
+	}, reasons)
+}
+
+func TestFinding_Location(t *testing.T) {
+	assert.Equal(t, "foo.go:3", (&Finding{File: "foo.go", StartLine: 3, EndLine: 3}).Location())
+	assert.Equal(t, "foo.go:3-5", (&Finding{File: "foo.go", StartLine: 3, EndLine: 5}).Location())
+}
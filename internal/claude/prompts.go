@@ -1,6 +1,8 @@
 package claude
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"strings"
 	"unicode"
 )
@@ -52,6 +54,16 @@ func BuildReviewPrompt(cfg *Config, title, diff string) string {
 	})
 }
 
+// BuildStructuredReviewPrompt builds a code review prompt
+// that asks for findings as JSON.
+// Use [ParseFindings] to parse the response.
+func BuildStructuredReviewPrompt(cfg *Config, title, diff string) string {
+	return BuildPrompt(cfg.Prompts.StructuredReview, map[string]string{
+		"title": title,
+		"diff":  diff,
+	})
+}
+
 // BuildSummaryPrompt builds a PR summary generation prompt.
 func BuildSummaryPrompt(cfg *Config, branch, base, commits, diff string) string {
 	return BuildPrompt(cfg.Prompts.Summary, map[string]string{
@@ -76,6 +88,15 @@ func BuildStackReviewPrompt(cfg *Config, branches string) string {
 	})
 }
 
+// PromptKey returns a key identifying a prompt sent to a model.
+// Prompts built from the same template and diff
+// and sent to the same model have the same key,
+// so the key can be used to cache responses.
+func PromptKey(prompt, model string) string {
+	sum := sha256.Sum256([]byte(model + "\x00" + prompt))
+	return hex.EncodeToString(sum[:])
+}
+
 // RefinePrompt appends a refinement instruction to an original prompt.
 func RefinePrompt(original, instruction string) string {
 	return original + "\n\nAdditional instruction: " + instruction
@@ -111,6 +111,23 @@ func TestBuildStackReviewPrompt(t *testing.T) {
 	})
 }
 
+func TestBuildStructuredReviewPrompt(t *testing.T) {
+	cfg := DefaultConfig()
+
+	prompt := BuildStructuredReviewPrompt(cfg, "My PR", "diff content")
+	assert.Contains(t, prompt, `Review PR: "My PR"`)
+	assert.Contains(t, prompt, "diff content")
+	assert.Contains(t, prompt, `"startLine"`)
+}
+
+func TestPromptKey(t *testing.T) {
+	key := PromptKey("review this", ModelSonnet)
+	assert.Len(t, key, 64)
+	assert.Equal(t, key, PromptKey("review this", ModelSonnet))
+	assert.NotEqual(t, key, PromptKey("review that", ModelSonnet))
+	assert.NotEqual(t, key, PromptKey("review this", ModelHaiku))
+}
+
 func TestRefinePrompt(t *testing.T) {
 	t.Run("Basic", func(t *testing.T) {
 		original := "Generate a commit message"
@@ -0,0 +1,209 @@
+package shorthand
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// MacroSeparator separates commands in a [Macro].
+const MacroSeparator = "&&"
+
+// _macroArgRe matches references to macro arguments:
+// "$1", "$2", etc. for individual arguments,
+// and "$@" for all arguments.
+var _macroArgRe = regexp.MustCompile(`\$(@|[1-9][0-9]*)`)
+
+// Macro is a user-defined shorthand that accepts positional arguments
+// or runs multiple commands in sequence.
+//
+// Commands in a macro are separated by "&&",
+// and are run in order until one of them fails.
+// Commands that start with "!" are external commands;
+// all others are git-spice commands.
+//
+// "$1", "$2", etc. expand to the corresponding argument to the macro,
+// and "$@" expands to all arguments.
+// If a macro doesn't reference any of its arguments,
+// the arguments are appended to the last command.
+type Macro struct {
+	commands [][]string
+
+	// maxArg is the highest argument position referenced by the macro.
+	maxArg int
+
+	// allArgs is true if the macro references "$@".
+	allArgs bool
+}
+
+// IsMacro reports whether the given shorthand long form
+// uses any macro features.
+// Long forms that aren't macros are plain argument lists.
+func IsMacro(words []string) bool {
+	for _, w := range words {
+		if w == MacroSeparator || _macroArgRe.MatchString(w) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseMacro parses a macro from the words in a shorthand long form.
+func ParseMacro(words []string) (*Macro, error) {
+	m := Macro{commands: [][]string{nil}}
+	for _, w := range words {
+		if w == MacroSeparator {
+			m.commands = append(m.commands, nil)
+			continue
+		}
+
+		for _, match := range _macroArgRe.FindAllStringSubmatch(w, -1) {
+			if match[1] == "@" {
+				m.allArgs = true
+				continue
+			}
+
+			n, err := strconv.Atoi(match[1])
+			if err != nil {
+				return nil, fmt.Errorf("bad argument reference %q: %w", match[0], err)
+			}
+			m.maxArg = max(m.maxArg, n)
+		}
+
+		last := len(m.commands) - 1
+		m.commands[last] = append(m.commands[last], w)
+	}
+
+	for i, cmd := range m.commands {
+		if len(cmd) == 0 {
+			return nil, fmt.Errorf("command %d is empty", i+1)
+		}
+		if cmd[0] == "!" {
+			return nil, fmt.Errorf("command %d: external command name is empty", i+1)
+		}
+	}
+
+	return &m, nil
+}
+
+// Len returns the number of commands in the macro.
+func (m *Macro) Len() int {
+	return len(m.commands)
+}
+
+// Expand expands the macro with the given arguments
+// and returns the commands to run.
+//
+// External commands retain their "!" prefix.
+func (m *Macro) Expand(args []string) ([][]string, error) {
+	if !m.refsArgs() {
+		cmds := cloneCommands(m.commands)
+		last := len(cmds) - 1
+		cmds[last] = append(cmds[last], args...)
+		return cmds, nil
+	}
+
+	if len(args) < m.maxArg {
+		return nil, fmt.Errorf("missing argument $%d", len(args)+1)
+	}
+	if !m.allArgs && len(args) > m.maxArg {
+		return nil, fmt.Errorf("unexpected argument %q", args[m.maxArg])
+	}
+
+	cmds := make([][]string, len(m.commands))
+	for i, cmd := range m.commands {
+		for _, w := range cmd {
+			if w == "$@" {
+				cmds[i] = append(cmds[i], args...)
+				continue
+			}
+
+			cmds[i] = append(cmds[i], substituteArgs(w, args))
+		}
+	}
+	return cmds, nil
+}
+
+// CompletionArgs returns the arguments to use for shell completion
+// when the macro has been invoked with the given arguments.
+//
+// This is the last command in the macro
+// with all available arguments substituted.
+// If the command references an argument that hasn't been provided yet,
+// the command is truncated at that point
+// so that completion predicts that argument.
+func (m *Macro) CompletionArgs(args []string) []string {
+	cmd := m.commands[len(m.commands)-1]
+	if !m.refsArgs() {
+		return append(slices.Clone(cmd), args...)
+	}
+
+	var out []string
+	for _, w := range cmd {
+		if w == "$@" {
+			return append(out, args...)
+		}
+
+		ok := true
+		for _, match := range _macroArgRe.FindAllStringSubmatch(w, -1) {
+			if match[1] == "@" {
+				continue
+			}
+			if n, _ := strconv.Atoi(match[1]); n > len(args) {
+				ok = false
+			}
+		}
+		if !ok {
+			break
+		}
+		out = append(out, substituteArgs(w, args))
+	}
+	return out
+}
+
+func (m *Macro) refsArgs() bool {
+	return m.allArgs || m.maxArg > 0
+}
+
+// substituteArgs replaces argument references in w.
+// "$@" inside a larger word expands to all arguments joined by spaces.
+// Missing arguments expand to an empty string.
+func substituteArgs(w string, args []string) string {
+	return _macroArgRe.ReplaceAllStringFunc(w, func(ref string) string {
+		if ref == "$@" {
+			return strings.Join(args, " ")
+		}
+
+		n, _ := strconv.Atoi(ref[1:])
+		if n > len(args) {
+			return ""
+		}
+		return args[n-1]
+	})
+}
+
+func cloneCommands(cmds [][]string) [][]string {
+	out := make([][]string, len(cmds))
+	for i, cmd := range cmds {
+		out[i] = slices.Clone(cmd)
+	}
+	return out
+}
+
+// ExternalCommand reports whether cmd, as returned by [Macro.Expand],
+// is an external command.
+// If so, it returns the command with the "!" prefix removed.
+func ExternalCommand(cmd []string) ([]string, bool) {
+	if len(cmd) == 0 {
+		return nil, false
+	}
+
+	name, ok := strings.CutPrefix(cmd[0], "!")
+	if !ok {
+		return nil, false
+	}
+
+	return append([]string{name}, cmd[1:]...), true
+}
@@ -0,0 +1,191 @@
+package shorthand_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/cli/shorthand"
+)
+
+func TestIsMacro(t *testing.T) {
+	assert.False(t, shorthand.IsMacro([]string{"commit", "create", "-m", "wip"}))
+	assert.False(t, shorthand.IsMacro([]string{"commit", "create", "-m", "costs $"}))
+	assert.True(t, shorthand.IsMacro([]string{"branch", "create", "$1"}))
+	assert.True(t, shorthand.IsMacro([]string{"branch", "create", "--", "$@"}))
+	assert.True(t, shorthand.IsMacro([]string{"repo", "sync", "&&", "stack", "restack"}))
+}
+
+func TestMacro_Expand(t *testing.T) {
+	tests := []struct {
+		name  string
+		words []string
+		args  []string
+		want  [][]string
+	}{
+		{
+			name:  "Chain",
+			words: []string{"repo", "sync", "&&", "stack", "restack", "&&", "stack", "submit"},
+			want: [][]string{
+				{"repo", "sync"},
+				{"stack", "restack"},
+				{"stack", "submit"},
+			},
+		},
+		{
+			name:  "ChainAppendsArgs",
+			words: []string{"repo", "sync", "&&", "stack", "submit"},
+			args:  []string{"--fill", "--draft"},
+			want: [][]string{
+				{"repo", "sync"},
+				{"stack", "submit", "--fill", "--draft"},
+			},
+		},
+		{
+			name:  "Positional",
+			words: []string{"branch", "create", "$1", "-m", "$2"},
+			args:  []string{"feat", "Add feature"},
+			want:  [][]string{{"branch", "create", "feat", "-m", "Add feature"}},
+		},
+		{
+			name:  "PositionalInWord",
+			words: []string{"commit", "create", "-m", "[wip] $1", "--branch=$2"},
+			args:  []string{"stuff", "feat"},
+			want:  [][]string{{"commit", "create", "-m", "[wip] stuff", "--branch=feat"}},
+		},
+		{
+			name:  "AllArgs",
+			words: []string{"branch", "delete", "--force", "$@"},
+			args:  []string{"a", "b", "c"},
+			want:  [][]string{{"branch", "delete", "--force", "a", "b", "c"}},
+		},
+		{
+			name:  "AllArgsEmpty",
+			words: []string{"branch", "delete", "$@"},
+			want:  [][]string{{"branch", "delete"}},
+		},
+		{
+			name:  "AllArgsInWord",
+			words: []string{"!echo", "args: $@"},
+			args:  []string{"a", "b"},
+			want:  [][]string{{"!echo", "args: a b"}},
+		},
+		{
+			name:  "RepeatedReference",
+			words: []string{"branch", "checkout", "$1", "&&", "!echo", "switched to $1"},
+			args:  []string{"feat"},
+			want: [][]string{
+				{"branch", "checkout", "feat"},
+				{"!echo", "switched to feat"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := shorthand.ParseMacro(tt.words)
+			require.NoError(t, err)
+			assert.Equal(t, len(tt.want), m.Len())
+
+			got, err := m.Expand(tt.args)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMacro_Expand_errors(t *testing.T) {
+	m, err := shorthand.ParseMacro([]string{"branch", "create", "$1", "-m", "$2"})
+	require.NoError(t, err)
+
+	t.Run("MissingArgument", func(t *testing.T) {
+		_, err := m.Expand([]string{"feat"})
+		assert.ErrorContains(t, err, "missing argument $2")
+	})
+
+	t.Run("UnexpectedArgument", func(t *testing.T) {
+		_, err := m.Expand([]string{"feat", "msg", "extra"})
+		assert.ErrorContains(t, err, `unexpected argument "extra"`)
+	})
+}
+
+func TestParseMacro_errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		words   []string
+		wantErr string
+	}{
+		{
+			name:    "Leading",
+			words:   []string{"&&", "repo", "sync"},
+			wantErr: "command 1 is empty",
+		},
+		{
+			name:    "Trailing",
+			words:   []string{"repo", "sync", "&&"},
+			wantErr: "command 2 is empty",
+		},
+		{
+			name:    "Consecutive",
+			words:   []string{"repo", "sync", "&&", "&&", "stack", "submit"},
+			wantErr: "command 2 is empty",
+		},
+		{
+			name:    "EmptyExternal",
+			words:   []string{"repo", "sync", "&&", "!"},
+			wantErr: "command 2: external command name is empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := shorthand.ParseMacro(tt.words)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestMacro_CompletionArgs(t *testing.T) {
+	tests := []struct {
+		name  string
+		words []string
+		args  []string
+		want  []string
+	}{
+		{
+			name:  "NoReferences",
+			words: []string{"repo", "sync", "&&", "stack", "submit"},
+			args:  []string{"--fill"},
+			want:  []string{"stack", "submit", "--fill"},
+		},
+		{
+			name:  "MissingArgument",
+			words: []string{"branch", "checkout", "$1", "&&", "branch", "onto", "$2"},
+			args:  []string{"feat"},
+			want:  []string{"branch", "onto"},
+		},
+		{
+			name:  "AllArguments",
+			words: []string{"branch", "create", "$1", "--", "$@"},
+			args:  []string{"feat"},
+			want:  []string{"branch", "create", "feat", "--", "feat"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := shorthand.ParseMacro(tt.words)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, m.CompletionArgs(tt.args))
+		})
+	}
+}
+
+func TestExternalCommand(t *testing.T) {
+	got, ok := shorthand.ExternalCommand([]string{"!make", "test"})
+	require.True(t, ok)
+	assert.Equal(t, []string{"make", "test"}, got)
+
+	_, ok = shorthand.ExternalCommand([]string{"repo", "sync"})
+	assert.False(t, ok)
+}
@@ -0,0 +1,286 @@
+// Package codeowners parses CODEOWNERS files
+// and reports the owners of files in a repository.
+//
+// Both the GitHub and GitLab flavors of the format are supported.
+// Patterns follow gitignore rules,
+// and the last matching rule decides the owners of a file.
+// GitLab sections are matched independently,
+// and owners from all matching sections are combined.
+package codeowners
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Paths lists the locations of CODEOWNERS files
+// relative to the root of a repository,
+// in the order in which they're searched.
+var Paths = []string{
+	".github/CODEOWNERS",
+	"CODEOWNERS",
+	"docs/CODEOWNERS",
+	".gitlab/CODEOWNERS",
+}
+
+// File is a parsed CODEOWNERS file.
+type File struct {
+	// Sections in the order they first appear in the file.
+	// Rules that appear before any section header
+	// are placed in an unnamed section.
+	sections []*section
+}
+
+type section struct {
+	name string
+
+	// optional is set for GitLab sections marked with "^".
+	// Approval from these owners is not required.
+	optional bool
+
+	// defaultOwners own files matched by rules in this section
+	// that don't list their own owners.
+	defaultOwners []string
+
+	rules []*rule
+}
+
+type rule struct {
+	pattern *regexp.Regexp
+	owners  []string
+
+	// exclude is set for GitLab rules starting with "!".
+	// Matching files have no owners in this section.
+	exclude bool
+}
+
+// _sectionRe matches GitLab section headers like:
+//
+//	[Section]
+//	^[Optional section]
+//	[Section][2] @default-owner
+var _sectionRe = regexp.MustCompile(`^(\^)?\[([^\]]+)\](?:\[\d+\])?(?:\s+(.*))?$`)
+
+// Parse parses a CODEOWNERS file.
+//
+// Sections with the same name (ignoring case) are combined.
+func Parse(r io.Reader) (*File, error) {
+	current := &section{}
+	f := &File{sections: []*section{current}}
+	sectionsByName := map[string]*section{"": current}
+
+	scanner := bufio.NewScanner(r)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if m := _sectionRe.FindStringSubmatch(line); m != nil {
+			name := strings.ToLower(strings.TrimSpace(m[2]))
+			sec, ok := sectionsByName[name]
+			if !ok {
+				sec = &section{name: m[2]}
+				sectionsByName[name] = sec
+				f.sections = append(f.sections, sec)
+			}
+			sec.optional = m[1] != ""
+			if owners := splitFields(m[3]); len(owners) > 0 {
+				sec.defaultOwners = owners
+			}
+			current = sec
+			continue
+		}
+
+		fields := splitFields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		pattern, exclude := fields[0], false
+		if current.name != "" {
+			// Exclusion patterns are only supported inside GitLab sections.
+			pattern, exclude = strings.CutPrefix(pattern, "!")
+		}
+
+		re, err := compilePattern(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+
+		current.rules = append(current.rules, &rule{
+			pattern: re,
+			owners:  fields[1:],
+			exclude: exclude,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read CODEOWNERS: %w", err)
+	}
+
+	return f, nil
+}
+
+// Owners returns the owners of the file at the given path
+// relative to the root of the repository.
+//
+// Owners are returned as written in the CODEOWNERS file,
+// e.g. "@user", "@org/team", or an email address,
+// in the order they're first listed.
+// Owners of optional GitLab sections are not included.
+func (f *File) Owners(path string) []string {
+	path = strings.TrimPrefix(path, "/")
+
+	var owners []string
+	for _, sec := range f.sections {
+		if sec.optional {
+			continue
+		}
+
+		var match *rule
+		for _, r := range slices.Backward(sec.rules) {
+			if r.pattern.MatchString(path) {
+				match = r
+				break
+			}
+		}
+		if match == nil || match.exclude {
+			continue
+		}
+
+		ruleOwners := match.owners
+		if len(ruleOwners) == 0 {
+			ruleOwners = sec.defaultOwners
+		}
+		for _, o := range ruleOwners {
+			if !slices.Contains(owners, o) {
+				owners = append(owners, o)
+			}
+		}
+	}
+	return owners
+}
+
+// splitFields splits a line into whitespace-separated fields,
+// stopping at the first unescaped "#" that starts a field.
+// Backslash-escaped spaces and "#"s are kept in fields.
+func splitFields(line string) []string {
+	var (
+		fields []string
+		field  strings.Builder
+		inside bool
+	)
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case c == '\\' && i+1 < len(line) && (line[i+1] == ' ' || line[i+1] == '#'):
+			i++
+			field.WriteByte(line[i])
+			inside = true
+
+		case c == ' ' || c == '\t':
+			if inside {
+				fields = append(fields, field.String())
+				field.Reset()
+				inside = false
+			}
+
+		case c == '#' && !inside:
+			// Comment until the end of the line.
+			return fields
+
+		default:
+			field.WriteByte(c)
+			inside = true
+		}
+	}
+	if inside {
+		fields = append(fields, field.String())
+	}
+	return fields
+}
+
+// compilePattern converts a gitignore-style pattern to a regular expression
+// that matches paths relative to the root of the repository.
+//
+// Patterns with a leading or inner "/" are anchored to the root.
+// Other patterns match at any depth.
+// Patterns that match a directory match all files inside it.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	orig := pattern
+	dirOnly := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+	anchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	if pattern == "" {
+		return nil, fmt.Errorf("invalid pattern %q", orig)
+	}
+
+	var re strings.Builder
+	re.WriteString("^")
+	if !anchored {
+		re.WriteString("(?:.*/)?")
+	}
+
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				atStart := i == 0 || pattern[i-1] == '/'
+				i++
+				switch {
+				case atStart && i+1 < len(pattern) && pattern[i+1] == '/':
+					// "**/" matches zero or more directories.
+					i++
+					re.WriteString("(?:.*/)?")
+				default:
+					re.WriteString(".*")
+				}
+				continue
+			}
+			re.WriteString("[^/]*")
+
+		case '?':
+			re.WriteString("[^/]")
+
+		case '[':
+			end := strings.IndexByte(pattern[i+1:], ']')
+			if end < 0 {
+				re.WriteString(regexp.QuoteMeta("["))
+				continue
+			}
+			class := pattern[i+1 : i+1+end]
+			if rest, ok := strings.CutPrefix(class, "!"); ok {
+				class = "^" + rest
+			}
+			re.WriteString("[" + class + "]")
+			i += end + 1
+
+		case '\\':
+			if i+1 < len(pattern) {
+				i++
+			}
+			re.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+
+		default:
+			re.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+
+	if dirOnly {
+		re.WriteString("/.*$")
+	} else {
+		re.WriteString("(?:/.*)?$")
+	}
+
+	compiled, err := regexp.Compile(re.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", orig, err)
+	}
+	return compiled, nil
+}
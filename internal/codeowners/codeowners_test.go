@@ -0,0 +1,176 @@
+package codeowners
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_github(t *testing.T) {
+	f, err := Parse(strings.NewReader(`
+# Default owners for everything.
+*       @global-owner1 @global-owner2
+
+*.js    @js-owner #This is an inline comment.
+*.go docs@example.com
+/build/logs/ @doctocat
+docs/*  docs@example.com
+apps/ @octocat
+/docs/ @doctocat
+/scripts/ @doctocat @octocat
+**/logs @octocat
+/apps/ @octocat
+/apps/github
+\#hash @hash-owner
+path\ with\ spaces/ @space-owner
+`))
+	require.NoError(t, err)
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"README.md", []string{"@global-owner1", "@global-owner2"}},
+		{"src/index.js", []string{"@js-owner"}},
+		{"main.go", []string{"docs@example.com"}},
+		{"build/logs/out.txt", []string{"@octocat"}},
+		{"docs/getting-started.md", []string{"@doctocat"}},
+		{"docs/build-app/troubleshooting.md", []string{"@doctocat"}},
+		{"scripts/run.sh", []string{"@doctocat", "@octocat"}},
+		{"deep/nested/logs/x", []string{"@octocat"}},
+		{"lib/apps/x.txt", []string{"@octocat"}},
+		{"apps/github/x.txt", nil},
+		{"#hash", []string{"@hash-owner"}},
+		{"path with spaces/file", []string{"@space-owner"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, f.Owners(tt.path))
+		})
+	}
+}
+
+func TestParse_gitlabSections(t *testing.T) {
+	f, err := Parse(strings.NewReader(`
+* @admin
+
+[Documentation] @docs-team
+docs/
+README.md @tech-writer
+
+[Database][2] @database-team
+*.sql
+model/db/
+!model/db/generated.sql
+
+^[Optional] @optional-team
+*.md
+
+[documentation]
+guides/ @guide-writer
+`))
+	require.NoError(t, err)
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"main.go", []string{"@admin"}},
+		{"docs/index.md", []string{"@admin", "@docs-team"}},
+		{"README.md", []string{"@admin", "@tech-writer"}},
+		{"schema.sql", []string{"@admin", "@database-team"}},
+		{"model/db/user.go", []string{"@admin", "@database-team"}},
+		{"model/db/generated.sql", []string{"@admin"}},
+		{"guides/intro.txt", []string{"@admin", "@guide-writer"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, f.Owners(tt.path))
+		})
+	}
+}
+
+func TestCompilePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		match   []string
+		noMatch []string
+	}{
+		{
+			pattern: "*",
+			match:   []string{"a", "a/b/c.txt"},
+		},
+		{
+			pattern: "*.txt",
+			match:   []string{"a.txt", "x/y/a.txt"},
+			noMatch: []string{"a.txt.go", "a.go"},
+		},
+		{
+			pattern: "/foo",
+			match:   []string{"foo", "foo/bar"},
+			noMatch: []string{"x/foo", "foobar"},
+		},
+		{
+			pattern: "foo/",
+			match:   []string{"foo/bar", "x/foo/bar"},
+			noMatch: []string{"foo", "foobar/x"},
+		},
+		{
+			pattern: "foo/bar",
+			match:   []string{"foo/bar", "foo/bar/baz"},
+			noMatch: []string{"x/foo/bar"},
+		},
+		{
+			pattern: "docs/*",
+			match:   []string{"docs/a.md"},
+			noMatch: []string{"x/docs/a.md"},
+		},
+		{
+			pattern: "a/**/b",
+			match:   []string{"a/b", "a/x/b", "a/x/y/b/c"},
+			noMatch: []string{"ab", "x/a/b"},
+		},
+		{
+			pattern: "a/**",
+			match:   []string{"a/b", "a/b/c"},
+			noMatch: []string{"b/a/c"},
+		},
+		{
+			pattern: "file?.[ch]",
+			match:   []string{"file1.c", "src/fileX.h"},
+			noMatch: []string{"file.c", "file12.c", "file1.go"},
+		},
+		{
+			pattern: "[!a]*.txt",
+			match:   []string{"b.txt"},
+			noMatch: []string{"a.txt"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			re, err := compilePattern(tt.pattern)
+			require.NoError(t, err)
+
+			for _, path := range tt.match {
+				assert.True(t, re.MatchString(path), "%q should match %q", tt.pattern, path)
+			}
+			for _, path := range tt.noMatch {
+				assert.False(t, re.MatchString(path), "%q should not match %q", tt.pattern, path)
+			}
+		})
+	}
+}
+
+func TestCompilePattern_invalid(t *testing.T) {
+	for _, pattern := range []string{"/", "[z-a]"} {
+		t.Run(pattern, func(t *testing.T) {
+			_, err := compilePattern(pattern)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,319 @@
+// Package dashboard implements a local web UI
+// showing tracked stacks and the health of their change requests.
+//
+// The dashboard is meant to be served on a loopback address
+// for the user running git-spice.
+// Requests for other hosts are rejected to defend against DNS rebinding,
+// and actions that change the repository require a per-server token.
+package dashboard
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	_ "embed" // for page.html
+	"html/template"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/silog"
+)
+
+// Page is the information shown on the dashboard.
+type Page struct {
+	// Trunk is the name of the trunk branch.
+	Trunk string
+
+	// Stacks are the branches stacked directly on top of trunk.
+	// Each holds the branches stacked on top of it.
+	Stacks []*Branch
+
+	// Warnings are problems encountered while loading the page
+	// that didn't prevent it from being shown.
+	Warnings []string
+
+	// UpdatedAt is the time at which the page was loaded.
+	UpdatedAt time.Time
+}
+
+// Branch is a tracked branch shown on the dashboard.
+type Branch struct {
+	Name string
+
+	// Current is set if the branch is checked out
+	// in the worktree that the dashboard was started from.
+	Current bool
+
+	// Worktree is the worktree that the branch is checked out in, if any.
+	Worktree string
+
+	// NeedsRestack is set if the branch is not on top of its base.
+	NeedsRestack bool
+
+	// NeedsPush is set if the branch is out of sync with its upstream.
+	NeedsPush bool
+
+	// Change is the change request submitted for the branch,
+	// or nil if the branch hasn't been submitted.
+	Change *Change
+
+	// Aboves are the branches stacked directly on top of this one.
+	Aboves []*Branch
+}
+
+// Change is a change request submitted for a branch.
+type Change struct {
+	ID  string
+	URL string
+
+	State          forge.ChangeState
+	Draft          bool
+	ReviewDecision forge.ChangeReviewDecision
+
+	// FailingChecks are the CI checks that failed for the change.
+	FailingChecks []*Check
+
+	// OpenThreads are the unresolved review threads on the change.
+	OpenThreads []*Thread
+}
+
+// Check is a CI check run for a change.
+type Check struct {
+	Name       string
+	Conclusion string
+	URL        string
+}
+
+// Thread is a review thread on a change.
+type Thread struct {
+	Author string
+	File   string // empty for threads not on a file
+	Line   int    // 0 if not anchored to a line
+	Body   string
+	URL    string
+}
+
+// Backend loads dashboard pages and runs actions requested from them.
+//
+// Calls to a Backend are serialized.
+type Backend interface {
+	// Load loads the current state of the repository.
+	Load(ctx context.Context) (*Page, error)
+
+	// Restack restacks the given branch and those above it.
+	// It returns messages reported by the operation.
+	Restack(ctx context.Context, branch string) (string, error)
+
+	// Submit submits the given branch.
+	// It returns messages reported by the operation.
+	Submit(ctx context.Context, branch string) (string, error)
+}
+
+// Handler serves the dashboard over HTTP.
+type Handler struct {
+	Log     *silog.Logger // required
+	Backend Backend       // required
+
+	// Token must be included in requests that run actions.
+	// It should be a secret random value unique to this server.
+	Token string // required
+
+	// Refresh is how often the page reloads itself.
+	// If zero, the page doesn't reload.
+	Refresh time.Duration
+
+	mu     sync.Mutex // serializes backend calls
+	notice *notice    // result of the last action, if not yet shown
+
+	tmplOnce sync.Once
+	tmpl     *template.Template
+}
+
+var _ http.Handler = (*Handler)(nil)
+
+// notice reports the result of an action on the next page load.
+type notice struct {
+	Action string
+	Branch string
+	Output string
+	Err    string
+}
+
+// pageData is the data passed to the page template.
+type pageData struct {
+	*Page
+
+	Refresh int // seconds
+	Notice  *notice
+	Error   string // set if the page could not be loaded
+}
+
+// ServeHTTP implements [http.Handler].
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !isLoopbackHost(r.Host) {
+		http.Error(w, "dashboard is only available on localhost", http.StatusForbidden)
+		return
+	}
+
+	var action func(context.Context, string) (string, error)
+	switch r.URL.Path {
+	case "/":
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		h.servePage(w, r)
+		return
+
+	case "/restack":
+		action = h.Backend.Restack
+	case "/submit":
+		action = h.Backend.Submit
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	h.serveAction(w, r, strings.TrimPrefix(r.URL.Path, "/"), action)
+}
+
+func (h *Handler) servePage(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	page, err := h.Backend.Load(r.Context())
+	n := h.notice
+	h.notice = nil
+	h.mu.Unlock()
+
+	data := pageData{
+		Page:    page,
+		Refresh: int(h.Refresh / time.Second),
+		Notice:  n,
+	}
+	if err != nil {
+		h.Log.Error("Could not load dashboard", "error", err)
+		data.Page = &Page{UpdatedAt: time.Now()}
+		data.Error = err.Error()
+	}
+
+	var buf bytes.Buffer
+	if err := h.template().Execute(&buf, data); err != nil {
+		h.Log.Error("Could not render dashboard", "error", err)
+		http.Error(w, "could not render dashboard", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	_, _ = w.Write(buf.Bytes())
+}
+
+func (h *Handler) serveAction(
+	w http.ResponseWriter,
+	r *http.Request,
+	name string,
+	action func(context.Context, string) (string, error),
+) {
+	token := r.PostFormValue("token")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(h.Token)) != 1 {
+		http.Error(w, "invalid or missing token", http.StatusForbidden)
+		return
+	}
+
+	branch := r.PostFormValue("branch")
+	if branch == "" {
+		http.Error(w, "missing branch", http.StatusBadRequest)
+		return
+	}
+
+	h.mu.Lock()
+	h.Log.Info("Running from dashboard", "action", name, "branch", branch)
+	output, err := action(r.Context(), branch)
+	n := &notice{Action: name, Branch: branch, Output: strings.TrimSpace(output)}
+	if err != nil {
+		h.Log.Error("Dashboard action failed", "action", name, "branch", branch, "error", err)
+		n.Err = err.Error()
+	}
+	h.notice = n
+	h.mu.Unlock()
+
+	// Post/Redirect/Get so that reloading the page doesn't repeat the action.
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+//go:embed page.html
+var _pageHTML string
+
+var _templateFuncs = template.FuncMap{
+	"token":   func() string { return "" }, // replaced per Handler
+	"review":  reviewLabel,
+	"class":   func(s string) string { return strings.ReplaceAll(s, " ", "-") },
+	"summary": summarize,
+}
+
+var _pageTemplate = template.Must(
+	template.New("page").Funcs(_templateFuncs).Parse(_pageHTML),
+)
+
+func (h *Handler) template() *template.Template {
+	h.tmplOnce.Do(func() {
+		h.tmpl = template.Must(_pageTemplate.Clone()).Funcs(template.FuncMap{
+			"token": func() string { return h.Token },
+		})
+	})
+	return h.tmpl
+}
+
+func reviewLabel(d forge.ChangeReviewDecision) string {
+	switch d {
+	case forge.ChangeReviewRequired:
+		return "review requested"
+	case forge.ChangeReviewChangesRequested:
+		return "changes requested"
+	case forge.ChangeReviewApproved:
+		return "approved"
+	default:
+		return ""
+	}
+}
+
+// summarize returns the first line of s,
+// truncated to a length suitable for a one-line summary.
+func summarize(s string) string {
+	const maxLen = 100
+
+	s, _, _ = strings.Cut(strings.TrimSpace(s), "\n")
+	if r := []rune(s); len(r) > maxLen {
+		s = string(r[:maxLen-1]) + "…"
+	}
+	return s
+}
+
+// isLoopbackHost reports whether the host part of a Host header
+// refers to the local machine.
+func isLoopbackHost(hostport string) bool {
+	host, _, err := net.SplitHostPort(hostport)
+	if err != nil {
+		host = hostport // no port
+	}
+	return IsLoopback(host)
+}
+
+// IsLoopback reports whether host is "localhost" or a loopback IP address.
+func IsLoopback(host string) bool {
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
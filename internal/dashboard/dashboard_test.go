@@ -0,0 +1,251 @@
+package dashboard
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/silog/silogtest"
+)
+
+type fakeBackend struct {
+	page    *Page
+	loadErr error
+
+	actions   []string // "action branch"
+	actionErr error
+}
+
+var _ Backend = (*fakeBackend)(nil)
+
+func (b *fakeBackend) Load(context.Context) (*Page, error) {
+	return b.page, b.loadErr
+}
+
+func (b *fakeBackend) Restack(_ context.Context, branch string) (string, error) {
+	b.actions = append(b.actions, "restack "+branch)
+	return "feat1: restacked on main\n", b.actionErr
+}
+
+func (b *fakeBackend) Submit(_ context.Context, branch string) (string, error) {
+	b.actions = append(b.actions, "submit "+branch)
+	return "", b.actionErr
+}
+
+func newTestHandler(t *testing.T, backend Backend) *Handler {
+	return &Handler{
+		Log:     silogtest.New(t),
+		Backend: backend,
+		Token:   "secret",
+		Refresh: 30 * time.Second,
+	}
+}
+
+func serve(h http.Handler, req *http.Request) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func postForm(path string, form url.Values) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "http://127.0.0.1:8080"+path, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req
+}
+
+func TestHandler_page(t *testing.T) {
+	backend := &fakeBackend{
+		page: &Page{
+			Trunk: "main",
+			Stacks: []*Branch{
+				{
+					Name:         "feat1",
+					NeedsRestack: true,
+					Change: &Change{
+						ID:             "#1",
+						URL:            "https://example.com/1",
+						State:          forge.ChangeOpen,
+						ReviewDecision: forge.ChangeReviewChangesRequested,
+						FailingChecks: []*Check{
+							{Name: "lint", Conclusion: "failure", URL: "https://example.com/lint"},
+						},
+						OpenThreads: []*Thread{
+							{Author: "bob", File: "main.go", Line: 12, Body: "Rename this.\nAlso, <b>bold</b>."},
+						},
+					},
+					Aboves: []*Branch{
+						{Name: "feat2", Current: true},
+					},
+				},
+				{
+					Name: "old",
+					Change: &Change{
+						ID:    "#3",
+						State: forge.ChangeMerged,
+					},
+				},
+			},
+			Warnings: []string{"checks unavailable"},
+		},
+	}
+	h := newTestHandler(t, backend)
+
+	rec := serve(h, httptest.NewRequest(http.MethodGet, "http://localhost:8080/", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	body := rec.Body.String()
+
+	assert.Contains(t, body, `<meta http-equiv="refresh" content="30">`)
+	assert.Contains(t, body, `<a href="https://example.com/1"`)
+	assert.Contains(t, body, `changes requested`)
+	assert.Contains(t, body, `1 failing`)
+	assert.Contains(t, body, `main.go:12`)
+	assert.Contains(t, body, `Rename this.`)
+	assert.NotContains(t, body, `<b>bold</b>`, "only the first line is shown")
+	assert.Contains(t, body, `checks unavailable`)
+	assert.Contains(t, body, `class="branch current"`)
+	assert.Contains(t, body, `name="token" value="secret"`)
+
+	// Only feat1 needs restacking,
+	// and merged changes can't be submitted.
+	assert.Equal(t, 1, strings.Count(body, `action="/restack"`))
+	assert.Equal(t, 2, strings.Count(body, `action="/submit"`))
+}
+
+func TestHandler_loadError(t *testing.T) {
+	h := newTestHandler(t, &fakeBackend{loadErr: errors.New("great sadness")})
+
+	rec := serve(h, httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "Could not load branches: great sadness")
+}
+
+func TestHandler_action(t *testing.T) {
+	backend := &fakeBackend{page: &Page{Trunk: "main"}}
+	h := newTestHandler(t, backend)
+
+	rec := serve(h, postForm("/restack", url.Values{
+		"token":  {"secret"},
+		"branch": {"feat1"},
+	}))
+	assert.Equal(t, http.StatusSeeOther, rec.Code)
+	assert.Equal(t, "/", rec.Header().Get("Location"))
+	assert.Equal(t, []string{"restack feat1"}, backend.actions)
+
+	// The result is shown once.
+	rec = serve(h, httptest.NewRequest(http.MethodGet, "http://127.0.0.1:8080/", nil))
+	assert.Contains(t, rec.Body.String(), "restack feat1: done")
+	assert.Contains(t, rec.Body.String(), "feat1: restacked on main")
+
+	rec = serve(h, httptest.NewRequest(http.MethodGet, "http://127.0.0.1:8080/", nil))
+	assert.NotContains(t, rec.Body.String(), "restack feat1")
+}
+
+func TestHandler_actionError(t *testing.T) {
+	backend := &fakeBackend{
+		page:      &Page{Trunk: "main"},
+		actionErr: errors.New("not logged in"),
+	}
+	h := newTestHandler(t, backend)
+
+	rec := serve(h, postForm("/submit", url.Values{
+		"token":  {"secret"},
+		"branch": {"feat1"},
+	}))
+	assert.Equal(t, http.StatusSeeOther, rec.Code)
+
+	rec = serve(h, httptest.NewRequest(http.MethodGet, "http://127.0.0.1:8080/", nil))
+	assert.Contains(t, rec.Body.String(), "submit feat1 failed: not logged in")
+}
+
+func TestHandler_rejects(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *http.Request
+		want int
+	}{
+		{
+			name: "ForeignHost",
+			req:  httptest.NewRequest(http.MethodGet, "http://evil.example.com/", nil),
+			want: http.StatusForbidden,
+		},
+		{
+			name: "MissingToken",
+			req:  postForm("/restack", url.Values{"branch": {"feat1"}}),
+			want: http.StatusForbidden,
+		},
+		{
+			name: "WrongToken",
+			req:  postForm("/submit", url.Values{"token": {"guess"}, "branch": {"feat1"}}),
+			want: http.StatusForbidden,
+		},
+		{
+			name: "MissingBranch",
+			req:  postForm("/submit", url.Values{"token": {"secret"}}),
+			want: http.StatusBadRequest,
+		},
+		{
+			name: "ActionWithGet",
+			req:  httptest.NewRequest(http.MethodGet, "http://localhost/restack?token=secret&branch=feat1", nil),
+			want: http.StatusMethodNotAllowed,
+		},
+		{
+			name: "PostPage",
+			req:  postForm("/", nil),
+			want: http.StatusMethodNotAllowed,
+		},
+		{
+			name: "UnknownPath",
+			req:  httptest.NewRequest(http.MethodGet, "http://localhost/delete", nil),
+			want: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := &fakeBackend{page: &Page{Trunk: "main"}}
+			rec := serve(newTestHandler(t, backend), tt.req)
+			assert.Equal(t, tt.want, rec.Code)
+			assert.Empty(t, backend.actions)
+		})
+	}
+}
+
+func TestIsLoopback(t *testing.T) {
+	tests := []struct {
+		host string
+		want bool
+	}{
+		{"localhost", true},
+		{"LOCALHOST", true},
+		{"127.0.0.1", true},
+		{"127.1.2.3", true},
+		{"::1", true},
+		{"[::1]", true},
+		{"", false},
+		{"0.0.0.0", false},
+		{"192.168.1.1", false},
+		{"example.com", false},
+		{"localhost.example.com", false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, IsLoopback(tt.host), "IsLoopback(%q)", tt.host)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	assert.Equal(t, "first line", summarize("  first line\nsecond line"))
+
+	long := strings.Repeat("x", 150)
+	got := summarize(long)
+	assert.Equal(t, 100, len([]rune(got)))
+	assert.True(t, strings.HasSuffix(got, "…"))
+}
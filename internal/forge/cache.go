@@ -0,0 +1,26 @@
+package forge
+
+import (
+	"context"
+
+	"go.abhg.dev/gs/internal/forge/forgecache"
+)
+
+// CachedRepositoryOpener is an optional capability implemented by a [Forge]
+// that can reuse repository metadata cached by earlier invocations
+// instead of looking it up every time a repository is opened.
+//
+// Repositories of forges that don't implement this
+// are opened with [Forge.OpenRepository] every time.
+type CachedRepositoryOpener interface {
+	// OpenCachedRepository is a variant of [Forge.OpenRepository]
+	// that reads and writes repository metadata to the given cache.
+	//
+	// The cache is already scoped to the repository.
+	OpenCachedRepository(
+		ctx context.Context,
+		tok AuthenticationToken,
+		repo RepositoryID,
+		cache *forgecache.Cache,
+	) (Repository, error)
+}
@@ -0,0 +1,28 @@
+package forge
+
+// ChangePusher is an optional capability implemented by a [Repository]
+// for forges that create and update changes from commits pushed
+// to a special ref instead of from branches,
+// e.g. Gerrit's refs/for/<branch>.
+//
+// Changes on these forges are stacked by commit ancestry,
+// so they're always proposed against trunk,
+// and the forge shows the stack without navigation comments.
+// Branches on forges that don't implement this
+// are pushed to branches of the same name as usual.
+type ChangePusher interface {
+	// PushRef returns the ref to push a branch's commits to
+	// so that they're proposed as changes against base.
+	//
+	// topic is the name that groups the branch's changes together,
+	// and is used to find them with FindChangesByBranch.
+	PushRef(base, topic string) string
+
+	// PrepareCommitMessage returns the message to use for a commit
+	// before it's pushed with PushRef,
+	// and reports whether it differs from msg.
+	//
+	// Forges use this to add trailers that identify the change
+	// across revisions of the commit.
+	PrepareCommitMessage(msg string) (string, bool)
+}
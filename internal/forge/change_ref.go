@@ -0,0 +1,48 @@
+package forge
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ChangeIDParser is an optional capability implemented by a [Repository]
+// that can parse references to changes entered by users.
+//
+// Changes can only be checked out by reference
+// from forges that implement this.
+type ChangeIDParser interface {
+	// ParseChangeID parses a reference to a change in this repository.
+	// References include the change number (e.g. "123")
+	// and the web URL of the change.
+	ParseChangeID(s string) (ChangeID, error)
+}
+
+// ParseChangeNumber parses a reference to a change by number.
+//
+// s may be a plain number ("123"),
+// a number with the given prefix (e.g. "#123" for prefix "#"),
+// or a URL that starts with changeURL followed by the number
+// (e.g. "https://github.com/owner/repo/pull/123" for changeURL
+// "https://github.com/owner/repo/pull/").
+// Anything after the number in a URL is ignored
+// if it starts with "/", "?", or "#".
+func ParseChangeNumber(s, prefix, changeURL string) (int64, error) {
+	s = strings.TrimSpace(s)
+
+	num := s
+	if rest, ok := strings.CutPrefix(s, changeURL); ok && changeURL != "" {
+		num = rest
+		if idx := strings.IndexAny(num, "/?#"); idx >= 0 {
+			num = num[:idx]
+		}
+	} else if prefix != "" {
+		num = strings.TrimPrefix(num, prefix)
+	}
+
+	n, err := strconv.ParseInt(num, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("not a change number or URL: %q", s)
+	}
+	return n, nil
+}
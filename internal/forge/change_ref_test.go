@@ -0,0 +1,46 @@
+package forge_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/forge"
+)
+
+func TestParseChangeNumber(t *testing.T) {
+	const changeURL = "https://example.com/owner/repo/pull/"
+
+	tests := []struct {
+		give string
+		want int64 // zero if invalid
+	}{
+		{give: "123", want: 123},
+		{give: " 42 ", want: 42},
+		{give: "#123", want: 123},
+		{give: "https://example.com/owner/repo/pull/7", want: 7},
+		{give: "https://example.com/owner/repo/pull/7/files", want: 7},
+		{give: "https://example.com/owner/repo/pull/7#discussion", want: 7},
+		{give: "https://example.com/owner/repo/pull/7?w=1", want: 7},
+		{give: "https://example.com/other/repo/pull/7"},
+		{give: "https://example.com/owner/repo/pull/"},
+		{give: "!123"},
+		{give: "#"},
+		{give: "0"},
+		{give: "-1"},
+		{give: "feature"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.give, func(t *testing.T) {
+			got, err := forge.ParseChangeNumber(tt.give, "#", changeURL)
+			if tt.want == 0 {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
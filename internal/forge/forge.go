@@ -53,21 +53,64 @@ func (r *Registry) Lookup(id string) (Forge, bool) {
 
 // MatchRemoteURL attempts to match the given remote URL with a registered forge.
 // Returns the matched forge, and information about the matched repository.
+//
+// For forges configured with multiple hosts (see [MultiHostForge]),
+// the returned Forge is the one for the host that matched.
 func MatchRemoteURL(r *Registry, remoteURL string) (forge Forge, rid RepositoryID, ok bool) {
 	for f := range r.All() {
-		rid, err := f.ParseRemoteURL(remoteURL)
-		if err == nil {
-			return f, rid, true
+		for _, h := range Hosts(f) {
+			rid, err := h.ParseRemoteURL(remoteURL)
+			if err == nil {
+				return h, rid, true
+			}
 		}
 	}
 	return nil, nil, false
 }
 
+// MultiHostForge is an optional capability implemented by a [Forge]
+// that may be configured to talk to more than one host at a time,
+// e.g. github.com and a GitHub Enterprise instance.
+//
+// Each host is represented by its own Forge
+// that parses remote URLs, authenticates,
+// and stores authentication tokens for that host only.
+type MultiHostForge interface {
+	Forge
+
+	// URL reports the base web URL of the host this Forge talks to.
+	URL() string
+
+	// Hosts returns a Forge for each host configured for this forge,
+	// starting with the default host.
+	//
+	// This may be called on the Forge for any of the hosts
+	// and must return the same list.
+	Hosts() []MultiHostForge
+}
+
+// Hosts returns a Forge for each host configured for the given forge.
+// For forges that do not implement [MultiHostForge],
+// this is just the forge itself.
+func Hosts(f Forge) []Forge {
+	mf, ok := f.(MultiHostForge)
+	if !ok {
+		return []Forge{f}
+	}
+
+	hosts := mf.Hosts()
+	forges := make([]Forge, len(hosts))
+	for i, h := range hosts {
+		forges[i] = h
+	}
+	return forges
+}
+
 // ErrUnsupportedURL indicates that the given remote URL
 // does not match any registered forge.
 var ErrUnsupportedURL = errors.New("unsupported URL")
 
-//go:generate mockgen -destination=forgetest/mocks.go -package forgetest -typed . Forge,RepositoryID,Repository,ReviewThreadLister,ChangeChecksLister,ViewerIdentifier
+//go:generate mockgen -destination=forgetest/mocks.go -package forgetest -typed . Forge,RepositoryID,Repository,ReviewThreadLister,ChangeChecksLister,ViewerIdentifier,IssueLinker,ReviewPoster,ChangeIDParser,TeamMemberLister,ChangeProjectLister
 
 // TODO:
 // Forge should become a struct with multiple interfaces or funcctions
@@ -99,11 +142,10 @@ type Forge interface {
 	ParseRemoteURL(remoteURL string) (RepositoryID, error)
 
 	// OpenRepository opens the remote repository that the given ID points to.
+	//
+	// Forges that can reuse metadata from earlier invocations
+	// also implement [CachedRepositoryOpener].
 	OpenRepository(ctx context.Context, tok AuthenticationToken, repo RepositoryID) (Repository, error)
-	// TODO: For GitHub, to avoid looking up the GQLID for the repository
-	// every time, we need a layer of metadata that Open can provide
-	// that is persisted to the store alongside branch state,
-	// and used in follow-up Open calls to avoid looking it up again.
 
 	// ChangeTemplatePaths reports the case-insensitive paths at which
 	// it's possible to define change templates in the repository.
@@ -298,6 +340,13 @@ type SubmitChangeRequest struct {
 	// This must have already been pushed to the remote.
 	Head string // required
 
+	// HeadHash is the commit at the top of Head that was pushed.
+	//
+	// Forges that create changes from pushed commits
+	// use this to pick the change for Head
+	// when the push created more than one.
+	HeadHash git.Hash
+
 	// Draft specifies whether the change should be marked as a draft.
 	Draft bool
 
@@ -348,6 +397,30 @@ type EditChangeOptions struct {
 	// AddAssignees are new users to assign to the change.
 	// Existing assignees associated with the change will not be modified.
 	AddAssignees []string
+
+	// RemoveLabels are labels to remove from the change.
+	// Labels that aren't applied to the change are ignored.
+	RemoveLabels []string
+
+	// RemoveReviewers are reviewers whose review requests
+	// should be withdrawn from the change.
+	RemoveReviewers []string
+
+	// RemoveAssignees are users to unassign from the change.
+	RemoveAssignees []string
+
+	// Milestone specifies the title of the milestone for the change.
+	//
+	// If nil, the milestone is not changed.
+	// If it points to an empty string, the milestone is removed.
+	Milestone *string
+
+	// AddProjects are the titles of projects to add the change to.
+	AddProjects []string
+
+	// RemoveProjects are the titles of projects to remove the change from.
+	// Projects that the change isn't part of are ignored.
+	RemoveProjects []string
 }
 
 // FindChangeItem is a single result from searching for changes in the
@@ -368,6 +441,10 @@ type FindChangeItem struct {
 	// HeadHash is the hash of the commit at the top of the change.
 	HeadHash git.Hash // required
 
+	// HeadName is the name of the branch that holds the change.
+	// This is empty if the forge does not report it.
+	HeadName string
+
 	// BaseName is the name of the base branch
 	// that this change is proposed against.
 	BaseName string // required
@@ -385,6 +462,10 @@ type FindChangeItem struct {
 	// Assignees are the usernames of users
 	// who are assigned to the change.
 	Assignees []string
+
+	// Milestone is the title of the milestone for the change.
+	// This is empty if the change isn't part of a milestone.
+	Milestone string
 }
 
 // ChangeStatus is a compact status summary for a change.
@@ -65,6 +65,56 @@ func TestRegister(t *testing.T) {
 	})
 }
 
+func TestMatchRemoteURL_multiHost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	newHost := func(url string) *multiHostForge {
+		mockForge := forgetest.NewMockForge(ctrl)
+		mockForge.EXPECT().ID().Return("multi").AnyTimes()
+		mockForge.EXPECT().ParseRemoteURL(gomock.Any()).
+			DoAndReturn(func(remoteURL string) (forge.RepositoryID, error) {
+				if strings.HasPrefix(remoteURL, url+"/") {
+					return forgetest.NewMockRepositoryID(ctrl), nil
+				}
+				return nil, forge.ErrUnsupportedURL
+			}).AnyTimes()
+		return &multiHostForge{Forge: mockForge, url: url}
+	}
+
+	primary := newHost("https://example.com")
+	secondary := newHost("https://example.org")
+	hosts := []forge.MultiHostForge{primary, secondary}
+	primary.hosts = hosts
+	secondary.hosts = hosts
+
+	var registry forge.Registry
+	defer registry.Register(primary)()
+
+	assert.Equal(t, []forge.Forge{primary, secondary}, forge.Hosts(primary))
+
+	f, _, ok := forge.MatchRemoteURL(&registry, "https://example.org/foo")
+	require.True(t, ok, "forge not found")
+	assert.Same(t, secondary, f, "expected forge for the matching host")
+
+	f, _, ok = forge.MatchRemoteURL(&registry, "https://example.com/foo")
+	require.True(t, ok, "forge not found")
+	assert.Same(t, primary, f, "expected forge for the matching host")
+
+	_, _, ok = forge.MatchRemoteURL(&registry, "https://example.net/foo")
+	assert.False(t, ok, "unexpected forge match")
+}
+
+type multiHostForge struct {
+	forge.Forge
+
+	url   string
+	hosts []forge.MultiHostForge
+}
+
+func (f *multiHostForge) URL() string { return f.url }
+
+func (f *multiHostForge) Hosts() []forge.MultiHostForge { return f.hosts }
+
 func TestChangeState(t *testing.T) {
 	tests := []struct {
 		state forge.ChangeState
@@ -0,0 +1,163 @@
+// Package forgecache caches metadata retrieved from forges
+// between git-spice invocations.
+//
+// Values are kept in a file inside the repository's Git directory
+// with an expiry time.
+// They're not part of the git-spice state,
+// so commands that only read information don't change the state.
+// Forges use the cache to avoid looking up information
+// that rarely changes (e.g. repository IDs) on every invocation,
+// and to revalidate responses with conditional requests
+// where the forge supports them.
+package forgecache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"time"
+
+	"go.abhg.dev/gs/internal/silog"
+)
+
+// Cache is a cache of forge metadata.
+//
+// Changes to the cache are held in memory
+// until they're written to disk with [Cache.Flush].
+//
+// A nil Cache is valid and caches nothing.
+// Failures to read from the cache are treated as cache misses.
+type Cache struct {
+	file   *file
+	log    *silog.Logger
+	prefix string
+
+	now func() time.Time // for testing
+}
+
+// New builds a cache stored in the file at the given path.
+// The file is created when the cache is first flushed.
+// Its directory must already exist.
+func New(path string, log *silog.Logger) *Cache {
+	if log == nil {
+		log = silog.Nop()
+	}
+	return &Cache{
+		file: &file{path: path, log: log},
+		log:  log,
+		now:  time.Now,
+	}
+}
+
+// Flush writes changes made to the cache, including all its scopes,
+// to disk in a single write.
+// Entries that expired long ago are evicted,
+// as are the oldest entries if the cache grows too large.
+func (c *Cache) Flush() error {
+	if c == nil {
+		return nil
+	}
+	return c.file.flush(c.now())
+}
+
+// Scope returns a view of the cache
+// where all keys are nested under the given path components.
+// Components are escaped so they may contain any character.
+//
+// Forges should scope the cache by host and repository
+// so that different repositories don't share entries.
+func (c *Cache) Scope(parts ...string) *Cache {
+	if c == nil {
+		return nil
+	}
+
+	escaped := make([]string, 0, len(parts)+1)
+	escaped = append(escaped, c.prefix)
+	for _, p := range parts {
+		escaped = append(escaped, url.PathEscape(p))
+	}
+
+	scoped := *c
+	scoped.prefix = path.Join(escaped...)
+	return &scoped
+}
+
+func (c *Cache) key(key string) string {
+	return path.Join(c.prefix, key)
+}
+
+// load retrieves the entry for a key, fresh or not.
+// It returns nil if there's no entry or it could not be read.
+func (c *Cache) load(key string) *entry {
+	if c == nil {
+		return nil
+	}
+
+	e, err := c.file.get(c.key(key))
+	if err != nil {
+		c.log.Debug("Could not read forge cache", "key", key, "error", err)
+		return nil
+	}
+	return e
+}
+
+// Get decodes the cached value for the given key into v.
+// It reports whether a fresh value was found.
+func (c *Cache) Get(ctx context.Context, key string, v any) bool {
+	e := c.load(key)
+	if e == nil || len(e.Value) == 0 || !c.now().Before(e.Expires) {
+		return false
+	}
+
+	if err := json.Unmarshal(e.Value, v); err != nil {
+		c.log.Debug("Ignoring unreadable forge cache entry", "key", key, "error", err)
+		return false
+	}
+	return true
+}
+
+// Set caches the given values for ttl.
+// Values must be JSON-serializable.
+func (c *Cache) Set(_ context.Context, ttl time.Duration, values map[string]any) error {
+	if c == nil || len(values) == 0 {
+		return nil
+	}
+
+	expires := c.now().Add(ttl)
+	entries := make(map[string]*entry, len(values))
+	for key, v := range values {
+		bs, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("marshal %v: %w", key, err)
+		}
+
+		entries[c.key(key)] = &entry{
+			Value:   bs,
+			Expires: expires,
+		}
+	}
+
+	c.file.set(entries)
+	return nil
+}
+
+// Delete removes the given keys from the cache.
+func (c *Cache) Delete(_ context.Context, keys ...string) error {
+	if c == nil || len(keys) == 0 {
+		return nil
+	}
+
+	entries := make(map[string]*entry, len(keys))
+	for _, key := range keys {
+		entries[c.key(key)] = nil
+	}
+	c.file.set(entries)
+	return nil
+}
+
+// save records a raw entry keyed relative to the scope.
+func (c *Cache) save(key string, e *entry) {
+	c.file.set(map[string]*entry{c.key(key): e})
+}
@@ -0,0 +1,144 @@
+package forgecache
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/silog/silogtest"
+)
+
+func newTestCache(t *testing.T) (*Cache, *time.Time) {
+	return newTestCacheAt(t, filepath.Join(t.TempDir(), FileName))
+}
+
+func newTestCacheAt(t *testing.T, path string) (*Cache, *time.Time) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	cache := New(path, silogtest.New(t))
+	cache.now = func() time.Time { return now }
+	return cache, &now
+}
+
+func TestCache_ttl(t *testing.T) {
+	ctx := t.Context()
+	cache, now := newTestCache(t)
+
+	var got string
+	assert.False(t, cache.Get(ctx, "key", &got), "empty cache")
+
+	require.NoError(t, cache.Set(ctx, time.Minute, map[string]any{"key": "value"}))
+	require.True(t, cache.Get(ctx, "key", &got))
+	assert.Equal(t, "value", got)
+
+	*now = now.Add(time.Minute)
+	assert.False(t, cache.Get(ctx, "key", &got), "expired")
+
+	require.NoError(t, cache.Set(ctx, time.Minute, map[string]any{"key": "new"}))
+	require.NoError(t, cache.Delete(ctx, "key"))
+	assert.False(t, cache.Get(ctx, "key", &got), "deleted")
+}
+
+func TestCache_scope(t *testing.T) {
+	ctx := t.Context()
+	cache, _ := newTestCache(t)
+
+	foo := cache.Scope("github", "alice/foo")
+	bar := cache.Scope("github", "alice/bar")
+	require.NoError(t, foo.Set(ctx, time.Hour, map[string]any{"id": 1}))
+
+	var got int
+	require.True(t, foo.Get(ctx, "id", &got))
+	assert.Equal(t, 1, got)
+	assert.False(t, bar.Get(ctx, "id", &got), "scopes must not share entries")
+	assert.False(t, cache.Get(ctx, "id", &got), "parent must not see scoped entries")
+}
+
+func TestCache_flush(t *testing.T) {
+	ctx := t.Context()
+	path := filepath.Join(t.TempDir(), FileName)
+
+	cache, _ := newTestCacheAt(t, path)
+	require.NoError(t, cache.Flush(), "nothing to flush")
+	assert.NoFileExists(t, path)
+
+	scoped := cache.Scope("github", "alice/foo")
+	require.NoError(t, scoped.Set(ctx, time.Hour, map[string]any{"a": 1}))
+	require.NoError(t, scoped.Set(ctx, time.Hour, map[string]any{"b": 2}))
+	assert.NoFileExists(t, path, "changes must not be written until flushed")
+	require.NoError(t, cache.Flush())
+
+	// Another process writes to the same file in the meantime.
+	other, _ := newTestCacheAt(t, path)
+	require.NoError(t, other.Scope("github", "alice/foo").Delete(ctx, "a"))
+	require.NoError(t, other.Flush())
+
+	require.NoError(t, scoped.Set(ctx, time.Hour, map[string]any{"c": 3}))
+	require.NoError(t, cache.Flush())
+
+	reopened, _ := newTestCacheAt(t, path)
+	reopened = reopened.Scope("github", "alice/foo")
+	var got int
+	assert.False(t, reopened.Get(ctx, "a", &got), "deleted by the other process")
+	require.True(t, reopened.Get(ctx, "b", &got))
+	assert.Equal(t, 2, got)
+	require.True(t, reopened.Get(ctx, "c", &got))
+	assert.Equal(t, 3, got)
+}
+
+func TestCache_evict(t *testing.T) {
+	ctx := t.Context()
+	path := filepath.Join(t.TempDir(), FileName)
+
+	cache, now := newTestCacheAt(t, path)
+	require.NoError(t, cache.Set(ctx, time.Hour, map[string]any{"old": 1}))
+	*now = now.Add(_retention)
+	require.NoError(t, cache.Set(ctx, time.Hour, map[string]any{"new": 2}))
+
+	// Expired, but still within the retention period.
+	require.NoError(t, cache.Flush())
+	bs, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(bs), `"old"`)
+
+	*now = now.Add(2 * time.Hour)
+	require.NoError(t, cache.Set(ctx, time.Hour, map[string]any{"newer": 3}))
+	require.NoError(t, cache.Flush())
+	bs, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(bs), `"old"`)
+	assert.Contains(t, string(bs), `"new"`)
+	assert.Contains(t, string(bs), `"newer"`)
+
+	t.Run("Size", func(t *testing.T) {
+		big := strings.Repeat("x", _maxFileSize/2)
+		require.NoError(t, cache.Set(ctx, time.Hour, map[string]any{"big1": big}))
+		*now = now.Add(time.Minute)
+		require.NoError(t, cache.Set(ctx, time.Hour, map[string]any{"big2": big}))
+		require.NoError(t, cache.Flush())
+
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, info.Size(), int64(_maxFileSize))
+
+		var got string
+		assert.False(t, cache.Get(ctx, "big1", &got), "oldest entry evicted")
+		assert.True(t, cache.Get(ctx, "big2", &got))
+	})
+}
+
+func TestCache_nil(t *testing.T) {
+	ctx := t.Context()
+
+	var cache *Cache
+	cache = cache.Scope("github", "alice/foo")
+
+	var got string
+	assert.False(t, cache.Get(ctx, "key", &got))
+	assert.NoError(t, cache.Set(ctx, time.Hour, map[string]any{"key": "value"}))
+	assert.NoError(t, cache.Delete(ctx, "key"))
+	assert.NoError(t, cache.Flush())
+}
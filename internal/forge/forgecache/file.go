@@ -0,0 +1,195 @@
+package forgecache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"go.abhg.dev/gs/internal/silog"
+)
+
+// FileName is the name of the cache file
+// inside the repository's Git directory.
+const FileName = "spice-forgecache.json"
+
+const (
+	// _retention is how long entries are kept after they expire.
+	// Expired entries may still be revalidated,
+	// so they aren't discarded right away.
+	_retention = 7 * 24 * time.Hour
+
+	// _maxFileSize is the approximate size limit of the cache file.
+	// Entries closest to eviction are dropped to stay under it.
+	_maxFileSize = 4 << 20
+)
+
+// entry is a single cached value.
+type entry struct {
+	// Value is the cached value.
+	Value json.RawMessage `json:"value,omitempty"`
+
+	// ETag is the entity tag reported by the forge for Value, if any.
+	// It may be used to revalidate the entry after it has expired.
+	ETag string `json:"etag,omitempty"`
+
+	// Expires is the time after which the entry is stale.
+	Expires time.Time `json:"expires,omitzero"`
+}
+
+func (e *entry) size() int {
+	// Rough estimate of the serialized size,
+	// not counting the key and JSON overhead.
+	return len(e.Value) + len(e.ETag) + 64
+}
+
+// file is the cache file shared by all scopes of a Cache.
+//
+// Changes are held in memory and written in one go by flush,
+// merged with changes made by other processes in the meantime.
+type file struct {
+	path string
+	log  *silog.Logger
+
+	mu      sync.Mutex
+	entries map[string]*entry // nil until loaded
+	pending map[string]*entry // nil values are deletions
+}
+
+func (f *file) get(key string) (*entry, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if e, ok := f.pending[key]; ok {
+		return e, nil
+	}
+
+	if f.entries == nil {
+		entries, err := f.read()
+		if err != nil {
+			return nil, err
+		}
+		f.entries = entries
+	}
+	return f.entries[key], nil
+}
+
+func (f *file) set(entries map[string]*entry) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.pending == nil {
+		f.pending = make(map[string]*entry, len(entries))
+	}
+	maps.Copy(f.pending, entries)
+}
+
+// flush writes pending changes to the file,
+// evicting entries that are no longer useful.
+// It does nothing if there are no pending changes.
+func (f *file) flush(now time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(f.pending) == 0 {
+		return nil
+	}
+
+	// Re-read the file to pick up changes made by other processes.
+	entries, err := f.read()
+	if err != nil {
+		f.log.Debug("Discarding unreadable forge cache", "path", f.path, "error", err)
+		entries = make(map[string]*entry)
+	}
+	for key, e := range f.pending {
+		if e == nil {
+			delete(entries, key)
+		} else {
+			entries[key] = e
+		}
+	}
+	evict(entries, now)
+
+	bs, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("marshal forge cache: %w", err)
+	}
+
+	// Write to a temporary file and rename it into place
+	// so that concurrent readers never see a partial file.
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), FileName+".*")
+	if err != nil {
+		return fmt.Errorf("create forge cache: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(bs); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("write forge cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write forge cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), f.path); err != nil {
+		return fmt.Errorf("replace forge cache: %w", err)
+	}
+
+	f.entries = entries
+	f.pending = nil
+	return nil
+}
+
+// read reads all entries from the file.
+// A missing file is an empty cache.
+func (f *file) read() (map[string]*entry, error) {
+	bs, err := os.ReadFile(f.path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return make(map[string]*entry), nil
+		}
+		return nil, err
+	}
+
+	var entries map[string]*entry
+	if err := json.Unmarshal(bs, &entries); err != nil {
+		return nil, fmt.Errorf("decode %v: %w", f.path, err)
+	}
+	if entries == nil {
+		entries = make(map[string]*entry)
+	}
+	return entries, nil
+}
+
+// evict removes entries that expired more than _retention ago,
+// and if the remaining entries are too large,
+// those that expire the soonest.
+func evict(entries map[string]*entry, now time.Time) {
+	var size int
+	for key, e := range entries {
+		if e == nil || now.After(e.Expires.Add(_retention)) {
+			delete(entries, key)
+			continue
+		}
+		size += len(key) + e.size()
+	}
+	if size <= _maxFileSize {
+		return
+	}
+
+	keys := slices.SortedFunc(maps.Keys(entries), func(a, b string) int {
+		return entries[a].Expires.Compare(entries[b].Expires)
+	})
+	for _, key := range keys {
+		if size <= _maxFileSize {
+			break
+		}
+		size -= len(key) + entries[key].size()
+		delete(entries, key)
+	}
+}
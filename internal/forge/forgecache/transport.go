@@ -0,0 +1,155 @@
+package forgecache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// _maxCachedBody is the largest response body
+// that will be cached for revalidation.
+// Larger responses are passed through without caching.
+const _maxCachedBody = 256 << 10
+
+// _httpDir is the directory under the cache's scope
+// that holds responses cached for revalidation.
+const _httpDir = "http"
+
+// cachedResponse is a response cached for revalidation.
+type cachedResponse struct {
+	Header http.Header `json:"header,omitempty"`
+	Body   []byte      `json:"body,omitempty"`
+}
+
+// Transport wraps an HTTP transport to make conditional requests.
+//
+// Responses to GET requests that carry an ETag are cached.
+// Later requests for the same resource send the ETag in If-None-Match,
+// and if the forge reports that the resource hasn't changed
+// (304 Not Modified), the cached response is returned in its place.
+// Forges like GitHub don't count these against the API rate limit.
+//
+// If the cache is nil, Transport returns the base transport unchanged.
+// If base is nil, [http.DefaultTransport] is used.
+func (c *Cache) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if c == nil {
+		return base
+	}
+	return &transport{cache: c.Scope(_httpDir), base: base}
+}
+
+type transport struct {
+	cache *Cache
+	base  http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Leave requests that are already conditional alone.
+	if req.Method != http.MethodGet || req.Header.Get("If-None-Match") != "" {
+		return t.base.RoundTrip(req)
+	}
+
+	ctx := req.Context()
+	key := requestKey(req)
+	cachedEntry := t.cache.load(key)
+
+	var cached *cachedResponse
+	if cachedEntry != nil && cachedEntry.ETag != "" {
+		if err := json.Unmarshal(cachedEntry.Value, &cached); err == nil {
+			req = req.Clone(ctx)
+			req.Header.Set("If-None-Match", cachedEntry.ETag)
+		} else {
+			cached = nil
+		}
+	}
+
+	res, err := t.base.RoundTrip(req)
+	if err != nil {
+		return res, err
+	}
+
+	switch {
+	case res.StatusCode == http.StatusNotModified && cached != nil:
+		_ = res.Body.Close()
+		t.cache.log.Debug("Using revalidated response from cache", "url", req.URL.Redacted())
+
+		// A 304 response carries updated metadata (e.g. rate limits)
+		// for the cached response.
+		header := cached.Header.Clone()
+		if header == nil {
+			header = make(http.Header)
+		}
+		for k, vs := range res.Header {
+			header[k] = vs
+		}
+		header.Set("Content-Length", strconv.Itoa(len(cached.Body)))
+
+		return &http.Response{
+			Status:        "200 OK",
+			StatusCode:    http.StatusOK,
+			Proto:         res.Proto,
+			ProtoMajor:    res.ProtoMajor,
+			ProtoMinor:    res.ProtoMinor,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader(cached.Body)),
+			ContentLength: int64(len(cached.Body)),
+			Request:       res.Request,
+		}, nil
+
+	case res.StatusCode == http.StatusOK:
+		etag := res.Header.Get("ETag")
+		if etag == "" || (cachedEntry != nil && cachedEntry.ETag == etag) {
+			return res, nil
+		}
+
+		body, err := io.ReadAll(io.LimitReader(res.Body, _maxCachedBody+1))
+		if err != nil {
+			_ = res.Body.Close()
+			return nil, err
+		}
+		if len(body) > _maxCachedBody {
+			// Too big to cache. Stitch the body back together.
+			res.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.MultiReader(bytes.NewReader(body), res.Body), res.Body}
+			return res, nil
+		}
+		_ = res.Body.Close()
+		res.Body = io.NopCloser(bytes.NewReader(body))
+
+		header := res.Header.Clone()
+		header.Del("Set-Cookie")
+		value, err := json.Marshal(cachedResponse{Header: header, Body: body})
+		if err != nil {
+			t.cache.log.Debug("Could not cache response", "url", req.URL.Redacted(), "error", err)
+			break
+		}
+
+		// The response must be revalidated before every use,
+		// so the entry is stale right away.
+		// It's kept around for revalidation until it's evicted.
+		t.cache.save(key, &entry{Value: value, ETag: etag, Expires: t.cache.now()})
+	}
+
+	return res, nil
+}
+
+// requestKey identifies the resource requested by req.
+// Credentials are part of the key because they may affect the response,
+// so they're hashed along with everything else.
+func requestKey(req *http.Request) string {
+	h := sha256.New()
+	_, _ = io.WriteString(h, req.URL.String())
+	for _, name := range []string{"Accept", "Authorization", "Private-Token", "Authentication-Token"} {
+		_, _ = io.WriteString(h, "\n"+name+": "+req.Header.Get(name))
+	}
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
@@ -0,0 +1,118 @@
+package forgecache
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransport_revalidate(t *testing.T) {
+	var (
+		body        atomic.Value
+		notModified atomic.Int32
+	)
+	body.Store(`{"state":"open"}`)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b := body.Load().(string)
+		etag := `"` + b + `"`
+		w.Header().Set("X-Total-Pages", "1")
+		if r.Header.Get("If-None-Match") == etag {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, b)
+	}))
+	t.Cleanup(srv.Close)
+
+	cache, _ := newTestCache(t)
+	client := &http.Client{Transport: cache.Scope("test").Transport(nil)}
+
+	get := func() (string, http.Header) {
+		t.Helper()
+
+		res, err := client.Get(srv.URL + "/change/1")
+		require.NoError(t, err)
+		defer func() { _ = res.Body.Close() }()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		bs, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		return string(bs), res.Header
+	}
+
+	got, _ := get()
+	assert.Equal(t, `{"state":"open"}`, got)
+	assert.Zero(t, notModified.Load())
+
+	got, header := get()
+	assert.Equal(t, `{"state":"open"}`, got)
+	assert.Equal(t, int32(1), notModified.Load(), "should revalidate")
+	assert.Equal(t, "application/json", header.Get("Content-Type"))
+	assert.Equal(t, "1", header.Get("X-Total-Pages"))
+
+	body.Store(`{"state":"merged"}`)
+	got, _ = get()
+	assert.Equal(t, `{"state":"merged"}`, got)
+	assert.Equal(t, int32(1), notModified.Load())
+
+	got, _ = get()
+	assert.Equal(t, `{"state":"merged"}`, got)
+	assert.Equal(t, int32(2), notModified.Load())
+}
+
+func TestTransport_largeBody(t *testing.T) {
+	large := strings.Repeat("x", _maxCachedBody+1)
+
+	var conditional atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") != "" {
+			conditional.Add(1)
+		}
+		w.Header().Set("ETag", `"large"`)
+		_, _ = io.WriteString(w, large)
+	}))
+	t.Cleanup(srv.Close)
+
+	cache, _ := newTestCache(t)
+	client := &http.Client{Transport: cache.Transport(nil)}
+
+	for range 2 {
+		res, err := client.Get(srv.URL)
+		require.NoError(t, err)
+		bs, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		assert.Equal(t, large, string(bs))
+	}
+	assert.Zero(t, conditional.Load(), "large responses should not be cached")
+}
+
+func TestTransport_nonGET(t *testing.T) {
+	var conditional atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") != "" {
+			conditional.Add(1)
+		}
+		w.Header().Set("ETag", `"x"`)
+		_, _ = io.WriteString(w, "ok")
+	}))
+	t.Cleanup(srv.Close)
+
+	cache, _ := newTestCache(t)
+	client := &http.Client{Transport: cache.Transport(nil)}
+	for range 2 {
+		res, err := client.Post(srv.URL, "text/plain", strings.NewReader("body"))
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+	}
+	assert.Zero(t, conditional.Load())
+}
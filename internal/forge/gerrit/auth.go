@@ -0,0 +1,136 @@
+package gerrit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/secret"
+	"go.abhg.dev/gs/internal/ui"
+)
+
+// AuthenticationToken defines the credentials used by the Gerrit forge.
+//
+// Gerrit authenticates REST API requests with HTTP basic authentication
+// using the account's username and HTTP password
+// (generated under Settings > HTTP Credentials).
+type AuthenticationToken struct {
+	forge.AuthenticationToken
+
+	// Username is the Gerrit account's username.
+	Username string `json:"username,omitempty"` // required
+
+	// Password is the Gerrit account's HTTP password.
+	Password string `json:"password,omitempty"` // required
+
+	// fromEnv is true if the credentials were set
+	// with environment variables.
+	fromEnv bool
+}
+
+var _ forge.AuthenticationToken = (*AuthenticationToken)(nil)
+
+// envToken returns credentials set with environment variables, if any.
+func (f *Forge) envToken() *AuthenticationToken {
+	if f.Options.Username == "" || f.Options.Password == "" {
+		return nil
+	}
+	return &AuthenticationToken{
+		Username: f.Options.Username,
+		Password: f.Options.Password,
+		fromEnv:  true,
+	}
+}
+
+// AuthenticationFlow prompts the user for their Gerrit HTTP credentials.
+// This rejects the request if the user is already authenticated
+// with GERRIT_USERNAME and GERRIT_PASSWORD.
+func (f *Forge) AuthenticationFlow(_ context.Context, view ui.View) (forge.AuthenticationToken, error) {
+	log := f.logger()
+	if f.envToken() != nil {
+		log.Error("Already authenticated with GERRIT_USERNAME and GERRIT_PASSWORD.")
+		log.Error("Unset them to login with a different method.")
+		return nil, errors.New("already authenticated")
+	}
+	if f.URL() == "" {
+		return nil, errors.New("no Gerrit URL configured: set spice.forge.gerrit.url")
+	}
+
+	required := func(what string) func(string) error {
+		return func(input string) error {
+			if strings.TrimSpace(input) == "" {
+				return errors.New(what + " is required")
+			}
+			return nil
+		}
+	}
+
+	var tok AuthenticationToken
+	err := ui.Run(view,
+		ui.NewInput().
+			WithTitle("Username").
+			WithDescription("Your username on "+f.URL()).
+			WithValidate(required("username")).
+			WithValue(&tok.Username),
+		ui.NewInput().
+			WithTitle("HTTP password").
+			WithDescription("Generate one at "+f.URL()+"/settings/#HTTPCredentials").
+			WithValidate(required("password")).
+			WithValue(&tok.Password),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tok, nil
+}
+
+// SaveAuthenticationToken saves the given authentication token to the stash.
+func (f *Forge) SaveAuthenticationToken(stash secret.Stash, t forge.AuthenticationToken) error {
+	tok := t.(*AuthenticationToken)
+	if tok.fromEnv {
+		// Credentials from the environment are never saved.
+		return nil
+	}
+	if tok.Username == "" || tok.Password == "" {
+		return errors.New("username and password are required")
+	}
+
+	bs, err := json.Marshal(tok)
+	if err != nil {
+		return fmt.Errorf("marshal token: %w", err)
+	}
+
+	f.logger().Debug("Saving authentication token to local secret storage")
+	return stash.SaveSecret(f.URL(), "token", string(bs))
+}
+
+// LoadAuthenticationToken loads the authentication token from the stash.
+// If the user has set GERRIT_USERNAME and GERRIT_PASSWORD,
+// they will be used instead.
+func (f *Forge) LoadAuthenticationToken(stash secret.Stash) (forge.AuthenticationToken, error) {
+	if tok := f.envToken(); tok != nil {
+		return tok, nil
+	}
+
+	tokstr, err := stash.LoadSecret(f.URL(), "token")
+	if err != nil {
+		return nil, fmt.Errorf("load token: %w", err)
+	}
+
+	var tok AuthenticationToken
+	if err := json.Unmarshal([]byte(tokstr), &tok); err != nil {
+		return nil, fmt.Errorf("unmarshal token: %w", err)
+	}
+
+	return &tok, nil
+}
+
+// ClearAuthenticationToken removes the authentication token from the stash.
+func (f *Forge) ClearAuthenticationToken(stash secret.Stash) error {
+	f.logger().Debug("Clearing authentication token from local secret storage")
+	return stash.DeleteSecret(f.URL(), "token")
+}
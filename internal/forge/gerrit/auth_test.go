@@ -0,0 +1,88 @@
+package gerrit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/secret"
+	"go.abhg.dev/gs/internal/silog/silogtest"
+	"go.abhg.dev/gs/internal/ui/uitest"
+)
+
+func TestAuthSaveAndLoad(t *testing.T) {
+	f := Forge{
+		Options: Options{URL: "https://review.example.com"},
+		Log:     silogtest.New(t),
+	}
+
+	var stash secret.MemoryStash
+	t.Run("DoesNotExist", func(t *testing.T) {
+		_, err := f.LoadAuthenticationToken(&stash)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, secret.ErrNotFound)
+	})
+
+	t.Run("MissingPassword", func(t *testing.T) {
+		err := f.SaveAuthenticationToken(&stash, &AuthenticationToken{Username: "user"})
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "username and password are required")
+	})
+
+	require.NoError(t, f.SaveAuthenticationToken(&stash, &AuthenticationToken{
+		Username: "user",
+		Password: "pass",
+	}))
+
+	t.Run("Exists", func(t *testing.T) {
+		tok, err := f.LoadAuthenticationToken(&stash)
+		require.NoError(t, err)
+		assert.Equal(t, &AuthenticationToken{
+			Username: "user",
+			Password: "pass",
+		}, tok)
+	})
+
+	t.Run("Env", func(t *testing.T) {
+		f := f
+		f.Options.Username = "envuser"
+		f.Options.Password = "envpass"
+
+		tok, err := f.LoadAuthenticationToken(&stash)
+		require.NoError(t, err)
+		assert.Equal(t, "envuser", tok.(*AuthenticationToken).Username)
+
+		// Saving credentials from the environment is a no-op.
+		require.NoError(t, f.SaveAuthenticationToken(&stash, tok))
+		saved, err := stash.LoadSecret(f.URL(), "token")
+		require.NoError(t, err)
+		assert.NotContains(t, saved, "envuser")
+	})
+
+	require.NoError(t, f.ClearAuthenticationToken(&stash))
+	_, err := f.LoadAuthenticationToken(&stash)
+	assert.ErrorIs(t, err, secret.ErrNotFound)
+}
+
+func TestAuthenticationFlow_alreadyAuthenticated(t *testing.T) {
+	f := Forge{
+		Options: Options{
+			URL:      "https://review.example.com",
+			Username: "user",
+			Password: "pass",
+		},
+		Log: silogtest.New(t),
+	}
+
+	_, err := f.AuthenticationFlow(t.Context(), uitest.NewEmulatorView(nil))
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "already authenticated")
+}
+
+func TestAuthenticationFlow_noURL(t *testing.T) {
+	f := Forge{Log: silogtest.New(t)}
+
+	_, err := f.AuthenticationFlow(t.Context(), uitest.NewEmulatorView(nil))
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "no Gerrit URL configured")
+}
@@ -0,0 +1,96 @@
+package gerrit
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// _changeIDKey is the trailer key for Gerrit Change-Ids.
+const _changeIDKey = "Change-Id"
+
+var (
+	// _changeIDRe matches a valid Change-Id value.
+	_changeIDRe = regexp.MustCompile(`^I[0-9a-fA-F]{40}$`)
+
+	// _trailerRe matches a single trailer line, e.g. "Signed-off-by: ...".
+	_trailerRe = regexp.MustCompile(`^[A-Za-z0-9-]+:\s`)
+)
+
+// _changeIDRand is the source of randomness for new Change-Ids.
+var _changeIDRand io.Reader = rand.Reader // var for testing
+
+// PrepareCommitMessage adds a Change-Id trailer to msg
+// if it doesn't already have one.
+//
+// Gerrit uses the Change-Id to match new revisions of a commit
+// with the change that was created for it.
+func (r *Repository) PrepareCommitMessage(msg string) (string, bool) {
+	if _, ok := ChangeID(msg); ok {
+		return msg, false
+	}
+
+	return addTrailer(msg, _changeIDKey+": "+newChangeID()), true
+}
+
+// ChangeID returns the Change-Id recorded in the trailers
+// of the given commit message.
+// It reports false if the message doesn't have a Change-Id.
+func ChangeID(msg string) (string, bool) {
+	for _, line := range trailerLines(msg) {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok || !strings.EqualFold(key, _changeIDKey) {
+			continue
+		}
+
+		value = strings.TrimSpace(value)
+		if _changeIDRe.MatchString(value) {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// newChangeID generates a new random Change-Id.
+func newChangeID() string {
+	var buf [20]byte
+	if _, err := io.ReadFull(_changeIDRand, buf[:]); err != nil {
+		// crypto/rand never fails.
+		panic(err)
+	}
+	return "I" + hex.EncodeToString(buf[:])
+}
+
+// trailerLines returns the lines in the trailer block of msg:
+// the last paragraph, if it isn't the subject,
+// and consists only of "Key: value" lines.
+func trailerLines(msg string) []string {
+	msg = strings.TrimRight(msg, " \t\n")
+	idx := strings.LastIndex(msg, "\n\n")
+	if idx < 0 {
+		// The only paragraph is the subject.
+		return nil
+	}
+
+	lines := strings.Split(msg[idx+2:], "\n")
+	for _, line := range lines {
+		if !_trailerRe.MatchString(line) {
+			return nil
+		}
+	}
+	return lines
+}
+
+// addTrailer adds a trailer line to the end of msg,
+// joining the existing trailer block if there is one.
+func addTrailer(msg, trailer string) string {
+	hasTrailers := len(trailerLines(msg)) > 0
+
+	msg = strings.TrimRight(msg, " \t\n")
+	if hasTrailers {
+		return msg + "\n" + trailer + "\n"
+	}
+	return msg + "\n\n" + trailer + "\n"
+}
@@ -0,0 +1,103 @@
+package gerrit
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChangeID(t *testing.T) {
+	const id = "I0123456789abcdef0123456789abcdef01234567"
+
+	tests := []struct {
+		name string
+		msg  string
+
+		want   string
+		wantOK bool
+	}{
+		{name: "SubjectOnly", msg: "Add feature\n"},
+		{
+			name: "SubjectLooksLikeTrailer",
+			msg:  "Change-Id: " + id + "\n",
+		},
+		{
+			name:   "Trailer",
+			msg:    "Add feature\n\nChange-Id: " + id + "\n",
+			want:   id,
+			wantOK: true,
+		},
+		{
+			name:   "MultipleTrailers",
+			msg:    "Add feature\n\nBody.\n\nSigned-off-by: A <a@example.com>\nChange-Id: " + id,
+			want:   id,
+			wantOK: true,
+		},
+		{
+			name: "NotInTrailerBlock",
+			msg:  "Add feature\n\nChange-Id: " + id + "\n\nMore body.\n",
+		},
+		{
+			name: "Invalid",
+			msg:  "Add feature\n\nChange-Id: I1234\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ChangeID(tt.msg)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPrepareCommitMessage(t *testing.T) {
+	oldRand := _changeIDRand
+	t.Cleanup(func() { _changeIDRand = oldRand })
+	_changeIDRand = bytes.NewReader(bytes.Repeat([]byte{0xab}, 1024))
+
+	const wantID = "Iabababababababababababababababababababab"
+
+	var repo Repository
+	tests := []struct {
+		name string
+		msg  string
+
+		want        string
+		wantChanged bool
+	}{
+		{
+			name:        "SubjectOnly",
+			msg:         "Add feature\n",
+			want:        "Add feature\n\nChange-Id: " + wantID + "\n",
+			wantChanged: true,
+		},
+		{
+			name:        "Body",
+			msg:         "Add feature\n\nThis adds a feature.\n",
+			want:        "Add feature\n\nThis adds a feature.\n\nChange-Id: " + wantID + "\n",
+			wantChanged: true,
+		},
+		{
+			name:        "ExistingTrailers",
+			msg:         "Add feature\n\nSigned-off-by: A <a@example.com>\n",
+			want:        "Add feature\n\nSigned-off-by: A <a@example.com>\nChange-Id: " + wantID + "\n",
+			wantChanged: true,
+		},
+		{
+			name: "HasChangeID",
+			msg:  "Add feature\n\nChange-Id: I0123456789abcdef0123456789abcdef01234567\n",
+			want: "Add feature\n\nChange-Id: I0123456789abcdef0123456789abcdef01234567\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed := repo.PrepareCommitMessage(tt.msg)
+			assert.Equal(t, tt.wantChanged, changed)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package gerrit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"go.abhg.dev/gs/internal/forge"
+)
+
+// ChangeMetadata is the metadata for a Gerrit change
+// persisted in git-spice's data store.
+type ChangeMetadata struct {
+	// Change is the change this metadata is for.
+	Change *Change `json:"change,omitempty"`
+
+	// NavigationComment is the message on the change
+	// where we visualize the stack of changes.
+	//
+	// git-spice doesn't post navigation comments on Gerrit,
+	// but this is tracked for parity with other forges.
+	NavigationComment *ChangeComment `json:"comment,omitempty"`
+}
+
+var _ forge.ChangeMetadata = (*ChangeMetadata)(nil)
+
+// ForgeID reports the forge ID that owns this metadata.
+func (*ChangeMetadata) ForgeID() string {
+	return "gerrit"
+}
+
+// ChangeID reports the change ID of the change.
+func (m *ChangeMetadata) ChangeID() forge.ChangeID {
+	return m.Change
+}
+
+// NavigationCommentID reports the ID of the navigation comment
+// left on the change.
+func (m *ChangeMetadata) NavigationCommentID() forge.ChangeCommentID {
+	if m.NavigationComment == nil {
+		return nil
+	}
+	return m.NavigationComment
+}
+
+// SetNavigationCommentID sets the ID of the navigation comment
+// left on the change.
+//
+// id may be nil.
+func (m *ChangeMetadata) SetNavigationCommentID(id forge.ChangeCommentID) {
+	m.NavigationComment = mustChangeComment(id)
+}
+
+// NewChangeMetadata returns the metadata for a change.
+func (r *Repository) NewChangeMetadata(_ context.Context, id forge.ChangeID) (forge.ChangeMetadata, error) {
+	return &ChangeMetadata{Change: mustChange(id)}, nil
+}
+
+// MarshalChangeMetadata serializes a ChangeMetadata into JSON.
+func (*Forge) MarshalChangeMetadata(md forge.ChangeMetadata) (json.RawMessage, error) {
+	return json.Marshal(md)
+}
+
+// UnmarshalChangeMetadata deserializes a ChangeMetadata from JSON.
+func (*Forge) UnmarshalChangeMetadata(data json.RawMessage) (forge.ChangeMetadata, error) {
+	var md ChangeMetadata
+	if err := json.Unmarshal(data, &md); err != nil {
+		return nil, fmt.Errorf("unmarshal change metadata: %w", err)
+	}
+	return &md, nil
+}
+
+// MarshalChangeID serializes a Change into JSON.
+func (*Forge) MarshalChangeID(id forge.ChangeID) (json.RawMessage, error) {
+	return json.Marshal(mustChange(id))
+}
+
+// UnmarshalChangeID deserializes a Change from JSON.
+func (*Forge) UnmarshalChangeID(data json.RawMessage) (forge.ChangeID, error) {
+	var id Change
+	if err := json.Unmarshal(data, &id); err != nil {
+		return nil, fmt.Errorf("unmarshal change ID: %w", err)
+	}
+	return &id, nil
+}
+
+// Change uniquely identifies a change in Gerrit.
+// It's a valid forge.ChangeID.
+type Change struct {
+	// Number is the change number.
+	// This will always be set.
+	Number int64 `json:"number"` // required
+}
+
+var _ forge.ChangeID = (*Change)(nil)
+
+func mustChange(id forge.ChangeID) *Change {
+	c, ok := id.(*Change)
+	if !ok {
+		panic(fmt.Sprintf("gerrit: expected *Change, got %T", id))
+	}
+	return c
+}
+
+// String returns the change number in the form "c/123",
+// matching Gerrit's short URLs for changes.
+func (c *Change) String() string {
+	return fmt.Sprintf("c/%d", c.Number)
+}
@@ -0,0 +1,21 @@
+package gerrit
+
+import (
+	"fmt"
+
+	"go.abhg.dev/gs/internal/forge"
+)
+
+var _ forge.ChangeIDParser = (*Repository)(nil)
+
+// ParseChangeID parses a reference to a change in this project.
+//
+// Changes may be referenced by number ("123") or by URL.
+func (r *Repository) ParseChangeID(s string) (forge.ChangeID, error) {
+	changeURL := fmt.Sprintf("%s/c/%s/+/", r.forge.URL(), r.project)
+	n, err := forge.ParseChangeNumber(s, "", changeURL)
+	if err != nil {
+		return nil, err
+	}
+	return &Change{Number: n}, nil
+}
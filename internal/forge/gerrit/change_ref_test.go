@@ -0,0 +1,41 @@
+package gerrit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/forge"
+)
+
+func TestRepository_ParseChangeID(t *testing.T) {
+	repo := &Repository{
+		project: "my/project",
+		forge:   &Forge{Options: Options{URL: "https://review.example.com/"}},
+	}
+
+	tests := []struct {
+		give string
+		want int64 // zero if invalid
+	}{
+		{give: "123", want: 123},
+		{give: "https://review.example.com/c/my/project/+/9", want: 9},
+		{give: "https://review.example.com/c/my/project/+/9/2", want: 9},
+		{give: "https://review.example.com/c/other/+/9"},
+		{give: "#123"},
+		{give: "I0123456789abcdef0123456789abcdef01234567"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.give, func(t *testing.T) {
+			got, err := repo.ParseChangeID(tt.give)
+			if tt.want == 0 {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, forge.ChangeID(&Change{Number: tt.want}), got)
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package gerrit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"go.abhg.dev/gs/internal/forge/ratelimit"
+	"go.abhg.dev/gs/internal/tracing"
+)
+
+// _xssiPrefix is prepended to all JSON responses from Gerrit
+// to prevent cross-site script inclusion.
+//
+// https://gerrit-review.googlesource.com/Documentation/rest-api.html#output
+const _xssiPrefix = ")]}'"
+
+// client is a minimal client for Gerrit's REST API.
+type client struct {
+	baseURL string // without trailing slash
+	http    *http.Client
+
+	// username and password for HTTP basic authentication.
+	// Requests are anonymous if username is empty.
+	username, password string
+}
+
+func newClient(baseURL string, tok *AuthenticationToken, httpClient *http.Client) (*client, error) {
+	if _, err := url.Parse(baseURL); err != nil {
+		return nil, fmt.Errorf("bad Gerrit URL: %w", err)
+	}
+	if httpClient == nil {
+		httpClient = &http.Client{
+			Transport: ratelimit.WrapTransport(tracing.WrapTransport(nil), nil),
+		}
+	}
+
+	c := &client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		http:    httpClient,
+	}
+	if tok != nil {
+		c.username = tok.Username
+		c.password = tok.Password
+	}
+	return c, nil
+}
+
+// Get sends a GET request to the given API path
+// and decodes the JSON response into dst.
+func (c *client) Get(ctx context.Context, path string, query url.Values, dst any) error {
+	return c.do(ctx, http.MethodGet, path, query, nil, dst)
+}
+
+// Post sends a POST request to the given API path with a JSON body
+// and decodes the JSON response into dst if it is non-nil.
+func (c *client) Post(ctx context.Context, path string, body, dst any) error {
+	return c.do(ctx, http.MethodPost, path, nil, body, dst)
+}
+
+// Put sends a PUT request to the given API path with a JSON body
+// and decodes the JSON response into dst if it is non-nil.
+func (c *client) Put(ctx context.Context, path string, body, dst any) error {
+	return c.do(ctx, http.MethodPut, path, nil, body, dst)
+}
+
+// do sends a request to the given API path.
+//
+// path is relative to the API root and must already be escaped,
+// e.g. "changes/my%2Fproject~123".
+func (c *client) do(
+	ctx context.Context,
+	method, path string,
+	query url.Values,
+	body, dst any,
+) error {
+	reqURL := c.baseURL + "/"
+	if c.username != "" {
+		// Authenticated requests are served under /a/.
+		reqURL += "a/"
+	}
+	reqURL += path
+	if len(query) > 0 {
+		reqURL += "?" + query.Encode()
+	}
+
+	var reqBody io.Reader
+	if body != nil {
+		bs, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("marshal request: %w", err)
+		}
+		reqBody = bytes.NewReader(bs)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, reqBody)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("%v %v: %w", method, path, err)
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return fmt.Errorf("%v %v: read response: %w", method, path, err)
+	}
+
+	if res.StatusCode >= 300 {
+		// Gerrit reports errors in plain text.
+		return &apiError{
+			Method:     method,
+			Path:       path,
+			StatusCode: res.StatusCode,
+			Message:    strings.TrimSpace(string(resBody)),
+		}
+	}
+
+	if dst == nil {
+		return nil
+	}
+
+	resBody = bytes.TrimPrefix(resBody, []byte(_xssiPrefix))
+	if err := json.Unmarshal(resBody, dst); err != nil {
+		return fmt.Errorf("%v %v: decode response: %w", method, path, err)
+	}
+	return nil
+}
+
+// apiError is an error response from the Gerrit REST API.
+type apiError struct {
+	Method, Path string
+	StatusCode   int
+	Message      string
+}
+
+func (e *apiError) Error() string {
+	msg := fmt.Sprintf("%v %v: %v", e.Method, e.Path, http.StatusText(e.StatusCode))
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
@@ -0,0 +1,60 @@
+package gerrit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient(t *testing.T) {
+	var gotPath string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		switch r.URL.Path {
+		case "/gerrit/a/ok", "/gerrit/ok":
+			_, _ = w.Write([]byte(_xssiPrefix + "\n" + `{"name":"foo"}`))
+		default:
+			http.Error(w, "Not found: "+r.URL.Path, http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	t.Run("Anonymous", func(t *testing.T) {
+		c, err := newClient(srv.URL+"/gerrit/", nil, srv.Client())
+		require.NoError(t, err)
+
+		var got projectInfo
+		require.NoError(t, c.Get(t.Context(), "ok", nil, &got))
+		assert.Equal(t, "foo", got.Name)
+		assert.Equal(t, "/gerrit/ok", gotPath)
+	})
+
+	t.Run("Authenticated", func(t *testing.T) {
+		c, err := newClient(srv.URL+"/gerrit", &AuthenticationToken{
+			Username: "user",
+			Password: "pass",
+		}, srv.Client())
+		require.NoError(t, err)
+
+		var got projectInfo
+		require.NoError(t, c.Get(t.Context(), "ok", nil, &got))
+		assert.Equal(t, "foo", got.Name)
+		assert.Equal(t, "/gerrit/a/ok", gotPath)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		c, err := newClient(srv.URL+"/gerrit", nil, srv.Client())
+		require.NoError(t, err)
+
+		err = c.Post(t.Context(), "missing", map[string]string{}, nil)
+		require.Error(t, err)
+
+		var apiErr *apiError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+		assert.Equal(t, "POST missing: Not Found: Not found: /gerrit/missing", err.Error())
+	})
+}
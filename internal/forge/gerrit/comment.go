@@ -0,0 +1,137 @@
+package gerrit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+
+	"go.abhg.dev/gs/internal/forge"
+)
+
+// ChangeComment identifies a message posted on a Gerrit change.
+//
+// ChangeComment implements [forge.ChangeCommentID].
+type ChangeComment struct {
+	// ID is the ID of the change message.
+	ID string `json:"id"` // required
+
+	// Change is the number of the change the message was posted on.
+	Change int64 `json:"change"` // required
+}
+
+var _ forge.ChangeCommentID = (*ChangeComment)(nil)
+
+func mustChangeComment(id forge.ChangeCommentID) *ChangeComment {
+	if id == nil {
+		return nil
+	}
+
+	c, ok := id.(*ChangeComment)
+	if !ok {
+		panic(fmt.Sprintf("unexpected change comment type: %T", id))
+	}
+	return c
+}
+
+func (c *ChangeComment) String() string {
+	return c.ID
+}
+
+// changeMessageInfo is a Gerrit ChangeMessageInfo entity.
+//
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#change-message-info
+type changeMessageInfo struct {
+	ID      string       `json:"id"`
+	Author  *accountInfo `json:"author,omitempty"`
+	Message string       `json:"message"`
+}
+
+// PostChangeComment posts a message on a change.
+func (r *Repository) PostChangeComment(
+	ctx context.Context,
+	id forge.ChangeID,
+	markdown string,
+) (forge.ChangeCommentID, error) {
+	number := mustChange(id).Number
+
+	// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#set-review
+	if err := r.client.Post(ctx, r.changePath(number, "revisions/current/review"), map[string]string{
+		"message": markdown,
+	}, nil); err != nil {
+		return nil, fmt.Errorf("post comment: %w", err)
+	}
+
+	// Reviews don't report the ID of the message they post.
+	// It's the most recent message on the change.
+	var messages []*changeMessageInfo
+	if err := r.client.Get(ctx, r.changePath(number, "messages"), nil, &messages); err != nil {
+		return nil, fmt.Errorf("list messages: %w", err)
+	}
+	if len(messages) == 0 {
+		return nil, errors.New("posted message not found")
+	}
+	msg := messages[len(messages)-1]
+
+	r.log.Debug("Posted comment", "id", msg.ID, "change", number)
+	return &ChangeComment{
+		ID:     msg.ID,
+		Change: number,
+	}, nil
+}
+
+// UpdateChangeComment always fails
+// because Gerrit doesn't allow editing messages on changes.
+func (r *Repository) UpdateChangeComment(
+	context.Context,
+	forge.ChangeCommentID,
+	string,
+) error {
+	return errors.New("gerrit does not support editing change messages")
+}
+
+// ListChangeComments lists messages on a change,
+// optionally applying the given filtering options.
+//
+// Gerrit doesn't allow editing messages,
+// so nothing is reported if options.CanUpdate is set.
+func (r *Repository) ListChangeComments(
+	ctx context.Context,
+	id forge.ChangeID,
+	options *forge.ListChangeCommentsOptions,
+) iter.Seq2[*forge.ListChangeCommentItem, error] {
+	number := mustChange(id).Number
+	return func(yield func(*forge.ListChangeCommentItem, error) bool) {
+		if options != nil && options.CanUpdate {
+			return
+		}
+
+		var messages []*changeMessageInfo
+		if err := r.client.Get(ctx, r.changePath(number, "messages"), nil, &messages); err != nil {
+			yield(nil, fmt.Errorf("list comments: %w", err))
+			return
+		}
+
+	messageLoop:
+		for _, msg := range messages {
+			if options != nil {
+				for _, re := range options.BodyMatchesAll {
+					if !re.MatchString(msg.Message) {
+						continue messageLoop
+					}
+				}
+			}
+
+			item := &forge.ListChangeCommentItem{
+				ID: &ChangeComment{
+					ID:     msg.ID,
+					Change: number,
+				},
+				Body: msg.Message,
+			}
+			if !yield(item, nil) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package gerrit
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/forge"
+)
+
+func TestChangeComments(t *testing.T) {
+	fake := &fakeGerrit{
+		Project: "project",
+		Changes: []*changeInfo{newChangeInfo(1, "feature", "aaa", "000")},
+		Messages: map[int64][]*changeMessageInfo{
+			1: {{ID: "msg0", Message: "Uploaded patch set 1."}},
+		},
+	}
+	repo := newFakeGerrit(t, fake)
+	change := &Change{Number: 1}
+
+	id, err := repo.PostChangeComment(t.Context(), change, "hello world")
+	require.NoError(t, err)
+	assert.Equal(t, &ChangeComment{ID: "msg1", Change: 1}, id)
+
+	err = repo.UpdateChangeComment(t.Context(), id, "goodbye")
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "does not support editing")
+
+	t.Run("List", func(t *testing.T) {
+		var bodies []string
+		for item, err := range repo.ListChangeComments(t.Context(), change, &forge.ListChangeCommentsOptions{
+			BodyMatchesAll: []*regexp.Regexp{regexp.MustCompile(`hello`)},
+		}) {
+			require.NoError(t, err)
+			bodies = append(bodies, item.Body)
+		}
+		assert.Equal(t, []string{"hello world"}, bodies)
+	})
+
+	t.Run("CanUpdate", func(t *testing.T) {
+		for range repo.ListChangeComments(t.Context(), change, &forge.ListChangeCommentsOptions{
+			CanUpdate: true,
+		}) {
+			t.Fatal("unexpected comment")
+		}
+	})
+}
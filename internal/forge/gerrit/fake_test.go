@@ -0,0 +1,184 @@
+package gerrit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/silog/silogtest"
+)
+
+// fakeGerrit is a minimal fake of the Gerrit REST API
+// sufficient to exercise the forge.
+//
+// Change queries are answered with every change in the project
+// that matches the "topic:" or "change:" terms of the query;
+// other search operators are ignored.
+type fakeGerrit struct {
+	Project string
+	Changes []*changeInfo
+
+	// Messages posted on changes, keyed by change number.
+	Messages map[int64][]*changeMessageInfo
+
+	mu       sync.Mutex
+	requests []fakeRequest
+}
+
+// fakeRequest is a request received by fakeGerrit
+// with the leading "/a/" removed from the path.
+type fakeRequest struct {
+	Method string
+	Path   string
+	Body   string
+}
+
+// newFakeGerrit starts a fake Gerrit server
+// and returns a Repository connected to it.
+func newFakeGerrit(t *testing.T, fake *fakeGerrit) *Repository {
+	t.Helper()
+
+	if fake.Messages == nil {
+		fake.Messages = make(map[int64][]*changeMessageInfo)
+	}
+
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	f := &Forge{Options: Options{URL: srv.URL}}
+	client, err := newClient(srv.URL, &AuthenticationToken{
+		Username: "user",
+		Password: "secret",
+	}, srv.Client())
+	require.NoError(t, err)
+
+	repo, err := newRepository(t.Context(), f, fake.Project, silogtest.New(t), client)
+	require.NoError(t, err)
+	return repo
+}
+
+// Requests returns requests received by the server
+// other than change queries and lookups.
+func (f *fakeGerrit) Requests() []fakeRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var reqs []fakeRequest
+	for _, r := range f.requests {
+		if r.Method != http.MethodGet {
+			reqs = append(reqs, r)
+		}
+	}
+	return reqs
+}
+
+func (f *fakeGerrit) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if user, pass, ok := r.BasicAuth(); !ok || user != "user" || pass != "secret" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	path, ok := strings.CutPrefix(r.URL.EscapedPath(), "/a/")
+	if !ok {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+
+	body, _ := io.ReadAll(r.Body)
+	f.mu.Lock()
+	f.requests = append(f.requests, fakeRequest{
+		Method: r.Method,
+		Path:   path,
+		Body:   strings.TrimSpace(string(body)),
+	})
+	f.mu.Unlock()
+
+	changePrefix := "changes/" + strings.ReplaceAll(f.Project, "/", "%2F") + "~"
+	switch {
+	case path == "projects/"+strings.ReplaceAll(f.Project, "/", "%2F"):
+		f.writeJSON(w, &projectInfo{ID: f.Project, Name: f.Project})
+
+	case path == "changes/":
+		f.writeJSON(w, f.query(r.URL.Query().Get("q")))
+
+	case strings.HasPrefix(path, changePrefix):
+		numStr, sub, _ := strings.Cut(strings.TrimPrefix(path, changePrefix), "/")
+		change := f.change(numStr)
+		if change == nil {
+			http.Error(w, "Not found", http.StatusNotFound)
+			return
+		}
+
+		switch sub {
+		case "":
+			f.writeJSON(w, change)
+		case "messages":
+			f.writeJSON(w, f.Messages[change.Number])
+		case "revisions/current/review":
+			var req struct{ Message string }
+			_ = json.Unmarshal(body, &req)
+			msgs := f.Messages[change.Number]
+			f.Messages[change.Number] = append(msgs, &changeMessageInfo{
+				ID:      fmt.Sprintf("msg%d", len(msgs)),
+				Message: req.Message,
+			})
+			f.writeJSON(w, struct{}{})
+		default:
+			// Other mutations are only recorded.
+			f.writeJSON(w, struct{}{})
+		}
+
+	default:
+		http.Error(w, "Not found", http.StatusNotFound)
+	}
+}
+
+func (f *fakeGerrit) change(numStr string) *changeInfo {
+	for _, c := range f.Changes {
+		if numStr == strconv.FormatInt(c.Number, 10) {
+			return c
+		}
+	}
+	return nil
+}
+
+func (f *fakeGerrit) query(q string) []*changeInfo {
+	var topic string
+	numbers := make(map[string]struct{})
+	for term := range strings.FieldsSeq(q) {
+		term = strings.Trim(term, "()")
+		if v, ok := strings.CutPrefix(term, "topic:"); ok {
+			topic = strings.Trim(v, `"`)
+		}
+		if v, ok := strings.CutPrefix(term, "change:"); ok {
+			numbers[v] = struct{}{}
+		}
+	}
+
+	var changes []*changeInfo
+	for _, c := range f.Changes {
+		if topic != "" && c.Topic != topic {
+			continue
+		}
+		if len(numbers) > 0 {
+			if _, ok := numbers[strconv.FormatInt(c.Number, 10)]; !ok {
+				continue
+			}
+		}
+		changes = append(changes, c)
+	}
+	return changes
+}
+
+func (f *fakeGerrit) writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = io.WriteString(w, _xssiPrefix+"\n")
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,136 @@
+package gerrit
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/git"
+)
+
+// _changeQueryOptions are the additional fields
+// requested for changes that are converted to [forge.FindChangeItem].
+//
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#query-options
+var _changeQueryOptions = []string{
+	"CURRENT_REVISION",
+	"CURRENT_COMMIT",
+	"DETAILED_LABELS",
+	"DETAILED_ACCOUNTS",
+}
+
+// FindChangesByBranch searches for changes pushed for the given branch.
+//
+// Changes pushed by git-spice use the branch name as their topic.
+// A branch with multiple commits has a change for each of them,
+// so only the topmost change in each relation chain for the topic
+// is reported.
+func (r *Repository) FindChangesByBranch(ctx context.Context, branch string, opts forge.FindChangesOptions) ([]*forge.FindChangeItem, error) {
+	opts.Limit = cmp.Or(opts.Limit, 10)
+
+	terms := []string{
+		"project:" + quoteQuery(r.project),
+		"topic:" + quoteQuery(branch),
+	}
+	switch opts.State {
+	case forge.ChangeOpen:
+		terms = append(terms, "status:open")
+	case forge.ChangeMerged:
+		terms = append(terms, "status:merged")
+	case forge.ChangeClosed:
+		terms = append(terms, "status:abandoned")
+	}
+
+	changes, err := r.queryChanges(ctx, strings.Join(terms, " "), _changeQueryOptions)
+	if err != nil {
+		return nil, fmt.Errorf("query changes: %w", err)
+	}
+
+	// Changes are listed most recently updated first.
+	// A change is at the top of its chain
+	// if no other change in the topic is based on it.
+	bases := make(map[string]struct{})
+	for _, c := range changes {
+		for _, p := range c.parents() {
+			bases[p] = struct{}{}
+		}
+	}
+
+	var items []*forge.FindChangeItem
+	for _, c := range changes {
+		if _, ok := bases[c.CurrentRevision]; ok {
+			continue
+		}
+
+		items = append(items, r.findChangeItem(c))
+		if len(items) >= opts.Limit {
+			break
+		}
+	}
+	return items, nil
+}
+
+// FindChangeByID searches for a change with the given ID.
+func (r *Repository) FindChangeByID(ctx context.Context, id forge.ChangeID) (*forge.FindChangeItem, error) {
+	number := mustChange(id).Number
+
+	query := url.Values{"o": _changeQueryOptions}
+	var change changeInfo
+	if err := r.client.Get(ctx, r.changePath(number, ""), query, &change); err != nil {
+		return nil, fmt.Errorf("get change %v: %w", id, err)
+	}
+
+	return r.findChangeItem(&change), nil
+}
+
+func (r *Repository) findChangeItem(c *changeInfo) *forge.FindChangeItem {
+	return &forge.FindChangeItem{
+		ID:        &Change{Number: c.Number},
+		URL:       r.changeURL(c.Number),
+		State:     c.state(),
+		Subject:   c.Subject,
+		HeadHash:  git.Hash(c.CurrentRevision),
+		BaseName:  c.Branch,
+		Draft:     c.WorkInProgress,
+		Labels:    c.Hashtags,
+		Reviewers: c.reviewers(),
+	}
+}
+
+// queryChanges runs a change search query
+// and returns all matching changes.
+//
+// https://gerrit-review.googlesource.com/Documentation/user-search.html
+func (r *Repository) queryChanges(ctx context.Context, q string, options []string) ([]*changeInfo, error) {
+	var all []*changeInfo
+	for {
+		query := url.Values{
+			"q": {q},
+			"o": options,
+		}
+		if len(all) > 0 {
+			query.Set("S", strconv.Itoa(len(all)))
+		}
+
+		var changes []*changeInfo
+		if err := r.client.Get(ctx, "changes/", query, &changes); err != nil {
+			return nil, err
+		}
+		all = append(all, changes...)
+
+		// If there are more results,
+		// the last change is marked with _more_changes.
+		if len(changes) == 0 || !changes[len(changes)-1].MoreChanges {
+			return all, nil
+		}
+	}
+}
+
+// quoteQuery quotes a value for use in a Gerrit search query.
+func quoteQuery(s string) string {
+	return strconv.Quote(s)
+}
@@ -0,0 +1,181 @@
+package gerrit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/git"
+)
+
+// newChangeInfo builds a change with a current revision
+// whose commit has the given parent.
+func newChangeInfo(number int64, topic, rev, parent string) *changeInfo {
+	return &changeInfo{
+		Number:          number,
+		Project:         "my/project",
+		Branch:          "main",
+		Topic:           topic,
+		Subject:         "Change " + rev,
+		Status:          "NEW",
+		CurrentRevision: rev,
+		Revisions: map[string]*revisionInfo{
+			rev: {
+				Number: 1,
+				Commit: &commitInfo{
+					Parents: []*commitInfo{{Commit: parent}},
+				},
+			},
+		},
+	}
+}
+
+func TestFindChangesByBranch(t *testing.T) {
+	// feature has a chain of two changes: 1 <- 2.
+	// other has a single change.
+	fake := &fakeGerrit{
+		Project: "my/project",
+		Changes: []*changeInfo{
+			newChangeInfo(1, "feature", "aaa", "000"),
+			newChangeInfo(2, "feature", "bbb", "aaa"),
+			newChangeInfo(3, "other", "ccc", "000"),
+		},
+	}
+	fake.Changes[1].Hashtags = []string{"bug"}
+	fake.Changes[1].WorkInProgress = true
+	fake.Changes[1].Reviewers = map[string][]*accountInfo{
+		"REVIEWER": {{Username: "alice"}},
+		"CC":       {{Username: "bob"}},
+	}
+	repo := newFakeGerrit(t, fake)
+
+	changes, err := repo.FindChangesByBranch(t.Context(), "feature", forge.FindChangesOptions{
+		State: forge.ChangeOpen,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []*forge.FindChangeItem{
+		{
+			ID:        &Change{Number: 2},
+			URL:       repo.forge.URL() + "/c/my/project/+/2",
+			State:     forge.ChangeOpen,
+			Subject:   "Change bbb",
+			HeadHash:  git.Hash("bbb"),
+			BaseName:  "main",
+			Draft:     true,
+			Labels:    []string{"bug"},
+			Reviewers: []string{"alice"},
+		},
+	}, changes)
+
+	fake.mu.Lock()
+	q := fake.requests[len(fake.requests)-1].Path
+	fake.mu.Unlock()
+	assert.Equal(t, "changes/", q)
+}
+
+func TestFindChangesByBranch_none(t *testing.T) {
+	repo := newFakeGerrit(t, &fakeGerrit{Project: "project"})
+
+	changes, err := repo.FindChangesByBranch(t.Context(), "feature", forge.FindChangesOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestFindChangeByID(t *testing.T) {
+	fake := &fakeGerrit{
+		Project: "my/project",
+		Changes: []*changeInfo{newChangeInfo(42, "feature", "abc", "000")},
+	}
+	fake.Changes[0].Status = _statusMerged
+	repo := newFakeGerrit(t, fake)
+
+	change, err := repo.FindChangeByID(t.Context(), &Change{Number: 42})
+	require.NoError(t, err)
+	assert.Equal(t, forge.ChangeMerged, change.State)
+	assert.Equal(t, git.Hash("abc"), change.HeadHash)
+
+	_, err = repo.FindChangeByID(t.Context(), &Change{Number: 43})
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "Not Found")
+}
+
+func TestChangeStatuses(t *testing.T) {
+	fake := &fakeGerrit{
+		Project: "project",
+		Changes: []*changeInfo{
+			newChangeInfo(1, "a", "aaa", "000"),
+			newChangeInfo(2, "b", "bbb", "000"),
+			newChangeInfo(3, "c", "ccc", "000"),
+		},
+	}
+	fake.Changes[0].Status = _statusMerged
+	fake.Changes[2].Status = _statusAbandoned
+	repo := newFakeGerrit(t, fake)
+
+	t.Run("Order", func(t *testing.T) {
+		statuses, err := repo.ChangeStatuses(t.Context(), []forge.ChangeID{
+			&Change{Number: 3},
+			&Change{Number: 1},
+			&Change{Number: 2},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, []forge.ChangeStatus{
+			{State: forge.ChangeClosed, HeadHash: "ccc"},
+			{State: forge.ChangeMerged, HeadHash: "aaa"},
+			{State: forge.ChangeOpen, HeadHash: "bbb"},
+		}, statuses)
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		_, err := repo.ChangeStatuses(t.Context(), []forge.ChangeID{
+			&Change{Number: 1},
+			&Change{Number: 4},
+		})
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "change c/4 not found")
+	})
+}
+
+func TestChangesDetails(t *testing.T) {
+	fake := &fakeGerrit{
+		Project: "project",
+		Changes: []*changeInfo{
+			newChangeInfo(1, "a", "aaa", "000"),
+			newChangeInfo(2, "b", "bbb", "000"),
+			newChangeInfo(3, "c", "ccc", "000"),
+			newChangeInfo(4, "d", "ddd", "000"),
+		},
+	}
+	fake.Changes[0].Labels = map[string]*labelInfo{
+		_codeReviewLabel: {Approved: &accountInfo{Username: "alice"}},
+	}
+	fake.Changes[1].Labels = map[string]*labelInfo{
+		_codeReviewLabel: {
+			Approved: &accountInfo{Username: "alice"},
+			Rejected: &accountInfo{Username: "bob"},
+		},
+	}
+	fake.Changes[2].Reviewers = map[string][]*accountInfo{
+		"REVIEWER": {{Username: "alice"}},
+	}
+	fake.Changes[3].WorkInProgress = true
+	repo := newFakeGerrit(t, fake)
+
+	details, err := repo.ChangesDetails(t.Context(), []forge.ChangeID{
+		&Change{Number: 1},
+		&Change{Number: 2},
+		&Change{Number: 3},
+		&Change{Number: 4},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []forge.ChangeDetails{
+		{State: forge.ChangeOpen, ReviewDecision: forge.ChangeReviewApproved},
+		{State: forge.ChangeOpen, ReviewDecision: forge.ChangeReviewChangesRequested},
+		{State: forge.ChangeOpen, ReviewDecision: forge.ChangeReviewRequired},
+		{State: forge.ChangeOpen, Draft: true, ReviewDecision: forge.ChangeReviewNoReview},
+	}, details)
+}
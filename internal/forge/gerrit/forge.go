@@ -0,0 +1,189 @@
+// Package gerrit provides a wrapper around Gerrit's REST API
+// in a manner compliant with the [forge.Forge] interface.
+//
+// Gerrit reviews individual commits rather than branches.
+// Commits pushed to refs/for/<branch> become changes
+// proposed against that branch,
+// and are identified across revisions by their Change-Id trailer.
+// Each tracked branch maps to the change for its topmost commit,
+// and all changes pushed for a branch share a topic named after it.
+package gerrit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/silog"
+)
+
+// Options defines command line options for the Gerrit Forge.
+// These are all hidden in the CLI,
+// and are expected to be set only via configuration
+// or environment variables.
+type Options struct {
+	// URL is the base URL of the Gerrit instance.
+	//
+	// Gerrit has no default host,
+	// so the forge is only used if this is set.
+	URL string `name:"gerrit-url" hidden:"" config:"forge.gerrit.url" env:"GERRIT_URL" help:"Base URL for Gerrit"`
+
+	// Username and Password are fixed credentials
+	// used to authenticate with Gerrit.
+	// These may be used to skip the login flow.
+	Username string `name:"gerrit-username" hidden:"" env:"GERRIT_USERNAME" help:"Gerrit username"`
+	Password string `name:"gerrit-password" hidden:"" env:"GERRIT_PASSWORD" help:"Gerrit HTTP password"`
+}
+
+// Forge builds a Gerrit Forge.
+type Forge struct {
+	Options Options
+
+	// Log specifies the logger to use.
+	Log *silog.Logger
+}
+
+var _ forge.Forge = (*Forge)(nil)
+
+func (f *Forge) logger() *silog.Logger {
+	if f.Log == nil {
+		return silog.Nop()
+	}
+	return f.Log.WithPrefix("gerrit")
+}
+
+// URL returns the base URL configured for the Gerrit Forge
+// without a trailing slash.
+// It is empty if no URL is configured.
+func (f *Forge) URL() string {
+	return strings.TrimRight(f.Options.URL, "/")
+}
+
+// ID reports a unique key for this forge.
+func (*Forge) ID() string { return "gerrit" }
+
+// CLIPlugin returns the CLI plugin for the Gerrit Forge.
+func (f *Forge) CLIPlugin() any { return &f.Options }
+
+// ParseRemoteURL parses the given remote URL and returns a [RepositoryID]
+// for the Gerrit project it points to.
+//
+// It returns [forge.ErrUnsupportedURL] if no Gerrit URL is configured,
+// or if the remote URL does not point to the configured host.
+func (f *Forge) ParseRemoteURL(remoteURL string) (forge.RepositoryID, error) {
+	if f.URL() == "" {
+		return nil, fmt.Errorf("%w: Gerrit URL is not configured", forge.ErrUnsupportedURL)
+	}
+
+	project, err := extractProject(f.URL(), remoteURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", forge.ErrUnsupportedURL, err)
+	}
+
+	return &RepositoryID{
+		url:     f.URL(),
+		project: project,
+	}, nil
+}
+
+// OpenRepository opens the Gerrit project that the given ID points to.
+func (f *Forge) OpenRepository(ctx context.Context, token forge.AuthenticationToken, id forge.RepositoryID) (forge.Repository, error) {
+	rid := mustRepositoryID(id)
+
+	client, err := newClient(f.URL(), token.(*AuthenticationToken), nil)
+	if err != nil {
+		return nil, fmt.Errorf("create Gerrit client: %w", err)
+	}
+
+	return newRepository(ctx, f, rid.project, f.logger(), client)
+}
+
+// RepositoryID is a unique identifier for a Gerrit project.
+type RepositoryID struct {
+	url     string // required
+	project string // required
+}
+
+var _ forge.RepositoryID = (*RepositoryID)(nil)
+
+func mustRepositoryID(id forge.RepositoryID) *RepositoryID {
+	rid, ok := id.(*RepositoryID)
+	if ok {
+		return rid
+	}
+	panic(fmt.Sprintf("expected *RepositoryID, got %T", id))
+}
+
+// String returns the name of the Gerrit project.
+func (rid *RepositoryID) String() string {
+	return rid.project
+}
+
+// ChangeURL returns the URL for a change hosted on Gerrit.
+func (rid *RepositoryID) ChangeURL(id forge.ChangeID) string {
+	return changeURL(rid.url, rid.project, mustChange(id).Number)
+}
+
+func changeURL(baseURL, project string, number int64) string {
+	return fmt.Sprintf("%s/c/%s/+/%d", baseURL, project, number)
+}
+
+// extractProject extracts the name of a Gerrit project
+// from a remote URL pointing to the Gerrit instance at gerritURL.
+func extractProject(gerritURL, remoteURL string) (string, error) {
+	baseURL, err := url.Parse(gerritURL)
+	if err != nil {
+		return "", fmt.Errorf("bad base URL: %w", err)
+	}
+
+	// We recognize the following Gerrit remote URL formats:
+	//
+	//	http(s)://gerrit.example.com/[a/]PROJECT
+	//	ssh://user@gerrit.example.com:29418/PROJECT
+	//	user@gerrit.example.com:PROJECT
+	//
+	// We can parse these all with url.Parse
+	// if we normalize the last one to:
+	//
+	//	ssh://user@gerrit.example.com/PROJECT
+	if !strings.Contains(remoteURL, "://") && strings.Contains(remoteURL, ":") {
+		// $user@$host:$path => ssh://$user@$host/$path
+		remoteURL = "ssh://" + strings.Replace(remoteURL, ":", "/", 1)
+	}
+
+	u, err := url.Parse(remoteURL)
+	if err != nil {
+		return "", fmt.Errorf("parse remote URL: %w", err)
+	}
+
+	// Gerrit serves SSH on a different port than HTTP,
+	// so compare only the host names.
+	if u.Hostname() != baseURL.Hostname() {
+		return "", fmt.Errorf("%v is not a Gerrit URL: expected host %q, got %q", u, baseURL.Hostname(), u.Hostname())
+	}
+
+	s := strings.Trim(u.Path, "/") // [PREFIX/][a/]PROJECT[.git]
+	if u.Scheme == "http" || u.Scheme == "https" {
+		// Gerrit may be served from a path, e.g. example.com/gerrit.
+		prefix := strings.Trim(baseURL.Path, "/")
+		if prefix != "" {
+			var ok bool
+			s, ok = strings.CutPrefix(s, prefix+"/")
+			if !ok {
+				return "", fmt.Errorf("path %q is not under %q", u.Path, baseURL.Path)
+			}
+		}
+
+		// Authenticated clones use the /a/ prefix.
+		s = strings.TrimPrefix(s, "a/")
+	}
+	s = strings.TrimSuffix(s, ".git")
+	if s == "" {
+		return "", errors.New("remote URL does not contain a Gerrit project")
+	}
+
+	return s, nil
+}
@@ -0,0 +1,116 @@
+package gerrit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/forge"
+)
+
+func TestExtractProject(t *testing.T) {
+	tests := []struct {
+		name      string
+		gerritURL string
+		remoteURL string
+
+		want    string
+		wantErr string
+	}{
+		{
+			name:      "HTTPS",
+			gerritURL: "https://review.example.com",
+			remoteURL: "https://review.example.com/my/project",
+			want:      "my/project",
+		},
+		{
+			name:      "HTTPSAuthenticated",
+			gerritURL: "https://review.example.com",
+			remoteURL: "https://review.example.com/a/my/project",
+			want:      "my/project",
+		},
+		{
+			name:      "DotGit",
+			gerritURL: "https://review.example.com",
+			remoteURL: "https://review.example.com/project.git",
+			want:      "project",
+		},
+		{
+			name:      "PathPrefix",
+			gerritURL: "https://example.com/gerrit/",
+			remoteURL: "https://example.com/gerrit/a/project",
+			want:      "project",
+		},
+		{
+			name:      "PathPrefixMismatch",
+			gerritURL: "https://example.com/gerrit",
+			remoteURL: "https://example.com/other/project",
+			wantErr:   "is not under",
+		},
+		{
+			name:      "SSH",
+			gerritURL: "https://review.example.com",
+			remoteURL: "ssh://user@review.example.com:29418/my/project",
+			want:      "my/project",
+		},
+		{
+			name:      "SCP",
+			gerritURL: "https://review.example.com",
+			remoteURL: "user@review.example.com:my/project.git",
+			want:      "my/project",
+		},
+		{
+			name:      "WrongHost",
+			gerritURL: "https://review.example.com",
+			remoteURL: "https://github.com/my/project",
+			wantErr:   "is not a Gerrit URL",
+		},
+		{
+			name:      "NoProject",
+			gerritURL: "https://review.example.com",
+			remoteURL: "https://review.example.com/",
+			wantErr:   "does not contain a Gerrit project",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := extractProject(tt.gerritURL, tt.remoteURL)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestForgeParseRemoteURL(t *testing.T) {
+	t.Run("NotConfigured", func(t *testing.T) {
+		var f Forge
+		_, err := f.ParseRemoteURL("https://review.example.com/project")
+		require.Error(t, err)
+		assert.ErrorIs(t, err, forge.ErrUnsupportedURL)
+	})
+
+	t.Run("WrongHost", func(t *testing.T) {
+		f := Forge{Options: Options{URL: "https://review.example.com"}}
+		_, err := f.ParseRemoteURL("https://github.com/foo/bar")
+		require.Error(t, err)
+		assert.ErrorIs(t, err, forge.ErrUnsupportedURL)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		f := Forge{Options: Options{URL: "https://review.example.com/"}}
+		rid, err := f.ParseRemoteURL("ssh://review.example.com:29418/my/project")
+		require.NoError(t, err)
+
+		assert.Equal(t, "my/project", rid.String())
+		assert.Equal(t,
+			"https://review.example.com/c/my/project/+/42",
+			rid.ChangeURL(&Change{Number: 42}))
+	})
+}
@@ -0,0 +1,186 @@
+package gerrit
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/silog"
+)
+
+// Repository is a Gerrit project.
+type Repository struct {
+	client *client
+
+	project string
+	log     *silog.Logger
+	forge   *Forge
+}
+
+var (
+	_ forge.Repository   = (*Repository)(nil)
+	_ forge.ChangePusher = (*Repository)(nil)
+)
+
+func newRepository(
+	ctx context.Context,
+	forge *Forge,
+	project string,
+	log *silog.Logger,
+	client *client,
+) (*Repository, error) {
+	// Verify that the project exists and is visible to us.
+	var info projectInfo
+	if err := client.Get(ctx, "projects/"+url.PathEscape(project), nil, &info); err != nil {
+		return nil, fmt.Errorf("get project: %w", err)
+	}
+
+	return &Repository{
+		client:  client,
+		project: project,
+		log:     log,
+		forge:   forge,
+	}, nil
+}
+
+// Forge returns the forge this repository belongs to.
+func (r *Repository) Forge() forge.Forge { return r.forge }
+
+// changePath returns the escaped API path for the given change,
+// optionally followed by a sub-resource, e.g. "topic".
+func (r *Repository) changePath(number int64, sub string) string {
+	// Changes are identified by "<project>~<number>"
+	// which is cheaper for Gerrit to look up than the number alone.
+	path := "changes/" + url.PathEscape(r.project+"~"+strconv.FormatInt(number, 10))
+	if sub != "" {
+		path += "/" + sub
+	}
+	return path
+}
+
+// changeURL returns the web URL for the given change number.
+func (r *Repository) changeURL(number int64) string {
+	return changeURL(r.forge.URL(), r.project, number)
+}
+
+// projectInfo is a Gerrit ProjectInfo entity.
+//
+// https://gerrit-review.googlesource.com/Documentation/rest-api-projects.html#project-info
+type projectInfo struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Change statuses reported by Gerrit.
+const (
+	_statusMerged    = "MERGED"
+	_statusAbandoned = "ABANDONED"
+)
+
+// changeInfo is a Gerrit ChangeInfo entity.
+//
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#change-info
+type changeInfo struct {
+	Number          int64    `json:"_number"`
+	Project         string   `json:"project"`
+	Branch          string   `json:"branch"`
+	Topic           string   `json:"topic,omitempty"`
+	ChangeID        string   `json:"change_id"`
+	Subject         string   `json:"subject"`
+	Status          string   `json:"status"`
+	WorkInProgress  bool     `json:"work_in_progress,omitempty"`
+	Hashtags        []string `json:"hashtags,omitempty"`
+	CurrentRevision string   `json:"current_revision,omitempty"`
+
+	// Revisions is keyed by commit hash.
+	// Only the current revision is requested.
+	Revisions map[string]*revisionInfo `json:"revisions,omitempty"`
+
+	// Reviewers is keyed by reviewer state, e.g. "REVIEWER" or "CC".
+	Reviewers map[string][]*accountInfo `json:"reviewers,omitempty"`
+
+	// Labels is keyed by label name, e.g. "Code-Review".
+	Labels map[string]*labelInfo `json:"labels,omitempty"`
+
+	// MoreChanges is set on the last change of a query result
+	// if there are more results to fetch.
+	MoreChanges bool `json:"_more_changes,omitempty"`
+}
+
+// state returns the forge state of the change.
+func (c *changeInfo) state() forge.ChangeState {
+	switch c.Status {
+	case _statusMerged:
+		return forge.ChangeMerged
+	case _statusAbandoned:
+		return forge.ChangeClosed
+	default:
+		return forge.ChangeOpen
+	}
+}
+
+// parents returns the parent commits of the current revision.
+func (c *changeInfo) parents() []string {
+	rev := c.Revisions[c.CurrentRevision]
+	if rev == nil || rev.Commit == nil {
+		return nil
+	}
+
+	parents := make([]string, len(rev.Commit.Parents))
+	for i, p := range rev.Commit.Parents {
+		parents[i] = p.Commit
+	}
+	return parents
+}
+
+// reviewers returns the usernames of reviewers of the change.
+func (c *changeInfo) reviewers() []string {
+	var names []string
+	for _, a := range c.Reviewers["REVIEWER"] {
+		if name := a.name(); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// revisionInfo is a Gerrit RevisionInfo entity.
+type revisionInfo struct {
+	Number int         `json:"_number"`
+	Commit *commitInfo `json:"commit,omitempty"`
+}
+
+// commitInfo is a Gerrit CommitInfo entity.
+type commitInfo struct {
+	Commit  string        `json:"commit,omitempty"`
+	Parents []*commitInfo `json:"parents,omitempty"`
+	Subject string        `json:"subject,omitempty"`
+}
+
+// accountInfo is a Gerrit AccountInfo entity.
+type accountInfo struct {
+	AccountID int64  `json:"_account_id"`
+	Username  string `json:"username,omitempty"`
+	Email     string `json:"email,omitempty"`
+	Name      string `json:"name,omitempty"`
+}
+
+// name returns the best available identifier for the account.
+func (a *accountInfo) name() string {
+	switch {
+	case a.Username != "":
+		return a.Username
+	case a.Email != "":
+		return a.Email
+	default:
+		return a.Name
+	}
+}
+
+// labelInfo is a Gerrit LabelInfo entity.
+type labelInfo struct {
+	Approved *accountInfo `json:"approved,omitempty"`
+	Rejected *accountInfo `json:"rejected,omitempty"`
+}
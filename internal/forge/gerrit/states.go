@@ -0,0 +1,105 @@
+package gerrit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/git"
+)
+
+// _codeReviewLabel is the label Gerrit uses for code review votes.
+const _codeReviewLabel = "Code-Review"
+
+// ChangeStatuses retrieves the states and head hashes of the given changes
+// with a single search query.
+func (r *Repository) ChangeStatuses(ctx context.Context, ids []forge.ChangeID) ([]forge.ChangeStatus, error) {
+	changes, err := r.changesByID(ctx, ids, []string{"CURRENT_REVISION"})
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]forge.ChangeStatus, len(changes))
+	for i, c := range changes {
+		statuses[i] = forge.ChangeStatus{
+			State:    c.state(),
+			HeadHash: git.Hash(c.CurrentRevision),
+		}
+	}
+	return statuses, nil
+}
+
+// ChangesDetails retrieves the state, work-in-progress status,
+// and review decision for the given changes.
+//
+// The review decision is based on the Code-Review label:
+// a veto means changes were requested,
+// and an approval means the change was approved.
+func (r *Repository) ChangesDetails(ctx context.Context, ids []forge.ChangeID) ([]forge.ChangeDetails, error) {
+	changes, err := r.changesByID(ctx, ids, []string{"DETAILED_LABELS", "DETAILED_ACCOUNTS"})
+	if err != nil {
+		return nil, err
+	}
+
+	details := make([]forge.ChangeDetails, len(changes))
+	for i, c := range changes {
+		details[i] = forge.ChangeDetails{
+			State:          c.state(),
+			Draft:          c.WorkInProgress,
+			ReviewDecision: reviewDecision(c),
+		}
+	}
+	return details, nil
+}
+
+func reviewDecision(c *changeInfo) forge.ChangeReviewDecision {
+	if label := c.Labels[_codeReviewLabel]; label != nil {
+		switch {
+		case label.Rejected != nil:
+			return forge.ChangeReviewChangesRequested
+		case label.Approved != nil:
+			return forge.ChangeReviewApproved
+		}
+	}
+
+	if len(c.Reviewers["REVIEWER"]) > 0 {
+		return forge.ChangeReviewRequired
+	}
+	return forge.ChangeReviewNoReview
+}
+
+// changesByID looks up the given changes with a single search query.
+// The returned changes are in the same order as ids.
+func (r *Repository) changesByID(ctx context.Context, ids []forge.ChangeID, options []string) ([]*changeInfo, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	terms := make([]string, len(ids))
+	for i, id := range ids {
+		terms[i] = "change:" + strconv.FormatInt(mustChange(id).Number, 10)
+	}
+	q := fmt.Sprintf("project:%v (%v)", quoteQuery(r.project), strings.Join(terms, " OR "))
+
+	found, err := r.queryChanges(ctx, q, options)
+	if err != nil {
+		return nil, fmt.Errorf("query changes: %w", err)
+	}
+
+	byNumber := make(map[int64]*changeInfo, len(found))
+	for _, c := range found {
+		byNumber[c.Number] = c
+	}
+
+	changes := make([]*changeInfo, len(ids))
+	for i, id := range ids {
+		c, ok := byNumber[mustChange(id).Number]
+		if !ok {
+			return nil, fmt.Errorf("change %v not found", id)
+		}
+		changes[i] = c
+	}
+	return changes, nil
+}
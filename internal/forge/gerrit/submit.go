@@ -0,0 +1,210 @@
+package gerrit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"go.abhg.dev/gs/internal/forge"
+)
+
+// PushRef returns the ref to push a branch's commits to
+// so that they become changes proposed against base.
+//
+// The changes are tagged with the given topic
+// so that FindChangesByBranch can find them later.
+//
+// https://gerrit-review.googlesource.com/Documentation/user-upload.html#push_create
+func (r *Repository) PushRef(base, topic string) string {
+	return "refs/for/" + base + "%topic=" + url.QueryEscape(topic)
+}
+
+// SubmitChange finalizes a change created by pushing to [Repository.PushRef].
+//
+// Gerrit creates changes when commits are pushed,
+// so this looks up the change for req.Head
+// and applies the remaining request options to it.
+// If the topic holds more than one chain of changes,
+// e.g. after earlier pushes of a rewritten branch,
+// the change at req.HeadHash is used.
+// The change's subject and description always come from its commit message,
+// so req.Subject and req.Body are not used.
+// Labels are added as hashtags.
+func (r *Repository) SubmitChange(ctx context.Context, req forge.SubmitChangeRequest) (forge.SubmitChangeResult, error) {
+	change, err := r.findPushedChange(ctx, req)
+	if err != nil {
+		return forge.SubmitChangeResult{}, err
+	}
+	number := mustChange(change.ID).Number
+
+	if req.Draft && !change.Draft {
+		if err := r.setWorkInProgress(ctx, number, true); err != nil {
+			return forge.SubmitChangeResult{}, err
+		}
+	}
+	if err := r.editHashtags(ctx, number, req.Labels, nil); err != nil {
+		return forge.SubmitChangeResult{}, err
+	}
+	if err := r.addReviewers(ctx, number, req.Reviewers); err != nil {
+		return forge.SubmitChangeResult{}, err
+	}
+	if len(req.Assignees) > 0 {
+		r.log.Warn("Gerrit does not support assignees. Ignoring.", "assignees", req.Assignees)
+	}
+
+	r.log.Debug("Submitted change", "change", number, "url", change.URL)
+	return forge.SubmitChangeResult{
+		ID:  change.ID,
+		URL: change.URL,
+	}, nil
+}
+
+// findPushedChange finds the open change pushed for a submit request.
+//
+// If req.HeadHash is set, the change must have it as its current revision.
+// Otherwise, the topic must hold only one chain of changes.
+func (r *Repository) findPushedChange(ctx context.Context, req forge.SubmitChangeRequest) (*forge.FindChangeItem, error) {
+	changes, err := r.FindChangesByBranch(ctx, req.Head, forge.FindChangesOptions{
+		State: forge.ChangeOpen,
+		Limit: 100,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("find pushed change: %w", err)
+	}
+
+	if req.HeadHash != "" {
+		for _, change := range changes {
+			if change.HeadHash == req.HeadHash {
+				return change, nil
+			}
+		}
+		return nil, fmt.Errorf("no open change found for %v at %v: was it pushed to refs/for/%v?",
+			req.Head, req.HeadHash.Short(), req.Base)
+	}
+
+	switch len(changes) {
+	case 0:
+		return nil, fmt.Errorf("no open change found for %v: was it pushed to refs/for/%v?", req.Head, req.Base)
+	case 1:
+		return changes[0], nil
+	default:
+		return nil, fmt.Errorf("%v: found %d unrelated open changes: cannot tell which one to submit", req.Head, len(changes))
+	}
+}
+
+// EditChange edits an existing change in a repository.
+//
+// Changing the base moves the change to a different branch.
+// Gerrit takes the change's subject and description from its commit message,
+// so the title and body options are not used.
+func (r *Repository) EditChange(ctx context.Context, id forge.ChangeID, opts forge.EditChangeOptions) error {
+	number := mustChange(id).Number
+
+	if opts.Base != "" || opts.Draft != nil {
+		change, err := r.FindChangeByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("get change for update: %w", err)
+		}
+
+		if opts.Base != "" && opts.Base != change.BaseName {
+			// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#move-change
+			if err := r.client.Post(ctx, r.changePath(number, "move"), map[string]string{
+				"destination_branch": opts.Base,
+			}, nil); err != nil {
+				return fmt.Errorf("move change to %v: %w", opts.Base, err)
+			}
+		}
+
+		if opts.Draft != nil && *opts.Draft != change.Draft {
+			if err := r.setWorkInProgress(ctx, number, *opts.Draft); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := r.editHashtags(ctx, number, opts.AddLabels, opts.RemoveLabels); err != nil {
+		return err
+	}
+	if err := r.addReviewers(ctx, number, opts.AddReviewers); err != nil {
+		return err
+	}
+	if err := r.removeReviewers(ctx, number, opts.RemoveReviewers); err != nil {
+		return err
+	}
+	if len(opts.AddAssignees) > 0 || len(opts.RemoveAssignees) > 0 {
+		r.log.Warn("Gerrit does not support assignees. Ignoring.",
+			"assignees", opts.AddAssignees, "removeAssignees", opts.RemoveAssignees)
+	}
+	if opts.Milestone != nil {
+		r.log.Warn("Gerrit does not support milestones. Ignoring.", "milestone", *opts.Milestone)
+	}
+	if len(opts.AddProjects) > 0 || len(opts.RemoveProjects) > 0 {
+		r.log.Warn("Gerrit does not support projects. Ignoring.",
+			"projects", opts.AddProjects, "removeProjects", opts.RemoveProjects)
+	}
+
+	return nil
+}
+
+// setWorkInProgress marks a change as work-in-progress or ready for review.
+func (r *Repository) setWorkInProgress(ctx context.Context, number int64, wip bool) error {
+	// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#set-work-in-progress
+	sub := "ready"
+	if wip {
+		sub = "wip"
+	}
+
+	if err := r.client.Post(ctx, r.changePath(number, sub), struct{}{}, nil); err != nil {
+		return fmt.Errorf("set work-in-progress=%v: %w", wip, err)
+	}
+	return nil
+}
+
+// editHashtags adds and removes hashtags on a change.
+func (r *Repository) editHashtags(ctx context.Context, number int64, add, remove []string) error {
+	if len(add) == 0 && len(remove) == 0 {
+		return nil
+	}
+
+	// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#set-hashtags
+	if err := r.client.Post(ctx, r.changePath(number, "hashtags"), struct {
+		Add    []string `json:"add,omitempty"`
+		Remove []string `json:"remove,omitempty"`
+	}{Add: add, Remove: remove}, nil); err != nil {
+		return fmt.Errorf("edit hashtags: %w", err)
+	}
+	return nil
+}
+
+// addReviewers adds reviewers to a change.
+//
+// Gerrit adds one reviewer per request,
+// so this reports all reviewers that couldn't be added.
+func (r *Repository) addReviewers(ctx context.Context, number int64, reviewers []string) error {
+	var errs []error
+	for _, reviewer := range reviewers {
+		// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#add-reviewer
+		if err := r.client.Post(ctx, r.changePath(number, "reviewers"), map[string]string{
+			"reviewer": reviewer,
+		}, nil); err != nil {
+			errs = append(errs, fmt.Errorf("add reviewer %v: %w", reviewer, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// removeReviewers removes reviewers from a change.
+//
+// Like addReviewers, this reports all reviewers that couldn't be removed.
+func (r *Repository) removeReviewers(ctx context.Context, number int64, reviewers []string) error {
+	var errs []error
+	for _, reviewer := range reviewers {
+		// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#delete-reviewer
+		path := r.changePath(number, "reviewers/"+url.PathEscape(reviewer)+"/delete")
+		if err := r.client.Post(ctx, path, struct{}{}, nil); err != nil {
+			errs = append(errs, fmt.Errorf("remove reviewer %v: %w", reviewer, err))
+		}
+	}
+	return errors.Join(errs...)
+}
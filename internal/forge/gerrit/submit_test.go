@@ -0,0 +1,163 @@
+package gerrit
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/forge"
+)
+
+func TestPushRef(t *testing.T) {
+	var repo Repository
+	assert.Equal(t, "refs/for/main%topic=feature", repo.PushRef("main", "feature"))
+	assert.Equal(t, "refs/for/release/1.x%topic=user%2Ffeat+1", repo.PushRef("release/1.x", "user/feat 1"))
+}
+
+func TestSubmitChange(t *testing.T) {
+	fake := &fakeGerrit{
+		Project: "project",
+		Changes: []*changeInfo{
+			newChangeInfo(1, "feature", "aaa", "000"),
+			newChangeInfo(2, "feature", "bbb", "aaa"),
+		},
+	}
+	repo := newFakeGerrit(t, fake)
+
+	result, err := repo.SubmitChange(t.Context(), forge.SubmitChangeRequest{
+		Subject:   "ignored",
+		Base:      "main",
+		Head:      "feature",
+		Draft:     true,
+		Labels:    []string{"bug", "ui"},
+		Reviewers: []string{"alice", "bob"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &Change{Number: 2}, result.ID)
+	assert.Equal(t, repo.forge.URL()+"/c/project/+/2", result.URL)
+
+	assert.Equal(t, []fakeRequest{
+		{Method: http.MethodPost, Path: "changes/project~2/wip", Body: `{}`},
+		{Method: http.MethodPost, Path: "changes/project~2/hashtags", Body: `{"add":["bug","ui"]}`},
+		{Method: http.MethodPost, Path: "changes/project~2/reviewers", Body: `{"reviewer":"alice"}`},
+		{Method: http.MethodPost, Path: "changes/project~2/reviewers", Body: `{"reviewer":"bob"}`},
+	}, fake.Requests())
+}
+
+func TestSubmitChange_notPushed(t *testing.T) {
+	repo := newFakeGerrit(t, &fakeGerrit{Project: "project"})
+
+	_, err := repo.SubmitChange(t.Context(), forge.SubmitChangeRequest{
+		Base: "main",
+		Head: "feature",
+	})
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "no open change found for feature")
+}
+
+func TestSubmitChange_multipleChains(t *testing.T) {
+	// feature was pushed twice with rewritten commits,
+	// leaving the changes from the first push open.
+	fake := &fakeGerrit{
+		Project: "project",
+		Changes: []*changeInfo{
+			newChangeInfo(1, "feature", "aaa", "000"),
+			newChangeInfo(2, "feature", "bbb", "aaa"),
+			newChangeInfo(3, "feature", "ccc", "000"),
+			newChangeInfo(4, "feature", "ddd", "ccc"),
+		},
+	}
+	repo := newFakeGerrit(t, fake)
+
+	t.Run("HeadHash", func(t *testing.T) {
+		result, err := repo.SubmitChange(t.Context(), forge.SubmitChangeRequest{
+			Base:     "main",
+			Head:     "feature",
+			HeadHash: "ddd",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, &Change{Number: 4}, result.ID)
+	})
+
+	t.Run("HeadHashNotPushed", func(t *testing.T) {
+		_, err := repo.SubmitChange(t.Context(), forge.SubmitChangeRequest{
+			Base:     "main",
+			Head:     "feature",
+			HeadHash: "eee",
+		})
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "no open change found for feature at eee")
+	})
+
+	t.Run("NoHeadHash", func(t *testing.T) {
+		_, err := repo.SubmitChange(t.Context(), forge.SubmitChangeRequest{
+			Base: "main",
+			Head: "feature",
+		})
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "found 2 unrelated open changes")
+	})
+}
+
+func TestEditChange(t *testing.T) {
+	t.Run("MoveAndReady", func(t *testing.T) {
+		fake := &fakeGerrit{
+			Project: "project",
+			Changes: []*changeInfo{newChangeInfo(1, "feature", "aaa", "000")},
+		}
+		fake.Changes[0].WorkInProgress = true
+		repo := newFakeGerrit(t, fake)
+
+		draft := false
+		require.NoError(t, repo.EditChange(t.Context(), &Change{Number: 1}, forge.EditChangeOptions{
+			Base:         "release",
+			Draft:        &draft,
+			AddLabels:    []string{"bug"},
+			AddReviewers: []string{"alice"},
+		}))
+
+		assert.Equal(t, []fakeRequest{
+			{Method: http.MethodPost, Path: "changes/project~1/move", Body: `{"destination_branch":"release"}`},
+			{Method: http.MethodPost, Path: "changes/project~1/ready", Body: `{}`},
+			{Method: http.MethodPost, Path: "changes/project~1/hashtags", Body: `{"add":["bug"]}`},
+			{Method: http.MethodPost, Path: "changes/project~1/reviewers", Body: `{"reviewer":"alice"}`},
+		}, fake.Requests())
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		fake := &fakeGerrit{
+			Project: "project",
+			Changes: []*changeInfo{newChangeInfo(1, "feature", "aaa", "000")},
+		}
+		repo := newFakeGerrit(t, fake)
+
+		milestone := "v1"
+		require.NoError(t, repo.EditChange(t.Context(), &Change{Number: 1}, forge.EditChangeOptions{
+			AddLabels:       []string{"ui"},
+			RemoveLabels:    []string{"bug"},
+			RemoveReviewers: []string{"bob@example.com"},
+			Milestone:       &milestone,
+		}))
+
+		assert.Equal(t, []fakeRequest{
+			{Method: http.MethodPost, Path: "changes/project~1/hashtags", Body: `{"add":["ui"],"remove":["bug"]}`},
+			{Method: http.MethodPost, Path: "changes/project~1/reviewers/bob@example.com/delete", Body: `{}`},
+		}, fake.Requests())
+	})
+
+	t.Run("NoChange", func(t *testing.T) {
+		fake := &fakeGerrit{
+			Project: "project",
+			Changes: []*changeInfo{newChangeInfo(1, "feature", "aaa", "000")},
+		}
+		repo := newFakeGerrit(t, fake)
+
+		draft := false
+		require.NoError(t, repo.EditChange(t.Context(), &Change{Number: 1}, forge.EditChangeOptions{
+			Base:  "main",
+			Draft: &draft,
+		}))
+		assert.Empty(t, fake.Requests())
+	})
+}
@@ -0,0 +1,21 @@
+package gerrit
+
+import (
+	"context"
+
+	"go.abhg.dev/gs/internal/forge"
+)
+
+// ChangeTemplatePaths reports the allowed paths for change templates.
+//
+// Gerrit takes change descriptions from commit messages,
+// so it doesn't support change templates.
+func (f *Forge) ChangeTemplatePaths() []string {
+	return nil
+}
+
+// ListChangeTemplates returns no templates
+// because Gerrit doesn't support them.
+func (r *Repository) ListChangeTemplates(context.Context) ([]*forge.ChangeTemplate, error) {
+	return nil, nil
+}
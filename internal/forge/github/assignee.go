@@ -35,6 +35,34 @@ func (r *Repository) addAssigneesToPullRequest(ctx context.Context, assignees []
 	return nil
 }
 
+func (r *Repository) removeAssigneesFromPullRequest(ctx context.Context, assignees []string, prGraphQLID githubv4.ID) error {
+	if len(assignees) == 0 {
+		return nil
+	}
+
+	assigneeIDs, err := r.assigneeIDs(ctx, assignees)
+	if err != nil {
+		return fmt.Errorf("get assignee IDs: %w", err)
+	}
+
+	var m struct {
+		RemoveAssigneesFromAssignable struct {
+			ClientMutationID githubv4.String `graphql:"clientMutationId"`
+		} `graphql:"removeAssigneesFromAssignable(input: $input)"`
+	}
+
+	input := githubv4.RemoveAssigneesFromAssignableInput{
+		AssignableID: prGraphQLID,
+		AssigneeIDs:  assigneeIDs,
+	}
+
+	if err := r.client.Mutate(ctx, &m, input, nil); err != nil {
+		return fmt.Errorf("remove assignees from assignable: %w", err)
+	}
+
+	return nil
+}
+
 // assigneeIDs resolves assignee logins to GitHub user IDs.
 // The returned slice may be shorter than the input
 // because duplicate logins are automatically deduplicated.
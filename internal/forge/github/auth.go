@@ -2,6 +2,8 @@ package github
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -38,6 +40,16 @@ type AuthenticationToken struct {
 
 var _ forge.AuthenticationToken = (*AuthenticationToken)(nil)
 
+// cacheKey identifies the user authenticated with this token
+// in cached metadata without revealing the token.
+func (t *AuthenticationToken) cacheKey() string {
+	if t.GitHubCLI {
+		return "gh"
+	}
+	sum := sha256.Sum256([]byte(t.AccessToken))
+	return hex.EncodeToString(sum[:8])
+}
+
 func (t *AuthenticationToken) tokenSource() oauth2.TokenSource {
 	if t.GitHubCLI {
 		return &CLITokenSource{}
@@ -65,7 +77,7 @@ func (f *Forge) AuthenticationFlow(ctx context.Context, view ui.View) (forge.Aut
 	log := f.logger()
 	// Already authenticated with GITHUB_TOKEN.
 	// If the user tries to authenticate again, we should error.
-	if f.Options.Token != "" {
+	if f.token() != "" {
 		// NB: alternatively, we can make this a no-op,
 		// and just omit saving it to the stash.
 		// Adjust based on user feedback.
@@ -92,7 +104,7 @@ func (f *Forge) AuthenticationFlow(ctx context.Context, view ui.View) (forge.Aut
 // SaveAuthenticationToken saves the given authentication token to the stash.
 func (f *Forge) SaveAuthenticationToken(stash secret.Stash, t forge.AuthenticationToken) error {
 	ght := t.(*AuthenticationToken)
-	if f.Options.Token != "" && f.Options.Token == ght.AccessToken {
+	if f.token() != "" && f.token() == ght.AccessToken {
 		// If the user has set GITHUB_TOKEN,
 		// we should not save it to the stash.
 		return nil
@@ -110,10 +122,10 @@ func (f *Forge) SaveAuthenticationToken(stash secret.Stash, t forge.Authenticati
 // LoadAuthenticationToken loads the authentication token from the stash.
 // If the user has set GITHUB_TOKEN, it will be used instead.
 func (f *Forge) LoadAuthenticationToken(stash secret.Stash) (forge.AuthenticationToken, error) {
-	if f.Options.Token != "" {
+	if tok := f.token(); tok != "" {
 		// If the user has set GITHUB_TOKEN, we should use that
 		// regardless of what's in the stash.
-		return &AuthenticationToken{AccessToken: f.Options.Token}, nil
+		return &AuthenticationToken{AccessToken: tok}, nil
 	}
 
 	tokstr, err := stash.LoadSecret(f.URL(), "token")
@@ -0,0 +1,22 @@
+package github
+
+import (
+	"strings"
+
+	"go.abhg.dev/gs/internal/forge"
+)
+
+var _ forge.ChangeIDParser = (*Repository)(nil)
+
+// ParseChangeID parses a reference to a pull request in this repository.
+//
+// Pull requests may be referenced by number ("123", "#123")
+// or by URL.
+func (r *Repository) ParseChangeID(s string) (forge.ChangeID, error) {
+	prURL := strings.TrimSuffix(r.forge.URL(), "/") + "/" + r.owner + "/" + r.repo + "/pull/"
+	n, err := forge.ParseChangeNumber(s, "#", prURL)
+	if err != nil {
+		return nil, err
+	}
+	return &PR{Number: int(n)}, nil
+}
@@ -0,0 +1,39 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/forge"
+)
+
+func TestRepository_ParseChangeID(t *testing.T) {
+	repo := &Repository{owner: "owner", repo: "repo", forge: &Forge{}}
+
+	tests := []struct {
+		give string
+		want int // zero if invalid
+	}{
+		{give: "123", want: 123},
+		{give: "#123", want: 123},
+		{give: "https://github.com/owner/repo/pull/9", want: 9},
+		{give: "https://github.com/owner/repo/pull/9/files", want: 9},
+		{give: "https://github.com/other/repo/pull/9"},
+		{give: "https://github.com/owner/repo/issues/9"},
+		{give: "!123"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.give, func(t *testing.T) {
+			got, err := repo.ParseChangeID(tt.give)
+			if tt.want == 0 {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, forge.ChangeID(&PR{Number: tt.want}), got)
+		})
+	}
+}
@@ -50,6 +50,9 @@ func (r *Repository) ListChangeChecks(
 												URL         string             `graphql:"url"`
 												StartedAt   *githubv4.DateTime `graphql:"startedAt"`
 												CompletedAt *githubv4.DateTime `graphql:"completedAt"`
+												Annotations struct {
+													Nodes []checkAnnotationNode `graphql:"nodes"`
+												} `graphql:"annotations(first: 50) @include(if: $withAnnotations)"`
 											} `graphql:"nodes"`
 										} `graphql:"checkRuns(first: 100)"`
 									} `graphql:"nodes"`
@@ -62,7 +65,8 @@ func (r *Repository) ListChangeChecks(
 		}
 
 		if err := r.client.Query(ctx, &q, map[string]any{
-			"id": gqlID,
+			"id":              gqlID,
+			"withAnnotations": githubv4.Boolean(opts.IncludeAnnotations),
 		}); err != nil {
 			yield(nil, fmt.Errorf("list change checks: %w", err))
 			return
@@ -104,6 +108,9 @@ func (r *Repository) ListChangeChecks(
 				if run.CompletedAt != nil {
 					item.EndedAt = run.CompletedAt.Time
 				}
+				for _, a := range run.Annotations.Nodes {
+					item.Annotations = append(item.Annotations, a.toAnnotation())
+				}
 
 				if !yield(item, nil) {
 					return
@@ -113,6 +120,43 @@ func (r *Repository) ListChangeChecks(
 	}
 }
 
+// checkAnnotationNode is a check run annotation
+// as returned by the GitHub GraphQL API.
+type checkAnnotationNode struct {
+	Path            string `graphql:"path"`
+	AnnotationLevel string `graphql:"annotationLevel"`
+	Title           string `graphql:"title"`
+	Message         string `graphql:"message"`
+	Location        struct {
+		Start struct {
+			Line int `graphql:"line"`
+		} `graphql:"start"`
+		End struct {
+			Line int `graphql:"line"`
+		} `graphql:"end"`
+	} `graphql:"location"`
+}
+
+func (n *checkAnnotationNode) toAnnotation() *forge.CheckAnnotation {
+	var level forge.CheckAnnotationLevel
+	switch strings.ToLower(n.AnnotationLevel) {
+	case "failure":
+		level = forge.CheckAnnotationFailure
+	case "warning":
+		level = forge.CheckAnnotationWarning
+	default:
+		level = forge.CheckAnnotationNotice
+	}
+
+	return &forge.CheckAnnotation{
+		File:      n.Path,
+		LineRange: [2]int{n.Location.Start.Line, n.Location.End.Line},
+		Level:     level,
+		Title:     n.Title,
+		Message:   n.Message,
+	}
+}
+
 // GetCheckLog fetches the log output for the given check run.
 //
 // TODO(v2): Implement by plumbing a *http.Client through NewRepository
@@ -300,6 +300,102 @@ func TestListChangeChecks_emptyCommits(t *testing.T) {
 	assert.Empty(t, items)
 }
 
+// TestListChangeChecks_annotations verifies that annotations are requested
+// only when asked for, and are converted to forge annotations.
+func TestListChangeChecks_annotations(t *testing.T) {
+	response := map[string]any{
+		"data": map[string]any{
+			"node": map[string]any{
+				"commits": map[string]any{
+					"nodes": []map[string]any{{
+						"commit": map[string]any{
+							"checkSuites": map[string]any{
+								"nodes": []map[string]any{{
+									"checkRuns": map[string]any{
+										"nodes": []map[string]any{{
+											"id":         "run1",
+											"name":       "lint",
+											"status":     "COMPLETED",
+											"conclusion": "FAILURE",
+											"annotations": map[string]any{
+												"nodes": []map[string]any{
+													{
+														"path":            "main.go",
+														"annotationLevel": "FAILURE",
+														"title":           "errcheck",
+														"message":         "error return value not checked",
+														"location": map[string]any{
+															"start": map[string]any{"line": 10},
+															"end":   map[string]any{"line": 12},
+														},
+													},
+													{
+														"path":            "README.md",
+														"annotationLevel": "NOTICE",
+														"message":         "consider a heading",
+														"location": map[string]any{
+															"start": map[string]any{"line": 1},
+															"end":   map[string]any{"line": 1},
+														},
+													},
+												},
+											},
+										}},
+									},
+								}},
+							},
+						},
+					}},
+				},
+			},
+		},
+	}
+
+	var gotVariables map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query     string         `json:"query"`
+			Variables map[string]any `json:"variables"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Contains(t, req.Query, "@include(if: $withAnnotations)")
+		gotVariables = req.Variables
+
+		assert.NoError(t, json.NewEncoder(w).Encode(response))
+	}))
+	defer srv.Close()
+
+	repo := newTestRepo(t, srv)
+	prID := &PR{Number: 1, GQLID: "prGQLID"}
+
+	var items []*forge.ChangeCheckItem
+	for item, err := range repo.ListChangeChecks(
+		t.Context(), prID,
+		&forge.ListChangeChecksOptions{IncludeAnnotations: true},
+	) {
+		require.NoError(t, err)
+		items = append(items, item)
+	}
+
+	assert.Equal(t, true, gotVariables["withAnnotations"])
+	require.Len(t, items, 1)
+	assert.Equal(t, []*forge.CheckAnnotation{
+		{
+			File:      "main.go",
+			LineRange: [2]int{10, 12},
+			Level:     forge.CheckAnnotationFailure,
+			Title:     "errcheck",
+			Message:   "error return value not checked",
+		},
+		{
+			File:      "README.md",
+			LineRange: [2]int{1, 1},
+			Level:     forge.CheckAnnotationNotice,
+			Message:   "consider a heading",
+		},
+	}, items[0].Annotations)
+}
+
 // TestGetCheckLog verifies that GetCheckLog returns the
 // forge.ErrCheckLogUnsupported sentinel with the run ID embedded.
 func TestGetCheckLog(t *testing.T) {
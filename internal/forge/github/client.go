@@ -4,13 +4,32 @@ import (
 	"net/http"
 
 	"github.com/shurcooL/githubv4"
+	"go.abhg.dev/gs/internal/forge/ratelimit"
 	"go.abhg.dev/gs/internal/graphqlutil"
+	"go.abhg.dev/gs/internal/silog"
+	"go.abhg.dev/gs/internal/tracing"
 )
 
 func newGitHubEnterpriseClient(
 	url string,
 	httpClient *http.Client,
+	log *silog.Logger,
 ) *githubv4.Client {
-	httpClient.Transport = graphqlutil.WrapTransport(httpClient.Transport)
+	httpClient.Transport = ratelimit.WrapTransport(
+		tracing.WrapTransport(
+			graphqlutil.WrapTransport(httpClient.Transport),
+		),
+		&ratelimit.Options{
+			Log:        log,
+			Idempotent: isIdempotent,
+		},
+	)
 	return githubv4.NewEnterpriseClient(url, httpClient)
 }
+
+// isIdempotent reports whether a request to the GitHub API
+// is safe to retry after a server error.
+// GraphQL queries are sent with POST, but don't have side effects.
+func isIdempotent(req *http.Request) bool {
+	return ratelimit.IdempotentMethod(req) || graphqlutil.IsQuery(req)
+}
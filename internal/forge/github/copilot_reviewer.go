@@ -210,13 +210,25 @@ func (r *Repository) restPost(
 	ctx context.Context,
 	endpoint string,
 	body io.Reader,
+) error {
+	return r.restSend(ctx, http.MethodPost, endpoint, body)
+}
+
+// restSend performs an authenticated request with the given method
+// to the GitHub REST API at the given endpoint with the given JSON body.
+// The response body is discarded.
+func (r *Repository) restSend(
+	ctx context.Context,
+	method string,
+	endpoint string,
+	body io.Reader,
 ) error {
 	reqURL, err := r.restURL(endpoint)
 	if err != nil {
 		return err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL, body)
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, body)
 	if err != nil {
 		return fmt.Errorf("build request: %w", err)
 	}
@@ -230,7 +242,7 @@ func (r *Repository) restPost(
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode >= 400 {
-		return newHTTPStatusError("POST", endpoint, resp)
+		return newHTTPStatusError(method, endpoint, resp)
 	}
 	return nil
 }
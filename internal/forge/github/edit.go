@@ -17,7 +17,13 @@ func (r *Repository) EditChange(ctx context.Context, fid forge.ChangeID, opts fo
 		cmputil.Zero(opts.Draft) &&
 		len(opts.AddLabels) == 0 &&
 		len(opts.AddReviewers) == 0 &&
-		len(opts.AddAssignees) == 0 {
+		len(opts.AddAssignees) == 0 &&
+		len(opts.RemoveLabels) == 0 &&
+		len(opts.RemoveReviewers) == 0 &&
+		len(opts.RemoveAssignees) == 0 &&
+		opts.Milestone == nil &&
+		len(opts.AddProjects) == 0 &&
+		len(opts.RemoveProjects) == 0 {
 		return nil // nothing to do
 	}
 	pr := mustPR(fid)
@@ -118,5 +124,35 @@ func (r *Repository) EditChange(ctx context.Context, fid forge.ChangeID, opts fo
 		return fmt.Errorf("add assignees to PR: %w", err)
 	}
 
+	if err := r.removeLabelsFromPullRequest(ctx, opts.RemoveLabels, graphQLID); err != nil {
+		return fmt.Errorf("remove labels from PR: %w", err)
+	}
+
+	if err := r.removeReviewersFromPullRequest(ctx, opts.RemoveReviewers, pr.Number); err != nil {
+		return fmt.Errorf("remove reviewers from PR: %w", err)
+	}
+
+	if err := r.removeAssigneesFromPullRequest(ctx, opts.RemoveAssignees, graphQLID); err != nil {
+		return fmt.Errorf("remove assignees from PR: %w", err)
+	}
+
+	if opts.Milestone != nil {
+		if err := r.setPullRequestMilestone(ctx, *opts.Milestone, pr.Number); err != nil {
+			return fmt.Errorf("set PR milestone: %w", err)
+		}
+	}
+
+	if len(opts.AddProjects) > 0 {
+		if err := r.addPullRequestToProjects(ctx, opts.AddProjects, graphQLID); err != nil {
+			return fmt.Errorf("add PR to projects: %w", err)
+		}
+	}
+
+	if len(opts.RemoveProjects) > 0 {
+		if err := r.removePullRequestFromProjects(ctx, opts.RemoveProjects, pr.Number); err != nil {
+			return fmt.Errorf("remove PR from projects: %w", err)
+		}
+	}
+
 	return nil
 }
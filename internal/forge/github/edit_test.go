@@ -0,0 +1,170 @@
+package github
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/forge"
+)
+
+func TestEditChange_removeAndMilestone(t *testing.T) {
+	var (
+		removedLabels    map[string]any
+		removedAssignees map[string]any
+		deletedItems     []map[string]any
+		deletedReviewers map[string]any
+		patchedIssue     map[string]any
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		raw, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+
+		var req struct {
+			Query     string         `json:"query"`
+			Variables map[string]any `json:"variables"`
+		}
+		assert.NoError(t, json.Unmarshal(raw, &req))
+
+		var data any
+		switch q := req.Query; {
+		case strings.Contains(q, "removeLabelsFromLabelable"):
+			removedLabels = req.Variables["input"].(map[string]any)
+			data = map[string]any{"removeLabelsFromLabelable": map[string]any{}}
+
+		case strings.Contains(q, "removeAssigneesFromAssignable"):
+			removedAssignees = req.Variables["input"].(map[string]any)
+			data = map[string]any{"removeAssigneesFromAssignable": map[string]any{}}
+
+		case strings.Contains(q, "deleteProjectV2Item"):
+			deletedItems = append(deletedItems, req.Variables["input"].(map[string]any))
+			data = map[string]any{"deleteProjectV2Item": map[string]any{"deletedItemId": "I1"}}
+
+		case strings.Contains(q, "label(name:"):
+			var label any // nil for missing labels
+			if req.Variables["label"] == "bug" {
+				label = map[string]any{"id": "L1"}
+			}
+			data = map[string]any{"repository": map[string]any{"label": label}}
+
+		case strings.Contains(q, "user(login:"):
+			assert.Equal(t, "carol", req.Variables["login"])
+			data = map[string]any{"user": map[string]any{"id": "U3"}}
+
+		case strings.Contains(q, "milestones("):
+			assert.Equal(t, "v1.0", req.Variables["title"])
+			data = map[string]any{"repository": map[string]any{
+				"milestones": map[string]any{"nodes": []any{
+					map[string]any{"number": 4, "title": "v1.0.1"},
+					map[string]any{"number": 3, "title": "v1.0"},
+				}},
+			}}
+
+		case strings.Contains(q, "projectItems("):
+			data = map[string]any{"repository": map[string]any{
+				"pullRequest": map[string]any{
+					"projectItems": map[string]any{"nodes": []any{
+						map[string]any{"id": "I1", "project": map[string]any{"id": "P1", "title": "Roadmap"}},
+						map[string]any{"id": "I2", "project": map[string]any{"id": "P2", "title": "Triage"}},
+					}},
+				},
+			}}
+
+		default:
+			t.Errorf("unexpected query: %s", q)
+		}
+
+		assert.NoError(t, json.NewEncoder(w).Encode(map[string]any{"data": data}))
+	})
+	mux.HandleFunc("DELETE /repos/owner/repo/pulls/1/requested_reviewers", func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&deletedReviewers))
+		_, _ = w.Write([]byte(`{}`))
+	})
+	mux.HandleFunc("PATCH /repos/owner/repo/issues/1", func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&patchedIssue))
+		_, _ = w.Write([]byte(`{}`))
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	repo := newCopilotTestRepo(t, srv)
+	milestone := "v1.0"
+	err := repo.EditChange(t.Context(), &PR{Number: 1, GQLID: "PR1"}, forge.EditChangeOptions{
+		RemoveLabels:    []string{"bug", "missing"},
+		RemoveReviewers: []string{"bob", "acme/core"},
+		RemoveAssignees: []string{"carol"},
+		Milestone:       &milestone,
+		RemoveProjects:  []string{"Roadmap"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]any{
+		"labelableId": "PR1",
+		"labelIds":    []any{"L1"},
+	}, removedLabels)
+	assert.Equal(t, map[string]any{
+		"assignableId": "PR1",
+		"assigneeIds":  []any{"U3"},
+	}, removedAssignees)
+	assert.Equal(t, map[string]any{
+		"reviewers":      []any{"bob"},
+		"team_reviewers": []any{"core"},
+	}, deletedReviewers)
+	assert.Equal(t, map[string]any{"milestone": float64(3)}, patchedIssue)
+	assert.Equal(t, []map[string]any{
+		{"projectId": "P1", "itemId": "I1"},
+	}, deletedItems)
+}
+
+func TestEditChange_clearMilestone(t *testing.T) {
+	var patchedIssue map[string]any
+	mux := http.NewServeMux()
+	mux.HandleFunc("PATCH /repos/owner/repo/issues/1", func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&patchedIssue))
+		_, _ = w.Write([]byte(`{}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	repo := newCopilotTestRepo(t, srv)
+	var milestone string
+	err := repo.EditChange(t.Context(), &PR{Number: 1, GQLID: "PR1"}, forge.EditChangeOptions{
+		Milestone: &milestone,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"milestone": nil}, patchedIssue)
+}
+
+func TestListChangeProjects(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables map[string]any `json:"variables"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, float64(42), req.Variables["number"])
+
+		assert.NoError(t, json.NewEncoder(w).Encode(map[string]any{
+			"data": map[string]any{"repository": map[string]any{
+				"pullRequest": map[string]any{
+					"projectItems": map[string]any{"nodes": []any{
+						map[string]any{"id": "I1", "project": map[string]any{"id": "P1", "title": "Roadmap"}},
+					}},
+				},
+			}},
+		}))
+	}))
+	defer srv.Close()
+
+	repo := newTestRepo(t, srv)
+	got, err := repo.ListChangeProjects(t.Context(), &PR{Number: 42})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Roadmap"}, got)
+}
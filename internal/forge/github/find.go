@@ -16,6 +16,7 @@ type findPRNode struct {
 	Title       githubv4.String           `graphql:"title"`
 	State       githubv4.PullRequestState `graphql:"state"`
 	HeadRefOid  githubv4.GitObjectID      `graphql:"headRefOid"`
+	HeadRefName githubv4.String           `graphql:"headRefName"`
 	BaseRefName githubv4.String           `graphql:"baseRefName"`
 	IsDraft     githubv4.Boolean          `graphql:"isDraft"`
 	Labels      struct {
@@ -39,6 +40,9 @@ type findPRNode struct {
 			Login githubv4.String `graphql:"login"`
 		} `graphql:"nodes"`
 	} `graphql:"assignees(first: 100)"`
+	Milestone *struct {
+		Title githubv4.String `graphql:"title"`
+	} `graphql:"milestone"`
 }
 
 func (n *findPRNode) toFindChangeItem() *forge.FindChangeItem {
@@ -66,6 +70,11 @@ func (n *findPRNode) toFindChangeItem() *forge.FindChangeItem {
 		}
 	}
 
+	var milestone string
+	if n.Milestone != nil {
+		milestone = string(n.Milestone.Title)
+	}
+
 	return &forge.FindChangeItem{
 		ID: &PR{
 			Number: int(n.Number),
@@ -76,10 +85,12 @@ func (n *findPRNode) toFindChangeItem() *forge.FindChangeItem {
 		Subject:   string(n.Title),
 		BaseName:  string(n.BaseRefName),
 		HeadHash:  git.Hash(n.HeadRefOid),
+		HeadName:  string(n.HeadRefName),
 		Draft:     bool(n.IsDraft),
 		Labels:    labels,
 		Reviewers: reviewers,
 		Assignees: assignees,
+		Milestone: milestone,
 	}
 }
 
@@ -13,6 +13,7 @@ import (
 
 	"github.com/shurcooL/githubv4"
 	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/forge/forgecache"
 	"go.abhg.dev/gs/internal/silog"
 	"golang.org/x/oauth2"
 )
@@ -37,7 +38,16 @@ type Options struct {
 
 	// Token is a fixed token used to authenticate with GitHub.
 	// This may be used to skip the login flow.
+	//
+	// This applies only to the host specified by URL.
 	Token string `name:"github-token" hidden:"" env:"GITHUB_TOKEN" help:"GitHub API token"`
+
+	// Hosts lists base URLs of additional GitHub hosts
+	// to support alongside the one specified by URL.
+	// Use this to work with GitHub and GitHub Enterprise at the same time.
+	//
+	// API URLs for these hosts are always inferred from the base URL.
+	Hosts []string `name:"github-hosts" hidden:"" config:"forge.github.hosts" env:"GITHUB_HOSTS" help:"Base URLs of additional GitHub hosts"`
 }
 
 // Forge builds a GitHub Forge.
@@ -46,9 +56,17 @@ type Forge struct {
 
 	// Log specifies the logger to use.
 	Log *silog.Logger
+
+	// host is the base URL of the host this Forge talks to
+	// if it is one of the additional hosts in Options.Hosts.
+	// It is empty for the default host.
+	host string
 }
 
-var _ forge.Forge = (*Forge)(nil)
+var (
+	_ forge.Forge          = (*Forge)(nil)
+	_ forge.MultiHostForge = (*Forge)(nil)
+)
 
 func (f *Forge) logger() *silog.Logger {
 	if f.Log == nil {
@@ -60,20 +78,29 @@ func (f *Forge) logger() *silog.Logger {
 // URL returns the base URL configured for the GitHub Forge
 // or the default URL if none is set.
 func (f *Forge) URL() string {
-	return cmp.Or(f.Options.URL, DefaultURL)
+	return cmp.Or(f.host, f.Options.URL, DefaultURL)
 }
 
 // APIURL returns the base API URL configured for the GitHub Forge
 // or the default URL if none is set.
 func (f *Forge) APIURL() string {
+	if f.host != "" {
+		return inferAPIURL(f.host)
+	}
+
 	if f.Options.APIURL != "" {
 		return f.Options.APIURL
 	}
 
-	// If the API URL is not set, and base URL is NOT github.com,
+	return inferAPIURL(f.Options.URL)
+}
+
+// inferAPIURL infers the API URL for the given GitHub base URL.
+func inferAPIURL(baseURL string) string {
+	// If the base URL is NOT github.com,
 	// assume API URL is $baseURL/api.
-	if f.Options.URL != "" && f.Options.URL != DefaultURL {
-		apiURL, err := url.JoinPath(f.Options.URL, "/api")
+	if baseURL != "" && baseURL != DefaultURL {
+		apiURL, err := url.JoinPath(baseURL, "/api")
 		if err == nil {
 			return apiURL
 		}
@@ -82,6 +109,57 @@ func (f *Forge) APIURL() string {
 	return DefaultAPIURL
 }
 
+// token returns the fixed authentication token for this host, if any.
+// GITHUB_TOKEN applies only to the default host.
+func (f *Forge) token() string {
+	if f.host != "" {
+		return ""
+	}
+	return f.Options.Token
+}
+
+// Hosts returns a Forge for each configured GitHub host,
+// starting with the default host.
+func (f *Forge) Hosts() []forge.MultiHostForge {
+	primary := f
+	if f.host != "" {
+		primary = &Forge{Options: f.Options, Log: f.Log}
+	}
+
+	hosts := []forge.MultiHostForge{primary}
+	seen := map[string]struct{}{primary.URL(): {}}
+	for _, h := range f.Options.Hosts {
+		hostURL := normalizeHostURL(h)
+		if hostURL == "" {
+			continue
+		}
+		if _, ok := seen[hostURL]; ok {
+			continue
+		}
+		seen[hostURL] = struct{}{}
+
+		hosts = append(hosts, &Forge{
+			Options: f.Options,
+			Log:     f.Log,
+			host:    hostURL,
+		})
+	}
+	return hosts
+}
+
+// normalizeHostURL turns a host name or URL
+// into a base URL with a scheme and no trailing slash.
+func normalizeHostURL(host string) string {
+	host = strings.TrimSpace(host)
+	if host == "" {
+		return ""
+	}
+	if !strings.Contains(host, "://") {
+		host = "https://" + host
+	}
+	return strings.TrimRight(host, "/")
+}
+
 // ID reports a unique key for this forge.
 func (*Forge) ID() string { return "github" }
 
@@ -105,20 +183,47 @@ func (f *Forge) ParseRemoteURL(remoteURL string) (forge.RepositoryID, error) {
 
 // OpenRepository opens the GitHub repository that the given ID points to.
 func (f *Forge) OpenRepository(ctx context.Context, tok forge.AuthenticationToken, id forge.RepositoryID) (forge.Repository, error) {
+	return f.openRepository(ctx, tok, id, nil)
+}
+
+var _ forge.CachedRepositoryOpener = (*Forge)(nil)
+
+// OpenCachedRepository opens a GitHub repository,
+// caching its GraphQL ID and the authenticated user's login.
+// REST API responses are revalidated with conditional requests.
+func (f *Forge) OpenCachedRepository(
+	ctx context.Context,
+	tok forge.AuthenticationToken,
+	id forge.RepositoryID,
+	cache *forgecache.Cache,
+) (forge.Repository, error) {
+	// The same owner/repo may exist on different GitHub instances.
+	return f.openRepository(ctx, tok, id, cache.Scope(f.APIURL()))
+}
+
+func (f *Forge) openRepository(
+	ctx context.Context,
+	tok forge.AuthenticationToken,
+	id forge.RepositoryID,
+	cache *forgecache.Cache,
+) (forge.Repository, error) {
 	rid := mustRepositoryID(id)
 
-	tokenSource := tok.(*AuthenticationToken).tokenSource()
-	httpClient := oauth2.NewClient(ctx, tokenSource)
-	ghc, err := newGitHubv4ClientFromHTTP(f.APIURL(), httpClient)
+	ghTok := tok.(*AuthenticationToken)
+	httpClient := oauth2.NewClient(ctx, ghTok.tokenSource())
+	ghc, err := newGitHubv4ClientFromHTTP(f.APIURL(), httpClient, f.logger())
 	if err != nil {
 		return nil, fmt.Errorf("create GitHub client: %w", err)
 	}
+	httpClient.Transport = cache.Transport(httpClient.Transport)
 
 	return newRepository(
 		ctx, f, rid.owner, rid.name, f.logger(), ghc, nil,
 		&repositoryOptions{
 			HTTPClient: httpClient,
 			APIURL:     f.APIURL(),
+			Cache:      cache,
+			ViewerKey:  ghTok.cacheKey(),
 		},
 	)
 }
@@ -154,12 +259,12 @@ func (rid *RepositoryID) ChangeURL(id forge.ChangeID) string {
 // newGitHubv4ClientFromHTTP constructs a GraphQL client targeting the
 // given REST API URL's /graphql endpoint, reusing the provided HTTP
 // client (which must already be authenticated).
-func newGitHubv4ClientFromHTTP(apiURL string, httpClient *http.Client) (*githubv4.Client, error) {
+func newGitHubv4ClientFromHTTP(apiURL string, httpClient *http.Client, log *silog.Logger) (*githubv4.Client, error) {
 	graphQLAPIURL, err := url.JoinPath(apiURL, "/graphql")
 	if err != nil {
 		return nil, fmt.Errorf("build GraphQL API URL: %w", err)
 	}
-	return newGitHubEnterpriseClient(graphQLAPIURL, httpClient), nil
+	return newGitHubEnterpriseClient(graphQLAPIURL, httpClient, log), nil
 }
 
 func extractRepoInfo(githubURL, remoteURL string) (owner, repo string, err error) {
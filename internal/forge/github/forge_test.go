@@ -196,3 +196,62 @@ func TestChangeURL(t *testing.T) {
 	got := repoID.ChangeURL(&PR{Number: 123})
 	assert.Equal(t, "https://github.com/example/repo/pull/123", got)
 }
+
+func TestForgeHosts(t *testing.T) {
+	f := Forge{
+		Options: Options{
+			Token: "default-token",
+			Hosts: []string{
+				"ghe.example.com",
+				"https://ghe.example.com/", // duplicate
+				"https://github.com",       // same as default
+				"",
+				"https://other.example.com",
+			},
+		},
+	}
+
+	hosts := f.Hosts()
+	require.Len(t, hosts, 3)
+
+	var urls, apiURLs []string
+	for _, h := range hosts {
+		urls = append(urls, h.URL())
+		apiURLs = append(apiURLs, h.(*Forge).APIURL())
+	}
+	assert.Equal(t, []string{
+		"https://github.com",
+		"https://ghe.example.com",
+		"https://other.example.com",
+	}, urls)
+	assert.Equal(t, []string{
+		DefaultAPIURL,
+		"https://ghe.example.com/api",
+		"https://other.example.com/api",
+	}, apiURLs)
+
+	t.Run("Token", func(t *testing.T) {
+		assert.Equal(t, "default-token", hosts[0].(*Forge).token())
+		assert.Empty(t, hosts[1].(*Forge).token(),
+			"GITHUB_TOKEN must not apply to additional hosts")
+	})
+
+	t.Run("SameFromAnyHost", func(t *testing.T) {
+		again := hosts[2].Hosts()
+		require.Len(t, again, len(hosts))
+		for i := range hosts {
+			assert.Equal(t, hosts[i].URL(), again[i].URL())
+		}
+	})
+
+	t.Run("ParseRemoteURL", func(t *testing.T) {
+		rid, err := hosts[1].ParseRemoteURL("git@ghe.example.com:foo/bar.git")
+		require.NoError(t, err)
+		assert.Equal(t,
+			"https://ghe.example.com/foo/bar/pull/42",
+			rid.ChangeURL(&PR{Number: 42}))
+
+		_, err = hosts[0].ParseRemoteURL("git@ghe.example.com:foo/bar.git")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,23 @@
+package github
+
+import "go.abhg.dev/gs/internal/forge"
+
+var _ forge.IssueLinker = (*Repository)(nil)
+
+// IssueLinkText returns a closing keyword reference to the given issue.
+//
+// Issues may be referenced by number ("123", "#123"),
+// by qualified number ("owner/repo#123"),
+// or by URL.
+//
+// See https://docs.github.com/en/issues/tracking-your-work-with-issues/using-issues/linking-a-pull-request-to-an-issue
+func (r *Repository) IssueLinkText(issue string) (string, bool) {
+	ref, ok := forge.IssueNumberRef(issue)
+	if !ok {
+		ref, ok = forge.IssueURLRef(issue, r.forge.URL(), "/issues/")
+	}
+	if !ok {
+		return "", false
+	}
+	return "Fixes " + ref, true
+}
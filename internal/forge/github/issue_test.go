@@ -0,0 +1,34 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepository_IssueLinkText(t *testing.T) {
+	repo := &Repository{forge: &Forge{}}
+
+	tests := []struct {
+		give string
+		want string // empty if not linked
+	}{
+		{give: "123", want: "Fixes #123"},
+		{give: "#123", want: "Fixes #123"},
+		{give: "other/repo#4", want: "Fixes other/repo#4"},
+		{
+			give: "https://github.com/owner/repo/issues/9",
+			want: "Fixes https://github.com/owner/repo/issues/9",
+		},
+		{give: "ABC-123"},
+		{give: "https://gitlab.com/owner/repo/-/issues/9"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.give, func(t *testing.T) {
+			got, ok := repo.IssueLinkText(tt.give)
+			assert.Equal(t, tt.want != "", ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
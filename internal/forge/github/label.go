@@ -199,3 +199,40 @@ func (r *Repository) DeleteLabel(ctx context.Context, labelID githubv4.ID) error
 
 	return nil
 }
+
+func (r *Repository) removeLabelsFromPullRequest(ctx context.Context, labels []string, prGraphQLID githubv4.ID) error {
+	if len(labels) == 0 {
+		return nil
+	}
+
+	labelIDs := make([]githubv4.ID, 0, len(labels))
+	for _, name := range labels {
+		id, err := r.LabelID(ctx, name)
+		if err != nil {
+			if errors.Is(err, ErrLabelNotFound) {
+				// A label that doesn't exist can't be on the PR.
+				continue
+			}
+			return fmt.Errorf("get label %q: %w", name, err)
+		}
+		labelIDs = append(labelIDs, id)
+	}
+	if len(labelIDs) == 0 {
+		return nil
+	}
+
+	var m struct {
+		RemoveLabelsFromLabelable struct {
+			Clientmutationid githubv4.String `graphql:"clientMutationId"`
+		} `graphql:"removeLabelsFromLabelable(input: $input)"`
+	}
+
+	input := githubv4.RemoveLabelsFromLabelableInput{
+		LabelableID: prGraphQLID,
+		LabelIDs:    labelIDs,
+	}
+	if err := r.client.Mutate(ctx, &m, input, nil); err != nil {
+		return fmt.Errorf("remove labels from labelable: %w", err)
+	}
+	return nil
+}
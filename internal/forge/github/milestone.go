@@ -0,0 +1,78 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// setPullRequestMilestone sets the milestone of a pull request
+// to the open milestone with the given title.
+// If the title is empty, the milestone is removed.
+//
+// This uses the REST API because the GraphQL API
+// has no way to clear a pull request's milestone.
+func (r *Repository) setPullRequestMilestone(ctx context.Context, title string, prNum int) error {
+	if r.httpClient == nil || r.apiURL == "" {
+		return errors.New("REST client not configured for this repository")
+	}
+
+	var milestone *int // nil removes the milestone
+	if title != "" {
+		number, err := r.milestoneNumber(ctx, title)
+		if err != nil {
+			return err
+		}
+		milestone = &number
+	}
+
+	body, err := json.Marshal(map[string]any{"milestone": milestone})
+	if err != nil {
+		return fmt.Errorf("marshal body: %w", err)
+	}
+
+	// Pull requests share numbers and milestones with issues.
+	endpoint := fmt.Sprintf("/repos/%s/%s/issues/%d", r.owner, r.repo, prNum)
+	if err := r.restSend(ctx, http.MethodPatch, endpoint, bytes.NewReader(body)); err != nil {
+		return fmt.Errorf("update milestone: %w", err)
+	}
+
+	r.log.Debug("Updated PR milestone", "pr", prNum, "milestone", title)
+	return nil
+}
+
+// milestoneNumber finds the number of an open milestone by its title.
+func (r *Repository) milestoneNumber(ctx context.Context, title string) (int, error) {
+	var query struct {
+		Repository struct {
+			Milestones struct {
+				Nodes []struct {
+					Number githubv4.Int    `graphql:"number"`
+					Title  githubv4.String `graphql:"title"`
+				} `graphql:"nodes"`
+			} `graphql:"milestones(query: $title, states: [OPEN], first: 100)"`
+		} `graphql:"repository(owner: $owner, name: $name)"`
+	}
+
+	variables := map[string]any{
+		"owner": githubv4.String(r.owner),
+		"name":  githubv4.String(r.repo),
+		"title": githubv4.String(title),
+	}
+	if err := r.client.Query(ctx, &query, variables); err != nil {
+		return 0, fmt.Errorf("query milestones: %w", err)
+	}
+
+	// The query matches substrings, so look for an exact match.
+	for _, node := range query.Repository.Milestones.Nodes {
+		if string(node.Title) == title {
+			return int(node.Number), nil
+		}
+	}
+	return 0, fmt.Errorf("milestone not found: %q", title)
+}
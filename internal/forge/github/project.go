@@ -0,0 +1,158 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/shurcooL/githubv4"
+	"go.abhg.dev/gs/internal/forge"
+)
+
+var _ forge.ChangeProjectLister = (*Repository)(nil)
+
+// prProjectItem is an item representing a pull request in a project.
+type prProjectItem struct {
+	ID      githubv4.ID `graphql:"id"`
+	Project struct {
+		ID    githubv4.ID     `graphql:"id"`
+		Title githubv4.String `graphql:"title"`
+	} `graphql:"project"`
+}
+
+// ListChangeProjects lists the titles of the projects
+// that a pull request belongs to.
+//
+// This requires the token to have the read:project scope.
+func (r *Repository) ListChangeProjects(ctx context.Context, id forge.ChangeID) ([]string, error) {
+	items, err := r.pullRequestProjectItems(ctx, mustPR(id).Number)
+	if err != nil {
+		return nil, err
+	}
+
+	titles := make([]string, len(items))
+	for i, item := range items {
+		titles[i] = string(item.Project.Title)
+	}
+	return titles, nil
+}
+
+func (r *Repository) pullRequestProjectItems(ctx context.Context, prNum int) ([]prProjectItem, error) {
+	var query struct {
+		Repository struct {
+			PullRequest struct {
+				ProjectItems struct {
+					Nodes []prProjectItem `graphql:"nodes"`
+				} `graphql:"projectItems(first: 100)"`
+			} `graphql:"pullRequest(number: $number)"`
+		} `graphql:"repository(owner: $owner, name: $name)"`
+	}
+
+	variables := map[string]any{
+		"owner":  githubv4.String(r.owner),
+		"name":   githubv4.String(r.repo),
+		"number": githubv4.Int(prNum),
+	}
+	if err := r.client.Query(ctx, &query, variables); err != nil {
+		return nil, fmt.Errorf("query project items: %w", err)
+	}
+	return query.Repository.PullRequest.ProjectItems.Nodes, nil
+}
+
+func (r *Repository) addPullRequestToProjects(ctx context.Context, projects []string, prGraphQLID githubv4.ID) error {
+	var errs []error
+	for _, title := range projects {
+		projectID, err := r.projectID(ctx, title)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		var m struct {
+			AddProjectV2ItemByID struct {
+				Item struct {
+					ID githubv4.ID `graphql:"id"`
+				} `graphql:"item"`
+			} `graphql:"addProjectV2ItemById(input: $input)"`
+		}
+
+		// NB: Adding an item that is already in the project
+		// returns the existing item.
+		input := githubv4.AddProjectV2ItemByIdInput{
+			ProjectID: projectID,
+			ContentID: prGraphQLID,
+		}
+		if err := r.client.Mutate(ctx, &m, input, nil); err != nil {
+			errs = append(errs, fmt.Errorf("add to project %q: %w", title, err))
+			continue
+		}
+		r.log.Debug("Added PR to project", "project", title)
+	}
+	return errors.Join(errs...)
+}
+
+func (r *Repository) removePullRequestFromProjects(ctx context.Context, projects []string, prNum int) error {
+	items, err := r.pullRequestProjectItems(ctx, prNum)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, item := range items {
+		title := string(item.Project.Title)
+		if !slices.Contains(projects, title) {
+			continue
+		}
+
+		var m struct {
+			DeleteProjectV2Item struct {
+				DeletedItemID githubv4.ID `graphql:"deletedItemId"`
+			} `graphql:"deleteProjectV2Item(input: $input)"`
+		}
+
+		input := githubv4.DeleteProjectV2ItemInput{
+			ProjectID: item.Project.ID,
+			ItemID:    item.ID,
+		}
+		if err := r.client.Mutate(ctx, &m, input, nil); err != nil {
+			errs = append(errs, fmt.Errorf("remove from project %q: %w", title, err))
+			continue
+		}
+		r.log.Debug("Removed PR from project", "project", title)
+	}
+	return errors.Join(errs...)
+}
+
+// projectID finds the ID of a project owned by the repository owner
+// by its title.
+func (r *Repository) projectID(ctx context.Context, title string) (githubv4.ID, error) {
+	var query struct {
+		RepositoryOwner struct {
+			ProjectV2Owner struct {
+				ProjectsV2 struct {
+					Nodes []struct {
+						ID    githubv4.ID     `graphql:"id"`
+						Title githubv4.String `graphql:"title"`
+					} `graphql:"nodes"`
+				} `graphql:"projectsV2(query: $query, first: 100)"`
+			} `graphql:"... on ProjectV2Owner"`
+		} `graphql:"repositoryOwner(login: $owner)"`
+	}
+
+	variables := map[string]any{
+		"owner": githubv4.String(r.owner),
+		"query": githubv4.String(title),
+	}
+	if err := r.client.Query(ctx, &query, variables); err != nil {
+		return nil, fmt.Errorf("query projects: %w", err)
+	}
+
+	// The query matches substrings, so look for an exact match.
+	for _, node := range query.RepositoryOwner.ProjectV2Owner.ProjectsV2.Nodes {
+		if string(node.Title) == title {
+			return node.ID, nil
+		}
+	}
+	return nil, fmt.Errorf("project not found: %q", title)
+}
@@ -0,0 +1,82 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/forge/ratelimit"
+	"golang.org/x/oauth2"
+)
+
+var _ forge.QuotaReporter = (*Forge)(nil)
+
+// APIQuotas reports the REST and GraphQL API quotas
+// of the user authenticated with the given token.
+//
+// Returns an empty list for GitHub Enterprise instances
+// that don't have rate limiting enabled.
+func (f *Forge) APIQuotas(ctx context.Context, tok forge.AuthenticationToken) ([]ratelimit.Quota, error) {
+	base, err := restBase(f.APIURL())
+	if err != nil {
+		return nil, fmt.Errorf("derive REST base URL: %w", err)
+	}
+	reqURL, err := url.JoinPath(base, "/rate_limit")
+	if err != nil {
+		return nil, fmt.Errorf("build URL: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+	setRESTHeaders(req)
+
+	// Checking the rate limit doesn't count against it,
+	// so this doesn't need to go through the rate-limited transport.
+	httpClient := oauth2.NewClient(ctx, tok.(*AuthenticationToken).tokenSource())
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		// GitHub Enterprise reports 404 if rate limiting is disabled.
+		return nil, nil
+	case resp.StatusCode >= 400:
+		return nil, newHTTPStatusError("GET", "/rate_limit", resp)
+	}
+
+	var res struct {
+		Resources map[string]struct {
+			Limit     int   `json:"limit"`
+			Remaining int   `json:"remaining"`
+			Reset     int64 `json:"reset"`
+		} `json:"resources"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	// Only report the resources that git-spice uses.
+	var quotas []ratelimit.Quota
+	for _, name := range []string{ratelimit.ResourceCore, ratelimit.ResourceGraphQL} {
+		r, ok := res.Resources[name]
+		if !ok {
+			continue
+		}
+		quotas = append(quotas, ratelimit.Quota{
+			Resource:  name,
+			Limit:     r.Limit,
+			Remaining: r.Remaining,
+			Reset:     time.Unix(r.Reset, 0),
+		})
+	}
+	return quotas, nil
+}
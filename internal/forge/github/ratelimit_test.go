@@ -0,0 +1,70 @@
+package github
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/forge/ratelimit"
+)
+
+func TestForgeAPIQuotas(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/rate_limit", r.URL.Path)
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+
+		_, _ = w.Write([]byte(`{
+			"resources": {
+				"core": {"limit": 5000, "used": 10, "remaining": 4990, "reset": 1714564800},
+				"search": {"limit": 30, "used": 0, "remaining": 30, "reset": 1714561260},
+				"graphql": {"limit": 5000, "used": 4900, "remaining": 100, "reset": 1714563000}
+			}
+		}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	f := Forge{Options: Options{APIURL: srv.URL}}
+	got, err := f.APIQuotas(t.Context(), &AuthenticationToken{AccessToken: "secret"})
+	require.NoError(t, err)
+	assert.Equal(t, []ratelimit.Quota{
+		{
+			Resource:  "core",
+			Limit:     5000,
+			Remaining: 4990,
+			Reset:     time.Unix(1714564800, 0),
+		},
+		{
+			Resource:  "graphql",
+			Limit:     5000,
+			Remaining: 100,
+			Reset:     time.Unix(1714563000, 0),
+		},
+	}, got)
+}
+
+func TestForgeAPIQuotas_disabled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, `{"message": "Rate limiting is not enabled."}`, http.StatusNotFound)
+	}))
+	t.Cleanup(srv.Close)
+
+	f := Forge{Options: Options{APIURL: srv.URL}}
+	got, err := f.APIQuotas(t.Context(), &AuthenticationToken{AccessToken: "secret"})
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func TestForgeAPIQuotas_error(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, `{"message": "Bad credentials"}`, http.StatusUnauthorized)
+	}))
+	t.Cleanup(srv.Close)
+
+	f := Forge{Options: Options{APIURL: srv.URL}}
+	_, err := f.APIQuotas(t.Context(), &AuthenticationToken{AccessToken: "secret"})
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "Bad credentials")
+}
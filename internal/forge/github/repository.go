@@ -4,9 +4,11 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/shurcooL/githubv4"
 	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/forge/forgecache"
 	"go.abhg.dev/gs/internal/silog"
 )
 
@@ -24,6 +26,12 @@ type Repository struct {
 	httpClient *http.Client
 	apiURL     string
 
+	// cache holds metadata cached between invocations.
+	// viewerKey is the key for the authenticated user's login in it.
+	// cache may be nil.
+	cache     *forgecache.Cache
+	viewerKey string
+
 	forge *Forge
 }
 
@@ -50,8 +58,19 @@ type repositoryOptions struct {
 	// REST: <APIURL>/v3/...). The REST path derivation happens
 	// inside restURL — callers pass the GraphQL base.
 	APIURL string
+
+	// Cache, if set, caches the repository ID
+	// and the authenticated user's login between invocations.
+	Cache *forgecache.Cache
+
+	// ViewerKey identifies the authenticated user in Cache.
+	ViewerKey string
 }
 
+// _repositoryIDTTL is how long the GraphQL ID of a repository is cached.
+// It changes only if the repository is deleted and recreated.
+const _repositoryIDTTL = 7 * 24 * time.Hour
+
 func newRepository(
 	ctx context.Context,
 	forge *Forge,
@@ -62,6 +81,17 @@ func newRepository(
 	opt *repositoryOptions,
 ) (*Repository, error) {
 	log = log.With("repo", fmt.Sprintf("%s/%s", owner, repo))
+	if opt == nil {
+		opt = &repositoryOptions{}
+	}
+
+	if repoID == "" || repoID == nil {
+		var cachedID string
+		if opt.Cache.Get(ctx, "repository-id", &cachedID) {
+			repoID = cachedID
+		}
+	}
+
 	if repoID == "" || repoID == nil {
 		var q struct {
 			Repository struct {
@@ -76,10 +106,11 @@ func newRepository(
 		}
 
 		repoID = q.Repository.ID
-	}
-
-	if opt == nil {
-		opt = &repositoryOptions{}
+		if err := opt.Cache.Set(ctx, _repositoryIDTTL, map[string]any{
+			"repository-id": repoID,
+		}); err != nil {
+			log.Debug("Could not cache repository ID", "error", err)
+		}
 	}
 
 	return &Repository{
@@ -90,6 +121,8 @@ func newRepository(
 		repoID:     repoID,
 		httpClient: opt.HTTPClient,
 		apiURL:     opt.APIURL,
+		cache:      opt.Cache,
+		viewerKey:  opt.ViewerKey,
 		forge:      forge,
 	}, nil
 }
@@ -0,0 +1,68 @@
+package github
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shurcooL/githubv4"
+	"go.abhg.dev/gs/internal/forge"
+)
+
+var _ forge.ReviewPoster = (*Repository)(nil)
+
+// PostPendingReview adds a pending review to a pull request
+// with the given inline comments.
+//
+// GitHub allows only one pending review per user on a pull request,
+// so this fails if the user already has one.
+func (r *Repository) PostPendingReview(
+	ctx context.Context,
+	id forge.ChangeID,
+	req *forge.PostReviewRequest,
+) error {
+	gqlID, err := r.graphQLID(ctx, mustPR(id))
+	if err != nil {
+		return err
+	}
+
+	threads := make([]*githubv4.DraftPullRequestReviewThread, len(req.Comments))
+	for i, c := range req.Comments {
+		side := githubv4.DiffSideRight
+		thread := &githubv4.DraftPullRequestReviewThread{
+			Path: githubv4.String(c.Path),
+			Line: githubv4.Int(c.EndLine),
+			Side: &side,
+			Body: githubv4.String(c.Body),
+		}
+		if c.StartLine != c.EndLine {
+			thread.StartLine = githubv4.NewInt(githubv4.Int(c.StartLine))
+			thread.StartSide = &side
+		}
+		threads[i] = thread
+	}
+
+	// Leaving out the event keeps the review pending.
+	input := githubv4.AddPullRequestReviewInput{
+		PullRequestID: gqlID,
+		Threads:       &threads,
+	}
+	if req.Body != "" {
+		input.Body = githubv4.NewString(githubv4.String(req.Body))
+	}
+
+	var m struct {
+		AddPullRequestReview struct {
+			PullRequestReview struct {
+				URL string `graphql:"url"`
+			} `graphql:"pullRequestReview"`
+		} `graphql:"addPullRequestReview(input: $input)"`
+	}
+	if err := r.client.Mutate(ctx, &m, input, nil); err != nil {
+		return fmt.Errorf("add pull request review: %w", err)
+	}
+
+	r.log.Debug("Posted pending review",
+		"pr", mustPR(id).Number,
+		"url", m.AddPullRequestReview.PullRequestReview.URL)
+	return nil
+}
@@ -0,0 +1,93 @@
+package github
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/forge"
+)
+
+func TestPostPendingReview(t *testing.T) {
+	t.Run("HappyPath", func(t *testing.T) {
+		var gotInput map[string]any
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			raw, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+
+			var req struct {
+				Query     string                    `json:"query"`
+				Variables map[string]map[string]any `json:"variables"`
+			}
+			assert.NoError(t, json.Unmarshal(raw, &req))
+			assert.Contains(t, req.Query, "addPullRequestReview")
+			gotInput = req.Variables["input"]
+
+			assert.NoError(t, json.NewEncoder(w).Encode(map[string]any{
+				"data": map[string]any{
+					"addPullRequestReview": map[string]any{
+						"pullRequestReview": map[string]any{
+							"url": "https://github.com/owner/repo/pull/1#pullrequestreview-1",
+						},
+					},
+				},
+			}))
+		}))
+		defer srv.Close()
+
+		repo := newTestRepo(t, srv)
+		err := repo.PostPendingReview(t.Context(), &PR{Number: 1, GQLID: "PR_ID"}, &forge.PostReviewRequest{
+			Body: "summary",
+			Comments: []*forge.ReviewComment{
+				{Path: "foo.go", StartLine: 3, EndLine: 3, Body: "single"},
+				{Path: "bar.go", StartLine: 5, EndLine: 8, Body: "range"},
+			},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]any{
+			"pullRequestId": "PR_ID",
+			"body":          "summary",
+			"threads": []any{
+				map[string]any{
+					"path": "foo.go",
+					"line": float64(3),
+					"side": "RIGHT",
+					"body": "single",
+				},
+				map[string]any{
+					"path":      "bar.go",
+					"line":      float64(8),
+					"side":      "RIGHT",
+					"startLine": float64(5),
+					"startSide": "RIGHT",
+					"body":      "range",
+				},
+			},
+		}, gotInput)
+	})
+
+	t.Run("GraphQLError", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			assert.NoError(t, json.NewEncoder(w).Encode(map[string]any{
+				"errors": []map[string]any{
+					{"message": "User can only have one pending review per pull request"},
+				},
+			}))
+		}))
+		defer srv.Close()
+
+		repo := newTestRepo(t, srv)
+		err := repo.PostPendingReview(t.Context(), &PR{Number: 1, GQLID: "PR_ID"}, &forge.PostReviewRequest{
+			Comments: []*forge.ReviewComment{
+				{Path: "foo.go", StartLine: 3, EndLine: 3, Body: "single"},
+			},
+		})
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "one pending review")
+	})
+}
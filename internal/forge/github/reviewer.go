@@ -1,9 +1,12 @@
 package github
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 	"strings"
 
 	"github.com/shurcooL/githubv4"
@@ -48,6 +51,53 @@ func (r *Repository) addReviewersToPullRequest(
 	return nil
 }
 
+// removeReviewersFromPullRequest removes review requests
+// for the given users and teams from a pull request.
+//
+// GraphQL has no way to remove a single review request,
+// so this uses the REST API.
+func (r *Repository) removeReviewersFromPullRequest(
+	ctx context.Context,
+	reviewers []string,
+	prNum int,
+) error {
+	if len(reviewers) == 0 {
+		return nil
+	}
+	if r.httpClient == nil || r.apiURL == "" {
+		return errors.New("REST client not configured for this repository")
+	}
+
+	users, teams := make([]string, 0, len(reviewers)), make([]string, 0)
+	for _, reviewer := range reviewers {
+		reviewer = strings.TrimSpace(reviewer)
+		if reviewer == "" {
+			continue
+		}
+
+		// The REST API identifies teams by slug alone.
+		if _, teamSlug, ok := strings.Cut(reviewer, "/"); ok {
+			teams = append(teams, teamSlug)
+		} else {
+			users = append(users, reviewer)
+		}
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"reviewers":      users,
+		"team_reviewers": teams,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal body: %w", err)
+	}
+
+	endpoint := fmt.Sprintf(
+		"/repos/%s/%s/pulls/%d/requested_reviewers",
+		r.owner, r.repo, prNum,
+	)
+	return r.restSend(ctx, http.MethodDelete, endpoint, bytes.NewReader(body))
+}
+
 // reviewersIDs resolves reviewer names to GraphQL IDs.
 // Returns separate slices for user IDs and team IDs.
 func (r *Repository) reviewersIDs(
@@ -0,0 +1,67 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/shurcooL/githubv4"
+	"go.abhg.dev/gs/internal/forge"
+)
+
+var _ forge.TeamMemberLister = (*Repository)(nil)
+
+// ListTeamMembers returns the logins of the members of a team
+// given in the form "org/team".
+//
+// Members of child teams are included.
+func (r *Repository) ListTeamMembers(ctx context.Context, team string) ([]string, error) {
+	org, slug, ok := strings.Cut(team, "/")
+	if !ok || org == "" || slug == "" {
+		return nil, fmt.Errorf("team %q is not in the form org/team", team)
+	}
+
+	var q struct {
+		Organization struct {
+			Team *struct {
+				Members struct {
+					PageInfo struct {
+						EndCursor   githubv4.String `graphql:"endCursor"`
+						HasNextPage bool            `graphql:"hasNextPage"`
+					} `graphql:"pageInfo"`
+
+					Nodes []struct {
+						Login string `graphql:"login"`
+					} `graphql:"nodes"`
+				} `graphql:"members(first: 100, after: $after)"`
+			} `graphql:"team(slug: $slug)"`
+		} `graphql:"organization(login: $org)"`
+	}
+	variables := map[string]any{
+		"org":   githubv4.String(org),
+		"slug":  githubv4.String(slug),
+		"after": (*githubv4.String)(nil),
+	}
+
+	var logins []string
+	for pageNum := 1; true; pageNum++ {
+		if err := r.client.Query(ctx, &q, variables); err != nil {
+			return nil, fmt.Errorf("list members of %v (page %d): %w", team, pageNum, err)
+		}
+
+		t := q.Organization.Team
+		if t == nil {
+			return nil, fmt.Errorf("team %v not found", team)
+		}
+		for _, m := range t.Members.Nodes {
+			logins = append(logins, m.Login)
+		}
+
+		if !t.Members.PageInfo.HasNextPage {
+			break
+		}
+		variables["after"] = t.Members.PageInfo.EndCursor
+	}
+
+	return logins, nil
+}
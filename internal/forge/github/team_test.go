@@ -0,0 +1,84 @@
+package github
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListTeamMembers(t *testing.T) {
+	t.Run("Paginated", func(t *testing.T) {
+		pages := []map[string]any{
+			{
+				"pageInfo": map[string]any{"endCursor": "c1", "hasNextPage": true},
+				"nodes":    []any{map[string]any{"login": "alice"}, map[string]any{"login": "bob"}},
+			},
+			{
+				"pageInfo": map[string]any{"endCursor": "c2", "hasNextPage": false},
+				"nodes":    []any{map[string]any{"login": "carol"}},
+			},
+		}
+
+		var afters []any
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			raw, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+
+			var req struct {
+				Query     string         `json:"query"`
+				Variables map[string]any `json:"variables"`
+			}
+			assert.NoError(t, json.Unmarshal(raw, &req))
+			assert.Equal(t, "acme", req.Variables["org"])
+			assert.Equal(t, "platform", req.Variables["slug"])
+			afters = append(afters, req.Variables["after"])
+
+			page := pages[len(afters)-1]
+			assert.NoError(t, json.NewEncoder(w).Encode(map[string]any{
+				"data": map[string]any{
+					"organization": map[string]any{
+						"team": map[string]any{"members": page},
+					},
+				},
+			}))
+		}))
+		defer srv.Close()
+
+		repo := newTestRepo(t, srv)
+		got, err := repo.ListTeamMembers(t.Context(), "acme/platform")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"alice", "bob", "carol"}, got)
+		assert.Equal(t, []any{nil, "c1"}, afters)
+	})
+
+	t.Run("TeamNotFound", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			assert.NoError(t, json.NewEncoder(w).Encode(map[string]any{
+				"data": map[string]any{
+					"organization": map[string]any{"team": nil},
+				},
+			}))
+		}))
+		defer srv.Close()
+
+		repo := newTestRepo(t, srv)
+		_, err := repo.ListTeamMembers(t.Context(), "acme/missing")
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "not found")
+	})
+
+	t.Run("InvalidName", func(t *testing.T) {
+		srv := httptest.NewServer(http.NotFoundHandler())
+		defer srv.Close()
+
+		repo := newTestRepo(t, srv)
+		_, err := repo.ListTeamMembers(t.Context(), "platform")
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "org/team")
+	})
+}
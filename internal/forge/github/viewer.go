@@ -3,14 +3,24 @@ package github
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"go.abhg.dev/gs/internal/forge"
 )
 
 var _ forge.ViewerIdentifier = (*Repository)(nil)
 
+// _viewerTTL is how long the login of the authenticated user is cached.
+const _viewerTTL = 24 * time.Hour
+
 // ViewerLogin returns the GitHub login of the authenticated user.
 func (r *Repository) ViewerLogin(ctx context.Context) (string, error) {
+	key := "viewer/" + r.viewerKey
+	var login string
+	if r.viewerKey != "" && r.cache.Get(ctx, key, &login) {
+		return login, nil
+	}
+
 	var q struct {
 		Viewer struct {
 			Login string `graphql:"login"`
@@ -21,5 +31,10 @@ func (r *Repository) ViewerLogin(ctx context.Context) (string, error) {
 		return "", fmt.Errorf("query viewer: %w", err)
 	}
 
+	if r.viewerKey != "" {
+		if err := r.cache.Set(ctx, _viewerTTL, map[string]any{key: q.Viewer.Login}); err != nil {
+			r.log.Debug("Could not cache viewer login", "error", err)
+		}
+	}
 	return q.Viewer.Login, nil
 }
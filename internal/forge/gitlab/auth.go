@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"cmp"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -43,6 +45,16 @@ type AuthenticationToken struct {
 
 var _ forge.AuthenticationToken = (*AuthenticationToken)(nil)
 
+// cacheKey identifies the user authenticated with this token
+// in cached metadata without revealing the token.
+func (t *AuthenticationToken) cacheKey() string {
+	if t.AuthType == AuthTypeGitLabCLI {
+		return "glab"
+	}
+	sum := sha256.Sum256([]byte(t.AccessToken))
+	return hex.EncodeToString(sum[:8])
+}
+
 // AuthType specifies the kind of authentication method used.
 type AuthType int
 
@@ -130,7 +142,7 @@ func (f *Forge) AuthenticationFlow(ctx context.Context, view ui.View) (forge.Aut
 	log := f.logger()
 	// Already authenticated with GITLAB_TOKEN.
 	// If the user tries to authenticate again, we should error.
-	if f.Options.Token != "" {
+	if f.token() != "" {
 		// NB: alternatively, we can make this a no-op,
 		// and just omit saving it to the stash.
 		// Adjust based on user feedback.
@@ -151,7 +163,7 @@ func (f *Forge) AuthenticationFlow(ctx context.Context, view ui.View) (forge.Aut
 
 	auth, err := selectAuthenticator(view, authenticatorOptions{
 		Endpoint: oauthEndpoint,
-		ClientID: f.Options.ClientID,
+		ClientID: f.clientID(),
 		Hostname: hostname,
 	})
 	if err != nil {
@@ -164,7 +176,7 @@ func (f *Forge) AuthenticationFlow(ctx context.Context, view ui.View) (forge.Aut
 // SaveAuthenticationToken saves the given authentication token to the stash.
 func (f *Forge) SaveAuthenticationToken(stash secret.Stash, t forge.AuthenticationToken) error {
 	ght := t.(*AuthenticationToken)
-	if f.Options.Token != "" && f.Options.Token == ght.AccessToken {
+	if f.token() != "" && f.token() == ght.AccessToken {
 		// If the user has set GITLAB_TOKEN,
 		// we should not save it to the stash.
 		return nil
@@ -203,11 +215,11 @@ func (f *Forge) SaveAuthenticationToken(stash secret.Stash, t forge.Authenticati
 // LoadAuthenticationToken loads the authentication token from the stash.
 // If the user has set GITLAB_TOKEN, it will be used instead.
 func (f *Forge) LoadAuthenticationToken(stash secret.Stash) (forge.AuthenticationToken, error) {
-	if f.Options.Token != "" {
+	if tok := f.token(); tok != "" {
 		// If the user has set GITLAB_TOKEN, we should use that
 		// regardless of what's in the stash.
 		return &AuthenticationToken{
-			AccessToken: f.Options.Token,
+			AccessToken: tok,
 			AuthType:    AuthTypeEnvironmentVariable,
 		}, nil
 	}
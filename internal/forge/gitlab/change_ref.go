@@ -0,0 +1,26 @@
+package gitlab
+
+import (
+	"strings"
+
+	"go.abhg.dev/gs/internal/forge"
+)
+
+var _ forge.ChangeIDParser = (*Repository)(nil)
+
+// ParseChangeID parses a reference to a merge request in this repository.
+//
+// Merge requests may be referenced by number ("123", "!123")
+// or by URL.
+func (r *Repository) ParseChangeID(s string) (forge.ChangeID, error) {
+	webURL := r.webURL
+	if webURL == "" {
+		webURL = strings.TrimSuffix(r.forge.URL(), "/") + "/" + r.owner + "/" + r.repo
+	}
+
+	n, err := forge.ParseChangeNumber(s, "!", webURL+"/-/merge_requests/")
+	if err != nil {
+		return nil, err
+	}
+	return &MR{Number: n}, nil
+}
@@ -0,0 +1,44 @@
+package gitlab
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/forge"
+)
+
+func TestRepository_ParseChangeID(t *testing.T) {
+	repo := &Repository{
+		owner:  "group/sub",
+		repo:   "repo",
+		webURL: "https://gitlab.com/group/sub/repo",
+		forge:  &Forge{},
+	}
+
+	tests := []struct {
+		give string
+		want int64 // zero if invalid
+	}{
+		{give: "123", want: 123},
+		{give: "!123", want: 123},
+		{give: "https://gitlab.com/group/sub/repo/-/merge_requests/9", want: 9},
+		{give: "https://gitlab.com/group/sub/repo/-/merge_requests/9/diffs", want: 9},
+		{give: "https://gitlab.com/group/other/-/merge_requests/9"},
+		{give: "https://gitlab.com/group/sub/repo/-/issues/9"},
+		{give: "#123"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.give, func(t *testing.T) {
+			got, err := repo.ParseChangeID(tt.give)
+			if tt.want == 0 {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, forge.ChangeID(&MR{Number: tt.want}), got)
+		})
+	}
+}
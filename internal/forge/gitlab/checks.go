@@ -0,0 +1,165 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"iter"
+	"strconv"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+	"go.abhg.dev/gs/internal/forge"
+)
+
+var _ forge.ChangeChecksLister = (*Repository)(nil)
+
+// GitLab's API paginates the pipeline jobs listing endpoint.
+var _listChangeChecksPageSize = 100 // var for testing
+
+// ListChangeChecks returns an iterator over jobs
+// in the latest pipeline for the given merge request.
+// If opts is nil, it defaults to &forge.ListChangeChecksOptions{OnlyFailing: true}.
+//
+// Job statuses are mapped to the check statuses and conclusions
+// used by [forge.ChangeCheckItem].
+// Jobs that are allowed to fail are never reported as failing.
+func (r *Repository) ListChangeChecks(
+	ctx context.Context,
+	id forge.ChangeID,
+	opts *forge.ListChangeChecksOptions,
+) iter.Seq2[*forge.ChangeCheckItem, error] {
+	if opts == nil {
+		opts = &forge.ListChangeChecksOptions{OnlyFailing: true}
+	}
+
+	mrNumber := mustMR(id).Number
+	return func(yield func(*forge.ChangeCheckItem, error) bool) {
+		pipelines, _, err := r.client.MergeRequests.ListMergeRequestPipelines(
+			r.repoID, mrNumber,
+			gitlab.WithContext(ctx),
+		)
+		if err != nil {
+			yield(nil, fmt.Errorf("list pipelines: %w", err))
+			return
+		}
+
+		// Pipelines are listed newest first.
+		// There are none if the project doesn't use CI.
+		if len(pipelines) == 0 {
+			return
+		}
+		pipeline := pipelines[0]
+
+		jobOptions := gitlab.ListJobsOptions{
+			ListOptions: gitlab.ListOptions{
+				PerPage: int64(_listChangeChecksPageSize),
+			},
+		}
+		for pageNum := 1; true; pageNum++ {
+			jobs, response, err := r.client.Jobs.ListPipelineJobs(
+				r.repoID, pipeline.ID, &jobOptions,
+				gitlab.WithContext(ctx),
+			)
+			if err != nil {
+				yield(nil, fmt.Errorf("list pipeline jobs (page %d): %w", pageNum, err))
+				return
+			}
+
+			for _, job := range jobs {
+				status, conclusion := jobCheckStatus(job)
+
+				// Apply OnlyFailing filter.
+				if opts.OnlyFailing {
+					switch conclusion {
+					case "failure", "timed_out", "cancelled", "action_required":
+						// Include this job.
+					default:
+						continue
+					}
+				}
+
+				item := &forge.ChangeCheckItem{
+					ID:         forge.CheckRunID(strconv.FormatInt(job.ID, 10)),
+					Name:       job.Name,
+					Status:     status,
+					Conclusion: conclusion,
+					URL:        job.WebURL,
+				}
+				if job.Stage != "" {
+					item.Name = job.Stage + " / " + job.Name
+				}
+				if job.StartedAt != nil {
+					item.StartedAt = *job.StartedAt
+				}
+				if job.FinishedAt != nil {
+					item.EndedAt = *job.FinishedAt
+				}
+
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			if response.CurrentPage >= response.TotalPages {
+				return
+			}
+
+			jobOptions.Page = response.NextPage
+		}
+	}
+}
+
+// jobCheckStatus maps the status of a GitLab CI job
+// to a check status and conclusion.
+func jobCheckStatus(job *gitlab.Job) (status, conclusion string) {
+	switch job.Status {
+	case "created", "pending", "waiting_for_resource", "preparing", "scheduled":
+		return "queued", ""
+	case "running":
+		return "in_progress", ""
+	case "manual":
+		// Manual jobs that aren't allowed to fail block the pipeline.
+		if job.AllowFailure {
+			return "completed", "neutral"
+		}
+		return "completed", "action_required"
+	case "success":
+		return "completed", "success"
+	case "skipped":
+		return "completed", "skipped"
+	case "canceled", "canceling":
+		return "completed", "cancelled"
+	case "failed":
+		switch {
+		case job.AllowFailure:
+			return "completed", "neutral"
+		case job.FailureReason == "job_execution_timeout":
+			return "completed", "timed_out"
+		default:
+			return "completed", "failure"
+		}
+	default:
+		return job.Status, ""
+	}
+}
+
+// GetCheckLog fetches the trace log for the given CI job.
+func (r *Repository) GetCheckLog(
+	ctx context.Context,
+	runID forge.CheckRunID,
+) (io.ReadCloser, error) {
+	jobID, err := strconv.ParseInt(string(runID), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid job ID %q: %w", runID, err)
+	}
+
+	trace, _, err := r.client.Jobs.GetTraceFile(
+		r.repoID, jobID,
+		gitlab.WithContext(ctx),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get job trace: %w", err)
+	}
+
+	return io.NopCloser(trace), nil
+}
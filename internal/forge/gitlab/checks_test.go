@@ -0,0 +1,50 @@
+package gitlab
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+func TestJobCheckStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		give gitlab.Job
+
+		wantStatus     string
+		wantConclusion string
+	}{
+		{"Created", gitlab.Job{Status: "created"}, "queued", ""},
+		{"Pending", gitlab.Job{Status: "pending"}, "queued", ""},
+		{"Running", gitlab.Job{Status: "running"}, "in_progress", ""},
+		{"Success", gitlab.Job{Status: "success"}, "completed", "success"},
+		{"Skipped", gitlab.Job{Status: "skipped"}, "completed", "skipped"},
+		{"Canceled", gitlab.Job{Status: "canceled"}, "completed", "cancelled"},
+		{"Failed", gitlab.Job{Status: "failed"}, "completed", "failure"},
+		{
+			"FailedAllowed",
+			gitlab.Job{Status: "failed", AllowFailure: true},
+			"completed", "neutral",
+		},
+		{
+			"TimedOut",
+			gitlab.Job{Status: "failed", FailureReason: "job_execution_timeout"},
+			"completed", "timed_out",
+		},
+		{"ManualBlocking", gitlab.Job{Status: "manual"}, "completed", "action_required"},
+		{
+			"ManualOptional",
+			gitlab.Job{Status: "manual", AllowFailure: true},
+			"completed", "neutral",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, conclusion := jobCheckStatus(&tt.give)
+			assert.Equal(t, tt.wantStatus, status)
+			assert.Equal(t, tt.wantConclusion, conclusion)
+		})
+	}
+}
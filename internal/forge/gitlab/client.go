@@ -1,23 +1,40 @@
 package gitlab
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"net/http"
 
 	gitlab "gitlab.com/gitlab-org/api/client-go"
+	"go.abhg.dev/gs/internal/forge/forgecache"
+	"go.abhg.dev/gs/internal/forge/ratelimit"
 	"go.abhg.dev/gs/internal/must"
+	"go.abhg.dev/gs/internal/silog"
+	"go.abhg.dev/gs/internal/tracing"
 	"golang.org/x/oauth2"
 )
 
 type gitlabClient struct {
+	Discussions      discussionsService
+	DraftNotes       draftNotesService
+	Groups           groupsService
+	Jobs             jobsService
 	MergeRequests    mergeRequestsService
+	Milestones       milestonesService
 	Notes            notesService
 	Projects         projectsService
 	ProjectTemplates projectTemplatesService
 	Users            usersService
 }
 
-func newGitLabClient(ctx context.Context, baseURL string, tok *AuthenticationToken) (*gitlabClient, error) {
+func newGitLabClient(
+	ctx context.Context,
+	baseURL string,
+	tok *AuthenticationToken,
+	log *silog.Logger,
+	cache *forgecache.Cache,
+) (*gitlabClient, error) {
 	var authSource gitlab.AuthSource
 	switch tok.AuthType {
 	case AuthTypePAT, AuthTypeEnvironmentVariable:
@@ -46,12 +63,27 @@ func newGitLabClient(ctx context.Context, baseURL string, tok *AuthenticationTok
 	must.NotBeNilf(authSource,
 		"No source for authentication type: %v", tok.AuthType)
 
-	client, err := gitlab.NewAuthSourceClient(authSource, gitlab.WithBaseURL(baseURL))
+	// Rate limits and transient failures are handled by our transport
+	// so that they're treated the same way across forges.
+	// GET requests are revalidated with ETags if a cache is available.
+	transport := ratelimit.WrapTransport(cache.Transport(tracing.WrapTransport(nil)), &ratelimit.Options{
+		Log: log,
+	})
+	client, err := gitlab.NewAuthSourceClient(authSource,
+		gitlab.WithBaseURL(baseURL),
+		gitlab.WithHTTPClient(&http.Client{Transport: transport}),
+		gitlab.WithoutRetries(),
+	)
 	if err != nil {
 		return nil, err
 	}
 	return &gitlabClient{
+		Discussions:      client.Discussions,
+		DraftNotes:       client.DraftNotes,
+		Groups:           client.Groups,
+		Jobs:             client.Jobs,
 		MergeRequests:    client.MergeRequests,
+		Milestones:       client.Milestones,
 		Notes:            client.Notes,
 		ProjectTemplates: client.ProjectTemplates,
 		Projects:         client.Projects,
@@ -103,10 +135,25 @@ type mergeRequestsService interface {
 		opt *gitlab.AcceptMergeRequestOptions,
 		options ...gitlab.RequestOptionFunc,
 	) (*gitlab.MergeRequest, *gitlab.Response, error)
+
+	ListMergeRequestPipelines(
+		pid any,
+		mergeRequest int64,
+		options ...gitlab.RequestOptionFunc,
+	) ([]*gitlab.PipelineInfo, *gitlab.Response, error)
 }
 
 var _ mergeRequestsService = gitlab.MergeRequestsServiceInterface(nil)
 
+// milestonesService allows listing milestones.
+type milestonesService interface {
+	ListMilestones(
+		pid any,
+		opt *gitlab.ListMilestonesOptions,
+		options ...gitlab.RequestOptionFunc,
+	) ([]*gitlab.Milestone, *gitlab.Response, error)
+}
+
 // notesService allows posting, listing, and fetching notes (comments)
 // on merge requests.
 type notesService interface {
@@ -139,6 +186,61 @@ type notesService interface {
 	) (*gitlab.Response, error)
 }
 
+// discussionsService allows listing and replying to
+// threaded discussions on merge requests.
+type discussionsService interface {
+	ListMergeRequestDiscussions(
+		pid any,
+		mergeRequest int64,
+		opt *gitlab.ListMergeRequestDiscussionsOptions,
+		options ...gitlab.RequestOptionFunc,
+	) ([]*gitlab.Discussion, *gitlab.Response, error)
+
+	AddMergeRequestDiscussionNote(
+		pid any,
+		mergeRequest int64,
+		discussion string,
+		opt *gitlab.AddMergeRequestDiscussionNoteOptions,
+		options ...gitlab.RequestOptionFunc,
+	) (*gitlab.Note, *gitlab.Response, error)
+}
+
+// draftNotesService allows adding draft notes to merge requests.
+// Draft notes form a pending review until they're published.
+type draftNotesService interface {
+	CreateDraftNote(
+		pid any,
+		mergeRequest int64,
+		opt *gitlab.CreateDraftNoteOptions,
+		options ...gitlab.RequestOptionFunc,
+	) (*gitlab.DraftNote, *gitlab.Response, error)
+}
+
+// groupsService allows listing the members of groups.
+type groupsService interface {
+	ListAllGroupMembers(
+		gid any,
+		opt *gitlab.ListGroupMembersOptions,
+		options ...gitlab.RequestOptionFunc,
+	) ([]*gitlab.GroupMember, *gitlab.Response, error)
+}
+
+// jobsService allows listing CI jobs and fetching their logs.
+type jobsService interface {
+	ListPipelineJobs(
+		pid any,
+		pipelineID int64,
+		opts *gitlab.ListJobsOptions,
+		options ...gitlab.RequestOptionFunc,
+	) ([]*gitlab.Job, *gitlab.Response, error)
+
+	GetTraceFile(
+		pid any,
+		jobID int64,
+		options ...gitlab.RequestOptionFunc,
+	) (*bytes.Reader, *gitlab.Response, error)
+}
+
 // projectsService allows listing and accessing projects.
 type projectsService interface {
 	GetProject(
@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	gitlab "gitlab.com/gitlab-org/api/client-go"
+	"go.abhg.dev/gs/internal/silog/silogtest"
 )
 
 // Client is a GitLab client exported for testing.
@@ -48,7 +49,7 @@ func TestNewGitLabClient_authMethods(t *testing.T) {
 		client, err := newGitLabClient(t.Context(), srv.URL, &AuthenticationToken{
 			AuthType:    AuthTypePAT,
 			AccessToken: "personal-access-token",
-		})
+		}, silogtest.New(t), nil)
 		require.NoError(t, err)
 
 		u, _, err := client.Users.CurrentUser(gitlab.WithContext(t.Context()))
@@ -60,7 +61,7 @@ func TestNewGitLabClient_authMethods(t *testing.T) {
 		client, err := newGitLabClient(t.Context(), srv.URL, &AuthenticationToken{
 			AuthType:    AuthTypeOAuth2,
 			AccessToken: "oauth2-token",
-		})
+		}, silogtest.New(t), nil)
 		require.NoError(t, err)
 
 		u, _, err := client.Users.CurrentUser(gitlab.WithContext(t.Context()))
@@ -72,7 +73,7 @@ func TestNewGitLabClient_authMethods(t *testing.T) {
 		client, err := newGitLabClient(t.Context(), srv.URL, &AuthenticationToken{
 			AuthType:    AuthTypeEnvironmentVariable,
 			AccessToken: "pat-from-env",
-		})
+		}, silogtest.New(t), nil)
 		require.NoError(t, err)
 
 		u, _, err := client.Users.CurrentUser(gitlab.WithContext(t.Context()))
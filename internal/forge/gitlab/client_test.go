@@ -3,7 +3,11 @@ package gitlab
 import gitlab "gitlab.com/gitlab-org/api/client-go"
 
 var (
+	_ discussionsService      = (*gitlab.DiscussionsService)(nil)
+	_ groupsService           = (*gitlab.GroupsService)(nil)
+	_ jobsService             = (*gitlab.JobsService)(nil)
 	_ mergeRequestsService    = (*gitlab.MergeRequestsService)(nil)
+	_ milestonesService       = (*gitlab.MilestonesService)(nil)
 	_ notesService            = (*gitlab.NotesService)(nil)
 	_ projectsService         = (*gitlab.ProjectsService)(nil)
 	_ projectTemplatesService = (*gitlab.ProjectTemplatesService)(nil)
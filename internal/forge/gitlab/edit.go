@@ -21,11 +21,20 @@ var _draftRegex = regexp.MustCompile(`(?i)^\s*(\[Draft]|Draft:|\(Draft\))\s*`)
 
 // EditChange edits an existing change in a repository.
 func (r *Repository) EditChange(ctx context.Context, id forge.ChangeID, opts forge.EditChangeOptions) error {
+	if len(opts.AddProjects) > 0 || len(opts.RemoveProjects) > 0 {
+		r.log.Warn("GitLab does not support adding merge requests to projects. Ignoring.",
+			"add", opts.AddProjects, "remove", opts.RemoveProjects)
+	}
+
 	if cmputil.Zero(opts.Base) &&
 		cmputil.Zero(opts.Draft) &&
+		cmputil.Zero(opts.Milestone) &&
 		len(opts.AddLabels) == 0 &&
+		len(opts.RemoveLabels) == 0 &&
 		len(opts.AddReviewers) == 0 &&
-		len(opts.AddAssignees) == 0 {
+		len(opts.RemoveReviewers) == 0 &&
+		len(opts.AddAssignees) == 0 &&
+		len(opts.RemoveAssignees) == 0 {
 		return nil // nothing to do
 	}
 
@@ -85,11 +94,18 @@ func (r *Repository) EditChange(ctx context.Context, id forge.ChangeID, opts for
 	if len(opts.AddLabels) > 0 {
 		updateOptions.AddLabels = (*gitlab.LabelOptions)(&opts.AddLabels)
 	}
+	if len(opts.RemoveLabels) > 0 {
+		updateOptions.RemoveLabels = (*gitlab.LabelOptions)(&opts.RemoveLabels)
+	}
 
-	if len(opts.AddReviewers) > 0 {
-		reviewerIDs, err := r.resolveReviewerIDs(ctx, opts.AddReviewers)
-		if err != nil {
-			return fmt.Errorf("resolve reviewer IDs: %w", err)
+	if len(opts.AddReviewers) > 0 || len(opts.RemoveReviewers) > 0 {
+		var reviewerIDs []int64
+		if len(opts.AddReviewers) > 0 {
+			var err error
+			reviewerIDs, err = r.resolveReviewerIDs(ctx, opts.AddReviewers)
+			if err != nil {
+				return fmt.Errorf("resolve reviewer IDs: %w", err)
+			}
 		}
 
 		mr, err := getMergeRequest()
@@ -97,15 +113,19 @@ func (r *Repository) EditChange(ctx context.Context, id forge.ChangeID, opts for
 			return err
 		}
 
-		merged := mergeAssigneeIDs(mr.Reviewers, reviewerIDs)
+		merged := mergeAssigneeIDs(mr.Reviewers, reviewerIDs, opts.RemoveReviewers)
 		updateOptions.ReviewerIDs = &merged
 		logUpdates = append(logUpdates, slog.Any("reviewers", merged))
 	}
 
-	if len(opts.AddAssignees) > 0 {
-		assigneeIDs, err := r.assigneeIDs(ctx, opts.AddAssignees)
-		if err != nil {
-			return fmt.Errorf("resolve assignees: %w", err)
+	if len(opts.AddAssignees) > 0 || len(opts.RemoveAssignees) > 0 {
+		var assigneeIDs []int64
+		if len(opts.AddAssignees) > 0 {
+			var err error
+			assigneeIDs, err = r.assigneeIDs(ctx, opts.AddAssignees)
+			if err != nil {
+				return fmt.Errorf("resolve assignees: %w", err)
+			}
 		}
 
 		mr, err := getMergeRequest()
@@ -113,11 +133,25 @@ func (r *Repository) EditChange(ctx context.Context, id forge.ChangeID, opts for
 			return err
 		}
 
-		merged := mergeAssigneeIDs(mr.Assignees, assigneeIDs)
+		merged := mergeAssigneeIDs(mr.Assignees, assigneeIDs, opts.RemoveAssignees)
 		updateOptions.AssigneeIDs = &merged
 		logUpdates = append(logUpdates, slog.Any("assignees", merged))
 	}
 
+	if opts.Milestone != nil {
+		// A milestone ID of 0 removes the milestone.
+		var milestoneID int64
+		if *opts.Milestone != "" {
+			var err error
+			milestoneID, err = r.milestoneID(ctx, *opts.Milestone)
+			if err != nil {
+				return err
+			}
+		}
+		updateOptions.MilestoneID = &milestoneID
+		logUpdates = append(logUpdates, slog.String("milestone", *opts.Milestone))
+	}
+
 	_, _, err := r.client.MergeRequests.UpdateMergeRequest(
 		r.repoID, mrID.Number, &updateOptions,
 		gitlab.WithContext(ctx),
@@ -135,13 +169,40 @@ func (r *Repository) EditChange(ctx context.Context, id forge.ChangeID, opts for
 	return nil
 }
 
-func mergeAssigneeIDs(existing []*gitlab.BasicUser, assignees []int64) []int64 {
+// mergeAssigneeIDs returns the IDs of the existing users
+// with the given IDs added and the users with the given usernames removed.
+//
+// The result is never nil so that removing all users
+// sends an empty list to GitLab instead of leaving the field unchanged.
+func mergeAssigneeIDs(existing []*gitlab.BasicUser, assignees []int64, remove []string) []int64 {
 	seen := make(map[int64]struct{}, len(existing)+len(assignees))
 	for _, user := range existing {
+		if slices.Contains(remove, user.Username) {
+			continue
+		}
 		seen[user.ID] = struct{}{}
 	}
 	for _, id := range assignees {
 		seen[id] = struct{}{}
 	}
-	return slices.Sorted(maps.Keys(seen))
+	ids := slices.AppendSeq(make([]int64, 0, len(seen)), maps.Keys(seen))
+	slices.Sort(ids)
+	return ids
+}
+
+// milestoneID finds the ID of an active milestone by title.
+// Milestones of the project's groups are included.
+func (r *Repository) milestoneID(ctx context.Context, title string) (int64, error) {
+	milestones, _, err := r.client.Milestones.ListMilestones(r.repoID, &gitlab.ListMilestonesOptions{
+		Title:            &title,
+		State:            gitlab.Ptr("active"),
+		IncludeAncestors: gitlab.Ptr(true),
+	}, gitlab.WithContext(ctx))
+	if err != nil {
+		return 0, fmt.Errorf("find milestone %q: %w", title, err)
+	}
+	if len(milestones) == 0 {
+		return 0, fmt.Errorf("milestone %q not found", title)
+	}
+	return milestones[0].ID, nil
 }
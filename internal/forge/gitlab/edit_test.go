@@ -0,0 +1,140 @@
+package gitlab
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/silog/silogtest"
+)
+
+func TestEditChange_removeAndMilestone(t *testing.T) {
+	var update map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		enc := json.NewEncoder(w)
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v4/projects/100":
+			assert.NoError(t, enc.Encode(newProject(100, nil, nil)))
+
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v4/user":
+			assert.NoError(t, enc.Encode(gitlab.User{ID: 1}))
+
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v4/users":
+			assert.Equal(t, "carol", r.URL.Query().Get("username"))
+			assert.NoError(t, enc.Encode([]*gitlab.User{{ID: 30, Username: "carol"}}))
+
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v4/projects/100/milestones":
+			assert.Equal(t, "v1.0", r.URL.Query().Get("title"))
+			assert.NoError(t, enc.Encode([]*gitlab.Milestone{{ID: 7, Title: "v1.0"}}))
+
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v4/projects/100/merge_requests/1":
+			assert.NoError(t, enc.Encode(gitlab.MergeRequest{
+				BasicMergeRequest: gitlab.BasicMergeRequest{
+					Reviewers: []*gitlab.BasicUser{
+						{ID: 10, Username: "alice"},
+						{ID: 20, Username: "bob"},
+					},
+					Assignees: []*gitlab.BasicUser{
+						{ID: 10, Username: "alice"},
+					},
+				},
+			}))
+
+		case r.Method == http.MethodPut && r.URL.Path == "/api/v4/projects/100/merge_requests/1":
+			raw, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			assert.NoError(t, json.Unmarshal(raw, &update))
+			assert.NoError(t, enc.Encode(gitlab.MergeRequest{}))
+
+		default:
+			t.Errorf("unexpected request: %v %v", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := newGitLabClient(t.Context(), srv.URL, &AuthenticationToken{
+		AuthType:    AuthTypePAT,
+		AccessToken: "token",
+	}, silogtest.New(t), nil)
+	require.NoError(t, err)
+	repoID := int64(100)
+	repo, err := newRepository(
+		t.Context(), new(Forge),
+		"owner", "repo",
+		silogtest.New(t),
+		client,
+		&repositoryOptions{RepositoryID: &repoID},
+	)
+	require.NoError(t, err)
+
+	milestone := "v1.0"
+	err = repo.EditChange(t.Context(), &MR{Number: 1}, forge.EditChangeOptions{
+		RemoveLabels:    []string{"stale"},
+		AddReviewers:    []string{"carol"},
+		RemoveReviewers: []string{"bob"},
+		RemoveAssignees: []string{"alice"},
+		Milestone:       &milestone,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]any{
+		"remove_labels": "stale",
+		"reviewer_ids":  []any{float64(10), float64(30)},
+		"assignee_ids":  []any{},
+		"milestone_id":  float64(7),
+	}, update)
+}
+
+func TestEditChange_clearMilestone(t *testing.T) {
+	var update map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		enc := json.NewEncoder(w)
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v4/projects/100":
+			assert.NoError(t, enc.Encode(newProject(100, nil, nil)))
+
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v4/user":
+			assert.NoError(t, enc.Encode(gitlab.User{ID: 1}))
+
+		case r.Method == http.MethodPut && r.URL.Path == "/api/v4/projects/100/merge_requests/1":
+			raw, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			assert.NoError(t, json.Unmarshal(raw, &update))
+			assert.NoError(t, enc.Encode(gitlab.MergeRequest{}))
+
+		default:
+			t.Errorf("unexpected request: %v %v", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := newGitLabClient(t.Context(), srv.URL, &AuthenticationToken{
+		AuthType:    AuthTypePAT,
+		AccessToken: "token",
+	}, silogtest.New(t), nil)
+	require.NoError(t, err)
+	repoID := int64(100)
+	repo, err := newRepository(
+		t.Context(), new(Forge),
+		"owner", "repo",
+		silogtest.New(t),
+		client,
+		&repositoryOptions{RepositoryID: &repoID},
+	)
+	require.NoError(t, err)
+
+	var milestone string
+	err = repo.EditChange(t.Context(), &MR{Number: 1}, forge.EditChangeOptions{
+		Milestone: &milestone,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"milestone_id": float64(0)}, update)
+}
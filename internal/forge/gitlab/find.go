@@ -40,10 +40,12 @@ func basicMergeRequestToFindChangeItem(mr *gitlab.BasicMergeRequest) *forge.Find
 		Subject:   mr.Title,
 		BaseName:  mr.TargetBranch,
 		HeadHash:  git.Hash(mr.SHA),
+		HeadName:  mr.SourceBranch,
 		Draft:     mr.Draft,
 		Labels:    labels,
 		Reviewers: reviewers,
 		Assignees: assignees,
+		Milestone: milestoneTitle(mr.Milestone),
 	}
 }
 
@@ -78,13 +80,24 @@ func mergeRequestToFindChangeItem(mr *gitlab.MergeRequest) *forge.FindChangeItem
 		Subject:   mr.Title,
 		BaseName:  mr.TargetBranch,
 		HeadHash:  git.Hash(mr.SHA),
+		HeadName:  mr.SourceBranch,
 		Draft:     mr.Draft,
 		Labels:    labels,
 		Reviewers: reviewers,
 		Assignees: assignees,
+		Milestone: milestoneTitle(mr.Milestone),
 	}
 }
 
+// milestoneTitle returns the title of a milestone,
+// or an empty string if there isn't one.
+func milestoneTitle(m *gitlab.Milestone) string {
+	if m == nil {
+		return ""
+	}
+	return m.Title
+}
+
 func mergeRequestState(s forge.ChangeState) string {
 	switch s {
 	case forge.ChangeOpen:
@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/forge/forgecache"
 	"go.abhg.dev/gs/internal/silog"
 )
 
@@ -42,6 +43,13 @@ type Options struct {
 	// RemoveSourceBranch specifies whether a branch should be deleted
 	// after its Merge Request is merged.
 	RemoveSourceBranch bool `name:"gitlab-remove-source-branch" hidden:"" config:"forge.gitlab.removeSourceBranch" default:"true" help:"Remove source branch after merging a merge request"`
+
+	// Hosts lists base URLs of additional GitLab instances
+	// to support alongside the one specified by URL.
+	//
+	// API URLs for these hosts are always the same as the base URL,
+	// and the OAuth client ID is not used for them.
+	Hosts []string `name:"gitlab-hosts" hidden:"" config:"forge.gitlab.hosts" env:"GITLAB_HOSTS" help:"Base URLs of additional GitLab hosts"`
 }
 
 // Forge builds a GitLab Forge.
@@ -50,9 +58,17 @@ type Forge struct {
 
 	// Log specifies the logger to use.
 	Log *silog.Logger
+
+	// host is the base URL of the host this Forge talks to
+	// if it is one of the additional hosts in Options.Hosts.
+	// It is empty for the default host.
+	host string
 }
 
-var _ forge.Forge = (*Forge)(nil)
+var (
+	_ forge.Forge          = (*Forge)(nil)
+	_ forge.MultiHostForge = (*Forge)(nil)
+)
 
 func (f *Forge) logger() *silog.Logger {
 	if f.Log == nil {
@@ -64,15 +80,77 @@ func (f *Forge) logger() *silog.Logger {
 // URL returns the base URL configured for the GitLab Forge
 // or the default URL if none is set.
 func (f *Forge) URL() string {
-	return cmp.Or(f.Options.URL, DefaultURL)
+	return cmp.Or(f.host, f.Options.URL, DefaultURL)
 }
 
 // APIURL returns the base API URL configured for the GitHub Forge
 // or the default URL if none is set.
 func (f *Forge) APIURL() string {
+	if f.host != "" {
+		return f.host
+	}
 	return cmp.Or(f.Options.APIURL, f.URL())
 }
 
+// token returns the fixed authentication token for this host, if any.
+// GITLAB_TOKEN applies only to the default host.
+func (f *Forge) token() string {
+	if f.host != "" {
+		return ""
+	}
+	return f.Options.Token
+}
+
+// clientID returns the OAuth client ID for this host, if any.
+func (f *Forge) clientID() string {
+	if f.host != "" {
+		return ""
+	}
+	return f.Options.ClientID
+}
+
+// Hosts returns a Forge for each configured GitLab host,
+// starting with the default host.
+func (f *Forge) Hosts() []forge.MultiHostForge {
+	primary := f
+	if f.host != "" {
+		primary = &Forge{Options: f.Options, Log: f.Log}
+	}
+
+	hosts := []forge.MultiHostForge{primary}
+	seen := map[string]struct{}{primary.URL(): {}}
+	for _, h := range f.Options.Hosts {
+		hostURL := normalizeHostURL(h)
+		if hostURL == "" {
+			continue
+		}
+		if _, ok := seen[hostURL]; ok {
+			continue
+		}
+		seen[hostURL] = struct{}{}
+
+		hosts = append(hosts, &Forge{
+			Options: f.Options,
+			Log:     f.Log,
+			host:    hostURL,
+		})
+	}
+	return hosts
+}
+
+// normalizeHostURL turns a host name or URL
+// into a base URL with a scheme and no trailing slash.
+func normalizeHostURL(host string) string {
+	host = strings.TrimSpace(host)
+	if host == "" {
+		return ""
+	}
+	if !strings.Contains(host, "://") {
+		host = "https://" + host
+	}
+	return strings.TrimRight(host, "/")
+}
+
 // ID reports a unique key for this forge.
 func (*Forge) ID() string { return "gitlab" }
 
@@ -98,15 +176,42 @@ func (f *Forge) ParseRemoteURL(remoteURL string) (forge.RepositoryID, error) {
 
 // OpenRepository opens the GitLab repository that the given ID points to.
 func (f *Forge) OpenRepository(ctx context.Context, token forge.AuthenticationToken, id forge.RepositoryID) (forge.Repository, error) {
+	return f.openRepository(ctx, token, id, nil)
+}
+
+var _ forge.CachedRepositoryOpener = (*Forge)(nil)
+
+// OpenCachedRepository opens a GitLab repository,
+// caching the project's metadata and the current user.
+// API responses are revalidated with conditional requests.
+func (f *Forge) OpenCachedRepository(
+	ctx context.Context,
+	token forge.AuthenticationToken,
+	id forge.RepositoryID,
+	cache *forgecache.Cache,
+) (forge.Repository, error) {
+	// The same owner/repo may exist on different GitLab instances.
+	return f.openRepository(ctx, token, id, cache.Scope(f.APIURL()))
+}
+
+func (f *Forge) openRepository(
+	ctx context.Context,
+	token forge.AuthenticationToken,
+	id forge.RepositoryID,
+	cache *forgecache.Cache,
+) (forge.Repository, error) {
 	rid := mustRepositoryID(id)
 
-	glc, err := newGitLabClient(ctx, f.APIURL(), token.(*AuthenticationToken))
+	glTok := token.(*AuthenticationToken)
+	glc, err := newGitLabClient(ctx, f.APIURL(), glTok, f.logger(), cache)
 	if err != nil {
 		return nil, fmt.Errorf("create GitLab client: %w", err)
 	}
 
 	return newRepository(ctx, f, rid.owner, rid.name, f.logger(), glc, &repositoryOptions{
 		RemoveSourceBranchOnMerge: f.Options.RemoveSourceBranch,
+		Cache:                     cache,
+		UserKey:                   glTok.cacheKey(),
 	})
 }
 
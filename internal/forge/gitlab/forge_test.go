@@ -196,3 +196,36 @@ func TestChangeURL(t *testing.T) {
 	got := repoID.ChangeURL(&MR{Number: 42})
 	assert.Equal(t, "https://gitlab.com/example/repo/-/merge_requests/42", got)
 }
+
+func TestForgeHosts(t *testing.T) {
+	f := Forge{
+		Options: Options{
+			Token:    "default-token",
+			ClientID: "default-client",
+			Hosts: []string{
+				"gitlab.example.com",
+				"https://gitlab.example.com/", // duplicate
+				"https://gitlab.com",          // same as default
+			},
+		},
+	}
+
+	hosts := f.Hosts()
+	require.Len(t, hosts, 2)
+
+	primary, extra := hosts[0].(*Forge), hosts[1].(*Forge)
+	assert.Equal(t, DefaultURL, primary.URL())
+	assert.Equal(t, "https://gitlab.example.com", extra.URL())
+	assert.Equal(t, "https://gitlab.example.com", extra.APIURL())
+
+	assert.Equal(t, "default-token", primary.token())
+	assert.Equal(t, "default-client", primary.clientID())
+	assert.Empty(t, extra.token())
+	assert.Empty(t, extra.clientID())
+
+	rid, err := extra.ParseRemoteURL("https://gitlab.example.com/foo/bar.git")
+	require.NoError(t, err)
+	assert.Equal(t,
+		"https://gitlab.example.com/foo/bar/-/merge_requests/42",
+		rid.ChangeURL(&MR{Number: 42}))
+}
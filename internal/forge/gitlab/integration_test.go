@@ -3,11 +3,13 @@ package gitlab_test
 import (
 	"context"
 	"crypto/rand"
+	"io"
 	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -56,6 +58,8 @@ func newGitLabClient(
 	}
 	client, _ := gogitlab.NewClient(token, gogitlab.WithHTTPClient(httpClient))
 	return &gitlab.Client{
+		Discussions:      client.Discussions,
+		Jobs:             client.Jobs,
 		MergeRequests:    client.MergeRequests,
 		Notes:            client.Notes,
 		ProjectTemplates: client.ProjectTemplates,
@@ -269,3 +273,148 @@ func randomString(n int) string {
 	}
 	return string(b)
 }
+
+func TestIntegration_Repository_ReviewThreads(t *testing.T) {
+	ctx := t.Context()
+	rec := newRecorder(t, t.Name())
+	ghc := newGitLabClient(rec.GetDefaultClient())
+	repo, err := gitlab.NewRepository(
+		ctx, new(gitlab.Forge), "abg", "test-repo",
+		silogtest.New(t), ghc,
+		&gitlab.RepositoryOptions{RepositoryID: _testRepoID},
+	)
+	require.NoError(t, err)
+
+	login, err := repo.ViewerLogin(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "gr4cza", login)
+
+	t.Run("Unresolved", func(t *testing.T) {
+		var threads []*forge.ReviewThreadItem
+		for thread, err := range repo.ListReviewThreads(ctx, &gitlab.MR{Number: 3}, nil) {
+			require.NoError(t, err)
+			threads = append(threads, thread)
+		}
+
+		require.Len(t, threads, 3)
+
+		assert.Equal(t, &forge.ReviewThreadItem{
+			ID:        "3:a3b2c7e8f9d0a1b2c3d4e5f6a7b5f0c2b1f6a1d0",
+			File:      "feature.go",
+			LineRange: [2]int{12, 12},
+			Author:    "abg",
+			Body:      "Handle the error here.",
+			Replies: []forge.ReviewReply{
+				{
+					Author:    "gr4cza",
+					Body:      "Done.",
+					CreatedAt: time.Date(2025, 7, 1, 11, 0, 0, 0, time.UTC),
+				},
+			},
+			URL: "https://gitlab.com/abg/test-repo/-/merge_requests/3#note_2242410010",
+		}, threads[0])
+
+		// Note on removed lines.
+		assert.Equal(t, "old.go", threads[1].File)
+		assert.Equal(t, [2]int{5, 7}, threads[1].LineRange)
+
+		// Thread that isn't on the diff.
+		assert.Empty(t, threads[2].File)
+		assert.Equal(t, [2]int{0, 0}, threads[2].LineRange)
+		assert.Equal(t, "Please add a test.", threads[2].Body)
+	})
+
+	t.Run("IncludeResolvedAndBots", func(t *testing.T) {
+		var bodies []string
+		for thread, err := range repo.ListReviewThreads(ctx, &gitlab.MR{Number: 3}, &forge.ListReviewThreadsOptions{
+			IncludeResolved: true,
+			BotAllowlist:    []string{"project_64779801_bot_3f9a1c"},
+		}) {
+			require.NoError(t, err)
+			bodies = append(bodies, thread.Body)
+		}
+
+		assert.Equal(t, []string{
+			"Handle the error here.",
+			"Why remove these?",
+			"Typo in the comment.",
+			"Please add a test.",
+			"Consider a constant.",
+		}, bodies)
+	})
+
+	t.Run("Reply", func(t *testing.T) {
+		id, err := repo.PostReviewThreadReply(ctx,
+			"3:a3b2c7e8f9d0a1b2c3d4e5f6a7b5f0c2b1f6a1d0",
+			"Addressed in b7e2a910.")
+		require.NoError(t, err)
+		assert.Equal(t, &gitlab.MRComment{Number: 2242410060, MRNumber: 3}, id)
+	})
+}
+
+func TestIntegration_Repository_ChangeChecks(t *testing.T) {
+	ctx := t.Context()
+	rec := newRecorder(t, t.Name())
+	ghc := newGitLabClient(rec.GetDefaultClient())
+	repo, err := gitlab.NewRepository(
+		ctx, new(gitlab.Forge), "abg", "test-repo",
+		silogtest.New(t), ghc,
+		&gitlab.RepositoryOptions{RepositoryID: _testRepoID},
+	)
+	require.NoError(t, err)
+
+	t.Run("OnlyFailing", func(t *testing.T) {
+		var checks []*forge.ChangeCheckItem
+		for check, err := range repo.ListChangeChecks(ctx, &gitlab.MR{Number: 3}, nil) {
+			require.NoError(t, err)
+			checks = append(checks, check)
+		}
+
+		assert.Equal(t, []*forge.ChangeCheckItem{
+			{
+				ID:         "10522002",
+				Name:       "test / unit",
+				Status:     "completed",
+				Conclusion: "failure",
+				URL:        "https://gitlab.com/abg/test-repo/-/jobs/10522002",
+				StartedAt:  time.Date(2025, 7, 1, 12, 3, 20, 0, time.UTC),
+				EndedAt:    time.Date(2025, 7, 1, 12, 6, 20, 0, time.UTC),
+			},
+			{
+				ID:         "10522004",
+				Name:       "test / integration",
+				Status:     "completed",
+				Conclusion: "timed_out",
+				URL:        "https://gitlab.com/abg/test-repo/-/jobs/10522004",
+				StartedAt:  time.Date(2025, 7, 1, 12, 3, 20, 0, time.UTC),
+				EndedAt:    time.Date(2025, 7, 1, 12, 9, 20, 0, time.UTC),
+			},
+		}, checks)
+	})
+
+	t.Run("All", func(t *testing.T) {
+		conclusions := make(map[string]string)
+		for check, err := range repo.ListChangeChecks(ctx, &gitlab.MR{Number: 3}, &forge.ListChangeChecksOptions{}) {
+			require.NoError(t, err)
+			conclusions[check.Name] = check.Conclusion
+		}
+
+		assert.Equal(t, map[string]string{
+			"build / compile":     "success",
+			"test / unit":         "failure",
+			"test / lint":         "neutral",
+			"test / integration":  "timed_out",
+			"deploy / review-app": "neutral",
+		}, conclusions)
+	})
+
+	t.Run("Log", func(t *testing.T) {
+		r, err := repo.GetCheckLog(ctx, "10522002")
+		require.NoError(t, err)
+		defer func() { assert.NoError(t, r.Close()) }()
+
+		log, err := io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Contains(t, string(log), "--- FAIL: TestFeature")
+	})
+}
@@ -0,0 +1,23 @@
+package gitlab
+
+import "go.abhg.dev/gs/internal/forge"
+
+var _ forge.IssueLinker = (*Repository)(nil)
+
+// IssueLinkText returns a closing pattern reference to the given issue.
+//
+// Issues may be referenced by number ("123", "#123"),
+// by qualified number ("group/project#123"),
+// or by URL.
+//
+// See https://docs.gitlab.com/user/project/issues/managing_issues/#closing-issues-automatically
+func (r *Repository) IssueLinkText(issue string) (string, bool) {
+	ref, ok := forge.IssueNumberRef(issue)
+	if !ok {
+		ref, ok = forge.IssueURLRef(issue, r.forge.URL(), "/-/issues/")
+	}
+	if !ok {
+		return "", false
+	}
+	return "Closes " + ref, true
+}
@@ -0,0 +1,34 @@
+package gitlab
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepository_IssueLinkText(t *testing.T) {
+	repo := &Repository{forge: &Forge{}}
+
+	tests := []struct {
+		give string
+		want string // empty if not linked
+	}{
+		{give: "123", want: "Closes #123"},
+		{give: "#123", want: "Closes #123"},
+		{give: "group/project#4", want: "Closes group/project#4"},
+		{
+			give: "https://gitlab.com/group/project/-/issues/9",
+			want: "Closes https://gitlab.com/group/project/-/issues/9",
+		},
+		{give: "ABC-123"},
+		{give: "https://github.com/owner/repo/issues/9"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.give, func(t *testing.T) {
+			got, ok := repo.IssueLinkText(tt.give)
+			assert.Equal(t, tt.want != "", ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/forge/ratelimit"
+)
+
+var _ forge.QuotaReporter = (*Forge)(nil)
+
+// APIQuotas reports the API quota of the user
+// authenticated with the given token.
+//
+// GitLab doesn't have an endpoint to check the rate limit,
+// so this reads the quota reported alongside the current user.
+// Returns an empty list if the instance doesn't have rate limits enabled.
+func (f *Forge) APIQuotas(ctx context.Context, tok forge.AuthenticationToken) ([]ratelimit.Quota, error) {
+	client, err := newGitLabClient(ctx, f.APIURL(), tok.(*AuthenticationToken), f.logger(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("create GitLab client: %w", err)
+	}
+
+	_, resp, err := client.Users.CurrentUser(gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("get current user: %w", err)
+	}
+
+	q, ok := ratelimit.ParseQuota(resp.Header, time.Now())
+	if !ok {
+		return nil, nil
+	}
+	return []ratelimit.Quota{q}, nil
+}
@@ -0,0 +1,54 @@
+package gitlab
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+	"go.abhg.dev/gs/internal/forge/ratelimit"
+)
+
+func TestForgeAPIQuotas(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v4/user", r.URL.Path)
+		assert.Equal(t, "token", r.Header.Get("Private-Token"))
+
+		w.Header().Set("RateLimit-Limit", "2000")
+		w.Header().Set("RateLimit-Remaining", "1999")
+		w.Header().Set("RateLimit-Reset", "1714564800")
+		assert.NoError(t, json.NewEncoder(w).Encode(gitlab.User{ID: 1}))
+	}))
+	t.Cleanup(srv.Close)
+
+	f := Forge{Options: Options{URL: srv.URL}}
+	got, err := f.APIQuotas(t.Context(), &AuthenticationToken{
+		AuthType:    AuthTypePAT,
+		AccessToken: "token",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []ratelimit.Quota{{
+		Limit:     2000,
+		Remaining: 1999,
+		Reset:     time.Unix(1714564800, 0),
+	}}, got)
+}
+
+func TestForgeAPIQuotas_disabled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		assert.NoError(t, json.NewEncoder(w).Encode(gitlab.User{ID: 1}))
+	}))
+	t.Cleanup(srv.Close)
+
+	f := Forge{Options: Options{URL: srv.URL}}
+	got, err := f.APIQuotas(t.Context(), &AuthenticationToken{
+		AuthType:    AuthTypePAT,
+		AccessToken: "token",
+	})
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
@@ -5,9 +5,11 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"time"
 
 	gitlab "gitlab.com/gitlab-org/api/client-go"
 	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/forge/forgecache"
 	"go.abhg.dev/gs/internal/silog"
 )
 
@@ -21,8 +23,13 @@ type Repository struct {
 
 	repoID int64
 
+	// webURL is the web address of the project,
+	// e.g. "https://gitlab.com/owner/repo".
+	webURL string
+
 	// Information about the current user:
 	userID   int64
+	userName string
 	userRole gitlab.AccessLevelValue
 
 	removeSourceBranchOnMerge bool
@@ -34,8 +41,38 @@ type repositoryOptions struct {
 	RepositoryID *int64 // if nil, repository ID will be looked up
 
 	RemoveSourceBranchOnMerge bool
+
+	// Cache, if set, caches project metadata
+	// and the current user between invocations.
+	Cache *forgecache.Cache
+
+	// UserKey identifies the current user in Cache.
+	UserKey string
+}
+
+// cachedProject is the project metadata cached between invocations.
+type cachedProject struct {
+	ID          int64                   `json:"id"`
+	WebURL      string                  `json:"webURL"`
+	AccessLevel gitlab.AccessLevelValue `json:"accessLevel"`
 }
 
+// cachedUser is the current user cached between invocations.
+type cachedUser struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+}
+
+const (
+	// _projectTTL is how long project metadata is cached.
+	// This includes the user's access level,
+	// so it's kept short enough to pick up permission changes.
+	_projectTTL = 24 * time.Hour
+
+	// _userTTL is how long the current user is cached.
+	_userTTL = 24 * time.Hour
+)
+
 func newRepository(
 	ctx context.Context,
 	forge *Forge,
@@ -45,11 +82,50 @@ func newRepository(
 	opts *repositoryOptions,
 ) (*Repository, error) {
 	opts = cmp.Or(opts, &repositoryOptions{})
-	repoID := opts.RepositoryID
+
+	project, err := lookupProject(ctx, owner, repo, log, client, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := lookupCurrentUser(ctx, log, client, opts)
+	if err != nil {
+		return nil, err
+	}
+	log.Debugf("Repository access level: %v", accessValueName(project.AccessLevel))
+
+	return &Repository{
+		client:   client,
+		owner:    owner,
+		repo:     repo,
+		forge:    forge,
+		log:      log,
+		userID:   user.ID,
+		userName: user.Username,
+		userRole: project.AccessLevel,
+		repoID:   project.ID,
+		webURL:   project.WebURL,
+
+		removeSourceBranchOnMerge: opts.RemoveSourceBranchOnMerge,
+	}, nil
+}
+
+func lookupProject(
+	ctx context.Context,
+	owner, repo string,
+	log *silog.Logger,
+	client *gitlabClient,
+	opts *repositoryOptions,
+) (*cachedProject, error) {
+	var cached cachedProject
+	if opts.Cache.Get(ctx, "project", &cached) &&
+		(opts.RepositoryID == nil || *opts.RepositoryID == cached.ID) {
+		return &cached, nil
+	}
 
 	var projectIdentifier string
-	if repoID != nil {
-		projectIdentifier = strconv.FormatInt(*repoID, 10)
+	if opts.RepositoryID != nil {
+		projectIdentifier = strconv.FormatInt(*opts.RepositoryID, 10)
 	} else {
 		projectIdentifier = owner + "/" + repo
 	}
@@ -61,31 +137,48 @@ func newRepository(
 		return nil, fmt.Errorf("get repository ID: %w", err)
 	}
 
-	user, _, err := client.Users.CurrentUser(gitlab.WithContext(ctx))
-	if err != nil {
-		return nil, fmt.Errorf("get current user: %w", err)
-	}
-
 	var accessLevel gitlab.AccessLevelValue
 	if project.Permissions.ProjectAccess != nil {
 		accessLevel = project.Permissions.ProjectAccess.AccessLevel
 	} else if project.Permissions.GroupAccess != nil {
 		accessLevel = project.Permissions.GroupAccess.AccessLevel
 	}
-	log.Debugf("Repository access level: %v", accessValueName(accessLevel))
 
-	return &Repository{
-		client:   client,
-		owner:    owner,
-		repo:     repo,
-		forge:    forge,
-		log:      log,
-		userID:   user.ID,
-		userRole: accessLevel,
-		repoID:   project.ID,
+	info := &cachedProject{
+		ID:          project.ID,
+		WebURL:      project.WebURL,
+		AccessLevel: accessLevel,
+	}
+	if err := opts.Cache.Set(ctx, _projectTTL, map[string]any{"project": info}); err != nil {
+		log.Debug("Could not cache project metadata", "error", err)
+	}
+	return info, nil
+}
 
-		removeSourceBranchOnMerge: opts.RemoveSourceBranchOnMerge,
-	}, nil
+func lookupCurrentUser(
+	ctx context.Context,
+	log *silog.Logger,
+	client *gitlabClient,
+	opts *repositoryOptions,
+) (*cachedUser, error) {
+	key := "user/" + opts.UserKey
+	var cached cachedUser
+	if opts.UserKey != "" && opts.Cache.Get(ctx, key, &cached) {
+		return &cached, nil
+	}
+
+	user, _, err := client.Users.CurrentUser(gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("get current user: %w", err)
+	}
+
+	info := &cachedUser{ID: user.ID, Username: user.Username}
+	if opts.UserKey != "" {
+		if err := opts.Cache.Set(ctx, _userTTL, map[string]any{key: info}); err != nil {
+			log.Debug("Could not cache current user", "error", err)
+		}
+	}
+	return info, nil
 }
 
 // Forge returns the forge this repository belongs to.
@@ -0,0 +1,70 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+	"go.abhg.dev/gs/internal/forge"
+)
+
+var _ forge.ReviewPoster = (*Repository)(nil)
+
+// PostPendingReview adds draft notes to a merge request
+// for the review summary and each inline comment.
+// The drafts are published together when the user submits their review.
+//
+// Multi-line comments are attached to their last line.
+func (r *Repository) PostPendingReview(
+	ctx context.Context,
+	id forge.ChangeID,
+	req *forge.PostReviewRequest,
+) error {
+	mrNumber := mustMR(id).Number
+	mr, _, err := r.client.MergeRequests.GetMergeRequest(
+		r.repoID, mrNumber, nil, gitlab.WithContext(ctx),
+	)
+	if err != nil {
+		return fmt.Errorf("get merge request: %w", err)
+	}
+
+	if req.Body != "" {
+		if _, _, err := r.client.DraftNotes.CreateDraftNote(
+			r.repoID, mrNumber,
+			&gitlab.CreateDraftNoteOptions{Note: &req.Body},
+			gitlab.WithContext(ctx),
+		); err != nil {
+			return fmt.Errorf("add review summary: %w", err)
+		}
+	}
+
+	for _, c := range req.Comments {
+		if _, _, err := r.client.DraftNotes.CreateDraftNote(
+			r.repoID, mrNumber,
+			&gitlab.CreateDraftNoteOptions{
+				Note:     &c.Body,
+				Position: draftNotePosition(&mr.DiffRefs, c),
+			},
+			gitlab.WithContext(ctx),
+		); err != nil {
+			return fmt.Errorf("add comment on %v:%d: %w", c.Path, c.EndLine, err)
+		}
+	}
+
+	r.log.Debug("Posted pending review", "mr", mrNumber, "comments", len(req.Comments))
+	return nil
+}
+
+// draftNotePosition returns the position of a comment
+// on a line in the new version of a file in a merge request's diff.
+func draftNotePosition(refs *gitlab.MergeRequestDiffRefs, c *forge.ReviewComment) *gitlab.PositionOptions {
+	return &gitlab.PositionOptions{
+		BaseSHA:      &refs.BaseSha,
+		StartSHA:     &refs.StartSha,
+		HeadSHA:      &refs.HeadSha,
+		PositionType: gitlab.Ptr("text"),
+		NewPath:      &c.Path,
+		OldPath:      &c.Path,
+		NewLine:      gitlab.Ptr(int64(c.EndLine)),
+	}
+}
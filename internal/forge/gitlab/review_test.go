@@ -0,0 +1,97 @@
+package gitlab
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/silog/silogtest"
+)
+
+func TestPostPendingReview(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		notes []map[string]any
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		enc := json.NewEncoder(w)
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v4/projects/100":
+			assert.NoError(t, enc.Encode(newProject(100, nil, nil)))
+
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v4/user":
+			assert.NoError(t, enc.Encode(gitlab.User{ID: 1}))
+
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v4/projects/100/merge_requests/1":
+			assert.NoError(t, enc.Encode(gitlab.MergeRequest{
+				DiffRefs: gitlab.MergeRequestDiffRefs{
+					BaseSha:  "base",
+					StartSha: "start",
+					HeadSha:  "head",
+				},
+			}))
+
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v4/projects/100/merge_requests/1/draft_notes":
+			raw, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			var note map[string]any
+			assert.NoError(t, json.Unmarshal(raw, &note))
+
+			mu.Lock()
+			notes = append(notes, note)
+			mu.Unlock()
+			assert.NoError(t, enc.Encode(gitlab.DraftNote{ID: int64(len(notes))}))
+
+		default:
+			t.Errorf("unexpected request: %v %v", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := newGitLabClient(t.Context(), srv.URL, &AuthenticationToken{
+		AuthType:    AuthTypePAT,
+		AccessToken: "token",
+	}, silogtest.New(t), nil)
+	require.NoError(t, err)
+	repoID := int64(100)
+	repo, err := newRepository(
+		t.Context(), new(Forge),
+		"owner", "repo",
+		silogtest.New(t),
+		client,
+		&repositoryOptions{RepositoryID: &repoID},
+	)
+	require.NoError(t, err)
+
+	err = repo.PostPendingReview(t.Context(), &MR{Number: 1}, &forge.PostReviewRequest{
+		Body: "summary",
+		Comments: []*forge.ReviewComment{
+			{Path: "foo.go", StartLine: 3, EndLine: 5, Body: "range"},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []map[string]any{
+		{"note": "summary"},
+		{
+			"note": "range",
+			"position": map[string]any{
+				"base_sha":      "base",
+				"start_sha":     "start",
+				"head_sha":      "head",
+				"position_type": "text",
+				"new_path":      "foo.go",
+				"old_path":      "foo.go",
+				"new_line":      float64(5),
+			},
+		},
+	}, notes)
+}
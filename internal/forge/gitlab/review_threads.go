@@ -0,0 +1,246 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"regexp"
+	"strconv"
+	"strings"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+	"go.abhg.dev/gs/internal/forge"
+)
+
+var _ forge.ReviewThreadLister = (*Repository)(nil)
+
+// GitLab's API paginates the discussions listing endpoint.
+var _listReviewThreadsPageSize = 50 // var for testing
+
+// ListReviewThreads returns an iterator over merge request discussions.
+//
+// Only resolvable discussions are considered review threads.
+// These include comments on the diff and threads started on the MR,
+// but not standalone comments or system notes.
+// Threads are filtered according to opts:
+// resolved threads are excluded unless IncludeResolved is set,
+// and bot-authored threads are excluded unless the bot username
+// is in BotAllowlist.
+//
+// GitLab does not report diff hunks for discussions,
+// so Hunk is always empty.
+func (r *Repository) ListReviewThreads(
+	ctx context.Context,
+	id forge.ChangeID,
+	opts *forge.ListReviewThreadsOptions,
+) iter.Seq2[*forge.ReviewThreadItem, error] {
+	if opts == nil {
+		opts = &forge.ListReviewThreadsOptions{}
+	}
+
+	mrNumber := mustMR(id).Number
+	return func(yield func(*forge.ReviewThreadItem, error) bool) {
+		listOptions := gitlab.ListMergeRequestDiscussionsOptions{
+			ListOptions: gitlab.ListOptions{
+				PerPage: int64(_listReviewThreadsPageSize),
+			},
+		}
+
+		for pageNum := 1; true; pageNum++ {
+			discussions, response, err := r.client.Discussions.ListMergeRequestDiscussions(
+				r.repoID, mrNumber, &listOptions,
+				gitlab.WithContext(ctx),
+			)
+			if err != nil {
+				yield(nil, fmt.Errorf("list review threads (page %d): %w", pageNum, err))
+				return
+			}
+
+			for _, d := range discussions {
+				item, ok := r.reviewThreadItem(mrNumber, d)
+				if !ok {
+					continue
+				}
+
+				// Skip resolved threads unless requested.
+				if item.IsResolved && !opts.IncludeResolved {
+					continue
+				}
+
+				// Apply bot filtering.
+				if isBot(item.Author) && !inBotAllowlist(item.Author, opts.BotAllowlist) {
+					continue
+				}
+
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			if response.CurrentPage >= response.TotalPages {
+				return
+			}
+
+			listOptions.Page = response.NextPage
+		}
+	}
+}
+
+// reviewThreadItem converts a discussion into a review thread.
+// It reports false if the discussion isn't a review thread.
+func (r *Repository) reviewThreadItem(mrNumber int64, d *gitlab.Discussion) (*forge.ReviewThreadItem, bool) {
+	if len(d.Notes) == 0 {
+		return nil, false
+	}
+
+	first := d.Notes[0]
+	if first.System || !first.Resolvable {
+		return nil, false
+	}
+
+	// A thread is resolved only if all its resolvable notes are.
+	resolved := true
+	var replies []forge.ReviewReply
+	for i, note := range d.Notes {
+		if note.Resolvable && !note.Resolved {
+			resolved = false
+		}
+
+		if i == 0 || note.System {
+			continue
+		}
+
+		reply := forge.ReviewReply{
+			Author: note.Author.Username,
+			Body:   note.Body,
+		}
+		if note.CreatedAt != nil {
+			reply.CreatedAt = *note.CreatedAt
+		}
+		replies = append(replies, reply)
+	}
+
+	item := &forge.ReviewThreadItem{
+		ID:         reviewThreadID(mrNumber, d.ID),
+		Author:     first.Author.Username,
+		Body:       first.Body,
+		Replies:    replies,
+		IsResolved: resolved,
+	}
+	if r.webURL != "" {
+		item.URL = fmt.Sprintf("%s/-/merge_requests/%d#note_%d", r.webURL, mrNumber, first.ID)
+	}
+
+	if pos := first.Position; pos != nil {
+		item.File = pos.NewPath
+		if item.File == "" {
+			item.File = pos.OldPath
+		}
+		item.LineRange = notePositionLines(pos)
+	}
+
+	return item, true
+}
+
+// notePositionLines returns the inclusive line range
+// covered by a diff note.
+//
+// Lines in the new version of the file are preferred.
+// Notes on removed lines use lines in the old version.
+func notePositionLines(pos *gitlab.NotePosition) [2]int {
+	line := func(newLine, oldLine int64) int {
+		if newLine != 0 {
+			return int(newLine)
+		}
+		return int(oldLine)
+	}
+
+	end := line(pos.NewLine, pos.OldLine)
+	start := end
+	if lr := pos.LineRange; lr != nil {
+		if lr.StartRange != nil {
+			start = line(lr.StartRange.NewLine, lr.StartRange.OldLine)
+		}
+		if lr.EndRange != nil {
+			end = line(lr.EndRange.NewLine, lr.EndRange.OldLine)
+		}
+	}
+	if start == 0 {
+		start = end
+	}
+	return [2]int{start, end}
+}
+
+// PostReviewThreadReply posts a reply to an existing merge request discussion.
+func (r *Repository) PostReviewThreadReply(
+	ctx context.Context,
+	threadID forge.ReviewThreadID,
+	body string,
+) (forge.ChangeCommentID, error) {
+	mrNumber, discussionID, err := parseReviewThreadID(threadID)
+	if err != nil {
+		return nil, err
+	}
+
+	note, _, err := r.client.Discussions.AddMergeRequestDiscussionNote(
+		r.repoID, mrNumber, discussionID,
+		&gitlab.AddMergeRequestDiscussionNoteOptions{Body: &body},
+		gitlab.WithContext(ctx),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("post review thread reply: %w", err)
+	}
+
+	r.log.Debug("Posted review thread reply", "id", note.ID, "mr", mrNumber)
+	return &MRComment{
+		Number:   note.ID,
+		MRNumber: mrNumber,
+	}, nil
+}
+
+// Discussion IDs are only unique within a merge request,
+// so review thread IDs take the form "<mr>:<discussion>".
+func reviewThreadID(mrNumber int64, discussionID string) forge.ReviewThreadID {
+	return forge.ReviewThreadID(strconv.FormatInt(mrNumber, 10) + ":" + discussionID)
+}
+
+func parseReviewThreadID(id forge.ReviewThreadID) (mrNumber int64, discussionID string, err error) {
+	mrStr, discussionID, ok := strings.Cut(string(id), ":")
+	if ok {
+		mrNumber, err = strconv.ParseInt(mrStr, 10, 64)
+	}
+	if !ok || err != nil || discussionID == "" {
+		return 0, "", fmt.Errorf("invalid review thread ID: %q", id)
+	}
+	return mrNumber, discussionID, nil
+}
+
+// _knownBotUsernames lists GitLab review bots
+// that aren't project or group access token bots.
+var _knownBotUsernames = map[string]struct{}{
+	"gitlabduo": {},
+}
+
+// _tokenBotRe matches usernames of bot users
+// that GitLab creates for project and group access tokens.
+var _tokenBotRe = regexp.MustCompile(`^(?:project|group)_\d+_bot(?:_[0-9a-f]+)?$`)
+
+// isBot reports whether username belongs to an automated account.
+func isBot(username string) bool {
+	if _tokenBotRe.MatchString(username) {
+		return true
+	}
+	_, ok := _knownBotUsernames[strings.ToLower(username)]
+	return ok
+}
+
+// inBotAllowlist reports whether username appears in the allowlist
+// with case-insensitive comparison.
+func inBotAllowlist(username string, allowlist []string) bool {
+	for _, allowed := range allowlist {
+		if strings.EqualFold(username, allowed) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package gitlab
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+	"go.abhg.dev/gs/internal/forge"
+)
+
+func TestReviewThreadID(t *testing.T) {
+	id := reviewThreadID(42, "abc123")
+	assert.Equal(t, forge.ReviewThreadID("42:abc123"), id)
+
+	mr, discussion, err := parseReviewThreadID(id)
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), mr)
+	assert.Equal(t, "abc123", discussion)
+
+	for _, bad := range []forge.ReviewThreadID{"", "abc123", "x:abc123", "42:"} {
+		_, _, err := parseReviewThreadID(bad)
+		assert.Error(t, err, "parseReviewThreadID(%q)", bad)
+	}
+}
+
+func TestNotePositionLines(t *testing.T) {
+	tests := []struct {
+		name string
+		give gitlab.NotePosition
+		want [2]int
+	}{
+		{
+			name: "NewLine",
+			give: gitlab.NotePosition{NewLine: 12},
+			want: [2]int{12, 12},
+		},
+		{
+			name: "OldLine",
+			give: gitlab.NotePosition{OldLine: 7},
+			want: [2]int{7, 7},
+		},
+		{
+			name: "Range",
+			give: gitlab.NotePosition{
+				NewLine: 9,
+				LineRange: &gitlab.LineRange{
+					StartRange: &gitlab.LinePosition{NewLine: 4},
+					EndRange:   &gitlab.LinePosition{NewLine: 9},
+				},
+			},
+			want: [2]int{4, 9},
+		},
+		{
+			name: "RangeAcrossRemovedLines",
+			give: gitlab.NotePosition{
+				NewLine: 6,
+				LineRange: &gitlab.LineRange{
+					StartRange: &gitlab.LinePosition{OldLine: 3},
+					EndRange:   &gitlab.LinePosition{NewLine: 6},
+				},
+			},
+			want: [2]int{3, 6},
+		},
+		{
+			name: "FileLevel",
+			give: gitlab.NotePosition{},
+			want: [2]int{0, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, notePositionLines(&tt.give))
+		})
+	}
+}
+
+func TestIsBot(t *testing.T) {
+	assert.True(t, isBot("project_64779801_bot_3f9a1c"))
+	assert.True(t, isBot("group_123_bot"))
+	assert.True(t, isBot("GitLabDuo"))
+	assert.False(t, isBot("abg"))
+	assert.False(t, isBot("robot"))
+}
@@ -0,0 +1,52 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+	"go.abhg.dev/gs/internal/forge"
+)
+
+var _ forge.TeamMemberLister = (*Repository)(nil)
+
+// GitLab's API paginates the group members listing endpoint.
+var _listTeamMembersPageSize = 100 // var for testing
+
+// ListTeamMembers returns the usernames of the members of a group
+// given by its full path, e.g. "group/subgroup".
+//
+// Members inherited from ancestor groups are included.
+// Blocked users are skipped.
+func (r *Repository) ListTeamMembers(ctx context.Context, team string) ([]string, error) {
+	opts := gitlab.ListGroupMembersOptions{
+		ListOptions: gitlab.ListOptions{
+			PerPage: int64(_listTeamMembersPageSize),
+		},
+	}
+
+	var usernames []string
+	for pageNum := 1; true; pageNum++ {
+		members, response, err := r.client.Groups.ListAllGroupMembers(
+			team, &opts,
+			gitlab.WithContext(ctx),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("list members of %v (page %d): %w", team, pageNum, err)
+		}
+
+		for _, m := range members {
+			if m.State == "blocked" {
+				continue
+			}
+			usernames = append(usernames, m.Username)
+		}
+
+		if response.CurrentPage >= response.TotalPages {
+			break
+		}
+		opts.Page = response.NextPage
+	}
+
+	return usernames, nil
+}
@@ -0,0 +1,78 @@
+package gitlab
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+	"go.abhg.dev/gs/internal/silog/silogtest"
+)
+
+func TestListTeamMembers(t *testing.T) {
+	pages := [][]*gitlab.GroupMember{
+		{
+			{Username: "alice", State: "active"},
+			{Username: "bob", State: "blocked"},
+		},
+		{
+			{Username: "carol", State: "active"},
+		},
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		enc := json.NewEncoder(w)
+		switch r.URL.EscapedPath() {
+		case "/api/v4/projects/100":
+			assert.NoError(t, enc.Encode(newProject(100, nil, nil)))
+			return
+
+		case "/api/v4/user":
+			assert.NoError(t, enc.Encode(gitlab.User{ID: 1}))
+			return
+
+		case "/api/v4/groups/acme%2Fplatform/members/all":
+			// handled below
+
+		default:
+			t.Errorf("unexpected request: %v %v", r.Method, r.URL.EscapedPath())
+			http.NotFound(w, r)
+			return
+		}
+
+		page, err := strconv.Atoi(r.URL.Query().Get("page"))
+		if err != nil {
+			page = 1
+		}
+		w.Header().Set("X-Page", strconv.Itoa(page))
+		w.Header().Set("X-Total-Pages", strconv.Itoa(len(pages)))
+		if page < len(pages) {
+			w.Header().Set("X-Next-Page", strconv.Itoa(page+1))
+		}
+		assert.NoError(t, enc.Encode(pages[page-1]))
+	}))
+	defer srv.Close()
+
+	client, err := newGitLabClient(t.Context(), srv.URL, &AuthenticationToken{
+		AuthType:    AuthTypePAT,
+		AccessToken: "token",
+	}, silogtest.New(t), nil)
+	require.NoError(t, err)
+	repoID := int64(100)
+	repo, err := newRepository(
+		t.Context(), new(Forge),
+		"owner", "repo",
+		silogtest.New(t),
+		client,
+		&repositoryOptions{RepositoryID: &repoID},
+	)
+	require.NoError(t, err)
+
+	got, err := repo.ListTeamMembers(t.Context(), "acme/platform")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice", "carol"}, got)
+}
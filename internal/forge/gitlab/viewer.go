@@ -0,0 +1,17 @@
+package gitlab
+
+import (
+	"context"
+
+	"go.abhg.dev/gs/internal/forge"
+)
+
+var _ forge.ViewerIdentifier = (*Repository)(nil)
+
+// ViewerLogin returns the GitLab username of the authenticated user.
+//
+// The user is looked up when the repository is opened,
+// so this does not make any API requests.
+func (r *Repository) ViewerLogin(context.Context) (string, error) {
+	return r.userName, nil
+}
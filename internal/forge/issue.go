@@ -0,0 +1,58 @@
+package forge
+
+import (
+	"regexp"
+	"strings"
+)
+
+// IssueLinker is an optional capability implemented by a [Repository]
+// that can link changes to issues in the forge's issue tracker.
+//
+// On forges that don't implement this,
+// issues are referenced in change descriptions as plain text.
+type IssueLinker interface {
+	// IssueLinkText returns text to include in the body of a change
+	// so that the forge links the change to the given issue,
+	// and closes the issue when the change is merged.
+	// For example, "Fixes #123" for GitHub.
+	//
+	// It reports false if issue does not refer to an issue
+	// in the forge's issue tracker.
+	IssueLinkText(issue string) (string, bool)
+}
+
+// _issueNumberRe matches references to issues by number
+// optionally qualified by a repository path.
+var _issueNumberRe = regexp.MustCompile(`^(?:([\w.-]+(?:/[\w.-]+)+)?#)?([0-9]+)$`)
+
+// IssueNumberRef reports whether issue refers to an issue by its number.
+// It accepts the forms "123", "#123", and "owner/repo#123",
+// and returns the reference in the form "#123" or "owner/repo#123".
+func IssueNumberRef(issue string) (string, bool) {
+	m := _issueNumberRe.FindStringSubmatch(strings.TrimSpace(issue))
+	if m == nil {
+		return "", false
+	}
+	return m[1] + "#" + m[2], true
+}
+
+// IssueURLRef reports whether issue is the web URL of an issue
+// hosted on the forge at baseURL.
+// issuePath is the path component between the repository and issue number,
+// e.g. "/issues/" for GitHub.
+//
+// The URL is returned unchanged if it matches.
+func IssueURLRef(issue, baseURL, issuePath string) (string, bool) {
+	issue = strings.TrimSpace(issue)
+	rest, ok := strings.CutPrefix(issue, strings.TrimSuffix(baseURL, "/")+"/")
+	if !ok {
+		return "", false
+	}
+
+	_, num, ok := strings.Cut(rest, issuePath)
+	if !ok || num == "" || strings.Trim(num, "0123456789") != "" {
+		return "", false
+	}
+
+	return issue, true
+}
@@ -0,0 +1,86 @@
+package forge_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.abhg.dev/gs/internal/forge"
+)
+
+func TestIssueNumberRef(t *testing.T) {
+	tests := []struct {
+		give string
+		want string // empty if not a number reference
+	}{
+		{give: "123", want: "#123"},
+		{give: "#123", want: "#123"},
+		{give: " #42 ", want: "#42"},
+		{give: "owner/repo#7", want: "owner/repo#7"},
+		{give: "group/sub.group/my-project#7", want: "group/sub.group/my-project#7"},
+		{give: "ABC-123"},
+		{give: "repo#7"},
+		{give: "#"},
+		{give: "https://github.com/owner/repo/issues/1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.give, func(t *testing.T) {
+			got, ok := forge.IssueNumberRef(tt.give)
+			assert.Equal(t, tt.want != "", ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestIssueURLRef(t *testing.T) {
+	tests := []struct {
+		name      string
+		give      string
+		baseURL   string
+		issuePath string
+		ok        bool
+	}{
+		{
+			name:      "GitHub",
+			give:      "https://github.com/owner/repo/issues/123",
+			baseURL:   "https://github.com",
+			issuePath: "/issues/",
+			ok:        true,
+		},
+		{
+			name:      "GitLab",
+			give:      "https://gitlab.example.com/group/project/-/issues/5",
+			baseURL:   "https://gitlab.example.com/",
+			issuePath: "/-/issues/",
+			ok:        true,
+		},
+		{
+			name:      "OtherHost",
+			give:      "https://example.atlassian.net/browse/ABC-123",
+			baseURL:   "https://github.com",
+			issuePath: "/issues/",
+		},
+		{
+			name:      "PullRequest",
+			give:      "https://github.com/owner/repo/pull/123",
+			baseURL:   "https://github.com",
+			issuePath: "/issues/",
+		},
+		{
+			name:      "NotANumber",
+			give:      "https://github.com/owner/repo/issues/new",
+			baseURL:   "https://github.com",
+			issuePath: "/issues/",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := forge.IssueURLRef(tt.give, tt.baseURL, tt.issuePath)
+			assert.Equal(t, tt.ok, ok)
+			if tt.ok {
+				assert.Equal(t, tt.give, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package forge
+
+import "context"
+
+// ChangeProjectLister is an optional capability implemented by a [Repository]
+// that can report the projects a change is part of.
+//
+// Projects are boards like GitHub Projects that track changes
+// separately from labels and milestones.
+// Forges without them don't implement this,
+// and editing the projects of a change fails on them.
+type ChangeProjectLister interface {
+	// ListChangeProjects returns the titles of the projects
+	// that the given change is part of.
+	ListChangeProjects(ctx context.Context, id ChangeID) ([]string, error)
+}
@@ -0,0 +1,20 @@
+package forge
+
+import (
+	"context"
+
+	"go.abhg.dev/gs/internal/forge/ratelimit"
+)
+
+// QuotaReporter is an optional capability implemented by a [Forge]
+// that can report how much of its API rate limit a user has left.
+//
+// 'gs auth status' shows this alongside the login status
+// for forges that implement it.
+type QuotaReporter interface {
+	// APIQuotas reports the API quotas of the user
+	// authenticated with the given token.
+	//
+	// Returns an empty list if the forge doesn't rate limit the user.
+	APIQuotas(ctx context.Context, tok AuthenticationToken) ([]ratelimit.Quota, error)
+}
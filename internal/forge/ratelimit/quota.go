@@ -0,0 +1,156 @@
+package ratelimit
+
+import (
+	"cmp"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Resources to which requests are attributed.
+//
+// These match the names GitHub uses for its rate limits.
+// Forges that don't report a resource have their quota
+// attributed to the resource the request would use on GitHub.
+const (
+	ResourceCore    = "core"
+	ResourceGraphQL = "graphql"
+)
+
+// Quota is the API quota for a rate-limited resource
+// as reported by a forge.
+type Quota struct {
+	// Resource is the name of the rate-limited resource,
+	// e.g. "core" or "graphql".
+	//
+	// This is empty if the forge doesn't name its resources.
+	Resource string
+
+	// Limit is the maximum number of requests (or GraphQL points)
+	// allowed in the current window.
+	Limit int
+
+	// Remaining is the number of requests (or GraphQL points)
+	// left in the current window.
+	Remaining int
+
+	// Reset is the time at which the quota is replenished.
+	// This is the zero value if the forge didn't report it.
+	Reset time.Time
+}
+
+// Exhausted reports whether the quota has been used up
+// and won't be replenished until after now.
+func (q Quota) Exhausted(now time.Time) bool {
+	return q.Remaining <= 0 && q.Reset.After(now)
+}
+
+// ParseQuota extracts the quota reported in the headers of a response.
+//
+// It understands the X-RateLimit-* headers reported by GitHub
+// and the RateLimit-* headers reported by GitLab.
+// ok is false if the headers don't report a quota.
+func ParseQuota(h http.Header, now time.Time) (q Quota, ok bool) {
+	prefix := "X-Ratelimit-"
+	if h.Get(prefix+"Remaining") == "" {
+		prefix = "Ratelimit-"
+	}
+
+	remaining, err := strconv.Atoi(h.Get(prefix + "Remaining"))
+	if err != nil {
+		return Quota{}, false
+	}
+	q.Remaining = remaining
+	q.Limit, _ = strconv.Atoi(h.Get(prefix + "Limit"))
+	q.Resource = h.Get(prefix + "Resource")
+
+	if reset, err := strconv.ParseInt(h.Get(prefix+"Reset"), 10, 64); err == nil {
+		// GitHub and GitLab report the reset time as a Unix timestamp,
+		// but the IETF draft for these headers uses a number of seconds.
+		// A timestamp would be in 2001 or later,
+		// so anything smaller is a number of seconds.
+		if reset < 1_000_000_000 {
+			q.Reset = now.Add(time.Duration(reset) * time.Second)
+		} else {
+			q.Reset = time.Unix(reset, 0)
+		}
+	}
+
+	return q, true
+}
+
+// retryAfter reports the delay requested by the Retry-After header
+// of a response, if any.
+func retryAfter(h http.Header, now time.Time) (time.Duration, bool) {
+	v := strings.TrimSpace(h.Get("Retry-After"))
+	if v == "" {
+		return 0, false
+	}
+
+	if secs, err := strconv.Atoi(v); err == nil {
+		return max(time.Duration(secs)*time.Second, 0), true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(t.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// Tracker tracks the API quotas reported by a forge
+// across requests.
+//
+// It is safe for concurrent use.
+// The zero value is ready to use.
+type Tracker struct {
+	mu     sync.Mutex
+	quotas map[string]Quota
+	warned map[string]bool // resources for which low quota was reported
+}
+
+// Quota returns the last quota reported for the given resource.
+func (t *Tracker) Quota(resource string) (Quota, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	q, ok := t.quotas[resource]
+	return q, ok
+}
+
+// Quotas returns the last quota reported for each resource,
+// sorted by resource name.
+func (t *Tracker) Quotas() []Quota {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return slices.SortedFunc(maps.Values(t.quotas), func(a, b Quota) int {
+		return cmp.Compare(a.Resource, b.Resource)
+	})
+}
+
+// record records a newly reported quota.
+// It reports whether the quota just dropped below
+// the low-quota threshold for the first time.
+func (t *Tracker) record(q Quota) (low bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.quotas == nil {
+		t.quotas = make(map[string]Quota)
+		t.warned = make(map[string]bool)
+	}
+	t.quotas[q.Resource] = q
+
+	if q.Limit > 0 && q.Remaining*_lowQuotaDivisor < q.Limit && !t.warned[q.Resource] {
+		t.warned[q.Resource] = true
+		return true
+	}
+	return false
+}
+
+// _lowQuotaDivisor sets the low-quota threshold:
+// a quota is low when less than 1/_lowQuotaDivisor of it remains.
+const _lowQuotaDivisor = 10
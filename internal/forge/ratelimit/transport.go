@@ -0,0 +1,438 @@
+// Package ratelimit provides an HTTP transport for forge API clients
+// that honors the rate limits reported by the forge,
+// and retries requests that fail transiently.
+package ratelimit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.abhg.dev/gs/internal/graphqlutil"
+	"go.abhg.dev/gs/internal/silog"
+	"go.abhg.dev/gs/internal/tracing"
+)
+
+// Default values for [Options].
+const (
+	DefaultMaxAttempts = 4
+	DefaultMaxWait     = time.Minute
+)
+
+const (
+	_minBackoff = 500 * time.Millisecond
+	_maxBackoff = 8 * time.Second
+
+	// _maxPeekBody is the most of a response body
+	// that is read to check for a secondary rate limit message.
+	_maxPeekBody = 64 << 10
+)
+
+// Error indicates that a request was rejected because of a rate limit,
+// and the limit would not reset soon enough to retry it.
+type Error struct {
+	// Host is the host that rejected the request.
+	Host string
+
+	// Resource is the rate-limited resource, if known.
+	Resource string
+
+	// Reset is when the rate limit resets.
+	// This is the zero value if the forge didn't report it.
+	Reset time.Time
+}
+
+func (e *Error) Error() string {
+	var s strings.Builder
+	s.WriteString(e.Host)
+	s.WriteString(": API rate limit exceeded")
+	if e.Resource != "" {
+		fmt.Fprintf(&s, " for %q", e.Resource)
+	}
+	if !e.Reset.IsZero() {
+		fmt.Fprintf(&s, "; resets at %v", e.Reset.Local().Format(time.Kitchen))
+	}
+	return s.String()
+}
+
+// Options configures the transport built by [WrapTransport].
+type Options struct {
+	// Tracker records the quotas reported by the forge.
+	// If nil, a new Tracker is used.
+	Tracker *Tracker
+
+	// Log is used to report waits for rate limits to reset,
+	// and quotas that are running low.
+	// If nil, nothing is logged.
+	Log *silog.Logger
+
+	// MaxAttempts is the maximum number of times a request is sent.
+	// Defaults to DefaultMaxAttempts.
+	MaxAttempts int
+
+	// MaxWait is the longest the transport will wait
+	// for a rate limit to reset before failing with [*Error].
+	// Defaults to DefaultMaxWait.
+	MaxWait time.Duration
+
+	// Idempotent reports whether a request may be retried
+	// after a server or network error.
+	//
+	// Requests rejected by a rate limit were not processed,
+	// so they're always retried regardless of this.
+	//
+	// Defaults to [IdempotentMethod].
+	Idempotent func(*http.Request) bool
+}
+
+// IdempotentMethod reports whether the request uses an HTTP method
+// that is idempotent per RFC 9110.
+func IdempotentMethod(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions,
+		http.MethodPut, http.MethodDelete, http.MethodTrace:
+		return true
+	default:
+		return false
+	}
+}
+
+// WrapTransport wraps an HTTP transport to honor the rate limits
+// reported by a forge and retry requests that fail transiently.
+//
+// Requests rejected by a rate limit are retried after the delay
+// requested by the Retry-After header or the rate limit reset time.
+// If that's longer than MaxWait, the request fails with [*Error].
+// Idempotent requests that fail with a server error (5xx),
+// a network error, or a retryable GraphQL error
+// are retried with jittered exponential backoff.
+//
+// Quotas reported by the forge are recorded in the Tracker.
+// Once a resource's quota is exhausted,
+// requests for it wait for the quota to reset before they're sent.
+//
+// The transport classifies errors reported by [graphqlutil],
+// so it should wrap a transport built by [graphqlutil.WrapTransport].
+//
+// If t is nil, [http.DefaultTransport] is used.
+func WrapTransport(t http.RoundTripper, opts *Options) http.RoundTripper {
+	if t == nil {
+		t = http.DefaultTransport
+	}
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	log := opts.Log
+	if log == nil {
+		log = silog.Nop()
+	}
+
+	tr := &transport{
+		t:           t,
+		tracker:     opts.Tracker,
+		log:         log,
+		maxAttempts: opts.MaxAttempts,
+		maxWait:     opts.MaxWait,
+		idempotent:  opts.Idempotent,
+		now:         time.Now,
+		sleep:       sleep,
+		jitter:      rand.Int64N,
+	}
+	if tr.tracker == nil {
+		tr.tracker = new(Tracker)
+	}
+	if tr.maxAttempts <= 0 {
+		tr.maxAttempts = DefaultMaxAttempts
+	}
+	if tr.maxWait <= 0 {
+		tr.maxWait = DefaultMaxWait
+	}
+	if tr.idempotent == nil {
+		tr.idempotent = IdempotentMethod
+	}
+	return tr
+}
+
+type transport struct {
+	t           http.RoundTripper
+	tracker     *Tracker
+	log         *silog.Logger
+	maxAttempts int
+	maxWait     time.Duration
+	idempotent  func(*http.Request) bool
+
+	// Hooks for testing.
+	now    func() time.Time
+	sleep  func(context.Context, time.Duration) error
+	jitter func(n int64) int64 // returns [0, n)
+}
+
+// outcome classifies the result of a single attempt.
+type outcome int
+
+const (
+	outcomeDone        outcome = iota // return the result as-is
+	outcomeRateLimited                // rejected by a rate limit
+	outcomeTransient                  // failed transiently
+)
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	resource := requestResource(req)
+	canRewind := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+
+	for attempt := 1; ; attempt++ {
+		// Wait out an exhausted quota instead of spending a request on it.
+		if q, ok := t.tracker.Quota(resource); ok && q.Exhausted(t.now()) {
+			if err := t.waitForReset(ctx, req.URL.Host, q.Resource, q.Reset.Sub(t.now()), q.Reset); err != nil {
+				return nil, err
+			}
+		}
+
+		attemptReq := req
+		if attempt > 1 {
+			var err error
+			attemptReq, err = rewind(req)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		res, err := t.t.RoundTrip(attemptReq)
+		if res != nil {
+			t.observe(req, resource, res.Header)
+		}
+
+		kind, delay, reset := t.classify(req, resource, res, err, attempt)
+		if kind == outcomeDone {
+			return res, err
+		}
+
+		lastAttempt := attempt >= t.maxAttempts || !canRewind
+		switch kind {
+		case outcomeRateLimited:
+			discard(res)
+			if lastAttempt {
+				return nil, &Error{Host: req.URL.Host, Resource: resource, Reset: reset}
+			}
+			if err := t.waitForReset(ctx, req.URL.Host, resource, delay, reset); err != nil {
+				return nil, err
+			}
+
+		case outcomeTransient:
+			if lastAttempt {
+				return res, err
+			}
+			discard(res)
+			t.log.Debug("Retrying request",
+				"method", req.Method,
+				"path", req.URL.Path,
+				"attempt", attempt,
+				"delay", delay)
+			if err := t.wait(ctx, "retry backoff", resource, delay); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// classify decides what to do with the result of an attempt.
+// For rate-limited and transient failures,
+// it also reports how long to wait before the next attempt,
+// and for rate-limited failures, when the limit resets if known.
+func (t *transport) classify(
+	req *http.Request,
+	resource string,
+	res *http.Response,
+	err error,
+	attempt int,
+) (kind outcome, delay time.Duration, reset time.Time) {
+	now := t.now()
+	backoff := t.backoff(attempt)
+
+	if err != nil {
+		switch {
+		case req.Context().Err() != nil:
+			return outcomeDone, 0, time.Time{}
+
+		case errors.Is(err, graphqlutil.ErrRateLimited):
+			// GraphQL rate limit errors don't include the response headers,
+			// so rely on the last quota reported for the resource.
+			if q, ok := t.tracker.Quota(resource); ok && q.Reset.After(now) {
+				return outcomeRateLimited, q.Reset.Sub(now), q.Reset
+			}
+			return outcomeRateLimited, backoff, time.Time{}
+
+		case t.idempotent(req) && (graphqlutil.IsRetryable(err) || isNetworkError(err)):
+			return outcomeTransient, backoff, time.Time{}
+
+		default:
+			return outcomeDone, 0, time.Time{}
+		}
+	}
+
+	switch res.StatusCode {
+	case http.StatusTooManyRequests, http.StatusForbidden:
+		if !isRateLimited(res, now) {
+			return outcomeDone, 0, time.Time{}
+		}
+
+		if d, ok := retryAfter(res.Header, now); ok {
+			return outcomeRateLimited, d, now.Add(d)
+		}
+		if q, ok := ParseQuota(res.Header, now); ok && q.Exhausted(now) {
+			return outcomeRateLimited, q.Reset.Sub(now), q.Reset
+		}
+		return outcomeRateLimited, backoff, time.Time{}
+
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		if !t.idempotent(req) {
+			return outcomeDone, 0, time.Time{}
+		}
+		if d, ok := retryAfter(res.Header, now); ok {
+			backoff = max(backoff, d)
+		}
+		return outcomeTransient, backoff, time.Time{}
+
+	default:
+		return outcomeDone, 0, time.Time{}
+	}
+}
+
+// isRateLimited reports whether a 403 or 429 response
+// was caused by a rate limit.
+//
+// GitHub reports both rate limits and permission errors with 403,
+// and reports secondary rate limits only in the response body.
+func isRateLimited(res *http.Response, now time.Time) bool {
+	if res.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	if _, ok := retryAfter(res.Header, now); ok {
+		return true
+	}
+	if q, ok := ParseQuota(res.Header, now); ok && q.Remaining <= 0 {
+		return true
+	}
+
+	// Peek at the body and put it back for the caller.
+	body, err := io.ReadAll(io.LimitReader(res.Body, _maxPeekBody))
+	res.Body = struct {
+		io.Reader
+		io.Closer
+	}{
+		Reader: io.MultiReader(bytes.NewReader(body), res.Body),
+		Closer: res.Body,
+	}
+	return err == nil && bytes.Contains(bytes.ToLower(body), []byte("secondary rate limit"))
+}
+
+// observe records the quota reported in a response.
+func (t *transport) observe(req *http.Request, resource string, h http.Header) {
+	q, ok := ParseQuota(h, t.now())
+	if !ok {
+		return
+	}
+	if q.Resource == "" {
+		q.Resource = resource
+	}
+
+	if t.tracker.record(q) {
+		t.log.Warnf("%v: %d of %d %s API requests remaining until %v",
+			req.URL.Host, q.Remaining, q.Limit, q.Resource,
+			q.Reset.Local().Format(time.Kitchen))
+	}
+}
+
+// waitForReset waits for a rate limit to reset.
+// Unlike backoff waits, these are reported to the user
+// because they may take a while.
+func (t *transport) waitForReset(ctx context.Context, host, resource string, delay time.Duration, reset time.Time) error {
+	if delay > t.maxWait {
+		return &Error{Host: host, Resource: resource, Reset: reset}
+	}
+
+	if delay >= time.Second {
+		t.log.Infof("%v: API rate limit exceeded, retrying in %v",
+			host, delay.Round(time.Second))
+	}
+	return t.wait(ctx, "rate limit wait", resource, delay)
+}
+
+func (t *transport) wait(ctx context.Context, name, resource string, delay time.Duration) error {
+	ctx, span := tracing.Start(ctx, tracing.CategoryHTTP, name,
+		"resource", resource, "delay", delay.String())
+	defer span.End()
+
+	return t.sleep(ctx, delay)
+}
+
+// backoff returns a jittered delay for the given attempt,
+// growing exponentially with each attempt.
+func (t *transport) backoff(attempt int) time.Duration {
+	d := min(_minBackoff<<(attempt-1), _maxBackoff)
+	half := int64(d / 2)
+	return time.Duration(half + t.jitter(half+1))
+}
+
+// requestResource reports the resource that a request is attributed to.
+func requestResource(req *http.Request) string {
+	if strings.HasSuffix(strings.TrimRight(req.URL.Path, "/"), "/graphql") {
+		return ResourceGraphQL
+	}
+	return ResourceCore
+}
+
+// isNetworkError reports whether err is a failure to talk to the server,
+// as opposed to, say, a failure to obtain credentials.
+func isNetworkError(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// rewind returns a copy of req that may be sent again.
+func rewind(req *http.Request) (*http.Request, error) {
+	newReq := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("rewind request body: %w", err)
+		}
+		newReq.Body = body
+	}
+	return newReq, nil
+}
+
+// discard drains and closes a response body
+// so that the connection may be reused.
+func discard(res *http.Response) {
+	if res == nil {
+		return
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, _maxPeekBody))
+	_ = res.Body.Close()
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
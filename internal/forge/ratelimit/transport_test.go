@@ -0,0 +1,479 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/graphqlutil"
+)
+
+func TestParseQuota(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		header http.Header
+		want   Quota
+		wantOK bool
+	}{
+		{
+			name: "GitHub",
+			header: http.Header{
+				"X-Ratelimit-Limit":     {"5000"},
+				"X-Ratelimit-Remaining": {"4321"},
+				"X-Ratelimit-Reset":     {strconv.FormatInt(now.Add(time.Hour).Unix(), 10)},
+				"X-Ratelimit-Resource":  {"graphql"},
+			},
+			want: Quota{
+				Resource:  "graphql",
+				Limit:     5000,
+				Remaining: 4321,
+				Reset:     now.Add(time.Hour),
+			},
+			wantOK: true,
+		},
+		{
+			name: "GitLab",
+			header: http.Header{
+				"Ratelimit-Limit":     {"2000"},
+				"Ratelimit-Remaining": {"0"},
+				"Ratelimit-Reset":     {strconv.FormatInt(now.Add(time.Minute).Unix(), 10)},
+			},
+			want: Quota{
+				Limit: 2000,
+				Reset: now.Add(time.Minute),
+			},
+			wantOK: true,
+		},
+		{
+			name: "ResetSeconds",
+			header: http.Header{
+				"Ratelimit-Limit":     {"100"},
+				"Ratelimit-Remaining": {"10"},
+				"Ratelimit-Reset":     {"30"},
+			},
+			want: Quota{
+				Limit:     100,
+				Remaining: 10,
+				Reset:     now.Add(30 * time.Second),
+			},
+			wantOK: true,
+		},
+		{
+			name:   "NoHeaders",
+			header: http.Header{},
+		},
+		{
+			name: "BadRemaining",
+			header: http.Header{
+				"X-Ratelimit-Remaining": {"lots"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseQuota(tt.header, now)
+			assert.Equal(t, tt.wantOK, ok)
+			if !tt.wantOK {
+				return
+			}
+			assert.Equal(t, tt.want.Resource, got.Resource)
+			assert.Equal(t, tt.want.Limit, got.Limit)
+			assert.Equal(t, tt.want.Remaining, got.Remaining)
+			assert.True(t, tt.want.Reset.Equal(got.Reset),
+				"reset: want %v, got %v", tt.want.Reset, got.Reset)
+		})
+	}
+}
+
+func TestTransport_retryAfter(t *testing.T) {
+	tt := newTestTransport(t, nil,
+		respond(http.StatusTooManyRequests, "Retry-After", "2"),
+		respond(http.StatusOK),
+	)
+
+	res, err := tt.Get(t, "/user")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, []time.Duration{2 * time.Second}, tt.sleeps)
+}
+
+func TestTransport_primaryRateLimit(t *testing.T) {
+	tt := newTestTransport(t, nil)
+	tt.responses = []func(*http.Request) (*http.Response, error){
+		respond(http.StatusForbidden,
+			"X-RateLimit-Limit", "5000",
+			"X-RateLimit-Remaining", "0",
+			"X-RateLimit-Reset", strconv.FormatInt(tt.now.Add(30*time.Second).Unix(), 10)),
+		respond(http.StatusOK),
+	}
+
+	res, err := tt.Get(t, "/user")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, []time.Duration{30 * time.Second}, tt.sleeps)
+}
+
+func TestTransport_resetTooFar(t *testing.T) {
+	tt := newTestTransport(t, nil)
+	reset := tt.now.Add(time.Hour)
+	tt.responses = []func(*http.Request) (*http.Response, error){
+		respond(http.StatusForbidden,
+			"X-RateLimit-Remaining", "0",
+			"X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10)),
+	}
+
+	_, err := tt.Get(t, "/user")
+	require.Error(t, err)
+
+	var rlErr *Error
+	require.ErrorAs(t, err, &rlErr)
+	assert.Equal(t, "core", rlErr.Resource)
+	assert.True(t, reset.Equal(rlErr.Reset))
+	assert.Empty(t, tt.sleeps)
+	assert.Equal(t, 1, tt.attempts)
+
+	t.Run("QuotaExhausted", func(t *testing.T) {
+		// The next request fails without being sent.
+		_, err := tt.Get(t, "/user")
+		require.ErrorAs(t, err, &rlErr)
+		assert.Equal(t, 1, tt.attempts)
+	})
+}
+
+func TestTransport_forbidden(t *testing.T) {
+	tt := newTestTransport(t, nil, respondBody(http.StatusForbidden, "Resource not accessible by integration"))
+
+	res, err := tt.Get(t, "/user")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, res.StatusCode)
+	assert.Equal(t, 1, tt.attempts)
+
+	// The body must be left intact after peeking at it.
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "Resource not accessible by integration", string(body))
+}
+
+func TestTransport_secondaryRateLimit(t *testing.T) {
+	tt := newTestTransport(t, nil,
+		respondBody(http.StatusForbidden, `{"message": "You have exceeded a secondary rate limit."}`),
+		respond(http.StatusOK),
+	)
+
+	res, err := tt.Post(t, "/graphql", `{"query":"mutation{createPullRequest}"}`)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, 2, tt.attempts)
+	assert.Equal(t, []time.Duration{_minBackoff}, tt.sleeps)
+}
+
+func TestTransport_serverError(t *testing.T) {
+	t.Run("Idempotent", func(t *testing.T) {
+		tt := newTestTransport(t, nil,
+			respond(http.StatusBadGateway),
+			respond(http.StatusServiceUnavailable),
+			respond(http.StatusOK),
+		)
+
+		res, err := tt.Get(t, "/user")
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, []time.Duration{_minBackoff, 2 * _minBackoff}, tt.sleeps)
+	})
+
+	t.Run("NotIdempotent", func(t *testing.T) {
+		tt := newTestTransport(t, nil,
+			respond(http.StatusBadGateway),
+			respond(http.StatusOK),
+		)
+
+		res, err := tt.Post(t, "/repos/foo/bar/pulls", `{}`)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadGateway, res.StatusCode)
+		assert.Equal(t, 1, tt.attempts)
+	})
+
+	t.Run("GiveUp", func(t *testing.T) {
+		tt := newTestTransport(t, &Options{MaxAttempts: 2},
+			respond(http.StatusBadGateway),
+			respond(http.StatusBadGateway),
+			respond(http.StatusOK),
+		)
+
+		res, err := tt.Get(t, "/user")
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadGateway, res.StatusCode)
+		assert.Equal(t, 2, tt.attempts)
+	})
+}
+
+func TestTransport_networkError(t *testing.T) {
+	netErr := &net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset by peer")}
+
+	t.Run("Retried", func(t *testing.T) {
+		tt := newTestTransport(t, nil,
+			fail(netErr),
+			respond(http.StatusOK),
+		)
+
+		res, err := tt.Get(t, "/user")
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, 2, tt.attempts)
+	})
+
+	t.Run("OtherErrors", func(t *testing.T) {
+		tt := newTestTransport(t, nil,
+			fail(errors.New("get token: gh not installed")),
+			respond(http.StatusOK),
+		)
+
+		_, err := tt.Get(t, "/user")
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "gh not installed")
+		assert.Equal(t, 1, tt.attempts)
+	})
+}
+
+func TestTransport_graphQLErrors(t *testing.T) {
+	rateLimited := graphqlutil.Errors{{Type: "RATE_LIMITED", Message: "API rate limit exceeded"}}
+	timeout := graphqlutil.Errors{{Message: "This may be the result of a timeout"}}
+	notFound := graphqlutil.Errors{{Type: "NOT_FOUND", Message: "Could not resolve"}}
+
+	t.Run("RateLimitedMutation", func(t *testing.T) {
+		tt := newTestTransport(t, nil)
+		tt.responses = []func(*http.Request) (*http.Response, error){
+			respond(http.StatusOK,
+				"X-RateLimit-Remaining", "1",
+				"X-RateLimit-Reset", strconv.FormatInt(tt.now.Add(20*time.Second).Unix(), 10),
+				"X-RateLimit-Resource", "graphql"),
+			fail(rateLimited),
+			respond(http.StatusOK),
+		}
+
+		_, err := tt.Post(t, "/graphql", `{"query":"query{viewer{login}}"}`)
+		require.NoError(t, err)
+
+		// The reset time is taken from the last reported quota.
+		_, err = tt.Post(t, "/graphql", `{"query":"mutation{createPullRequest}"}`)
+		require.NoError(t, err)
+		assert.Equal(t, []time.Duration{20 * time.Second}, tt.sleeps)
+	})
+
+	t.Run("TimeoutQuery", func(t *testing.T) {
+		tt := newTestTransport(t, &Options{
+			Idempotent: graphqlutil.IsQuery,
+		}, fail(timeout), respond(http.StatusOK))
+
+		_, err := tt.Post(t, "/graphql", `{"query":"query{viewer{login}}"}`)
+		require.NoError(t, err)
+		assert.Equal(t, 2, tt.attempts)
+	})
+
+	t.Run("TimeoutMutation", func(t *testing.T) {
+		tt := newTestTransport(t, &Options{
+			Idempotent: graphqlutil.IsQuery,
+		}, fail(timeout), respond(http.StatusOK))
+
+		_, err := tt.Post(t, "/graphql", `{"query":"mutation{createPullRequest}"}`)
+		require.Error(t, err)
+		assert.Equal(t, 1, tt.attempts)
+	})
+
+	t.Run("NotRetryable", func(t *testing.T) {
+		tt := newTestTransport(t, &Options{
+			Idempotent: graphqlutil.IsQuery,
+		}, fail(notFound), respond(http.StatusOK))
+
+		_, err := tt.Post(t, "/graphql", `{"query":"query{viewer{login}}"}`)
+		require.ErrorIs(t, err, graphqlutil.ErrNotFound)
+		assert.Equal(t, 1, tt.attempts)
+	})
+}
+
+func TestTransport_waitForExhaustedQuota(t *testing.T) {
+	tt := newTestTransport(t, nil)
+	tt.responses = []func(*http.Request) (*http.Response, error){
+		respond(http.StatusOK,
+			"X-RateLimit-Limit", "5000",
+			"X-RateLimit-Remaining", "0",
+			"X-RateLimit-Reset", strconv.FormatInt(tt.now.Add(10*time.Second).Unix(), 10)),
+		respond(http.StatusOK),
+	}
+
+	_, err := tt.Get(t, "/user")
+	require.NoError(t, err)
+	assert.Empty(t, tt.sleeps)
+
+	_, err = tt.Get(t, "/user")
+	require.NoError(t, err)
+	assert.Equal(t, []time.Duration{10 * time.Second}, tt.sleeps)
+
+	q, ok := tt.tracker.Quota("core")
+	require.True(t, ok)
+	assert.Equal(t, 0, q.Remaining)
+	assert.Equal(t, 5000, q.Limit)
+}
+
+func TestTransport_rewindsBody(t *testing.T) {
+	var bodies []string
+	record := func(next func(*http.Request) (*http.Response, error)) func(*http.Request) (*http.Response, error) {
+		return func(req *http.Request) (*http.Response, error) {
+			bs, err := io.ReadAll(req.Body)
+			require.NoError(t, err)
+			bodies = append(bodies, string(bs))
+			return next(req)
+		}
+	}
+
+	tt := newTestTransport(t, nil,
+		record(respond(http.StatusTooManyRequests, "Retry-After", "1")),
+		record(respond(http.StatusOK)),
+	)
+
+	_, err := tt.Post(t, "/graphql", `{"query":"mutation{createPullRequest}"}`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`{"query":"mutation{createPullRequest}"}`,
+		`{"query":"mutation{createPullRequest}"}`,
+	}, bodies)
+}
+
+func TestTransport_maxAttempts(t *testing.T) {
+	tt := newTestTransport(t, &Options{MaxAttempts: 3},
+		respond(http.StatusTooManyRequests, "Retry-After", "1"),
+		respond(http.StatusTooManyRequests, "Retry-After", "1"),
+		respond(http.StatusTooManyRequests, "Retry-After", "1"),
+		respond(http.StatusOK),
+	)
+
+	_, err := tt.Get(t, "/user")
+	var rlErr *Error
+	require.ErrorAs(t, err, &rlErr)
+	assert.Equal(t, "example.com: API rate limit exceeded for \"core\"; resets at "+
+		rlErr.Reset.Local().Format(time.Kitchen), rlErr.Error())
+	assert.Equal(t, 3, tt.attempts)
+}
+
+func TestTransport_canceled(t *testing.T) {
+	tt := newTestTransport(t, nil,
+		respond(http.StatusTooManyRequests, "Retry-After", "10"),
+		respond(http.StatusOK),
+	)
+	tt.transport.sleep = func(context.Context, time.Duration) error {
+		return context.Canceled
+	}
+
+	_, err := tt.Get(t, "/user")
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, tt.attempts)
+}
+
+func TestTracker_lowQuota(t *testing.T) {
+	var tracker Tracker
+	assert.False(t, tracker.record(Quota{Resource: "core", Limit: 100, Remaining: 50}))
+	assert.True(t, tracker.record(Quota{Resource: "core", Limit: 100, Remaining: 9}))
+	assert.False(t, tracker.record(Quota{Resource: "core", Limit: 100, Remaining: 8}),
+		"low quota must be reported only once")
+	assert.True(t, tracker.record(Quota{Resource: "graphql", Limit: 100, Remaining: 0}))
+
+	assert.Equal(t, []Quota{
+		{Resource: "core", Limit: 100, Remaining: 8},
+		{Resource: "graphql", Limit: 100, Remaining: 0},
+	}, tracker.Quotas())
+}
+
+type testTransport struct {
+	*transport
+
+	now       time.Time
+	responses []func(*http.Request) (*http.Response, error)
+	attempts  int
+	sleeps    []time.Duration
+}
+
+func newTestTransport(
+	t *testing.T,
+	opts *Options,
+	responses ...func(*http.Request) (*http.Response, error),
+) *testTransport {
+	tt := &testTransport{
+		now:       time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		responses: responses,
+	}
+
+	tt.transport = WrapTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		require.Less(t, tt.attempts, len(tt.responses), "unexpected request")
+		res := tt.responses[tt.attempts]
+		tt.attempts++
+		return res(req)
+	}), opts).(*transport)
+	tt.transport.now = func() time.Time { return tt.now }
+	tt.transport.sleep = func(_ context.Context, d time.Duration) error {
+		tt.sleeps = append(tt.sleeps, d)
+		tt.now = tt.now.Add(d)
+		return nil
+	}
+	tt.transport.jitter = func(n int64) int64 { return n - 1 } // no jitter
+	return tt
+}
+
+func (tt *testTransport) Get(t *testing.T, path string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "https://example.com"+path, nil)
+	require.NoError(t, err)
+	return tt.RoundTrip(req)
+}
+
+func (tt *testTransport) Post(t *testing.T, path, body string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, "https://example.com"+path, strings.NewReader(body))
+	require.NoError(t, err)
+	return tt.RoundTrip(req)
+}
+
+// respond returns a response with the given status and headers.
+func respond(status int, headers ...string) func(*http.Request) (*http.Response, error) {
+	return func(*http.Request) (*http.Response, error) {
+		h := make(http.Header)
+		for i := 0; i+1 < len(headers); i += 2 {
+			h.Set(headers[i], headers[i+1])
+		}
+		return &http.Response{
+			StatusCode: status,
+			Header:     h,
+			Body:       io.NopCloser(strings.NewReader("")),
+		}, nil
+	}
+}
+
+func respondBody(status int, body string) func(*http.Request) (*http.Response, error) {
+	return func(*http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: status,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(strings.NewReader(body)),
+		}, nil
+	}
+}
+
+func fail(err error) func(*http.Request) (*http.Response, error) {
+	return func(*http.Request) (*http.Response, error) {
+		return nil, err
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
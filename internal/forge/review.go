@@ -0,0 +1,43 @@
+package forge
+
+import "context"
+
+// ReviewPoster is an optional capability implemented by a [Repository]
+// that can post reviews with inline comments on a change.
+//
+// Reviews can't be posted to forges that don't implement this.
+type ReviewPoster interface {
+	// PostPendingReview adds a pending review to a change
+	// with the given summary and inline comments.
+	//
+	// The review is visible only to its author
+	// until they submit it from the forge's web interface.
+	PostPendingReview(ctx context.Context, id ChangeID, req *PostReviewRequest) error
+}
+
+// PostReviewRequest is a request to post a review on a change.
+type PostReviewRequest struct {
+	// Body is the summary of the review.
+	// It may be empty.
+	Body string
+
+	// Comments are inline comments on the change's diff.
+	Comments []*ReviewComment
+}
+
+// ReviewComment is an inline comment posted as part of a review.
+type ReviewComment struct {
+	// Path is the path of the file in the new version of the change.
+	Path string // required
+
+	// StartLine and EndLine are the inclusive range of lines
+	// in the new version of the file that the comment is about.
+	// They're equal for single-line comments.
+	//
+	// Forges that don't support multi-line comments
+	// attach the comment to EndLine.
+	StartLine, EndLine int // required
+
+	// Body is the text of the comment.
+	Body string // required
+}
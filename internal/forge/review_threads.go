@@ -132,6 +132,44 @@ type ChangeCheckItem struct {
 
 	// EndedAt is the time the check run finished executing.
 	EndedAt time.Time
+
+	// Annotations are the problems that the check run reported
+	// at specific locations in the code (e.g. lint errors).
+	//
+	// This is only populated if requested with
+	// [ListChangeChecksOptions.IncludeAnnotations].
+	Annotations []*CheckAnnotation
+}
+
+// CheckAnnotationLevel is the severity of a check annotation.
+type CheckAnnotationLevel string
+
+// Supported check annotation levels.
+const (
+	CheckAnnotationFailure CheckAnnotationLevel = "failure"
+	CheckAnnotationWarning CheckAnnotationLevel = "warning"
+	CheckAnnotationNotice  CheckAnnotationLevel = "notice"
+)
+
+// CheckAnnotation is a problem reported by a CI check run
+// at a location in the code.
+type CheckAnnotation struct {
+	// File is the path of the file the annotation refers to.
+	File string
+
+	// LineRange is the inclusive [start, end] line range
+	// within the file that the annotation covers.
+	// Both elements are 0 when the annotation is not anchored to lines.
+	LineRange [2]int
+
+	// Level is the severity of the annotation.
+	Level CheckAnnotationLevel
+
+	// Title is a short summary of the annotation, if any.
+	Title string
+
+	// Message describes the problem.
+	Message string
 }
 
 // ListChangeChecksOptions specifies filtering options
@@ -144,6 +182,11 @@ type ListChangeChecksOptions struct {
 	// When the struct is passed but the field is left at its zero value,
 	// all check runs are returned.
 	OnlyFailing bool
+
+	// IncludeAnnotations requests annotations for each check run
+	// in [ChangeCheckItem.Annotations].
+	// Forges that don't support annotations ignore this.
+	IncludeAnnotations bool
 }
 
 // ChangeChecksLister is an optional capability implemented by a [Repository]
@@ -120,6 +120,12 @@ type shamChange struct {
 
 	// Assignees are users assigned to the change.
 	Assignees []string
+
+	// Milestone is the title of the milestone for the change, if any.
+	Milestone string
+
+	// Projects are the titles of projects the change is part of.
+	Projects []string
 }
 
 // Change is a change proposal against a repository.
@@ -171,6 +177,12 @@ type Change struct {
 
 	// Assignees are users assigned to the change.
 	Assignees []string `json:"assignees,omitempty"`
+
+	// Milestone is the title of the milestone for the change, if any.
+	Milestone string `json:"milestone,omitempty"`
+
+	// Projects are the titles of projects the change is part of.
+	Projects []string `json:"projects,omitempty"`
 }
 
 // toChange converts an internal shamChange
@@ -215,6 +227,8 @@ func (sh *ShamHub) toChange(c shamChange) (*Change, error) {
 		Labels:             c.Labels,
 		RequestedReviewers: requestedReviewers,
 		Assignees:          assignees,
+		Milestone:          c.Milestone,
+		Projects:           c.Projects,
 	}
 	switch c.State {
 	case shamChangeOpen:
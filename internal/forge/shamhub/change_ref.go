@@ -0,0 +1,21 @@
+package shamhub
+
+import (
+	"fmt"
+
+	"go.abhg.dev/gs/internal/forge"
+)
+
+// Compile-time check that forgeRepository implements ChangeIDParser.
+var _ forge.ChangeIDParser = (*forgeRepository)(nil)
+
+// ParseChangeID parses a reference to a change in this repository.
+// Changes may be referenced by number ("123", "#123") or by URL.
+func (r *forgeRepository) ParseChangeID(s string) (forge.ChangeID, error) {
+	changeURL := fmt.Sprintf("%s/%s/%s/changes/", r.forge.URL, r.owner, r.repo)
+	n, err := forge.ParseChangeNumber(s, "#", changeURL)
+	if err != nil {
+		return nil, err
+	}
+	return ChangeID(n), nil
+}
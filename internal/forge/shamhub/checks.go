@@ -29,6 +29,8 @@ type shamCheck struct {
 	StartedAt  time.Time
 	EndedAt    time.Time
 	Log        string
+
+	Annotations []CheckAnnotationInput
 }
 
 // ChangeCheckInput specifies the fields of a seeded check for tests.
@@ -39,6 +41,28 @@ type ChangeCheckInput struct {
 	URL        string
 	StartedAt  time.Time
 	EndedAt    time.Time
+
+	Annotations []CheckAnnotationInput
+}
+
+// CheckAnnotationInput specifies the fields of an annotation
+// on a seeded check.
+type CheckAnnotationInput struct {
+	File      string                     `json:"file,omitempty"`
+	LineRange [2]int                     `json:"lineRange,omitzero"`
+	Level     forge.CheckAnnotationLevel `json:"level,omitempty"`
+	Title     string                     `json:"title,omitempty"`
+	Message   string                     `json:"message,omitempty"`
+}
+
+func (a *CheckAnnotationInput) toAnnotation() *forge.CheckAnnotation {
+	return &forge.CheckAnnotation{
+		File:      a.File,
+		LineRange: a.LineRange,
+		Level:     a.Level,
+		Title:     a.Title,
+		Message:   a.Message,
+	}
 }
 
 // SeedCheck inserts a check run for owner/repo/changeNumber with the given log.
@@ -66,6 +90,7 @@ func (sh *ShamHub) SeedCheck(
 		StartedAt:    item.StartedAt,
 		EndedAt:      item.EndedAt,
 		Log:          log,
+		Annotations:  item.Annotations,
 	})
 
 	return forge.CheckRunID(strconv.Itoa(id)), nil
@@ -92,6 +117,11 @@ func (sh *ShamHub) ListChangeChecksForTest(
 
 // toChangeCheckItem converts a shamCheck to a forge.ChangeCheckItem.
 func toChangeCheckItem(c shamCheck) *forge.ChangeCheckItem {
+	var annotations []*forge.CheckAnnotation
+	for _, a := range c.Annotations {
+		annotations = append(annotations, a.toAnnotation())
+	}
+
 	return &forge.ChangeCheckItem{
 		ID:         forge.CheckRunID(strconv.Itoa(c.ID)),
 		Name:       c.Name,
@@ -100,6 +130,8 @@ func toChangeCheckItem(c shamCheck) *forge.ChangeCheckItem {
 		URL:        c.URL,
 		StartedAt:  c.StartedAt,
 		EndedAt:    c.EndedAt,
+
+		Annotations: annotations,
 	}
 }
 
@@ -129,7 +161,8 @@ type listChecksRequest struct {
 	Repo   string `path:"repo" json:"-"`
 	Number int    `path:"number" json:"-"`
 
-	OnlyFailing bool `json:"onlyFailing,omitempty"`
+	OnlyFailing        bool `json:"onlyFailing,omitempty"`
+	IncludeAnnotations bool `json:"includeAnnotations,omitempty"`
 }
 
 // listChecksResponse is the response type for listing check runs.
@@ -146,6 +179,8 @@ type listCheckItem struct {
 	URL        string    `json:"url,omitempty"`
 	StartedAt  time.Time `json:"startedAt,omitzero"`
 	EndedAt    time.Time `json:"endedAt,omitzero"`
+
+	Annotations []CheckAnnotationInput `json:"annotations,omitempty"`
 }
 
 // handleListChecks handles POST /{owner}/{repo}/changes/{number}/checks/list.
@@ -170,7 +205,7 @@ func (sh *ShamHub) handleListChecks(
 			continue
 		}
 
-		items = append(items, &listCheckItem{
+		item := &listCheckItem{
 			ID:         strconv.Itoa(c.ID),
 			Name:       c.Name,
 			Status:     c.Status,
@@ -178,7 +213,11 @@ func (sh *ShamHub) handleListChecks(
 			URL:        c.URL,
 			StartedAt:  c.StartedAt,
 			EndedAt:    c.EndedAt,
-		})
+		}
+		if req.IncludeAnnotations {
+			item.Annotations = c.Annotations
+		}
+		items = append(items, item)
 	}
 
 	return &listChecksResponse{Items: items}, nil
@@ -230,8 +269,10 @@ func (r *forgeRepository) ListChangeChecks(
 	opts *forge.ListChangeChecksOptions,
 ) iter.Seq2[*forge.ChangeCheckItem, error] {
 	onlyFailing := true // default when opts is nil
+	var includeAnnotations bool
 	if opts != nil {
 		onlyFailing = opts.OnlyFailing
+		includeAnnotations = opts.IncludeAnnotations
 	}
 
 	changeNum := int(id.(ChangeID))
@@ -241,7 +282,10 @@ func (r *forgeRepository) ListChangeChecks(
 		"checks", "list",
 	)
 
-	req := listChecksRequest{OnlyFailing: onlyFailing}
+	req := listChecksRequest{
+		OnlyFailing:        onlyFailing,
+		IncludeAnnotations: includeAnnotations,
+	}
 
 	return func(yield func(*forge.ChangeCheckItem, error) bool) {
 		var res listChecksResponse
@@ -260,6 +304,9 @@ func (r *forgeRepository) ListChangeChecks(
 				StartedAt:  item.StartedAt,
 				EndedAt:    item.EndedAt,
 			}
+			for _, a := range item.Annotations {
+				check.Annotations = append(check.Annotations, a.toAnnotation())
+			}
 
 			if !yield(check, nil) {
 				return
@@ -49,6 +49,12 @@ func TestForgeRepository_ListChangeChecks(t *testing.T) {
 		Status:     "completed",
 		Conclusion: "failure",
 		URL:        "http://example.com/checks/1",
+		Annotations: []CheckAnnotationInput{{
+			File:      "main.go",
+			LineRange: [2]int{3, 4},
+			Level:     forge.CheckAnnotationFailure,
+			Message:   "undefined: foo",
+		}},
 	}, "build failed: exit status 1\n")
 	require.NoError(t, err)
 
@@ -104,6 +110,30 @@ func TestForgeRepository_ListChangeChecks(t *testing.T) {
 		assert.Equal(t, failID, items[0].ID)
 		assert.Equal(t, "CI / build", items[0].Name)
 		assert.Equal(t, "failure", items[0].Conclusion)
+		assert.Empty(t, items[0].Annotations, "annotations were not requested")
+	})
+
+	t.Run("Annotations", func(t *testing.T) {
+		var items []*forge.ChangeCheckItem
+		for item, err := range lister.ListChangeChecks(
+			t.Context(),
+			ChangeID(changeNumber),
+			&forge.ListChangeChecksOptions{
+				OnlyFailing:        true,
+				IncludeAnnotations: true,
+			},
+		) {
+			require.NoError(t, err)
+			items = append(items, item)
+		}
+
+		require.Len(t, items, 1)
+		assert.Equal(t, []*forge.CheckAnnotation{{
+			File:      "main.go",
+			LineRange: [2]int{3, 4},
+			Level:     forge.CheckAnnotationFailure,
+			Message:   "undefined: foo",
+		}}, items[0].Annotations)
 	})
 
 	t.Run("ZeroValueOpts", func(t *testing.T) {
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/rogpeppe/go-internal/testscript"
+	"go.abhg.dev/gs/internal/forge"
 	"go.abhg.dev/gs/internal/silog/silogtest"
 	"go.abhg.dev/io/ioutil"
 	"gopkg.in/yaml.v3"
@@ -223,6 +224,23 @@ func (c *Cmd) Run(ts *testscript.TestScript, neg bool, args []string) {
 		}
 		ts.Check(sh.RejectChange(req))
 
+	case "rate-limit":
+		// shamhub rate-limit <n>
+		// Rejects the next n API requests
+		// as if the rate limit had been exceeded.
+		if len(args) != 1 {
+			ts.Fatalf("usage: shamhub rate-limit <n>")
+		}
+		if sh == nil {
+			ts.Fatalf("ShamHub not initialized")
+		}
+
+		n, err := strconv.Atoi(args[0])
+		if err != nil {
+			ts.Fatalf("invalid count: %s", err)
+		}
+		sh.RateLimit(n)
+
 	case "draft":
 		if len(args) != 2 {
 			ts.Fatalf("usage: shamhub draft <owner/repo> <pr>")
@@ -331,6 +349,82 @@ func (c *Cmd) Run(ts *testscript.TestScript, neg bool, args []string) {
 			Reviewer: reviewer,
 		}))
 
+	case "thread":
+		// shamhub thread <owner/repo> <pr> <author> <file>:<lines> <body>
+		// Opens a review thread on lines of a file in the PR.
+		// Lines are a single line number or an inclusive range "start-end".
+		if len(args) != 5 {
+			ts.Fatalf("usage: shamhub thread <owner/repo> <pr> <author> <file>:<lines> <body>")
+		}
+		if sh == nil {
+			ts.Fatalf("ShamHub not initialized")
+		}
+
+		ownerRepo, prStr, author := args[0], args[1], args[2]
+		owner, repo, ok := strings.Cut(ownerRepo, "/")
+		if !ok {
+			ts.Fatalf("invalid owner/repo: %s", ownerRepo)
+		}
+		pr, err := strconv.Atoi(prStr)
+		if err != nil {
+			ts.Fatalf("invalid PR number: %s", err)
+		}
+		file, lines, err := parseFileLines(args[3])
+		if err != nil {
+			ts.Fatalf("invalid location: %s", err)
+		}
+
+		_, err = sh.SeedReviewThread(owner, repo, pr, ReviewThreadInput{
+			File:      file,
+			LineRange: lines,
+			Author:    author,
+			Body:      args[4],
+		})
+		ts.Check(err)
+
+	case "check":
+		// shamhub check <owner/repo> <pr> <name> <conclusion> [<level> <file>:<lines> <message>]...
+		// Adds a completed check run to the PR
+		// with optional annotations on lines of files.
+		if len(args) < 4 || (len(args)-4)%3 != 0 {
+			ts.Fatalf("usage: shamhub check <owner/repo> <pr> <name> <conclusion> [<level> <file>:<lines> <message>]...")
+		}
+		if sh == nil {
+			ts.Fatalf("ShamHub not initialized")
+		}
+
+		ownerRepo, prStr, name, conclusion := args[0], args[1], args[2], args[3]
+		owner, repo, ok := strings.Cut(ownerRepo, "/")
+		if !ok {
+			ts.Fatalf("invalid owner/repo: %s", ownerRepo)
+		}
+		pr, err := strconv.Atoi(prStr)
+		if err != nil {
+			ts.Fatalf("invalid PR number: %s", err)
+		}
+
+		var annotations []CheckAnnotationInput
+		for rest := args[4:]; len(rest) > 0; rest = rest[3:] {
+			file, lines, err := parseFileLines(rest[1])
+			if err != nil {
+				ts.Fatalf("invalid location: %s", err)
+			}
+			annotations = append(annotations, CheckAnnotationInput{
+				File:      file,
+				LineRange: lines,
+				Level:     forge.CheckAnnotationLevel(rest[0]),
+				Message:   rest[2],
+			})
+		}
+
+		_, err = sh.SeedCheck(owner, repo, pr, ChangeCheckInput{
+			Name:        name,
+			Status:      "completed",
+			Conclusion:  conclusion,
+			Annotations: annotations,
+		}, "")
+		ts.Check(err)
+
 	case "register":
 		if len(args) != 1 {
 			ts.Fatalf("usage: shamhub register <username>")
@@ -431,6 +525,18 @@ func (c *Cmd) Run(ts *testscript.TestScript, neg bool, args []string) {
 				return enc.Encode(v)
 			}
 
+		case "reviews":
+			if len(args) != 0 {
+				ts.Fatalf("usage: shamhub dump reviews")
+			}
+
+			give = sh.ListPendingReviews()
+			encode = func(v any) error {
+				enc := yaml.NewEncoder(ts.Stdout())
+				enc.SetIndent(2)
+				return enc.Encode(v)
+			}
+
 		case "change":
 			if len(args) != 1 {
 				ts.Fatalf("usage: shamhub dump change <N>")
@@ -464,3 +570,24 @@ func (c *Cmd) Run(ts *testscript.TestScript, neg bool, args []string) {
 		ts.Fatalf("unknown command: %s", cmd)
 	}
 }
+
+// parseFileLines parses a location in the form "file:line"
+// or "file:start-end".
+func parseFileLines(s string) (file string, lines [2]int, err error) {
+	file, lineStr, ok := strings.Cut(s, ":")
+	if !ok {
+		return "", lines, fmt.Errorf("expected <file>:<lines>: %q", s)
+	}
+
+	startStr, endStr, ok := strings.Cut(lineStr, "-")
+	if !ok {
+		endStr = startStr
+	}
+	if lines[0], err = strconv.Atoi(startStr); err != nil {
+		return "", lines, fmt.Errorf("invalid start line: %w", err)
+	}
+	if lines[1], err = strconv.Atoi(endStr); err != nil {
+		return "", lines, fmt.Errorf("invalid end line: %w", err)
+	}
+	return file, lines, nil
+}
@@ -19,6 +19,13 @@ type editChangeRequest struct {
 	Labels    []string `json:"labels,omitempty"`
 	Reviewers []string `json:"reviewers,omitempty"`
 	Assignees []string `json:"assignees,omitempty"`
+	Milestone *string  `json:"milestone,omitempty"`
+	Projects  []string `json:"projects,omitempty"`
+
+	RemoveLabels    []string `json:"remove_labels,omitempty"`
+	RemoveReviewers []string `json:"remove_reviewers,omitempty"`
+	RemoveAssignees []string `json:"remove_assignees,omitempty"`
+	RemoveProjects  []string `json:"remove_projects,omitempty"`
 }
 
 type editChangeResponse struct{}
@@ -104,9 +111,34 @@ func (sh *ShamHub) handleEditChange(_ context.Context, req *editChangeRequest) (
 		sh.changes[changeIdx].Assignees = assignees
 	}
 
+	if m := req.Milestone; m != nil {
+		sh.changes[changeIdx].Milestone = *m
+	}
+	for _, project := range req.Projects {
+		if !slices.Contains(sh.changes[changeIdx].Projects, project) {
+			sh.changes[changeIdx].Projects = append(sh.changes[changeIdx].Projects, project)
+		}
+	}
+
+	change := &sh.changes[changeIdx]
+	change.Labels = removeAll(change.Labels, req.RemoveLabels)
+	change.RequestedReviewers = removeAll(change.RequestedReviewers, req.RemoveReviewers)
+	change.Assignees = removeAll(change.Assignees, req.RemoveAssignees)
+	change.Projects = removeAll(change.Projects, req.RemoveProjects)
+
 	return &editChangeResponse{}, nil // empty for now
 }
 
+// removeAll returns items without any of the values in remove.
+func removeAll(items, remove []string) []string {
+	if len(remove) == 0 {
+		return items
+	}
+	return slices.DeleteFunc(items, func(item string) bool {
+		return slices.Contains(remove, item)
+	})
+}
+
 func (r *forgeRepository) EditChange(ctx context.Context, fid forge.ChangeID, opts forge.EditChangeOptions) error {
 	var req editChangeRequest
 	if opts.Base != "" {
@@ -118,6 +150,12 @@ func (r *forgeRepository) EditChange(ctx context.Context, fid forge.ChangeID, op
 	req.Labels = opts.AddLabels
 	req.Reviewers = opts.AddReviewers
 	req.Assignees = opts.AddAssignees
+	req.Milestone = opts.Milestone
+	req.Projects = opts.AddProjects
+	req.RemoveLabels = opts.RemoveLabels
+	req.RemoveReviewers = opts.RemoveReviewers
+	req.RemoveAssignees = opts.RemoveAssignees
+	req.RemoveProjects = opts.RemoveProjects
 
 	id := fid.(ChangeID)
 	u := r.apiURL.JoinPath(r.owner, r.repo, "change", strconv.Itoa(int(id)))
@@ -128,3 +166,17 @@ func (r *forgeRepository) EditChange(ctx context.Context, fid forge.ChangeID, op
 
 	return nil
 }
+
+var _ forge.ChangeProjectLister = (*forgeRepository)(nil)
+
+// ListChangeProjects returns the titles of the projects
+// that a change is part of.
+func (r *forgeRepository) ListChangeProjects(ctx context.Context, fid forge.ChangeID) ([]string, error) {
+	id := fid.(ChangeID)
+	u := r.apiURL.JoinPath(r.owner, r.repo, "change", strconv.Itoa(int(id)))
+	var res Change
+	if err := r.client.Get(ctx, u.String(), &res); err != nil {
+		return nil, fmt.Errorf("get change: %w", err)
+	}
+	return res.Projects, nil
+}
@@ -142,12 +142,14 @@ func toFindChangeItem(c *Change) *forge.FindChangeItem {
 		URL:       c.URL,
 		Subject:   c.Subject,
 		HeadHash:  git.Hash(c.Head.Hash),
+		HeadName:  c.Head.Name,
 		BaseName:  c.Base.Name,
 		Draft:     c.Draft,
 		State:     state,
 		Labels:    labels,
 		Reviewers: reviewers,
 		Assignees: assignees,
+		Milestone: c.Milestone,
 	}
 }
 
@@ -11,8 +11,11 @@ import (
 	"strings"
 
 	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/forge/forgecache"
+	"go.abhg.dev/gs/internal/forge/ratelimit"
 	"go.abhg.dev/gs/internal/must"
 	"go.abhg.dev/gs/internal/silog"
+	"go.abhg.dev/gs/internal/tracing"
 )
 
 // Options defines CLI options for the ShamHub forge.
@@ -70,8 +73,26 @@ func (f *Forge) ParseRemoteURL(remoteURL string) (forge.RepositoryID, error) {
 }
 
 // OpenRepository opens the repository that this repository ID points to.
-func (f *Forge) OpenRepository(_ context.Context, token forge.AuthenticationToken, id forge.RepositoryID) (forge.Repository, error) {
-	return newRepository(f, token.(*AuthenticationToken), id.(*RepositoryID), http.DefaultClient)
+func (f *Forge) OpenRepository(ctx context.Context, token forge.AuthenticationToken, id forge.RepositoryID) (forge.Repository, error) {
+	return f.OpenCachedRepository(ctx, token, id, nil)
+}
+
+var _ forge.CachedRepositoryOpener = (*Forge)(nil)
+
+// OpenCachedRepository opens the repository that this repository ID points to,
+// revalidating GET responses with conditional requests.
+func (f *Forge) OpenCachedRepository(
+	_ context.Context,
+	token forge.AuthenticationToken,
+	id forge.RepositoryID,
+	cache *forgecache.Cache,
+) (forge.Repository, error) {
+	httpClient := &http.Client{
+		Transport: ratelimit.WrapTransport(cache.Transport(tracing.WrapTransport(nil)), &ratelimit.Options{
+			Log: f.Log,
+		}),
+	}
+	return newRepository(f, token.(*AuthenticationToken), id.(*RepositoryID), httpClient)
 }
 
 // newRepository creates a new repository instance with the given HTTP client.
@@ -1,8 +1,11 @@
 package shamhub
 
 import (
+	"bytes"
 	"cmp"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -183,13 +186,28 @@ func buildRESTHandler[State, Req, Res any](state State, handler func(State, cont
 			return
 		}
 
-		w.Header().Set("Content-Type", "application/json")
-		enc := json.NewEncoder(w)
+		var body bytes.Buffer
+		enc := json.NewEncoder(&body)
 		enc.SetIndent("", "  ") // pretty print JSON
 		if err := enc.Encode(res); err != nil {
 			http.Error(w, fmt.Sprintf("encode response: %v", err), http.StatusInternalServerError)
 			return
 		}
+
+		// Like real forges, tag GET responses
+		// so that clients can make conditional requests.
+		if r.Method == http.MethodGet {
+			sum := sha256.Sum256(body.Bytes())
+			etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+			w.Header().Set("ETag", etag)
+			if r.Header.Get("If-None-Match") == etag {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body.Bytes())
 	})
 }
 
@@ -257,6 +275,10 @@ func (sh *ShamHub) apiHandler() http.Handler {
 			}
 		}
 
+		if !sh.countRequest(w, r) {
+			return
+		}
+
 		mux.ServeHTTP(w, r)
 	})
 }
@@ -0,0 +1,21 @@
+package shamhub
+
+import "go.abhg.dev/gs/internal/forge"
+
+// Compile-time check that forgeRepository implements IssueLinker.
+var _ forge.IssueLinker = (*forgeRepository)(nil)
+
+// IssueLinkText returns a GitHub-style closing keyword reference
+// to the given issue.
+// ShamHub does not have an issue tracker,
+// so this is only used to exercise issue linking in tests.
+func (r *forgeRepository) IssueLinkText(issue string) (string, bool) {
+	ref, ok := forge.IssueNumberRef(issue)
+	if !ok {
+		ref, ok = forge.IssueURLRef(issue, r.forge.URL, "/issues/")
+	}
+	if !ok {
+		return "", false
+	}
+	return "Fixes " + ref, true
+}
@@ -0,0 +1,183 @@
+package shamhub
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"go.abhg.dev/gs/internal/forge"
+)
+
+// Compile-time check that forgeRepository implements ReviewPoster.
+var _ forge.ReviewPoster = (*forgeRepository)(nil)
+
+// shamPendingReview is a review that hasn't been submitted yet.
+type shamPendingReview struct {
+	ID           int
+	Owner        string
+	Repo         string
+	ChangeNumber int
+
+	Author   string
+	Body     string
+	Comments []shamReviewComment
+}
+
+// shamReviewComment is an inline comment in a pending review.
+type shamReviewComment struct {
+	Path               string
+	StartLine, EndLine int
+	Body               string
+}
+
+// PendingReview is a review on a ShamHub change
+// that hasn't been submitted yet.
+type PendingReview struct {
+	Change   int                     `yaml:"change"`
+	Author   string                  `yaml:"author,omitempty"`
+	Body     string                  `yaml:"body,omitempty"`
+	Comments []*PendingReviewComment `yaml:"comments,omitempty"`
+}
+
+// PendingReviewComment is an inline comment in a [PendingReview].
+type PendingReviewComment struct {
+	Path  string `yaml:"path"`
+	Lines string `yaml:"lines"` // "N" or "N-M"
+	Body  string `yaml:"body"`
+}
+
+// ListPendingReviews returns all pending reviews in ShamHub.
+func (sh *ShamHub) ListPendingReviews() []*PendingReview {
+	sh.mu.RLock()
+	defer sh.mu.RUnlock()
+
+	reviews := make([]*PendingReview, len(sh.pendingReviews))
+	for i, r := range sh.pendingReviews {
+		comments := make([]*PendingReviewComment, len(r.Comments))
+		for j, c := range r.Comments {
+			lines := strconv.Itoa(c.StartLine)
+			if c.EndLine != c.StartLine {
+				lines += "-" + strconv.Itoa(c.EndLine)
+			}
+			comments[j] = &PendingReviewComment{
+				Path:  c.Path,
+				Lines: lines,
+				Body:  c.Body,
+			}
+		}
+
+		reviews[i] = &PendingReview{
+			Change:   r.ChangeNumber,
+			Author:   r.Author,
+			Body:     r.Body,
+			Comments: comments,
+		}
+	}
+	return reviews
+}
+
+var _ = shamhubRESTHandler(
+	"POST /{owner}/{repo}/changes/{number}/reviews",
+	(*ShamHub).handlePostPendingReview,
+)
+
+type postPendingReviewRequest struct {
+	Owner  string `path:"owner" json:"-"`
+	Repo   string `path:"repo" json:"-"`
+	Number int    `path:"number" json:"-"`
+
+	Body     string                      `json:"body,omitempty"`
+	Comments []*postPendingReviewComment `json:"comments,omitempty"`
+}
+
+type postPendingReviewComment struct {
+	Path      string `json:"path"`
+	StartLine int    `json:"startLine"`
+	EndLine   int    `json:"endLine"`
+	Body      string `json:"body"`
+}
+
+type postPendingReviewResponse struct {
+	ID int `json:"id"`
+}
+
+// handlePostPendingReview handles POST /{owner}/{repo}/changes/{number}/reviews.
+// Like GitHub, it allows only one pending review per user on a change.
+func (sh *ShamHub) handlePostPendingReview(
+	_ context.Context,
+	req *postPendingReviewRequest,
+) (*postPendingReviewResponse, error) {
+	comments := make([]shamReviewComment, len(req.Comments))
+	for i, c := range req.Comments {
+		if c.Path == "" || c.Body == "" {
+			return nil, badRequestErrorf("comment %d: path and body are required", i)
+		}
+		if c.StartLine <= 0 || c.EndLine < c.StartLine {
+			return nil, badRequestErrorf("comment %d: invalid line range %d-%d", i, c.StartLine, c.EndLine)
+		}
+		comments[i] = shamReviewComment(*c)
+	}
+
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	var found bool
+	for _, c := range sh.changes {
+		if c.Base.Owner == req.Owner && c.Base.Repo == req.Repo && c.Number == req.Number {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, notFoundErrorf("change %d not found in %s/%s", req.Number, req.Owner, req.Repo)
+	}
+
+	for _, r := range sh.pendingReviews {
+		if r.Owner == req.Owner && r.Repo == req.Repo && r.ChangeNumber == req.Number && r.Author == sh.viewerLogin {
+			return nil, badRequestErrorf("change %d already has a pending review", req.Number)
+		}
+	}
+
+	review := shamPendingReview{
+		ID:           len(sh.pendingReviews) + 1,
+		Owner:        req.Owner,
+		Repo:         req.Repo,
+		ChangeNumber: req.Number,
+		Author:       sh.viewerLogin,
+		Body:         req.Body,
+		Comments:     comments,
+	}
+	sh.pendingReviews = append(sh.pendingReviews, review)
+
+	return &postPendingReviewResponse{ID: review.ID}, nil
+}
+
+// PostPendingReview adds a pending review to a change.
+func (r *forgeRepository) PostPendingReview(
+	ctx context.Context,
+	id forge.ChangeID,
+	req *forge.PostReviewRequest,
+) error {
+	changeNum := int(id.(ChangeID))
+	u := r.apiURL.JoinPath(r.owner, r.repo, "changes", strconv.Itoa(changeNum), "reviews")
+
+	comments := make([]*postPendingReviewComment, len(req.Comments))
+	for i, c := range req.Comments {
+		comments[i] = &postPendingReviewComment{
+			Path:      c.Path,
+			StartLine: c.StartLine,
+			EndLine:   c.EndLine,
+			Body:      c.Body,
+		}
+	}
+
+	var res postPendingReviewResponse
+	if err := r.client.Post(ctx, u.String(), postPendingReviewRequest{
+		Body:     req.Body,
+		Comments: comments,
+	}, &res); err != nil {
+		return fmt.Errorf("post pending review: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package shamhub
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/forge/ratelimit"
+	"go.abhg.dev/gs/internal/must"
+)
+
+// _rateLimit is the number of API requests ShamHub allows per hour.
+const _rateLimit = 5000
+
+var _ forge.QuotaReporter = (*Forge)(nil)
+
+var _ = shamhubRESTHandler("GET /rate_limit", (*ShamHub).handleRateLimit)
+
+type rateLimitRequest struct{}
+
+type rateLimitResponse struct {
+	Limit     int   `json:"limit"`
+	Remaining int   `json:"remaining"`
+	Reset     int64 `json:"reset"` // Unix timestamp
+}
+
+func (sh *ShamHub) handleRateLimit(context.Context, rateLimitRequest) (*rateLimitResponse, error) {
+	sh.mu.RLock()
+	defer sh.mu.RUnlock()
+
+	return &rateLimitResponse{
+		Limit:     _rateLimit,
+		Remaining: max(_rateLimit-sh.apiRequests, 0),
+		Reset:     sh.rateLimitReset.Unix(),
+	}, nil
+}
+
+// RateLimit rejects the next n API requests
+// as if the rate limit had been exceeded.
+// Rejected requests should be retried after a second.
+func (sh *ShamHub) RateLimit(n int) {
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	sh.rateLimited = n
+}
+
+// countRequest counts an API request against the rate limit,
+// and reports the quota in the response headers.
+// It reports false if the request was rejected by the rate limit
+// and should not be handled.
+func (sh *ShamHub) countRequest(w http.ResponseWriter, r *http.Request) bool {
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	if now := time.Now(); now.After(sh.rateLimitReset) {
+		sh.apiRequests = 0
+		sh.rateLimitReset = now.Add(time.Hour)
+	}
+
+	// Like GitHub, checking the rate limit doesn't count against it.
+	if r.URL.Path != "/rate_limit" {
+		sh.apiRequests++
+	}
+
+	h := w.Header()
+	h.Set("RateLimit-Limit", strconv.Itoa(_rateLimit))
+	h.Set("RateLimit-Remaining", strconv.Itoa(max(_rateLimit-sh.apiRequests, 0)))
+	h.Set("RateLimit-Reset", strconv.FormatInt(sh.rateLimitReset.Unix(), 10))
+
+	if sh.rateLimited > 0 {
+		sh.rateLimited--
+		h.Set("Retry-After", "1")
+		http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+		return false
+	}
+	return true
+}
+
+// APIQuotas reports the API quota of the user
+// authenticated with the given token.
+func (f *Forge) APIQuotas(ctx context.Context, tok forge.AuthenticationToken) ([]ratelimit.Quota, error) {
+	must.NotBeBlankf(f.APIURL, "API URL is required")
+
+	client := f.jsonHTTPClient()
+	client.headers = map[string]string{
+		"Authentication-Token": tok.(*AuthenticationToken).tok,
+	}
+
+	u, err := url.JoinPath(f.APIURL, "/rate_limit")
+	if err != nil {
+		return nil, fmt.Errorf("parse API URL: %w", err)
+	}
+
+	var res rateLimitResponse
+	if err := client.Get(ctx, u, &res); err != nil {
+		return nil, fmt.Errorf("get rate limit: %w", err)
+	}
+
+	return []ratelimit.Quota{{
+		Resource:  ratelimit.ResourceCore,
+		Limit:     res.Limit,
+		Remaining: res.Remaining,
+		Reset:     time.Unix(res.Reset, 0),
+	}}, nil
+}
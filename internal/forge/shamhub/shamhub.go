@@ -12,6 +12,7 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 
 	"go.abhg.dev/gs/internal/silog"
 	"go.abhg.dev/gs/internal/xec"
@@ -34,16 +35,21 @@ type ShamHub struct {
 	apiServer *httptest.Server // API server
 	gitServer *httptest.Server // Git HTTP remote
 
-	mu            sync.RWMutex
-	changes       []shamChange       // all changes
-	users         []shamUser         // all users
-	comments      []shamComment      // all comments
-	repos         []shamRepo         // all repositories
-	reviewThreads []shamReviewThread // all review threads
-	checks        []shamCheck        // all check runs
+	mu             sync.RWMutex
+	changes        []shamChange        // all changes
+	users          []shamUser          // all users
+	comments       []shamComment       // all comments
+	repos          []shamRepo          // all repositories
+	reviewThreads  []shamReviewThread  // all review threads
+	checks         []shamCheck         // all check runs
+	pendingReviews []shamPendingReview // all unsubmitted reviews
 
 	tokens      map[string]string // token -> username
 	viewerLogin string            // login of the authenticated viewer
+
+	apiRequests    int       // API requests made in the rate limit window
+	rateLimitReset time.Time // end of the rate limit window
+	rateLimited    int       // number of upcoming API requests to reject
 }
 
 // Config configures a ShamHub server.
@@ -0,0 +1,18 @@
+package forge
+
+import "context"
+
+// TeamMemberLister is an optional capability implemented by a [Repository]
+// that can list the members of teams or groups on the forge.
+//
+// On forges that don't implement this,
+// teams are requested as reviewers as-is
+// instead of being expanded to their members.
+type TeamMemberLister interface {
+	// ListTeamMembers returns the logins of the members of a team.
+	//
+	// team is the name of the team as written in CODEOWNERS files
+	// without the leading "@", e.g. "org/team" on GitHub
+	// or "group/subgroup" on GitLab.
+	ListTeamMembers(ctx context.Context, team string) ([]string, error)
+}
@@ -0,0 +1,346 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.abhg.dev/gs/internal/silog"
+	"go.abhg.dev/gs/internal/tracing"
+	"go.abhg.dev/gs/internal/xec"
+)
+
+// objectReader reads objects from a repository
+// through a long-lived 'git cat-file --batch-command' process.
+//
+// The process is started on first use and shared by all requests.
+// Requests are serialized.
+// If the process fails, it's restarted on the next request.
+type objectReader struct {
+	newCmd func(ctx context.Context) *xec.Cmd
+	log    *silog.Logger
+
+	mu     sync.Mutex
+	cmd    *xec.Cmd       // nil if not running
+	stdin  io.WriteCloser // guarded by mu
+	stdout *bufio.Reader  // guarded by mu
+}
+
+func newObjectReader(log *silog.Logger, newCmd func(ctx context.Context) *xec.Cmd) *objectReader {
+	return &objectReader{newCmd: newCmd, log: log}
+}
+
+// objectHeader is the header printed by 'git cat-file --batch-command'
+// for an object that exists.
+type objectHeader struct {
+	Hash Hash
+	Type Type
+	Size int64
+}
+
+// errObjectAmbiguous indicates that an object name
+// matched more than one object.
+var errObjectAmbiguous = errors.New("ambiguous object name")
+
+// Info reports the hash, type, and size of the named object.
+// It returns [ErrNotExist] if the object doesn't exist.
+func (o *objectReader) Info(ctx context.Context, name string) (objectHeader, error) {
+	return o.request(ctx, "info", name, nil)
+}
+
+// Contents writes the contents of the named object to dst
+// and reports its header.
+// It returns [ErrNotExist] if the object doesn't exist.
+func (o *objectReader) Contents(ctx context.Context, name string, dst io.Writer) (objectHeader, error) {
+	if dst == nil {
+		dst = io.Discard
+	}
+	return o.request(ctx, "contents", name, dst)
+}
+
+// Close stops the cat-file process if it's running.
+// The reader may be used after Close; a new process will be started.
+func (o *objectReader) Close() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.stop(false /* kill */)
+}
+
+func (o *objectReader) request(ctx context.Context, command, name string, dst io.Writer) (objectHeader, error) {
+	if strings.ContainsAny(name, "\n") {
+		return objectHeader{}, fmt.Errorf("invalid object name: %q", name)
+	}
+	if err := ctx.Err(); err != nil {
+		return objectHeader{}, err
+	}
+
+	_, span := tracing.Start(ctx, tracing.CategoryExec, "git cat-file", "request", command+" "+name)
+	defer span.End()
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.cmd == nil {
+		if err := o.start(ctx); err != nil {
+			return objectHeader{}, err
+		}
+	}
+
+	hdr, err := o.roundTrip(command, name, dst)
+	if err != nil && !errors.Is(err, ErrNotExist) && !errors.Is(err, errObjectAmbiguous) {
+		// The process is in an unknown state.
+		// Restart it on the next request.
+		_ = o.stop(true /* kill */)
+	}
+	return hdr, err
+}
+
+func (o *objectReader) roundTrip(command, name string, dst io.Writer) (objectHeader, error) {
+	if _, err := fmt.Fprintf(o.stdin, "%s %s\n", command, name); err != nil {
+		return objectHeader{}, fmt.Errorf("cat-file: write request: %w", err)
+	}
+
+	line, err := o.stdout.ReadString('\n')
+	if err != nil {
+		return objectHeader{}, fmt.Errorf("cat-file: read header: %w", err)
+	}
+	line = strings.TrimSuffix(line, "\n")
+
+	// The header is one of:
+	//
+	//	<hash> SP <type> SP <size> LF
+	//	<name> SP missing LF
+	//	<name> SP ambiguous LF
+	//
+	// Names may contain spaces,
+	// so match the missing and ambiguous forms against the full name.
+	switch {
+	case line == name+" missing":
+		return objectHeader{}, ErrNotExist
+	case line == name+" ambiguous":
+		return objectHeader{}, fmt.Errorf("%q: %w", name, errObjectAmbiguous)
+	}
+
+	toks := strings.Split(line, " ")
+	if len(toks) != 3 {
+		return objectHeader{}, fmt.Errorf("cat-file: unexpected header: %q", line)
+	}
+	size, err := strconv.ParseInt(toks[2], 10, 64)
+	if err != nil {
+		return objectHeader{}, fmt.Errorf("cat-file: bad size in header %q: %w", line, err)
+	}
+	hdr := objectHeader{
+		Hash: Hash(toks[0]),
+		Type: Type(toks[1]),
+		Size: size,
+	}
+	if dst == nil {
+		return hdr, nil
+	}
+
+	// Contents are followed by a trailing LF.
+	if _, err := io.CopyN(dst, o.stdout, size); err != nil {
+		return objectHeader{}, fmt.Errorf("cat-file: read contents: %w", err)
+	}
+	if b, err := o.stdout.ReadByte(); err != nil {
+		return objectHeader{}, fmt.Errorf("cat-file: read trailing newline: %w", err)
+	} else if b != '\n' {
+		return objectHeader{}, fmt.Errorf("cat-file: expected trailing newline, got %q", b)
+	}
+	return hdr, nil
+}
+
+// start starts the cat-file process.
+// mu must be held.
+func (o *objectReader) start(ctx context.Context) error {
+	// The process outlives the request that started it,
+	// so it must not be killed when that request's context is canceled,
+	// and it isn't traced as part of that request.
+	// Individual requests are traced instead.
+	cmd := o.newCmd(tracing.WithoutRecorder(context.WithoutCancel(ctx)))
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("cat-file: stdin pipe: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		_ = stdin.Close()
+		return fmt.Errorf("cat-file: stdout pipe: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		_ = stdin.Close()
+		return fmt.Errorf("cat-file: start: %w", err)
+	}
+
+	o.cmd = cmd
+	o.stdin = stdin
+	o.stdout = bufio.NewReader(stdout)
+	return nil
+}
+
+// stop stops the cat-file process if it's running.
+// If kill is false, the process is asked to exit by closing its input.
+// mu must be held.
+func (o *objectReader) stop(kill bool) error {
+	if o.cmd == nil {
+		return nil
+	}
+
+	cmd, stdin := o.cmd, o.stdin
+	o.cmd, o.stdin, o.stdout = nil, nil, nil
+
+	if kill {
+		// The process may be blocked writing output
+		// that nobody will read.
+		_ = stdin.Close()
+		_ = cmd.Kill()
+		_ = cmd.Wait()
+		return nil
+	}
+
+	// cat-file exits when its input is closed.
+	if err := stdin.Close(); err != nil {
+		_ = cmd.Kill()
+		_ = cmd.Wait()
+		return fmt.Errorf("cat-file: close input: %w", err)
+	}
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("cat-file: %w", err)
+	}
+	return nil
+}
+
+// ReadObjectAt writes the contents of the blob at the given path
+// in the given tree-ish to dst.
+// It returns [ErrNotExist] if there's no such path,
+// and an error if the path isn't a blob.
+//
+// This uses a long-lived 'git cat-file' process
+// so it's cheap to call many times.
+func (r *Repository) ReadObjectAt(ctx context.Context, treeish, path string, dst io.Writer) error {
+	var buf bytes.Buffer
+	hdr, err := r.objects.Contents(ctx, treeish+":"+path, &buf)
+	if err != nil {
+		return err
+	}
+	if hdr.Type != BlobType {
+		return fmt.Errorf("%v:%v: expected %v, got %v", treeish, path, BlobType, hdr.Type)
+	}
+	_, err = buf.WriteTo(dst)
+	return err
+}
+
+// Close releases resources held by the repository,
+// such as long-lived Git processes.
+//
+// The repository may still be used after Close;
+// resources will be reacquired as needed.
+func (r *Repository) Close() error {
+	return r.objects.Close()
+}
+
+// ReadTree lists the entries in the tree at the given tree-ish
+// like [Repository.ListTree],
+// but reads trees through the long-lived 'git cat-file' process
+// instead of running 'git ls-tree'.
+//
+// The tree-ish may be any name that resolves to a tree,
+// e.g. "main^{tree}" or "refs/heads/main:dir".
+// If it does not exist, the iterator yields [ErrNotExist].
+func (r *Repository) ReadTree(ctx context.Context, treeish string, opts ListTreeOptions) iter.Seq2[TreeEntry, error] {
+	return func(yield func(TreeEntry, error) bool) {
+		_ = r.readTree(ctx, treeish, "", opts.Recurse, yield)
+	}
+}
+
+// readTree yields the entries of the named tree with the given path prefix.
+// It returns false if iteration should stop,
+// either because the consumer stopped or because of an error.
+func (r *Repository) readTree(
+	ctx context.Context,
+	name, prefix string,
+	recurse bool,
+	yield func(TreeEntry, error) bool,
+) bool {
+	var buf bytes.Buffer
+	hdr, err := r.objects.Contents(ctx, name, &buf)
+	if err != nil {
+		yield(TreeEntry{}, err)
+		return false
+	}
+	if hdr.Type != TreeType {
+		yield(TreeEntry{}, fmt.Errorf("%v: expected %v, got %v", name, TreeType, hdr.Type))
+		return false
+	}
+
+	// Tree objects are a sequence of entries in the form:
+	//
+	//	<mode> SP <name> NUL <raw hash>
+	//
+	// The raw hash is half the length of the hex hash.
+	hashLen := len(hdr.Hash) / 2
+	data := buf.Bytes()
+	for len(data) > 0 {
+		modeName, rest, ok := bytes.Cut(data, []byte{0})
+		if !ok || len(rest) < hashLen {
+			yield(TreeEntry{}, fmt.Errorf("%v: malformed tree entry: %q", name, data))
+			return false
+		}
+		rawHash := rest[:hashLen]
+		data = rest[hashLen:]
+
+		modeStr, entName, ok := bytes.Cut(modeName, []byte{' '})
+		if !ok {
+			yield(TreeEntry{}, fmt.Errorf("%v: malformed tree entry: %q", name, modeName))
+			return false
+		}
+		mode, err := ParseMode(string(modeStr))
+		if err != nil {
+			yield(TreeEntry{}, fmt.Errorf("%v: bad mode %q: %w", name, modeStr, err))
+			return false
+		}
+
+		ent := TreeEntry{
+			Mode: mode,
+			Type: modeType(mode),
+			Hash: Hash(hex.EncodeToString(rawHash)),
+			Name: prefix + string(entName),
+		}
+		if recurse && ent.Type == TreeType {
+			if !r.readTree(ctx, ent.Hash.String(), ent.Name+"/", recurse, yield) {
+				return false
+			}
+			continue
+		}
+
+		if !yield(ent, nil) {
+			return false
+		}
+	}
+	return true
+}
+
+// modeType reports the type of object referenced by a tree entry
+// with the given mode.
+func modeType(m Mode) Type {
+	switch m {
+	case DirMode:
+		return TreeType
+	case gitlinkMode:
+		return CommitType
+	default:
+		return BlobType
+	}
+}
+
+// gitlinkMode is the mode of submodule entries in a tree.
+const gitlinkMode Mode = 0o160000
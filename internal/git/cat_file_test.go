@@ -0,0 +1,198 @@
+package git_test
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/git/gittest"
+	"go.abhg.dev/gs/internal/silog/silogtest"
+	"go.abhg.dev/gs/internal/sliceutil"
+	"go.abhg.dev/gs/internal/text"
+)
+
+func newCatFileFixture(t testing.TB) (*git.Repository, string) {
+	fixture, err := gittest.LoadFixtureScript([]byte(text.Dedent(`
+		as 'Test <test@example.com>'
+		at '2026-10-18T12:00:00Z'
+
+		git init
+		git add .
+		git commit -m 'Initial commit'
+		git tag -a v1 -m 'Version 1'
+
+		-- README.md --
+		Hello
+		-- dir/file with spaces.txt --
+		spaces
+		-- dir/sub/deep.txt --
+		deep
+		-- empty.txt --
+	`)))
+	require.NoError(t, err)
+	t.Cleanup(fixture.Cleanup)
+
+	repo, err := git.Open(t.Context(), fixture.Dir(), git.OpenOptions{
+		Log: silogtest.New(t),
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, repo.Close()) })
+	return repo, fixture.Dir()
+}
+
+func TestIntegrationReadObjectAt(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	repo, _ := newCatFileFixture(t)
+
+	for _, tt := range []struct {
+		path string
+		want string
+	}{
+		{"README.md", "Hello\n"},
+		{"dir/file with spaces.txt", "spaces\n"},
+		{"dir/sub/deep.txt", "deep\n"},
+		{"empty.txt", ""},
+	} {
+		var buf bytes.Buffer
+		require.NoError(t, repo.ReadObjectAt(ctx, "main", tt.path, &buf), "path %q", tt.path)
+		assert.Equal(t, tt.want, buf.String(), "path %q", tt.path)
+	}
+
+	t.Run("Missing", func(t *testing.T) {
+		err := repo.ReadObjectAt(ctx, "main", "does/not/exist", new(bytes.Buffer))
+		assert.ErrorIs(t, err, git.ErrNotExist)
+
+		err = repo.ReadObjectAt(ctx, "no-such-branch", "README.md", new(bytes.Buffer))
+		assert.ErrorIs(t, err, git.ErrNotExist)
+	})
+
+	t.Run("NotBlob", func(t *testing.T) {
+		err := repo.ReadObjectAt(ctx, "main", "dir", new(bytes.Buffer))
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "expected blob, got tree")
+	})
+}
+
+func TestIntegrationReadObject_peelsTags(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	repo, dir := newCatFileFixture(t)
+
+	// Like 'git cat-file commit <tag>',
+	// ReadObject peels annotated tags to the requested type.
+	cmd := exec.Command("git", "rev-parse", "refs/tags/v1")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	require.NoError(t, err)
+	tag := git.Hash(strings.TrimSpace(string(out)))
+
+	var buf bytes.Buffer
+	require.NoError(t, repo.ReadObject(ctx, git.CommitType, tag, &buf))
+	assert.Contains(t, buf.String(), "Initial commit")
+
+	err = repo.ReadObject(ctx, git.BlobType, tag, new(bytes.Buffer))
+	assert.Error(t, err)
+}
+
+func TestIntegrationRevParse_batch(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	repo, _ := newCatFileFixture(t)
+
+	_, err := repo.PeelToCommit(ctx, "no-such-branch")
+	assert.ErrorIs(t, err, git.ErrNotExist)
+
+	tree, err := repo.PeelToTree(ctx, "main")
+	require.NoError(t, err)
+
+	readme, err := repo.HashAt(ctx, "main", "README.md")
+	require.NoError(t, err)
+	readmeFromTree, err := repo.HashAt(ctx, tree.String(), "README.md")
+	require.NoError(t, err)
+	assert.Equal(t, readme, readmeFromTree)
+
+	// The reader is restarted after it's closed.
+	require.NoError(t, repo.Close())
+	got, err := repo.PeelToTree(ctx, "main")
+	require.NoError(t, err)
+	assert.Equal(t, tree, got)
+}
+
+func TestIntegrationReadTree(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	repo, _ := newCatFileFixture(t)
+
+	tree, err := repo.PeelToTree(ctx, "main")
+	require.NoError(t, err)
+
+	for _, recurse := range []bool{false, true} {
+		t.Run(fmt.Sprintf("Recurse=%v", recurse), func(t *testing.T) {
+			opts := git.ListTreeOptions{Recurse: recurse}
+
+			want, err := sliceutil.CollectErr(repo.ListTree(ctx, tree, opts))
+			require.NoError(t, err)
+
+			got, err := sliceutil.CollectErr(repo.ReadTree(ctx, "main^{tree}", opts))
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+		})
+	}
+
+	t.Run("Subdirectory", func(t *testing.T) {
+		got, err := sliceutil.CollectErr(repo.ReadTree(ctx, "main:dir", git.ListTreeOptions{}))
+		require.NoError(t, err)
+
+		names := make([]string, len(got))
+		for i, ent := range got {
+			names[i] = ent.Name
+		}
+		assert.Equal(t, []string{"file with spaces.txt", "sub"}, names)
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		_, err := sliceutil.CollectErr(repo.ReadTree(ctx, "main:nope", git.ListTreeOptions{}))
+		assert.ErrorIs(t, err, git.ErrNotExist)
+	})
+}
+
+// BenchmarkReadObjectAt compares reading blobs
+// through the long-lived object reader
+// with running a process for each blob.
+func BenchmarkReadObjectAt(b *testing.B) {
+	repo, dir := newCatFileFixture(b)
+	ctx := b.Context()
+
+	b.Run("Batch", func(b *testing.B) {
+		for b.Loop() {
+			var buf bytes.Buffer
+			if err := repo.ReadObjectAt(ctx, "main", "README.md", &buf); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("Process", func(b *testing.B) {
+		for b.Loop() {
+			cmd := exec.Command("git", "cat-file", "blob", "main:README.md")
+			cmd.Dir = dir
+			out, err := cmd.Output()
+			if err != nil {
+				b.Fatal(err)
+			}
+			if !strings.HasPrefix(string(out), "Hello") {
+				b.Fatalf("unexpected output: %q", out)
+			}
+		}
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
 
 	"go.abhg.dev/gs/internal/xec"
 )
@@ -93,11 +94,25 @@ func (r *Repository) IsAncestor(ctx context.Context, a, b Hash) bool {
 }
 
 func (r *Repository) revParse(ctx context.Context, ref string) (Hash, error) {
-	out, err := r.revParseCmd(ctx, ref).OutputChomp()
+	// Names that refer to the index (":path")
+	// must be resolved by a fresh process
+	// because the long-lived object reader caches the index.
+	if strings.HasPrefix(ref, ":") {
+		out, err := r.revParseCmd(ctx, ref).OutputChomp()
+		if err != nil {
+			return "", ErrNotExist
+		}
+		return Hash(out), nil
+	}
+
+	hdr, err := r.objects.Info(ctx, ref)
 	if err != nil {
-		return "", ErrNotExist
+		if errors.Is(err, ErrNotExist) || errors.Is(err, errObjectAmbiguous) {
+			return "", ErrNotExist
+		}
+		return "", fmt.Errorf("resolve %v: %w", ref, err)
 	}
-	return Hash(out), nil
+	return hdr.Hash, nil
 }
 
 func (r *Repository) revParseCmd(ctx context.Context, ref string) *xec.Cmd {
@@ -0,0 +1,195 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// LineMap maps line numbers in files
+// from one version of the code to another.
+//
+// Build one with [Repository.DiffLineMap] or [Worktree.DiffLineMap].
+type LineMap struct {
+	files map[string]*fileLineMap // old path => changes
+}
+
+type fileLineMap struct {
+	NewPath string // empty if the file was deleted
+	Hunks   []lineMapHunk
+}
+
+// lineMapHunk is a hunk of a diff without context lines.
+//
+// If OldLines is zero, lines were added after OldStart.
+// If NewLines is zero, lines were removed after NewStart.
+type lineMapHunk struct {
+	OldStart, OldLines int
+	NewStart, NewLines int
+}
+
+// MapLine maps a line of a file in the old version of the code
+// to the corresponding line in the new version.
+//
+// newPath is the path of the file in the new version,
+// which differs from path if the file was renamed,
+// and is empty if the file was deleted.
+// exact is false if the line itself was changed or removed,
+// in which case newLine is the closest line in the new version.
+func (m *LineMap) MapLine(path string, line int) (newPath string, newLine int, exact bool) {
+	f, ok := m.files[path]
+	if !ok {
+		return path, line, true
+	}
+	if f.NewPath == "" {
+		return "", 0, false
+	}
+
+	delta := 0
+	for _, h := range f.Hunks {
+		if h.OldLines == 0 {
+			// Lines added after OldStart.
+			if line <= h.OldStart {
+				break
+			}
+			delta += h.NewLines
+			continue
+		}
+
+		if line < h.OldStart {
+			break
+		}
+		if line < h.OldStart+h.OldLines {
+			// The line itself was changed or removed.
+			return f.NewPath, max(h.NewStart, 1), false
+		}
+		delta += h.NewLines - h.OldLines
+	}
+	return f.NewPath, line + delta, true
+}
+
+// DiffLineMap builds a LineMap from the old version of files
+// in the commit-ish from to their new version in to.
+func (r *Repository) DiffLineMap(ctx context.Context, from, to string) (*LineMap, error) {
+	out, err := r.gitCmd(ctx, lineMapDiffArgs(from, to)...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("git diff: %w", err)
+	}
+
+	m, err := parseLineMap(bytes.NewReader(out))
+	if err != nil {
+		return nil, fmt.Errorf("parse diff: %w", err)
+	}
+	return m, nil
+}
+
+func lineMapDiffArgs(from string, to ...string) []string {
+	args := []string{
+		"diff", "--unified=0", "--find-renames",
+		"--no-color", "--no-ext-diff",
+		"--src-prefix=a/", "--dst-prefix=b/",
+		from,
+	}
+	return append(args, to...)
+}
+
+// lineMapHunkRegex matches a hunk header in a diff without context lines
+// and captures the old and new line ranges.
+// Example: "@@ -10,2 +12,0 @@"
+var lineMapHunkRegex = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+func parseLineMap(r io.Reader) (*LineMap, error) {
+	m := &LineMap{files: make(map[string]*fileLineMap)}
+
+	var (
+		oldPath string
+		cur     *fileLineMap
+		inHunks bool // past the file header
+	)
+	scan := bufio.NewScanner(r)
+	scan.Buffer(nil, 1<<20)
+	for scan.Scan() {
+		line := scan.Text()
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			oldPath, cur, inHunks = "", nil, false
+
+		case inHunks && !strings.HasPrefix(line, "@@ "):
+			// Removed or added lines.
+			// These may look like headers, e.g. "--- " for "-- ".
+
+		case strings.HasPrefix(line, "rename from "):
+			// Renames without changes have no "---" and "+++" lines.
+			oldPath = diffHeaderPath(strings.TrimPrefix(line, "rename from "), "")
+
+		case strings.HasPrefix(line, "rename to "):
+			cur = &fileLineMap{
+				NewPath: diffHeaderPath(strings.TrimPrefix(line, "rename to "), ""),
+			}
+			m.files[oldPath] = cur
+
+		case strings.HasPrefix(line, "--- "):
+			oldPath = diffHeaderPath(strings.TrimPrefix(line, "--- "), "a/")
+
+		case strings.HasPrefix(line, "+++ "):
+			if oldPath == "" {
+				continue // new file: there are no old lines to map
+			}
+			cur = &fileLineMap{
+				NewPath: diffHeaderPath(strings.TrimPrefix(line, "+++ "), "b/"),
+			}
+			m.files[oldPath] = cur
+
+		case strings.HasPrefix(line, "@@ "):
+			inHunks = true
+			if cur == nil {
+				continue
+			}
+			match := lineMapHunkRegex.FindStringSubmatch(line)
+			if match == nil {
+				return nil, fmt.Errorf("bad hunk header: %q", line)
+			}
+			cur.Hunks = append(cur.Hunks, lineMapHunk{
+				OldStart: atoiOr(match[1], 0),
+				OldLines: atoiOr(match[2], 1),
+				NewStart: atoiOr(match[3], 0),
+				NewLines: atoiOr(match[4], 1),
+			})
+		}
+	}
+	if err := scan.Err(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// diffHeaderPath returns the path in a "---" or "+++" line of a diff
+// with the given prefix removed.
+// It returns an empty string for /dev/null.
+func diffHeaderPath(s, prefix string) string {
+	// Git adds a trailing tab to paths with spaces.
+	s = strings.TrimSuffix(s, "\t")
+	if s == "/dev/null" {
+		return ""
+	}
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+	return strings.TrimPrefix(s, prefix)
+}
+
+func atoiOr(s string, def int) int {
+	if s == "" {
+		return def
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return def
+	}
+	return n
+}
@@ -0,0 +1,109 @@
+package git_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/git/gittest"
+	"go.abhg.dev/gs/internal/silog/silogtest"
+	"go.abhg.dev/gs/internal/text"
+)
+
+func TestDiffLineMap(t *testing.T) {
+	t.Parallel()
+
+	fixture, err := gittest.LoadFixtureScript([]byte(text.Dedent(`
+		as 'Test <test@example.com>'
+		at '2025-06-21T10:00:00Z'
+
+		git init
+		git add edit.txt old-name.txt gone.txt
+		git commit -m 'Initial commit'
+		git tag before
+
+		cp $WORK/extra/edit.txt edit.txt
+		git mv old-name.txt new-name.txt
+		git rm gone.txt
+		git commit -am 'Change files'
+
+		# Uncommitted change on top.
+		cp $WORK/extra/edit-wt.txt edit.txt
+
+		-- edit.txt --
+		1
+		2
+		3
+		4
+		5
+		6
+		-- old-name.txt --
+		a
+		b
+		c
+		d
+		e
+		f
+		-- gone.txt --
+		gone
+		-- extra/edit.txt --
+		0
+		1
+		2
+		3 changed
+		5
+		6
+		-- extra/edit-wt.txt --
+		0
+		1
+		2
+		3 changed
+		5
+		5.5
+		6
+	`)))
+	require.NoError(t, err)
+	t.Cleanup(fixture.Cleanup)
+
+	wt, err := git.OpenWorktree(t.Context(), fixture.Dir(), git.OpenOptions{
+		Log: silogtest.New(t),
+	})
+	require.NoError(t, err)
+
+	type mapped struct {
+		Path  string
+		Line  int
+		Exact bool
+	}
+	mapLine := func(m *git.LineMap, path string, line int) mapped {
+		newPath, newLine, exact := m.MapLine(path, line)
+		return mapped{newPath, newLine, exact}
+	}
+
+	t.Run("Commits", func(t *testing.T) {
+		m, err := wt.Repository().DiffLineMap(t.Context(), "before", "HEAD")
+		require.NoError(t, err)
+
+		// A line was added at the top.
+		assert.Equal(t, mapped{"edit.txt", 2, true}, mapLine(m, "edit.txt", 1))
+		assert.Equal(t, mapped{"edit.txt", 3, true}, mapLine(m, "edit.txt", 2))
+		// 3 was changed and 4 was removed.
+		assert.Equal(t, mapped{"edit.txt", 4, false}, mapLine(m, "edit.txt", 3))
+		assert.Equal(t, mapped{"edit.txt", 4, false}, mapLine(m, "edit.txt", 4))
+		assert.Equal(t, mapped{"edit.txt", 5, true}, mapLine(m, "edit.txt", 5))
+		assert.Equal(t, mapped{"edit.txt", 6, true}, mapLine(m, "edit.txt", 6))
+
+		assert.Equal(t, mapped{"new-name.txt", 3, true}, mapLine(m, "old-name.txt", 3))
+		assert.Equal(t, mapped{"", 0, false}, mapLine(m, "gone.txt", 1))
+		assert.Equal(t, mapped{"other.txt", 7, true}, mapLine(m, "other.txt", 7))
+	})
+
+	t.Run("Worktree", func(t *testing.T) {
+		m, err := wt.DiffLineMap(t.Context(), "before")
+		require.NoError(t, err)
+
+		assert.Equal(t, mapped{"edit.txt", 5, true}, mapLine(m, "edit.txt", 5))
+		assert.Equal(t, mapped{"edit.txt", 7, true}, mapLine(m, "edit.txt", 6))
+	})
+}
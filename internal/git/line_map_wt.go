@@ -0,0 +1,23 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+)
+
+// DiffLineMap builds a LineMap from the old version of files
+// in the commit-ish from to their current contents in the working tree.
+// Untracked files are ignored.
+func (w *Worktree) DiffLineMap(ctx context.Context, from string) (*LineMap, error) {
+	out, err := w.gitCmd(ctx, lineMapDiffArgs(from)...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("git diff: %w", err)
+	}
+
+	m, err := parseLineMap(bytes.NewReader(out))
+	if err != nil {
+		return nil, fmt.Errorf("parse diff: %w", err)
+	}
+	return m, nil
+}
@@ -26,13 +26,17 @@ func (t Type) String() string {
 // into the given writer.
 //
 // This is not useful for tree objects. Use ListTree instead.
+//
+// This uses a long-lived 'git cat-file' process
+// so it's cheap to call many times.
 func (r *Repository) ReadObject(ctx context.Context, typ Type, hash Hash, dst io.Writer) error {
 	must.NotBeBlankf(string(typ), "object type must not be blank")
 	must.NotBeBlankf(string(hash), "object hash must not be blank")
 
-	cmd := r.gitCmd(ctx, "cat-file", string(typ), hash.String()).WithStdout(dst)
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("cat-file: %w", err)
+	// Like 'git cat-file <type> <hash>',
+	// peel tags to the requested type.
+	if _, err := r.objects.Contents(ctx, hash.String()+"^{"+string(typ)+"}", dst); err != nil {
+		return fmt.Errorf("cat-file: %v %v: %w", typ, hash, err)
 	}
 	return nil
 }
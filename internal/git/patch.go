@@ -0,0 +1,151 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Placeholders that 'git format-patch --cover-letter'
+// leaves in the cover letter for the user to fill in.
+const (
+	_coverSubjectPlaceholder = "*** SUBJECT HERE ***"
+	_coverBlurbPlaceholder   = "*** BLURB HERE ***"
+)
+
+// FormatPatchRequest is a request to export commits as email patches.
+type FormatPatchRequest struct {
+	// Base and Head specify the commits to export:
+	// those reachable from Head but not from Base.
+	Base, Head Hash // required
+
+	// OutputDir is the directory to write patch files to.
+	// It is created if it doesn't exist.
+	//
+	// Exactly one of OutputDir and Stdout must be set.
+	OutputDir string
+
+	// Stdout receives all patches as a single mbox
+	// if OutputDir is not set.
+	Stdout io.Writer
+
+	// SubjectPrefix is the prefix used in patch subjects
+	// instead of "PATCH".
+	SubjectPrefix string
+
+	// Version is the version of the patch series.
+	// Subjects are marked with it, e.g. "[PATCH v2 1/3]",
+	// if it's greater than 1.
+	Version int
+
+	// CoverLetter, if set, adds a cover letter to the series.
+	CoverLetter *CoverLetter
+
+	// RangeDiff, if set, adds a range-diff against a previous version
+	// of the series to the cover letter.
+	// This requires CoverLetter to be set.
+	RangeDiff *CommitSpan
+}
+
+// CoverLetter is the introductory message of a patch series.
+type CoverLetter struct {
+	Subject string // required
+	Body    string
+}
+
+// CommitSpan is a linear range of commits:
+// those reachable from Head but not from Base.
+type CommitSpan struct {
+	Base, Head Hash
+}
+
+func (s CommitSpan) String() string {
+	return s.Base.String() + ".." + s.Head.String()
+}
+
+// FormatPatch exports commits as email patches
+// suitable for 'git send-email'.
+//
+// If the patches were written to a directory,
+// it returns the paths to the files in order,
+// starting with the cover letter if there is one.
+func (r *Repository) FormatPatch(ctx context.Context, req FormatPatchRequest) ([]string, error) {
+	if (req.OutputDir == "") == (req.Stdout == nil) {
+		return nil, errors.New("exactly one of OutputDir or Stdout must be set")
+	}
+	if req.RangeDiff != nil && req.CoverLetter == nil {
+		return nil, errors.New("range-diff requires a cover letter")
+	}
+
+	args := []string{"format-patch", "--numbered", "--base=" + req.Base.String()}
+	if req.SubjectPrefix != "" {
+		args = append(args, "--subject-prefix="+req.SubjectPrefix)
+	}
+	if req.Version > 1 {
+		args = append(args, "--reroll-count="+strconv.Itoa(req.Version))
+	}
+	if req.CoverLetter != nil {
+		args = append(args, "--cover-letter")
+	}
+	if req.RangeDiff != nil {
+		args = append(args, "--range-diff="+req.RangeDiff.String())
+	}
+	if req.OutputDir != "" {
+		args = append(args, "--output-directory="+req.OutputDir)
+	} else {
+		args = append(args, "--stdout")
+	}
+	args = append(args, req.Base.String()+".."+req.Head.String())
+
+	out, err := r.gitCmd(ctx, args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("git format-patch: %w", err)
+	}
+
+	if req.Stdout != nil {
+		if req.CoverLetter != nil {
+			out = fillCoverLetter(out, req.CoverLetter)
+		}
+		if _, err := req.Stdout.Write(out); err != nil {
+			return nil, fmt.Errorf("write patches: %w", err)
+		}
+		return nil, nil
+	}
+
+	// With --output-directory, git prints the path of each file it wrote.
+	var files []string
+	for line := range strings.Lines(string(out)) {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, line)
+		}
+	}
+
+	if req.CoverLetter != nil && len(files) > 0 {
+		coverFile := files[0]
+		bs, err := os.ReadFile(coverFile)
+		if err != nil {
+			return nil, fmt.Errorf("read cover letter: %w", err)
+		}
+		bs = fillCoverLetter(bs, req.CoverLetter)
+		if err := os.WriteFile(coverFile, bs, 0o644); err != nil {
+			return nil, fmt.Errorf("write cover letter: %w", err)
+		}
+	}
+
+	return files, nil
+}
+
+// fillCoverLetter replaces the placeholders in a generated cover letter
+// with the given subject and body.
+//
+// If msg holds multiple messages, only the first one is changed.
+func fillCoverLetter(msg []byte, cover *CoverLetter) []byte {
+	msg = bytes.Replace(msg, []byte(_coverSubjectPlaceholder), []byte(cover.Subject), 1)
+	msg = bytes.Replace(msg, []byte(_coverBlurbPlaceholder), []byte(strings.TrimRight(cover.Body, "\n")), 1)
+	return msg
+}
@@ -0,0 +1,106 @@
+package git_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/git/gittest"
+	"go.abhg.dev/gs/internal/silog/silogtest"
+	"go.abhg.dev/gs/internal/text"
+)
+
+func TestRepository_FormatPatch(t *testing.T) {
+	fixture, err := gittest.LoadFixtureScript([]byte(text.Dedent(`
+		as 'Test User <test@example.com>'
+		at '2025-08-23T06:07:08Z'
+
+		git init
+		git commit --allow-empty -m 'Initial commit'
+		git add feature1.txt
+		git commit -m 'Add feature 1'
+		git add feature2.txt
+		git commit -m 'Add feature 2'
+
+		-- feature1.txt --
+		feature 1
+		-- feature2.txt --
+		feature 2
+	`)))
+	require.NoError(t, err)
+	t.Cleanup(fixture.Cleanup)
+
+	ctx := t.Context()
+	wt, err := git.OpenWorktree(ctx, fixture.Dir(), git.OpenOptions{
+		Log: silogtest.New(t),
+	})
+	require.NoError(t, err)
+	repo := wt.Repository()
+
+	base, err := repo.PeelToCommit(ctx, "HEAD~2")
+	require.NoError(t, err)
+	head, err := repo.PeelToCommit(ctx, "HEAD")
+	require.NoError(t, err)
+
+	cover := &git.CoverLetter{
+		Subject: "My series",
+		Body:    "Adds two features.\n",
+	}
+
+	t.Run("OutputDir", func(t *testing.T) {
+		dir := t.TempDir()
+		files, err := repo.FormatPatch(ctx, git.FormatPatchRequest{
+			Base:        base,
+			Head:        head,
+			OutputDir:   dir,
+			Version:     2,
+			CoverLetter: cover,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			filepath.Join(dir, "v2-0000-cover-letter.patch"),
+			filepath.Join(dir, "v2-0001-Add-feature-1.patch"),
+			filepath.Join(dir, "v2-0002-Add-feature-2.patch"),
+		}, files)
+
+		bs, err := os.ReadFile(files[0])
+		require.NoError(t, err)
+		assert.Contains(t, string(bs), "Subject: [PATCH v2 0/2] My series\n")
+		assert.Contains(t, string(bs), "\nAdds two features.\n")
+		assert.NotContains(t, string(bs), "***")
+	})
+
+	t.Run("Stdout", func(t *testing.T) {
+		var out bytes.Buffer
+		files, err := repo.FormatPatch(ctx, git.FormatPatchRequest{
+			Base:          base,
+			Head:          head,
+			Stdout:        &out,
+			SubjectPrefix: "RFC PATCH",
+			CoverLetter:   cover,
+			RangeDiff:     &git.CommitSpan{Base: base, Head: head},
+		})
+		require.NoError(t, err)
+		assert.Empty(t, files)
+
+		assert.Contains(t, out.String(), "Subject: [RFC PATCH 0/2] My series\n")
+		assert.Contains(t, out.String(), "Subject: [RFC PATCH 2/2] Add feature 2\n")
+		assert.Contains(t, out.String(), "Range-diff:\n")
+		assert.Contains(t, out.String(), "base-commit: "+base.String())
+	})
+
+	t.Run("RangeDiffWithoutCover", func(t *testing.T) {
+		_, err := repo.FormatPatch(ctx, git.FormatPatchRequest{
+			Base:      base,
+			Head:      head,
+			Stdout:    new(bytes.Buffer),
+			RangeDiff: &git.CommitSpan{Base: base, Head: head},
+		})
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "requires a cover letter")
+	})
+}
@@ -0,0 +1,42 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// RangeDiffRequest is a request to compare two versions
+// of a range of commits with 'git range-diff'.
+type RangeDiffRequest struct {
+	// Old and New are the two versions of the range.
+	Old, New CommitSpan // required
+
+	// NoPatch lists how commits in the two versions correspond
+	// without showing the differences between them.
+	NoPatch bool
+
+	// Stdout receives the output.
+	// Output is colored if this is a terminal.
+	Stdout io.Writer // required
+}
+
+// RangeDiff compares two versions of a range of commits,
+// matching commits in the old version with those in the new version.
+func (r *Repository) RangeDiff(ctx context.Context, req RangeDiffRequest) error {
+	if req.Stdout == nil {
+		return errors.New("range-diff: no output specified")
+	}
+
+	args := []string{"range-diff"}
+	if req.NoPatch {
+		args = append(args, "--no-patch")
+	}
+	args = append(args, req.Old.String(), req.New.String())
+
+	if err := r.gitCmd(ctx, args...).WithStdout(req.Stdout).Run(); err != nil {
+		return fmt.Errorf("git range-diff: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package git_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/git/gittest"
+	"go.abhg.dev/gs/internal/silog/silogtest"
+	"go.abhg.dev/gs/internal/text"
+)
+
+func TestRepository_RangeDiff(t *testing.T) {
+	fixture, err := gittest.LoadFixtureScript([]byte(text.Dedent(`
+		as 'Test User <test@example.com>'
+		at '2025-08-23T06:07:08Z'
+
+		git init
+		git commit --allow-empty -m 'Initial commit'
+		git checkout -b v1
+		git add feature1.txt
+		git commit -m 'Add feature 1'
+		git add feature2.txt
+		git commit -m 'Add feature 2'
+
+		git checkout -b v2
+		cp $WORK/extra/feature2.txt feature2.txt
+		git add feature2.txt
+		git commit --amend -m 'Add feature 2'
+
+		-- feature1.txt --
+		feature 1
+		-- feature2.txt --
+		feature 2
+		line 2
+		line 3
+		line 4
+		line 5
+		line 6
+		line 7
+		line 8
+		line 9
+		line 10
+		line 11
+		line 12
+		line 13
+		line 14
+		line 15
+		line 16
+		line 17
+		line 18
+		line 19
+		line 20
+		-- extra/feature2.txt --
+		feature 2, fixed
+		line 2
+		line 3
+		line 4
+		line 5
+		line 6
+		line 7
+		line 8
+		line 9
+		line 10
+		line 11
+		line 12
+		line 13
+		line 14
+		line 15
+		line 16
+		line 17
+		line 18
+		line 19
+		line 20
+	`)))
+	require.NoError(t, err)
+	t.Cleanup(fixture.Cleanup)
+
+	ctx := t.Context()
+	wt, err := git.OpenWorktree(ctx, fixture.Dir(), git.OpenOptions{
+		Log: silogtest.New(t),
+	})
+	require.NoError(t, err)
+	repo := wt.Repository()
+
+	base, err := repo.PeelToCommit(ctx, "main")
+	require.NoError(t, err)
+	v1, err := repo.PeelToCommit(ctx, "v1")
+	require.NoError(t, err)
+	v2, err := repo.PeelToCommit(ctx, "v2")
+	require.NoError(t, err)
+
+	req := git.RangeDiffRequest{
+		Old: git.CommitSpan{Base: base, Head: v1},
+		New: git.CommitSpan{Base: base, Head: v2},
+	}
+
+	t.Run("NoPatch", func(t *testing.T) {
+		var out bytes.Buffer
+		req := req
+		req.NoPatch = true
+		req.Stdout = &out
+		require.NoError(t, repo.RangeDiff(ctx, req))
+
+		lines := bytes.Split(bytes.TrimSpace(out.Bytes()), []byte("\n"))
+		require.Len(t, lines, 2)
+		assert.Regexp(t, `^1: +[0-9a-f]+ = 1: +[0-9a-f]+ Add feature 1$`, string(lines[0]))
+		assert.Regexp(t, `^2: +[0-9a-f]+ ! 2: +[0-9a-f]+ Add feature 2$`, string(lines[1]))
+	})
+
+	t.Run("Patch", func(t *testing.T) {
+		var out bytes.Buffer
+		req := req
+		req.Stdout = &out
+		require.NoError(t, repo.RangeDiff(ctx, req))
+		assert.Contains(t, out.String(), "    ++feature 2, fixed")
+	})
+
+	t.Run("NoStdout", func(t *testing.T) {
+		require.Error(t, repo.RangeDiff(ctx, git.RangeDiffRequest{
+			Old: req.Old,
+			New: req.New,
+		}))
+	})
+}
@@ -156,14 +156,27 @@ type Repository struct {
 
 	log  *silog.Logger
 	exec execer
+
+	// objects is shared by all copies of the repository.
+	objects *objectReader
 }
 
 func newRepository(gitDir string, log *silog.Logger, exec execer) *Repository {
-	return &Repository{
+	r := &Repository{
 		gitDir: gitDir,
 		log:    log,
 		exec:   exec,
 	}
+	r.objects = newObjectReader(log, func(ctx context.Context) *xec.Cmd {
+		return r.gitCmd(ctx, "cat-file", "--batch-command")
+	})
+	return r
+}
+
+// CommonDir returns the absolute path to the repository's Git directory
+// that is shared by all its worktrees.
+func (r *Repository) CommonDir() string {
+	return r.gitDir
 }
 
 // WithLogger returns a copy of the repository
@@ -185,6 +185,11 @@ func (r CommitRange) FirstParent() CommitRange {
 	return append(r, "--first-parent")
 }
 
+// Merges indicates that only merge commits should be listed.
+func (r CommitRange) Merges() CommitRange {
+	return append(r, "--merges")
+}
+
 // Reverse indicates that the commits should be listed in reverse order.
 func (r CommitRange) Reverse() CommitRange {
 	return append(r, "--reverse")
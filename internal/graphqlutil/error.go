@@ -21,8 +21,53 @@ var (
 	ErrNotFound      = errors.New("not found")
 	ErrForbidden     = errors.New("forbidden")
 	ErrUnprocessable = errors.New("unprocessable")
+	ErrRateLimited   = errors.New("rate limited")
 )
 
+// IsRetryable reports whether err is a GraphQL error
+// that may not occur if the request is sent again unchanged.
+// See [Error.Retryable] for details.
+//
+// Errors that aren't GraphQL errors are not retryable.
+func IsRetryable(err error) bool {
+	var gqlErrs Errors
+	if errors.As(err, &gqlErrs) {
+		return gqlErrs.Retryable()
+	}
+
+	var gqlErr *Error
+	return errors.As(err, &gqlErr) && gqlErr.Retryable()
+}
+
+// IsQuery reports whether an HTTP request is a GraphQL query,
+// as opposed to a mutation or subscription.
+// Queries don't have side effects, so they're safe to retry.
+//
+// The request body is read with GetBody,
+// so it's left intact for the request to be sent.
+func IsQuery(r *http.Request) bool {
+	if r.Method != http.MethodPost || r.GetBody == nil {
+		return false
+	}
+
+	body, err := r.GetBody()
+	if err != nil {
+		return false
+	}
+	defer func() { _ = body.Close() }()
+
+	var payload struct {
+		Query string `json:"query"`
+	}
+	if err := json.NewDecoder(io.LimitReader(body, 1<<20)).Decode(&payload); err != nil {
+		return false
+	}
+
+	// Anonymous queries may omit the operation type.
+	query := strings.TrimSpace(payload.Query)
+	return strings.HasPrefix(query, "{") || strings.HasPrefix(query, "query")
+}
+
 // graphQLTransport wraps an HTTP transport
 // with an understanding of GraphQL errors.
 //
@@ -108,6 +153,19 @@ func (t *graphQLTransport) RoundTrip(r *http.Request) (res *http.Response, err e
 // Errors is a list of GraphQL errors.
 type Errors []*Error
 
+// Retryable reports whether all errors in the list are retryable.
+func (e Errors) Retryable() bool {
+	if len(e) == 0 {
+		return false
+	}
+	for _, err := range e {
+		if !err.Retryable() {
+			return false
+		}
+	}
+	return true
+}
+
 func (e Errors) Unwrap() []error {
 	errs := make([]error, len(e))
 	for i, err := range e {
@@ -145,6 +203,29 @@ func (e *Error) Is(target error) bool {
 		return e.Type == "FORBIDDEN"
 	case ErrUnprocessable:
 		return e.Type == "UNPROCESSABLE"
+	case ErrRateLimited:
+		return e.Type == "RATE_LIMITED"
+	default:
+		return false
+	}
+}
+
+// Retryable reports whether the request that failed with this error
+// may succeed if it's sent again unchanged.
+//
+// Rate limit errors and server-side timeouts are retryable.
+// Errors caused by the request itself, such as validation
+// or permission errors, are not.
+func (e *Error) Retryable() bool {
+	switch e.Type {
+	case "RATE_LIMITED":
+		return true
+	case "":
+		// GitHub reports timeouts without a type:
+		//
+		//   Something went wrong while executing your query.
+		//   This may be the result of a timeout, or it could be a GitHub bug.
+		return strings.Contains(e.Message, "timeout")
 	default:
 		return false
 	}
@@ -1,9 +1,11 @@
 package graphqlutil_test
 
 import (
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"sync"
 	"testing"
 	"testing/iotest"
@@ -164,6 +166,24 @@ func TestGraphQLResponse(t *testing.T) {
 				new(*graphqlutil.Error),
 			},
 		},
+		{
+			name: "rate limited",
+			body: `{
+				"errors": [
+					{
+						"type": "RATE_LIMITED",
+						"message": "API rate limit exceeded for user ID 1."
+					}
+				]
+			}`,
+			wantErrorIs: []error{
+				graphqlutil.ErrRateLimited,
+			},
+			wantErrorAs: []any{
+				new(graphqlutil.Errors),
+				new(*graphqlutil.Error),
+			},
+		},
 		{
 			name: "unrecognized error",
 			body: `{
@@ -285,6 +305,99 @@ func TestErrorString(t *testing.T) {
 	}
 }
 
+func TestIsRetryable(t *testing.T) {
+	rateLimited := &graphqlutil.Error{
+		Type:    "RATE_LIMITED",
+		Message: "API rate limit exceeded for user ID 1.",
+	}
+	timeout := &graphqlutil.Error{
+		Message: "Something went wrong while executing your query. " +
+			"This may be the result of a timeout, or it could be a GitHub bug.",
+	}
+	notFound := &graphqlutil.Error{
+		Type:    "NOT_FOUND",
+		Message: "Could not resolve to a Repository with the name 'foo/bar'.",
+	}
+
+	tests := []struct {
+		name string
+		give error
+		want bool
+	}{
+		{name: "RateLimited", give: rateLimited, want: true},
+		{name: "Timeout", give: timeout, want: true},
+		{name: "NotFound", give: notFound},
+		{name: "UntypedMessage", give: &graphqlutil.Error{Message: "lol"}},
+		{name: "List", give: graphqlutil.Errors{rateLimited, timeout}, want: true},
+		{name: "ListMixed", give: graphqlutil.Errors{rateLimited, notFound}},
+		{name: "EmptyList", give: graphqlutil.Errors{}},
+		{name: "Wrapped", give: fmt.Errorf("query: %w", graphqlutil.Errors{rateLimited}), want: true},
+		{name: "NotGraphQL", give: assert.AnError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, graphqlutil.IsRetryable(tt.give))
+		})
+	}
+}
+
+func TestIsQuery(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		body   string
+		want   bool
+	}{
+		{
+			name:   "Query",
+			method: http.MethodPost,
+			body:   `{"query":"query($owner:String!){repository(owner:$owner){id}}"}`,
+			want:   true,
+		},
+		{
+			name:   "Anonymous",
+			method: http.MethodPost,
+			body:   `{"query":"{viewer{login}}"}`,
+			want:   true,
+		},
+		{
+			name:   "Mutation",
+			method: http.MethodPost,
+			body:   `{"query":"mutation($input:CreatePullRequestInput!){createPullRequest(input:$input){pullRequest{id}}}"}`,
+		},
+		{
+			name:   "NotJSON",
+			method: http.MethodPost,
+			body:   `query { viewer { login } }`,
+		},
+		{
+			name:   "Get",
+			method: http.MethodGet,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body io.Reader
+			if tt.body != "" {
+				body = strings.NewReader(tt.body)
+			}
+			req, err := http.NewRequest(tt.method, "https://api.github.com/graphql", body)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, graphqlutil.IsQuery(req))
+
+			// The body must be left intact.
+			if body != nil {
+				bs, err := io.ReadAll(req.Body)
+				require.NoError(t, err)
+				assert.Equal(t, tt.body, string(bs))
+			}
+		})
+	}
+}
+
 // This test triggers the data race where buffers are returned to the pool
 // while response bodies are still being read from them.
 func TestRoundTrip_concurrentReads(t *testing.T) {
@@ -12,6 +12,7 @@ import (
 	"fmt"
 
 	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/hook"
 	"go.abhg.dev/gs/internal/silog"
 	"go.abhg.dev/gs/internal/spice"
 )
@@ -189,6 +190,15 @@ func (h *Handler) BeginAutostash(
 			return
 		}
 
+		// A hook aborted the operation before it started,
+		// so nothing else will restore the stash.
+		if hook.IsAbort(*errPtr) {
+			if err := h.RestoreAutostash(ctx, stashHash.String()); err != nil {
+				*errPtr = errors.Join(*errPtr, err)
+			}
+			return
+		}
+
 		// Failure: schedule stash restoration via RebaseRescue.
 		*errPtr = h.Service.RebaseRescue(ctx, spice.RebaseRescueRequest{
 			Err:     *errPtr,
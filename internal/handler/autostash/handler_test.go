@@ -12,6 +12,7 @@ import (
 	"go.uber.org/mock/gomock"
 
 	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/hook"
 	"go.abhg.dev/gs/internal/silog"
 	"go.abhg.dev/gs/internal/silog/silogtest"
 	"go.abhg.dev/gs/internal/spice"
@@ -100,6 +101,43 @@ func TestHandler_AutoStash(t *testing.T) {
 		assert.Contains(t, logBuf.String(), "Failed to apply autostash")
 		assert.Contains(t, logBuf.String(), "apply them with 'git stash pop'")
 	})
+
+	t.Run("HookAbort", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		mockWorktree := NewMockGitWorktree(mockCtrl)
+
+		stashHash := git.Hash("stashhash")
+		mockWorktree.EXPECT().
+			StashCreate(gomock.Any(), "autostash message").
+			Return(stashHash, nil)
+
+		// No RebaseRescue expected:
+		// the stash is restored right away.
+		cleanup, err := (&Handler{
+			Log:      silogtest.New(t),
+			Worktree: mockWorktree,
+			Service:  NewMockService(mockCtrl),
+		}).BeginAutostash(t.Context(), &Options{
+			Message:   "autostash message",
+			Branch:    "feature",
+			ResetMode: ResetNone,
+		})
+		require.NoError(t, err)
+
+		mockWorktree.EXPECT().
+			StashApply(gomock.Any(), stashHash.String()).
+			Return(nil)
+
+		abortErr := &hook.AbortError{
+			Event: hook.PreRestack,
+			Hook:  "check",
+			Err:   errors.New("exit status 1"),
+		}
+		err = fmt.Errorf("restack: %w", abortErr)
+		cleanup(&err)
+
+		assert.ErrorIs(t, err, abortErr)
+	})
 }
 
 func TestHandler_AutoStash_reset(t *testing.T) {
@@ -10,8 +10,10 @@ import (
 	"slices"
 	"strings"
 
+	"go.abhg.dev/gs/internal/forge"
 	"go.abhg.dev/gs/internal/git"
 	"go.abhg.dev/gs/internal/graph"
+	"go.abhg.dev/gs/internal/hook"
 	"go.abhg.dev/gs/internal/must"
 	"go.abhg.dev/gs/internal/silog"
 	"go.abhg.dev/gs/internal/spice"
@@ -65,6 +67,10 @@ type Handler struct {
 	Worktree   GitWorktree   // required
 	Store      Store         // required
 	Service    Service       // required
+
+	// Hooks runs hooks after branches are deleted.
+	// If nil, no hooks run.
+	Hooks *hook.Runner
 }
 
 // Request is a request to delete one or more branches.
@@ -88,6 +94,8 @@ func (h *Handler) DeleteBranches(ctx context.Context, req *Request) error {
 
 		Head   git.Hash // head hash (set only if exists)
 		Exists bool
+
+		Change forge.ChangeMetadata // nil if not submitted
 	}
 
 	repo := h.Repository
@@ -111,7 +119,10 @@ func (h *Handler) DeleteBranches(ctx context.Context, req *Request) error {
 		base := h.Store.Trunk()
 		tracked, exists := true, true
 
-		var head git.Hash
+		var (
+			head   git.Hash
+			change forge.ChangeMetadata
+		)
 		if b, err := h.Service.LookupBranch(ctx, branch); err != nil {
 			if delErr := new(spice.DeletedBranchError); errors.As(err, &delErr) {
 				exists = false
@@ -127,6 +138,7 @@ func (h *Handler) DeleteBranches(ctx context.Context, req *Request) error {
 		} else {
 			head = b.Head
 			base = b.Base
+			change = b.Change
 			must.NotBeBlankf(base, "base branch for %v must be set", branch)
 			must.NotBeBlankf(head.String(), "head commit for %v must be set", branch)
 		}
@@ -146,6 +158,7 @@ func (h *Handler) DeleteBranches(ctx context.Context, req *Request) error {
 			Base:    base,
 			Tracked: tracked,
 			Exists:  exists,
+			Change:  change,
 		}
 	}
 
@@ -317,7 +330,10 @@ func (h *Handler) DeleteBranches(ctx context.Context, req *Request) error {
 	}
 
 	branchTx := h.Store.BeginBranchTx()
-	var untrackedNames []string
+	var (
+		untrackedNames []string
+		deleted        []hook.Branch // for hooks
+	)
 	for _, b := range deleteOrder {
 		branch, head := b.Name, b.Head
 		exists, tracked, force := b.Exists, b.Tracked, req.Force
@@ -361,6 +377,16 @@ func (h *Handler) DeleteBranches(ctx context.Context, req *Request) error {
 			log.Infof("%v: deleted (was %v)", branch, head.Short())
 		}
 
+		deletedBranch := hook.Branch{
+			Name:   branch,
+			Head:   head.String(),
+			Change: hook.NewChange(b.Change),
+		}
+		if tracked {
+			deletedBranch.Base = b.Base
+		}
+		deleted = append(deleted, deletedBranch)
+
 		if tracked {
 			if err := branchTx.Delete(ctx, branch); err != nil {
 				log.Warn("Unable to untrack branch", "branch", branch, "error", err)
@@ -376,5 +402,8 @@ func (h *Handler) DeleteBranches(ctx context.Context, req *Request) error {
 		return fmt.Errorf("update state: %w", err)
 	}
 
-	return nil
+	if len(deleted) == 0 {
+		return nil
+	}
+	return h.Hooks.RunBranches(ctx, hook.BranchDeleted, deleted)
 }
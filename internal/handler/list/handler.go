@@ -7,12 +7,16 @@ import (
 	"errors"
 	"fmt"
 	"iter"
+	"maps"
+	"net/url"
 	"runtime"
 	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/forge/forgecache"
 	"go.abhg.dev/gs/internal/git"
 	"go.abhg.dev/gs/internal/silog"
 	"go.abhg.dev/gs/internal/sliceutil"
@@ -61,11 +65,20 @@ type Handler struct {
 		f forge.Forge,
 		repo forge.RepositoryID,
 	) (forge.Repository, error) // required
+
+	// Cache holds change information retrieved from forges
+	// by earlier invocations.
+	// If unset, change information is always retrieved from the forge.
+	Cache *forgecache.Cache
 }
 
 // Options holds command line options for the log command.
 type Options struct {
 	All bool `short:"a" long:"all" config:"log.all" help:"Show all tracked branches, not just the current stack."`
+
+	Issue string `placeholder:"ISSUE" released:"unreleased" help:"Show only branches linked to this issue and the branches below them."`
+
+	ChangeCacheTTL time.Duration `name:"cr-status-cache-ttl" config:"log.crStatusCacheTTL" hidden:"" default:"30s" help:"How long to reuse change request information retrieved from the forge. Set to 0 to always query the forge."`
 }
 
 // Include specifies what additional information to include in the response.
@@ -130,6 +143,10 @@ type BranchItem struct {
 
 	Commits []git.CommitDetail // only if IncludeCommits is set
 
+	// Issue is the issue tracker reference associated with the branch.
+	// Empty if the branch is not linked to an issue.
+	Issue string
+
 	// ChangeID is the ID of the associated change, if any.
 	ChangeID             forge.ChangeID
 	ChangeURL            string                     // only if IncludeChangeURL is set
@@ -275,6 +292,7 @@ func (h *Handler) ListBranches(ctx context.Context, req *BranchesRequest) (*Bran
 				}
 
 				item.Base = branch.Base
+				item.Issue = branch.Issue
 
 				if branch.Change != nil {
 					item.ChangeID = branch.Change.ChangeID()
@@ -316,7 +334,9 @@ func (h *Handler) ListBranches(ctx context.Context, req *BranchesRequest) (*Bran
 	}
 
 	var branchesToLog iter.Seq[string]
-	if req.Options.All {
+	if issue := req.Options.Issue; issue != "" {
+		branchesToLog = issueBranches(branchGraph, issue)
+	} else if req.Options.All {
 		branchesToLog = branchGraph.Names()
 	} else {
 		// If req.Branch is not tracked,
@@ -364,12 +384,12 @@ func (h *Handler) ListBranches(ctx context.Context, req *BranchesRequest) (*Bran
 			// Load full details (state, draft, review decision).
 			// Try to load change details, but don't fail the whole operation
 			// if something goes wrong.
-			if err := h.loadChangeDetails(ctx, remoteForge, remoteRepoID, items); err != nil {
+			if err := h.loadChangeDetails(ctx, remoteForge, remoteRepoID, items, req.Options.ChangeCacheTTL); err != nil {
 				log.Warn("Could not load change details", "error", err)
 			}
 		} else if req.Include&IncludeChangeState != 0 {
 			// Load only change states.
-			if err := h.loadChangeStates(ctx, remoteForge, remoteRepoID, items); err != nil {
+			if err := h.loadChangeStates(ctx, remoteForge, remoteRepoID, items, req.Options.ChangeCacheTTL); err != nil {
 				log.Warn("Could not load change states", "error", err)
 			}
 		}
@@ -381,21 +401,91 @@ func (h *Handler) ListBranches(ctx context.Context, req *BranchesRequest) (*Bran
 	}, nil
 }
 
+// issueBranches returns all branches linked to the given issue,
+// along with their downstack branches so that they stay connected to trunk.
+func issueBranches(graph *spice.BranchGraph, issue string) iter.Seq[string] {
+	names := make(map[string]struct{})
+	for branch := range graph.All() {
+		if !strings.EqualFold(branch.Issue, issue) {
+			continue
+		}
+
+		for name := range graph.Downstack(branch.Name) {
+			names[name] = struct{}{}
+		}
+	}
+	return maps.Keys(names)
+}
+
+// cachedChange is the information about a change
+// that's cached between invocations.
+type cachedChange struct {
+	State          forge.ChangeState          `json:"state"`
+	Draft          bool                       `json:"draft,omitempty"`
+	ReviewDecision forge.ChangeReviewDecision `json:"reviewDecision,omitempty"`
+
+	// HasDetails is set if Draft and ReviewDecision are known.
+	// Otherwise, only State was retrieved.
+	HasDetails bool `json:"hasDetails,omitempty"`
+}
+
+// changeCache returns the cache for changes in the given repository.
+// It returns nil if caching is disabled.
+func (h *Handler) changeCache(
+	remoteForge forge.Forge,
+	remoteRepoID forge.RepositoryID,
+	ttl time.Duration,
+) *forgecache.Cache {
+	if ttl <= 0 {
+		return nil
+	}
+	return h.Cache.Scope(remoteForge.ID(), remoteRepoID.String(), "changes")
+}
+
+// loadCachedChanges fills in branches with cached change information.
+// It returns the indexes of branches (with changes)
+// that were not found in the cache.
+func loadCachedChanges(
+	ctx context.Context,
+	cache *forgecache.Cache,
+	branches []*BranchItem,
+	needDetails bool,
+) (misses []int) {
+	for i, b := range branches {
+		if b.ChangeID == nil {
+			continue
+		}
+
+		var c cachedChange
+		if !cache.Get(ctx, url.PathEscape(b.ChangeID.String()), &c) ||
+			(needDetails && !c.HasDetails) {
+			misses = append(misses, i)
+			continue
+		}
+
+		b.ChangeState = c.State
+		if c.HasDetails {
+			b.ChangeDraft = c.Draft
+			b.ChangeReviewDecision = c.ReviewDecision
+		}
+	}
+	return misses
+}
+
 func (h *Handler) loadChangeStates(
 	ctx context.Context,
 	remoteForge forge.Forge,
 	remoteRepoID forge.RepositoryID,
 	branches []*BranchItem,
+	ttl time.Duration,
 ) error {
-	// Collect IDs in the same order as items for stable mapping.
-	branchesIdx := make([]int, 0, len(branches)) // index in items
-	changeIDs := make([]forge.ChangeID, 0, len(branches))
-	// For each changeIDs[i], branchesIdx[i] is the index in branches.
-	for i, b := range branches {
-		if b.ChangeID != nil {
-			branchesIdx = append(branchesIdx, i)
-			changeIDs = append(changeIDs, b.ChangeID)
-		}
+	cache := h.changeCache(remoteForge, remoteRepoID, ttl)
+	misses := loadCachedChanges(ctx, cache, branches, false /* needDetails */)
+
+	// For each changeIDs[i], misses[i] is the index in branches.
+	changeIDs := make([]forge.ChangeID, len(misses))
+	for j, idx := range misses {
+		changeIDs[j] = branches[idx].ChangeID
 	}
 
 	if len(changeIDs) == 0 {
@@ -412,8 +502,15 @@ func (h *Handler) loadChangeStates(
 		return fmt.Errorf("retrieve change states: %w", err)
 	}
 
-	for j, idx := range branchesIdx {
+	toCache := make(map[string]any, len(changeIDs))
+	for j, idx := range misses {
 		branches[idx].ChangeState = statuses[j].State
+		toCache[url.PathEscape(changeIDs[j].String())] = cachedChange{
+			State: statuses[j].State,
+		}
+	}
+	if err := cache.Set(ctx, ttl, toCache); err != nil {
+		h.Log.Debug("Could not cache change states", "error", err)
 	}
 
 	return nil
@@ -424,16 +521,15 @@ func (h *Handler) loadChangeDetails(
 	remoteForge forge.Forge,
 	remoteRepoID forge.RepositoryID,
 	branches []*BranchItem,
+	ttl time.Duration,
 ) error {
-	// Collect IDs in the same order as items for stable mapping.
-	branchesIdx := make([]int, 0, len(branches)) // index in items
-	changeIDs := make([]forge.ChangeID, 0, len(branches))
-	// For each changeIDs[i], branchesIdx[i] is the index in branches.
-	for i, b := range branches {
-		if b.ChangeID != nil {
-			branchesIdx = append(branchesIdx, i)
-			changeIDs = append(changeIDs, b.ChangeID)
-		}
+	cache := h.changeCache(remoteForge, remoteRepoID, ttl)
+	misses := loadCachedChanges(ctx, cache, branches, true /* needDetails */)
+
+	// For each changeIDs[i], misses[i] is the index in branches.
+	changeIDs := make([]forge.ChangeID, len(misses))
+	for j, idx := range misses {
+		changeIDs[j] = branches[idx].ChangeID
 	}
 
 	if len(changeIDs) == 0 {
@@ -453,10 +549,20 @@ func (h *Handler) loadChangeDetails(
 		return fmt.Errorf("forge returned %d details for %d changes", len(details), len(changeIDs))
 	}
 
-	for j, idx := range branchesIdx {
+	toCache := make(map[string]any, len(changeIDs))
+	for j, idx := range misses {
 		branches[idx].ChangeState = details[j].State
 		branches[idx].ChangeDraft = details[j].Draft
 		branches[idx].ChangeReviewDecision = details[j].ReviewDecision
+		toCache[url.PathEscape(changeIDs[j].String())] = cachedChange{
+			State:          details[j].State,
+			Draft:          details[j].Draft,
+			ReviewDecision: details[j].ReviewDecision,
+			HasDetails:     true,
+		}
+	}
+	if err := cache.Set(ctx, ttl, toCache); err != nil {
+		h.Log.Debug("Could not cache change details", "error", err)
 	}
 
 	return nil
@@ -9,11 +9,13 @@ import (
 	"slices"
 
 	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/hook"
 	"go.abhg.dev/gs/internal/iterutil"
 	"go.abhg.dev/gs/internal/must"
 	"go.abhg.dev/gs/internal/silog"
 	"go.abhg.dev/gs/internal/spice"
 	"go.abhg.dev/gs/internal/spice/state"
+	"go.abhg.dev/gs/internal/tracing"
 )
 
 //go:generate mockgen -package restack -destination mocks_test.go . GitWorktree,Service
@@ -45,6 +47,10 @@ type Handler struct {
 	Worktree GitWorktree   // required
 	Store    Store         // required
 	Service  Service       // required
+
+	// Hooks runs hooks before and after restacking.
+	// If nil, no hooks run.
+	Hooks *hook.Runner
 }
 
 // Scope specifies which branches are affected
@@ -96,6 +102,9 @@ type Request struct {
 
 // Restack restacks one or more branches according to the request.
 func (h *Handler) Restack(ctx context.Context, req *Request) (int, error) {
+	ctx, span := tracing.Start(ctx, tracing.CategoryHandler, "restack", "branch", req.Branch)
+	defer span.End()
+
 	must.NotBeBlankf(req.Branch, "branch must not be blank")
 	must.NotBeEmptyf(req.ContinueCommand, "continue command must not be set")
 
@@ -174,6 +183,13 @@ func (h *Handler) Restack(ctx context.Context, req *Request) (int, error) {
 				skipped[branch] = struct{}{}
 				continue
 			}
+
+			if info.ReadOnly {
+				// Branches above it can still be restacked
+				// onto its current head.
+				h.Log.Warnf("%v: branch is read-only, skipping", branch)
+				continue
+			}
 		}
 
 		branchWT := branchGraph.Worktree(branch)
@@ -191,7 +207,13 @@ func (h *Handler) Restack(ctx context.Context, req *Request) (int, error) {
 	}
 	branchesToRestack = branchesToActuallyRestack
 
-	var restackCount int
+	if len(branchesToRestack) > 0 {
+		if err := h.Hooks.Run(ctx, hook.PreRestack, branchesToRestack); err != nil {
+			return 0, err
+		}
+	}
+
+	var restacked []string
 loop:
 	for _, branch := range branchesToRestack {
 		res, err := h.Service.Restack(ctx, branch)
@@ -235,8 +257,9 @@ loop:
 		}
 
 		h.Log.Infof("%v: restacked on %v", branch, res.Base)
-		restackCount++
+		restacked = append(restacked, branch)
 	}
+	restackCount := len(restacked)
 
 	if requestBranchWT != "" && requestBranchWT != currentWT {
 		h.Log.Warnf("%v: checked out in another worktree (%v), not checking out here", req.Branch, requestBranchWT)
@@ -246,5 +269,11 @@ loop:
 		}
 	}
 
+	if restackCount > 0 {
+		if err := h.Hooks.Run(ctx, hook.PostRestack, restacked); err != nil {
+			return 0, err
+		}
+	}
+
 	return restackCount, nil
 }
@@ -697,6 +697,52 @@ func TestHandler_Restack_skipCheckedOut(t *testing.T) {
 	})
 }
 
+func TestHandler_Restack_skipReadOnly(t *testing.T) {
+	// Read-only branches are not restacked,
+	// but branches above them are restacked onto them.
+	var logBuffer bytes.Buffer
+	log := silog.New(&logBuffer, nil)
+	ctrl := gomock.NewController(t)
+
+	mockService := NewMockService(ctrl)
+	mockService.EXPECT().
+		BranchGraph(gomock.Any(), gomock.Any()).
+		Return(newBranchGraphBuilder("main").
+			Branch("theirs", "main").
+			Branch("mine", "theirs").
+			ReadOnly("theirs").
+			Build(t), nil)
+	mockService.EXPECT().
+		Restack(gomock.Any(), "mine").
+		Return(&spice.RestackResponse{Base: "theirs"}, nil)
+
+	mockWorktree := NewMockGitWorktree(ctrl)
+	mockWorktree.EXPECT().
+		RootDir().
+		Return(t.TempDir())
+	mockWorktree.EXPECT().
+		CheckoutBranch(gomock.Any(), "theirs").
+		Return(nil)
+
+	handler := &Handler{
+		Log:      log,
+		Worktree: mockWorktree,
+		Store:    statetest.NewMemoryStore(t, "main", "", log),
+		Service:  mockService,
+	}
+
+	count, err := handler.Restack(t.Context(), &Request{
+		Branch:          "theirs",
+		ContinueCommand: []string{"false"},
+		Scope:           ScopeUpstack,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	assert.Contains(t, logBuffer.String(), "theirs: branch is read-only, skipping")
+	assert.Contains(t, logBuffer.String(), "mine: restacked on theirs")
+}
+
 func TestHandler_Restack_errors(t *testing.T) {
 	t.Run("BranchGraph", func(t *testing.T) {
 		log := silog.Nop()
@@ -867,6 +913,15 @@ func (b *branchGraphBuilder) Branch(name, base string) *branchGraphBuilder {
 	return b
 }
 
+func (b *branchGraphBuilder) ReadOnly(branch string) *branchGraphBuilder {
+	for i := range b.items {
+		if b.items[i].Name == branch {
+			b.items[i].ReadOnly = true
+		}
+	}
+	return b
+}
+
 func (b *branchGraphBuilder) Worktree(branch, wt string) *branchGraphBuilder {
 	b.worktrees[branch] = wt
 	return b
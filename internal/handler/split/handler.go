@@ -110,6 +110,12 @@ type BranchRequest struct {
 	// SelectCommits is a function that allows the user to select commits
 	// for splitting the branch if the --at flag is not provided.
 	SelectCommits func(context.Context, []git.CommitDetail) ([]Point, error)
+
+	// ValidateName reports whether the name of a new branch is acceptable.
+	// It is not called for the original branch name.
+	//
+	// If unset, all names are accepted.
+	ValidateName func(string) error // optional
 }
 
 // BranchResult is the result of splitting a branch.
@@ -175,6 +181,12 @@ func (h *Handler) SplitBranch(ctx context.Context, req *BranchRequest) (*BranchR
 			return nil, fmt.Errorf("--at[%d]: branch already exists: %v", i, split.Name)
 		}
 
+		if split.Name != branch && req.ValidateName != nil {
+			if err := req.ValidateName(split.Name); err != nil {
+				return nil, fmt.Errorf("--at[%d]: %w", i, err)
+			}
+		}
+
 		commitHash, err := h.Repository.PeelToCommit(ctx, split.Commit)
 		if err != nil {
 			return nil, fmt.Errorf("--at[%d]: resolve commit %q: %w", i, split.Commit, err)
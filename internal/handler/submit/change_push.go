@@ -0,0 +1,155 @@
+package submit
+
+import (
+	"context"
+	"fmt"
+
+	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/spice/state"
+)
+
+// prepareChangeCommits rewrites the messages of commits in a branch
+// as requested by a forge that creates changes from pushed commits,
+// e.g. to add Gerrit's Change-Id trailers.
+//
+// Rewriting a commit changes the hashes of all commits after it,
+// so commits in branches upstack from the branch are rewritten too.
+// Only commit messages change, so the branches don't need a restack.
+//
+// It reports whether any commits were rewritten.
+func (h *Handler) prepareChangeCommits(
+	ctx context.Context,
+	branchName string,
+	pusher forge.ChangePusher,
+) (bool, error) {
+	upstack, err := h.Service.ListUpstack(ctx, branchName)
+	if err != nil {
+		return false, fmt.Errorf("list upstack: %w", err)
+	}
+
+	type refUpdate struct {
+		Branch   string
+		Old, New git.Hash
+	}
+
+	var (
+		refUpdates []refUpdate
+		upserts    []state.UpsertRequest
+		rewritten  = make(map[git.Hash]git.Hash) // old -> new
+	)
+	for _, name := range upstack {
+		branch, err := h.Service.LookupBranch(ctx, name)
+		if err != nil {
+			return false, fmt.Errorf("lookup branch %v: %w", name, err)
+		}
+
+		commits := git.CommitRangeFrom(branch.Head).
+			ExcludeFrom(branch.BaseHash).
+			Reverse()
+		for hash, err := range h.Repository.ListCommits(ctx, commits) {
+			if err != nil {
+				return false, fmt.Errorf("list commits of %v: %w", name, err)
+			}
+
+			newHash, err := h.prepareChangeCommit(ctx, hash, pusher, rewritten)
+			if err != nil {
+				return false, fmt.Errorf("%v: %w", name, err)
+			}
+			if newHash != hash {
+				rewritten[hash] = newHash
+			}
+		}
+
+		if newHead, ok := rewritten[branch.Head]; ok {
+			refUpdates = append(refUpdates, refUpdate{
+				Branch: name,
+				Old:    branch.Head,
+				New:    newHead,
+			})
+		}
+		if newBase, ok := rewritten[branch.BaseHash]; ok {
+			upserts = append(upserts, state.UpsertRequest{
+				Name:     name,
+				BaseHash: newBase,
+			})
+		}
+	}
+
+	if len(refUpdates) == 0 {
+		return false, nil
+	}
+
+	for _, update := range refUpdates {
+		if err := h.Repository.SetRef(ctx, git.SetRefRequest{
+			Ref:     "refs/heads/" + update.Branch,
+			Hash:    update.New,
+			OldHash: update.Old,
+			Reason:  "git-spice: prepare commits for submit",
+		}); err != nil {
+			return false, fmt.Errorf("update branch %v: %w", update.Branch, err)
+		}
+		h.Log.Debug("Rewrote commit messages",
+			"branch", update.Branch,
+			"old", update.Old.Short(),
+			"new", update.New.Short())
+	}
+
+	tx := h.Store.BeginBranchTx()
+	for _, req := range upserts {
+		if err := tx.Upsert(ctx, req); err != nil {
+			return false, fmt.Errorf("update base hash of %v: %w", req.Name, err)
+		}
+	}
+	if err := tx.Commit(ctx, "submit: prepare commits of "+branchName); err != nil {
+		return false, fmt.Errorf("update state: %w", err)
+	}
+
+	return true, nil
+}
+
+// prepareChangeCommit rewrites a single commit if its message
+// needs to be changed, or if any of its parents were rewritten.
+// It returns the hash of the original commit if nothing changed.
+func (h *Handler) prepareChangeCommit(
+	ctx context.Context,
+	hash git.Hash,
+	pusher forge.ChangePusher,
+	rewritten map[git.Hash]git.Hash,
+) (git.Hash, error) {
+	commit, err := h.Repository.ReadCommit(ctx, hash.String())
+	if err != nil {
+		return "", fmt.Errorf("read commit %v: %w", hash.Short(), err)
+	}
+
+	msg, changed := pusher.PrepareCommitMessage(commit.Message())
+	parents := make([]git.Hash, len(commit.Parents))
+	for i, parent := range commit.Parents {
+		if newParent, ok := rewritten[parent]; ok {
+			parent = newParent
+			changed = true
+		}
+		parents[i] = parent
+	}
+	if !changed {
+		return hash, nil
+	}
+
+	// The committer is the current user, as with 'git commit --amend'.
+	newHash, err := h.Repository.CommitTree(ctx, git.CommitTreeRequest{
+		Tree:    commit.Tree,
+		Message: msg,
+		Parents: parents,
+		Author:  &commit.Author,
+	})
+	if err != nil {
+		return "", fmt.Errorf("rewrite commit %v: %w", hash.Short(), err)
+	}
+	return newHash, nil
+}
+
+// changePushRefspec returns the refspec to push the given commit
+// for review as changes against base.
+func changePushRefspec(pusher forge.ChangePusher, commit git.Hash, base, topic string) git.Refspec {
+	return git.Refspec(commit.String() + ":" + pusher.PushRef(base, topic))
+}
@@ -0,0 +1,161 @@
+package submit
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/git/gittest"
+	"go.abhg.dev/gs/internal/silog/silogtest"
+	"go.abhg.dev/gs/internal/spice"
+	"go.abhg.dev/gs/internal/spice/state"
+	"go.abhg.dev/gs/internal/spice/state/statetest"
+	"go.abhg.dev/gs/internal/spice/state/storage"
+	"go.abhg.dev/gs/internal/text"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// fakeChangePusher adds a "Change-Id: <subject>" trailer
+// to messages that don't have one.
+type fakeChangePusher struct{}
+
+func (fakeChangePusher) PushRef(base, topic string) string {
+	return "refs/for/" + base + "%topic=" + topic
+}
+
+func (fakeChangePusher) PrepareCommitMessage(msg string) (string, bool) {
+	if strings.Contains(msg, "Change-Id:") {
+		return msg, false
+	}
+	subject, _, _ := strings.Cut(msg, "\n")
+	return strings.TrimRight(msg, "\n") + "\n\nChange-Id: " + subject + "\n", true
+}
+
+func TestPrepareChangeCommits(t *testing.T) {
+	t.Setenv("GIT_COMMITTER_NAME", "Test Committer")
+	t.Setenv("GIT_COMMITTER_EMAIL", "committer@example.com")
+
+	fixture, err := gittest.LoadFixtureScript([]byte(text.Dedent(`
+		# main -> feature1 -> feature2
+		as 'Test Author <test@example.com>'
+		at '2025-06-20T21:28:29Z'
+
+		git init
+		git commit --allow-empty -m 'Initial commit'
+
+		git checkout -b feature1
+		git commit --allow-empty -m 'feature1 a'
+		git commit --allow-empty -m 'feature1 b' -m 'Change-Id: existing'
+
+		git checkout -b feature2
+		git commit --allow-empty -m 'feature2'
+	`)))
+	require.NoError(t, err)
+	t.Cleanup(fixture.Cleanup)
+
+	ctx := t.Context()
+	log := silogtest.New(t)
+	wt, err := git.OpenWorktree(ctx, fixture.Dir(), git.OpenOptions{Log: log})
+	require.NoError(t, err)
+	repo := wt.Repository()
+
+	store, err := state.InitStore(ctx, state.InitStoreRequest{
+		DB:    storage.NewDB(make(storage.MapBackend)),
+		Trunk: "main",
+		Log:   log,
+	})
+	require.NoError(t, err)
+
+	peel := func(rev string) git.Hash {
+		hash, err := repo.PeelToCommit(ctx, rev)
+		require.NoError(t, err)
+		return hash
+	}
+	mainHash := peel("main")
+	oldFeature1 := peel("feature1")
+
+	require.NoError(t, statetest.UpdateBranch(ctx, store, &statetest.UpdateRequest{
+		Upserts: []state.UpsertRequest{
+			{Name: "feature1", Base: "main", BaseHash: mainHash},
+			{Name: "feature2", Base: "feature1", BaseHash: oldFeature1},
+		},
+	}))
+
+	mockCtrl := gomock.NewController(t)
+	mockService := NewMockService(mockCtrl)
+	mockService.EXPECT().
+		ListUpstack(gomock.Any(), "feature1").
+		Return([]string{"feature1", "feature2"}, nil)
+	mockService.EXPECT().
+		LookupBranch(gomock.Any(), "feature1").
+		Return(&spice.LookupBranchResponse{
+			Base:     "main",
+			BaseHash: mainHash,
+			Head:     oldFeature1,
+		}, nil)
+	mockService.EXPECT().
+		LookupBranch(gomock.Any(), "feature2").
+		Return(&spice.LookupBranchResponse{
+			Base:     "feature1",
+			BaseHash: oldFeature1,
+			Head:     peel("feature2"),
+		}, nil)
+
+	handler := &Handler{
+		Log:        log,
+		Repository: repo,
+		Worktree:   wt,
+		Store:      store,
+		Service:    mockService,
+	}
+
+	rewritten, err := handler.prepareChangeCommits(ctx, "feature1", fakeChangePusher{})
+	require.NoError(t, err)
+	assert.True(t, rewritten)
+
+	messages := func(rev string) []string {
+		var msgs []string
+		commits := git.CommitRangeFrom(peel(rev)).ExcludeFrom(mainHash).Reverse()
+		for hash, err := range repo.ListCommits(ctx, commits) {
+			require.NoError(t, err)
+			commit, err := repo.ReadCommit(ctx, hash.String())
+			require.NoError(t, err)
+			msgs = append(msgs, strings.TrimSpace(commit.Message()))
+		}
+		return msgs
+	}
+
+	assert.Equal(t, []string{
+		"feature1 a\n\nChange-Id: feature1 a",
+		"feature1 b\n\nChange-Id: existing",
+		"feature2\n\nChange-Id: feature2",
+	}, messages("feature2"))
+
+	// feature2 must still be based on the new feature1.
+	newFeature1 := peel("feature1")
+	assert.NotEqual(t, oldFeature1, newFeature1)
+	assert.Equal(t, newFeature1, peel("feature2^"))
+
+	feature2, err := store.LookupBranch(ctx, "feature2")
+	require.NoError(t, err)
+	assert.Equal(t, newFeature1, feature2.BaseHash)
+
+	t.Run("NoChanges", func(t *testing.T) {
+		mockService.EXPECT().
+			ListUpstack(gomock.Any(), "feature2").
+			Return([]string{"feature2"}, nil)
+		mockService.EXPECT().
+			LookupBranch(gomock.Any(), "feature2").
+			Return(&spice.LookupBranchResponse{
+				Base:     "feature1",
+				BaseHash: newFeature1,
+				Head:     peel("feature2"),
+			}, nil)
+
+		rewritten, err := handler.prepareChangeCommits(ctx, "feature2", fakeChangePusher{})
+		require.NoError(t, err)
+		assert.False(t, rewritten)
+	})
+}
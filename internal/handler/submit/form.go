@@ -22,6 +22,9 @@ type branchSubmitForm struct {
 	opts   *Options
 
 	tmpl *forge.ChangeTemplate
+
+	// issueLink is added to the end of the body if non-empty.
+	issueLink string
 }
 
 func newBranchSubmitForm(
@@ -123,6 +126,7 @@ func (f *branchSubmitForm) bodyField(body *string) ui.Field {
 			}
 			*body += f.tmpl.Body
 		}
+		*body = appendIssueLink(*body, f.issueLink)
 
 		ed := ui.NewOpenEditor(editor).
 			WithValue(body).
@@ -8,6 +8,8 @@ import (
 	"encoding"
 	"errors"
 	"fmt"
+	"io"
+	"iter"
 	"os"
 	"slices"
 	"sort"
@@ -19,11 +21,13 @@ import (
 	"go.abhg.dev/gs/internal/browser"
 	"go.abhg.dev/gs/internal/forge"
 	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/hook"
 	"go.abhg.dev/gs/internal/iterutil"
 	"go.abhg.dev/gs/internal/must"
 	"go.abhg.dev/gs/internal/silog"
 	"go.abhg.dev/gs/internal/spice"
 	"go.abhg.dev/gs/internal/spice/state"
+	"go.abhg.dev/gs/internal/tracing"
 	"go.abhg.dev/gs/internal/ui"
 )
 
@@ -38,6 +42,15 @@ type GitRepository interface {
 	Var(ctx context.Context, name string) (string, error)
 	CommitMessageRange(ctx context.Context, start string, stop string) ([]git.CommitMessage, error)
 	RemoteFetchRefspecs(ctx context.Context, remote string) ([]git.Refspec, error)
+	ListCommits(ctx context.Context, commits git.CommitRange) iter.Seq2[git.Hash, error]
+	ReadCommit(ctx context.Context, commitish string) (*git.CommitObject, error)
+	CommitTree(ctx context.Context, req git.CommitTreeRequest) (git.Hash, error)
+	SetRef(ctx context.Context, req git.SetRefRequest) error
+	RangeDiff(ctx context.Context, req git.RangeDiffRequest) error
+	DiffTree(ctx context.Context, treeish1, treeish2 string) iter.Seq2[git.FileStatus, error]
+	HashAt(ctx context.Context, treeish, path string) (git.Hash, error)
+	ReadObject(ctx context.Context, typ git.Type, hash git.Hash, dst io.Writer) error
+	Fetch(ctx context.Context, opts git.FetchOptions) error
 }
 
 var _ GitRepository = (*git.Repository)(nil)
@@ -58,6 +71,15 @@ type Store interface {
 	LoadPreparedBranch(ctx context.Context, name string) (*state.PreparedBranch, error)
 	SavePreparedBranch(ctx context.Context, b *state.PreparedBranch) error
 	ClearPreparedBranch(ctx context.Context, name string) error
+
+	AppendBranchVersion(ctx context.Context, branch string, v state.BranchVersion) (int, error)
+	LoadBranchHistory(ctx context.Context, branch string) ([]state.BranchVersion, error)
+
+	LoadBranchOwners(ctx context.Context, branch string) (*state.BranchOwners, error)
+
+	SavePendingSubmit(ctx context.Context, p *state.PendingSubmit) error
+	ListPendingSubmits(ctx context.Context) ([]*state.PendingSubmit, error)
+	ClearPendingSubmit(ctx context.Context, branch string) error
 }
 
 var _ Store = (*state.Store)(nil)
@@ -67,6 +89,7 @@ type Service interface {
 	LoadBranches(context.Context) ([]spice.LoadBranchItem, error)
 	VerifyRestacked(ctx context.Context, name string) error
 	LookupBranch(ctx context.Context, name string) (*spice.LookupBranchResponse, error)
+	ListUpstack(ctx context.Context, start string) ([]string, error)
 	UnusedBranchName(ctx context.Context, remote string, branch string) (string, error)
 	ListChangeTemplates(context.Context, string, forge.Repository) ([]*forge.ChangeTemplate, error)
 }
@@ -88,12 +111,18 @@ type Handler struct {
 	// non-fast-forward pushes to force-with-lease.
 	RestackMethod spice.RestackMethod
 
+	// Hooks runs hooks before and after submitting.
+	// If nil, no hooks run.
+	Hooks *hook.Runner
+
 	// TODO: these should not be a func reference
 	// this whole memoize thing is a bit of a hack
 	FindRemote           func(ctx context.Context) (string, error)                          // required
 	OpenRemoteRepository func(ctx context.Context, remote string) (forge.Repository, error) // required
 	remote               memoizedValue[string]
 	remoteRepository     memoizedValue[forge.Repository]
+	viewerLogin          memoizedValue[string]
+	teams                map[string][]string // team name -> members
 }
 
 // Remote returns the remote name for the current repository,
@@ -147,10 +176,17 @@ type Options struct {
 	NavCommentDownstack NavCommentDownstack `name:"nav-comment-downstack" config:"submit.navigationComment.downstack" enum:"all,open" default:"all" hidden:"" help:"Which downstack CRs to include in navigation comments. Must be one of: all, open."`
 	NavCommentMarker    string              `name:"nav-comment-marker" config:"submit.navigationCommentStyle.marker" hidden:"" help:"Marker to use for the current change in navigation comments. Defaults to '◀'."`
 
+	PushComment bool `name:"push-comment" config:"submit.pushComment" hidden:"" default:"false" help:"Post a comment listing changes since the last push when updating a change request."`
+
 	Force      bool  `help:"Force push, bypassing safety checks"`
-	NoVerify   bool  `help:"Bypass pre-push hooks when pushing to the remote." released:"v0.15.0"`
+	NoVerify   bool  `help:"Bypass pre-push and pre-submit hooks." released:"v0.15.0"`
 	UpdateOnly *bool `short:"u" negatable:"" help:"Only update existing change requests, do not create new ones"`
 
+	// Offline queues the submission instead of contacting the forge.
+	// Queued submissions are replayed by 'repo flush'
+	// or the next submit that isn't offline.
+	Offline bool `help:"Queue the submission to be replayed later with 'gs repo flush'" released:"unreleased"`
+
 	// DraftDefault is used to set the default draft value
 	// when creating new Change Requests.
 	//
@@ -167,6 +203,11 @@ type Options struct {
 	Reviewers           []string `short:"r" name:"reviewer" help:"Add reviewers to the change request. Pass multiple times or separate with commas." released:"v0.21.0"`
 	ConfiguredReviewers []string `name:"configured-reviewers" help:"Default reviewers to add to change requests." hidden:"" config:"submit.reviewers" released:"v0.21.0"` // merged with Reviewers
 
+	// CodeOwners requests reviews from the owners of changed files
+	// listed in the repository's CODEOWNERS file.
+	// Branches may override this with 'gs branch owners'.
+	CodeOwners bool `name:"codeowners" negatable:"" config:"submit.codeowners" help:"Request reviews from owners of changed files listed in the CODEOWNERS file." released:"unreleased"`
+
 	// Copilot requests GitHub Copilot Code Review on the change.
 	// The request is idempotent: if Copilot is already in the change's
 	// requested reviewers or has submitted a review, it is a no-op.
@@ -296,6 +337,9 @@ type BatchRequest struct {
 // SubmitBatch submits a batch of branches to a remote repository,
 // creating or updating change requests as needed.
 func (h *Handler) SubmitBatch(ctx context.Context, req *BatchRequest) error {
+	ctx, span := tracing.Start(ctx, tracing.CategoryHandler, "submit batch", "branches", req.Branches)
+	defer span.End()
+
 	opts := cmp.Or(req.Options, &Options{})
 	mergeConfiguredOptions(opts)
 
@@ -306,6 +350,14 @@ func (h *Handler) SubmitBatch(ctx context.Context, req *BatchRequest) error {
 		opts.UpdateOnly = &batchOpts.UpdateOnlyDefault
 	}
 
+	if err := h.runPreSubmitHooks(ctx, opts, req.Branches); err != nil {
+		return err
+	}
+
+	if !opts.Offline && !opts.DryRun {
+		h.flushBeforeSubmit(ctx, req.Branches)
+	}
+
 	var branchesToComment []string
 	for _, branch := range req.Branches {
 		// Shallow copy the options because submitBranch may modify them.
@@ -319,6 +371,9 @@ func (h *Handler) SubmitBatch(ctx context.Context, req *BatchRequest) error {
 		if err != nil {
 			return fmt.Errorf("submit branch %s: %w", branch, err)
 		}
+		if !opts.Offline && !opts.DryRun {
+			h.clearPending(ctx, branch)
+		}
 		if status.Submitted {
 			branchesToComment = append(branchesToComment, branch)
 		}
@@ -328,7 +383,7 @@ func (h *Handler) SubmitBatch(ctx context.Context, req *BatchRequest) error {
 		return nil // nothing to do
 	}
 
-	return updateNavigationComments(
+	if err := updateNavigationComments(
 		ctx,
 		h.Store, h.Service, h.Log,
 		opts.NavComment,
@@ -337,7 +392,11 @@ func (h *Handler) SubmitBatch(ctx context.Context, req *BatchRequest) error {
 		opts.NavCommentMarker,
 		branchesToComment,
 		h.RemoteRepository,
-	)
+	); err != nil {
+		return err
+	}
+
+	return h.Hooks.Run(ctx, hook.PostSubmit, branchesToComment)
 }
 
 // Request is a request to submit a single branch to a remote repository.
@@ -359,8 +418,19 @@ type Request struct {
 // Submit submits a single branch to a remote repository,
 // creating or updating a change request as needed.
 func (h *Handler) Submit(ctx context.Context, req *Request) error {
+	ctx, span := tracing.Start(ctx, tracing.CategoryHandler, "submit", "branch", req.Branch)
+	defer span.End()
+
 	opts := cmp.Or(req.Options, &Options{})
 	mergeConfiguredOptions(opts)
+	if err := h.runPreSubmitHooks(ctx, opts, []string{req.Branch}); err != nil {
+		return err
+	}
+
+	if !opts.Offline && !opts.DryRun {
+		h.flushBeforeSubmit(ctx, []string{req.Branch})
+	}
+
 	status, err := h.submitBranch(
 		ctx,
 		req.Branch,
@@ -374,13 +444,16 @@ func (h *Handler) Submit(ctx context.Context, req *Request) error {
 	if err != nil {
 		return fmt.Errorf("submit branch %s: %w", req.Branch, err)
 	}
+	if !opts.Offline && !opts.DryRun {
+		h.clearPending(ctx, req.Branch)
+	}
 
 	if !status.Submitted || opts.DryRun {
 		// Nothing was submitted, so nothing to do.
 		return nil
 	}
 
-	return updateNavigationComments(
+	if err := updateNavigationComments(
 		ctx,
 		h.Store, h.Service, h.Log,
 		opts.NavComment,
@@ -389,7 +462,20 @@ func (h *Handler) Submit(ctx context.Context, req *Request) error {
 		opts.NavCommentMarker,
 		[]string{req.Branch},
 		h.RemoteRepository,
-	)
+	); err != nil {
+		return err
+	}
+
+	return h.Hooks.Run(ctx, hook.PostSubmit, []string{req.Branch})
+}
+
+// runPreSubmitHooks runs pre-submit hooks for the given branches
+// unless the submission is a dry run or hooks were bypassed.
+func (h *Handler) runPreSubmitHooks(ctx context.Context, opts *Options, branches []string) error {
+	if opts.DryRun || opts.NoVerify {
+		return nil
+	}
+	return h.Hooks.Run(ctx, hook.PreSubmit, branches)
 }
 
 type submitStatus struct {
@@ -413,6 +499,9 @@ func (h *Handler) submitBranch(
 	branchToSubmit string,
 	opts *submitOptions,
 ) (status submitStatus, err error) {
+	ctx, span := tracing.Start(ctx, tracing.CategoryHandler, "submit branch", "branch", branchToSubmit)
+	defer span.End()
+
 	if branchToSubmit == h.Store.Trunk() {
 		return status, errors.New("cannot submit trunk")
 	}
@@ -436,6 +525,10 @@ func (h *Handler) submitBranch(
 		return status, fmt.Errorf("lookup branch: %w", err)
 	}
 
+	if opts.Offline {
+		return status, h.queueSubmit(ctx, branchToSubmit, branch, opts)
+	}
+
 	// Various code paths down below should call this
 	// if the branch is being published as a CR (new or existing)
 	// so it should get a nav comment.
@@ -446,6 +539,23 @@ func (h *Handler) submitBranch(
 		})
 	}
 
+	// Forges like Gerrit create changes from pushed commits
+	// instead of branches.
+	// These may need commit messages to be rewritten before pushing.
+	var pusher forge.ChangePusher
+	if opts.Publish {
+		remoteRepo, err := h.RemoteRepository(ctx)
+		if err != nil {
+			return status, fmt.Errorf("open remote repository: %w", err)
+		}
+		pusher, _ = remoteRepo.(forge.ChangePusher)
+	}
+	if pusher != nil && !opts.DryRun {
+		if _, err := h.prepareChangeCommits(ctx, branchToSubmit, pusher); err != nil {
+			return status, fmt.Errorf("prepare commits: %w", err)
+		}
+	}
+
 	commitHash, err := h.Repository.PeelToCommit(ctx, branchToSubmit)
 	if err != nil {
 		return status, fmt.Errorf("peel to commit: %w", err)
@@ -491,9 +601,33 @@ func (h *Handler) submitBranch(
 		if err != nil {
 			return status, fmt.Errorf("lookup base branch: %w", err)
 		}
+
+		// Commits pushed for review create changes for all their
+		// unsubmitted ancestors, so the base must be submitted first
+		// to get changes of its own.
+		if pusher != nil && baseBranch.Change == nil {
+			log.Errorf("%v: cannot be submitted because base branch %q has not been submitted.", branchToSubmit, branch.Base)
+			log.Errorf("Try submitting the base branch first:")
+			log.Errorf("  gs branch submit --branch=%s", branch.Base)
+			return status, forge.ErrUnsubmittedBase
+		}
+
 		upstreamBase = cmp.Or(baseBranch.UpstreamBranch, branch.Base)
 	}
 
+	// Changes created from pushed commits are stacked by their ancestry,
+	// so they're always proposed against trunk.
+	if pusher != nil && opts.Base == "" {
+		upstreamBase = h.Store.Trunk()
+	}
+
+	if opts.Publish {
+		span := git.CommitSpan{Base: branch.BaseHash, Head: commitHash}
+		if owners := h.codeOwnerReviewers(ctx, branchToSubmit, span, opts.CodeOwners); len(owners) > 0 {
+			opts.Reviewers = mergeConfiguredValues(opts.Reviewers, owners)
+		}
+	}
+
 	var existingChange *forge.FindChangeItem
 	if branch.Change == nil && opts.Publish {
 		// If the branch doesn't have a CR associated with it,
@@ -678,40 +812,45 @@ func (h *Handler) submitBranch(
 		// Otherwise, we will push to origin/feature,
 		// but won't have a local refs/remotes/origin/feature
 		// to track it after a 'git fetch'.
-		if refspecs, err := h.Repository.RemoteFetchRefspecs(ctx, remote); err != nil {
-			log.Warn("Unable to verify remote's fetch refspecs",
-				"remote", remote,
-				"error", err)
-		} else {
-			wantMatch := "refs/heads/" + upstreamBranch
-			var hasMatch bool
-			for _, refspec := range refspecs {
-				if refspec.Matches(wantMatch) {
-					hasMatch = true
-					break
+		//
+		// This doesn't apply to commits pushed for review
+		// as those don't create a branch.
+		if pusher == nil {
+			if refspecs, err := h.Repository.RemoteFetchRefspecs(ctx, remote); err != nil {
+				log.Warn("Unable to verify remote's fetch refspecs",
+					"remote", remote,
+					"error", err)
+			} else {
+				wantMatch := "refs/heads/" + upstreamBranch
+				var hasMatch bool
+				for _, refspec := range refspecs {
+					if refspec.Matches(wantMatch) {
+						hasMatch = true
+						break
+					}
 				}
-			}
 
-			if !hasMatch && !opts.Force {
-				log.Errorf("Remote '%v' has refspecs:", remote)
-				for _, refspec := range refspecs {
-					log.Errorf("  - %v", refspec)
+				if !hasMatch && !opts.Force {
+					log.Errorf("Remote '%v' has refspecs:", remote)
+					for _, refspec := range refspecs {
+						log.Errorf("  - %v", refspec)
+					}
+					user := cmp.Or(os.Getenv("USER"), "yourname")
+					log.Errorf("None of these will fetch branch '%v' after pushing.", upstreamBranch)
+					log.Error("This will make follow up changes on them impossible.")
+					log.Error("To fix this, you can do one of the following:")
+					log.Errorf("1. Manually add a fetch refspec for just this branch:")
+					log.Errorf("       git config --add remote.%v.fetch +refs/heads/%v:refs/remotes/%v/%v",
+						remote, upstreamBranch, remote, upstreamBranch)
+					log.Errorf("2. Prefix all your branches with your username (e.g. '%v/%v'),", user, upstreamBranch)
+					log.Errorf("   and add a fetch refspec to fetch all branches under that prefix:")
+					log.Errorf("       git config --add remote.%v.fetch '+refs/heads/%v/*:refs/remotes/%v/%v/*'",
+						remote, user, remote, user)
+					log.Errorf("   You can configure git-spice to automatically add this prefix for future branches with:")
+					log.Errorf("       git config --global spice.branchCreate.prefix %v/", user)
+					log.Errorf("3. Use the --force flag to push anyway (not recommended).")
+					return status, errors.New("remote cannot fetch pushed branch")
 				}
-				user := cmp.Or(os.Getenv("USER"), "yourname")
-				log.Errorf("None of these will fetch branch '%v' after pushing.", upstreamBranch)
-				log.Error("This will make follow up changes on them impossible.")
-				log.Error("To fix this, you can do one of the following:")
-				log.Errorf("1. Manually add a fetch refspec for just this branch:")
-				log.Errorf("       git config --add remote.%v.fetch +refs/heads/%v:refs/remotes/%v/%v",
-					remote, upstreamBranch, remote, upstreamBranch)
-				log.Errorf("2. Prefix all your branches with your username (e.g. '%v/%v'),", user, upstreamBranch)
-				log.Errorf("   and add a fetch refspec to fetch all branches under that prefix:")
-				log.Errorf("       git config --add remote.%v.fetch '+refs/heads/%v/*:refs/remotes/%v/%v/*'",
-					remote, user, remote, user)
-				log.Errorf("   You can configure git-spice to automatically add this prefix for future branches with:")
-				log.Errorf("       git config --global spice.branchCreate.prefix %v/", user)
-				log.Errorf("3. Use the --force flag to push anyway (not recommended).")
-				return status, errors.New("remote cannot fetch pushed branch")
 			}
 		}
 
@@ -734,6 +873,8 @@ func (h *Handler) submitBranch(
 				remote, // TODO: need this?
 				remoteRepo,
 				upstreamBranch, branch.Base, upstreamBase,
+				commitHash,
+				branch.Issue,
 				opts,
 			)
 			if err != nil {
@@ -750,17 +891,22 @@ func (h *Handler) submitBranch(
 			NoVerify: opts.NoVerify,
 		}
 
+		if pusher != nil {
+			// Pushes for review never need to be forced.
+			pushOpts.Refspec = changePushRefspec(pusher, commitHash, upstreamBase, upstreamBranch)
+		}
+
 		// If we've already pushed this branch before,
 		// we may need a force push.
 		// Use a --force-with-lease only if this push
 		// cannot be done as a fast-forward update.
-		if !opts.Force && h.RestackMethod != spice.RestackMethodMerge {
+		if pusher == nil && !opts.Force && h.RestackMethod != spice.RestackMethodMerge {
 			existingHash, err := h.Repository.PeelToCommit(ctx, remote+"/"+upstreamBranch)
 			if err == nil && !h.Repository.IsAncestor(ctx, existingHash, commitHash) {
 				pushOpts.ForceWithLease = upstreamBranch + ":" + existingHash.String()
 			}
 		}
-		if !opts.Force && h.RestackMethod == spice.RestackMethodMerge {
+		if pusher == nil && !opts.Force && h.RestackMethod == spice.RestackMethodMerge {
 			existingHash, err := h.Repository.PeelToCommit(ctx, remote+"/"+upstreamBranch)
 			if err == nil && !h.Repository.IsAncestor(ctx, existingHash, commitHash) {
 				return status, rejectNonFastForwardPush(log, upstreamBranch, h.RestackMethod)
@@ -779,6 +925,7 @@ func (h *Handler) submitBranch(
 			}
 			return status, fmt.Errorf("push branch: %w", err)
 		}
+		h.recordVersion(ctx, branchToSubmit, git.CommitSpan{Base: branch.BaseHash, Head: commitHash})
 
 		// At this point, even if any other operation fails,
 		// we need to save to the state that we pushed the branch
@@ -802,9 +949,12 @@ func (h *Handler) submitBranch(
 			}
 		}()
 
-		upstream := remote + "/" + upstreamBranch
-		if err := h.Repository.SetBranchUpstream(ctx, branchToSubmit, upstream); err != nil {
-			log.Warn("Could not set upstream", "branch", branchToSubmit, "remote", remote, "error", err)
+		// Commits pushed for review don't have a remote branch to track.
+		if pusher == nil {
+			upstream := remote + "/" + upstreamBranch
+			if err := h.Repository.SetBranchUpstream(ctx, branchToSubmit, upstream); err != nil {
+				log.Warn("Could not set upstream", "branch", branchToSubmit, "remote", remote, "error", err)
+			}
 		}
 
 		if prepared != nil {
@@ -954,7 +1104,11 @@ func (h *Handler) submitBranch(
 				Force:    opts.Force,
 				NoVerify: opts.NoVerify,
 			}
-			if !opts.Force && h.RestackMethod != spice.RestackMethodMerge {
+			if pusher != nil {
+				// Pushes for review never need to be forced.
+				pushOpts.Refspec = changePushRefspec(pusher, commitHash, upstreamBase, upstreamBranch)
+			}
+			if pusher == nil && !opts.Force && h.RestackMethod != spice.RestackMethodMerge {
 				// Force push, but only if the ref is exactly
 				// where we think it is, and only if needed.
 				existingHash, err := h.Repository.PeelToCommit(ctx, remote+"/"+upstreamBranch)
@@ -962,7 +1116,7 @@ func (h *Handler) submitBranch(
 					pushOpts.ForceWithLease = upstreamBranch + ":" + existingHash.String()
 				}
 			}
-			if !opts.Force && h.RestackMethod == spice.RestackMethodMerge {
+			if pusher == nil && !opts.Force && h.RestackMethod == spice.RestackMethodMerge {
 				existingHash, err := h.Repository.PeelToCommit(ctx, remote+"/"+upstreamBranch)
 				if err == nil && !h.Repository.IsAncestor(ctx, existingHash, commitHash) {
 					return status, rejectNonFastForwardPush(log, upstreamBranch, h.RestackMethod)
@@ -981,6 +1135,17 @@ func (h *Handler) submitBranch(
 				log.Error("Push failed. Branch may have been updated by someone else. Try with --force.")
 				return status, fmt.Errorf("push branch: %w", err)
 			}
+
+			span := git.CommitSpan{Base: branch.BaseHash, Head: commitHash}
+			prev, version := h.recordVersion(ctx, branchToSubmit, span)
+			if opts.PushComment && prev != nil {
+				remoteRepo, err := h.RemoteRepository(ctx)
+				if err != nil {
+					log.Warn("Could not post changes since last push", "error", err)
+				} else {
+					h.postPushComment(ctx, remoteRepo, pull.ID, prev.Span, span, version)
+				}
+			}
 		}
 
 		if len(updates) > 0 {
@@ -1078,8 +1243,13 @@ func (h *Handler) prepareBranch(
 	remoteName string,
 	remoteRepo forge.Repository,
 	upstreamBranch, baseBranch, upstreamBase string,
+	headHash git.Hash,
+	issue string,
 	opts *submitOptions,
 ) (*preparedBranch, error) {
+	ctx, span := tracing.Start(ctx, tracing.CategoryHandler, "prepare branch", "branch", branchToSubmit)
+	defer span.End()
+
 	// Fetch the template while we're prompting the other fields.
 	changeTemplatesCh := make(chan []*forge.ChangeTemplate, 1)
 	go func() {
@@ -1158,8 +1328,17 @@ func (h *Handler) prepareBranch(
 		}
 	}
 
+	// Forges that create changes from pushed commits
+	// take the title and body from the commit message,
+	// so there's nothing to prompt for.
+	fill := opts.Fill
+	if _, ok := remoteRepo.(forge.ChangePusher); ok {
+		fill = true
+	}
+
 	var fields []ui.Field
 	form := newBranchSubmitForm(ctx, h.Service, h.Repository, remoteRepo, h.Log, opts.Options)
+	form.issueLink = issueLinkText(remoteRepo, issue)
 	if opts.Title == "" {
 		opts.Title = defaultTitle
 		fields = append(fields, form.titleField(&opts.Title, msgs))
@@ -1167,7 +1346,7 @@ func (h *Handler) prepareBranch(
 
 	if opts.Body == "" {
 		opts.Body = defaultBody.String()
-		if opts.Fill {
+		if fill {
 			// If the user selected --fill,
 			// and there are templates to choose from,
 			// just pick the first template in the body.
@@ -1175,6 +1354,7 @@ func (h *Handler) prepareBranch(
 			if len(tmpls) > 0 {
 				opts.Body += "\n\n" + tmpls[0].Body
 			}
+			opts.Body = appendIssueLink(opts.Body, form.issueLink)
 		} else {
 			// Otherwise, we'll prompt for the template (if needed)
 			// and the body.
@@ -1192,7 +1372,7 @@ func (h *Handler) prepareBranch(
 	}
 
 	// TODO: should we assume --fill if --no-prompt?
-	if len(fields) > 0 && !opts.Fill {
+	if len(fields) > 0 && !fill {
 		if !ui.Interactive(h.View) {
 			return nil, fmt.Errorf("prompt for commit information: %w", ui.ErrPrompt)
 		}
@@ -1250,6 +1430,7 @@ func (h *Handler) prepareBranch(
 		PreparedBranch: storePrepared,
 		draft:          draft,
 		head:           upstreamBranch,
+		headHash:       headHash,
 		base:           upstreamBase,
 		remoteRepo:     remoteRepo,
 		store:          h.Store,
@@ -1260,6 +1441,39 @@ func (h *Handler) prepareBranch(
 	}, nil
 }
 
+// issueLinkText returns text to add to the body of a change
+// to link it to the given issue.
+// It returns an empty string if there's no issue.
+//
+// If the forge supports it, this will use the forge's native syntax
+// to link the change to the issue.
+func issueLinkText(remoteRepo forge.Repository, issue string) string {
+	if issue == "" {
+		return ""
+	}
+
+	if linker, ok := remoteRepo.(forge.IssueLinker); ok {
+		if text, ok := linker.IssueLinkText(issue); ok {
+			return text
+		}
+	}
+
+	return "Issue: " + issue
+}
+
+// appendIssueLink adds the issue link to the end of the body
+// unless the body already includes it.
+func appendIssueLink(body, issueLink string) string {
+	if issueLink == "" || strings.Contains(body, issueLink) {
+		return body
+	}
+
+	if body == "" {
+		return issueLink
+	}
+	return strings.TrimRight(body, "\n") + "\n\n" + issueLink
+}
+
 func listChangeTemplates(
 	ctx context.Context,
 	svc Service,
@@ -1293,6 +1507,7 @@ type preparedBranch struct {
 	state.PreparedBranch
 
 	head      string
+	headHash  git.Hash
 	base      string
 	draft     bool
 	labels    []string
@@ -1309,6 +1524,7 @@ func (b *preparedBranch) Publish(ctx context.Context) (forge.ChangeID, string, e
 		Subject:   b.Subject,
 		Body:      b.Body,
 		Head:      b.head,
+		HeadHash:  b.headHash,
 		Base:      b.base,
 		Draft:     b.draft,
 		Labels:    b.labels,
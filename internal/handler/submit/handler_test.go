@@ -8,6 +8,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/forge/forgetest"
 	"go.abhg.dev/gs/internal/silog/silogtest"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -54,3 +55,49 @@ func TestBranchSubmit_listChangeTemplates(t *testing.T) {
 		assert.Empty(t, got)
 	})
 }
+
+func TestIssueLinkText(t *testing.T) {
+	t.Run("NoIssue", func(t *testing.T) {
+		assert.Empty(t, issueLinkText(nil, ""))
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		repo := forgetest.NewMockRepository(gomock.NewController(t))
+		assert.Equal(t, "Issue: ABC-123", issueLinkText(repo, "ABC-123"))
+	})
+
+	t.Run("Linker", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		linker := forgetest.NewMockIssueLinker(ctrl)
+		linker.EXPECT().IssueLinkText("#42").Return("Fixes #42", true)
+		linker.EXPECT().IssueLinkText("ABC-123").Return("", false)
+
+		repo := struct {
+			*forgetest.MockRepository
+			*forgetest.MockIssueLinker
+		}{forgetest.NewMockRepository(ctrl), linker}
+
+		assert.Equal(t, "Fixes #42", issueLinkText(repo, "#42"))
+		assert.Equal(t, "Issue: ABC-123", issueLinkText(repo, "ABC-123"))
+	})
+}
+
+func TestAppendIssueLink(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		link string
+		want string
+	}{
+		{name: "NoLink", body: "body", want: "body"},
+		{name: "EmptyBody", link: "Fixes #1", want: "Fixes #1"},
+		{name: "Append", body: "body\n", link: "Fixes #1", want: "body\n\nFixes #1"},
+		{name: "AlreadyPresent", body: "Fixes #1\n\nbody", link: "Fixes #1", want: "Fixes #1\n\nbody"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, appendIssueLink(tt.body, tt.link))
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package submit
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/spice/state"
+)
+
+var _timeNow = time.Now
+
+func init() {
+	now := os.Getenv("GIT_SPICE_NOW")
+	if now != "" {
+		t, err := time.Parse(time.RFC3339, now)
+		if err == nil {
+			_timeNow = func() time.Time {
+				return t
+			}
+		}
+	}
+}
+
+// recordVersion records a pushed version of a branch in its history.
+// It returns the version pushed before it, if any,
+// and the number of the new version.
+//
+// Failures are logged and otherwise ignored.
+func (h *Handler) recordVersion(
+	ctx context.Context,
+	branch string,
+	span git.CommitSpan,
+) (prev *state.BranchVersion, version int) {
+	history, err := h.Store.LoadBranchHistory(ctx, branch)
+	if err != nil {
+		h.Log.Warn("Could not load branch history", "branch", branch, "error", err)
+		return nil, 0
+	}
+
+	version, err = h.Store.AppendBranchVersion(ctx, branch, state.BranchVersion{
+		Span: span,
+		Time: _timeNow(),
+	})
+	if err != nil {
+		h.Log.Warn("Could not record pushed version", "branch", branch, "error", err)
+		return nil, 0
+	}
+
+	// If the version number didn't change, nothing new was pushed.
+	if len(history) == 0 || version == len(history) {
+		return nil, version
+	}
+	return &history[len(history)-1], version
+}
+
+// postPushComment posts a comment on a change
+// listing how its commits changed since the previous push.
+//
+// Failures are logged and otherwise ignored.
+func (h *Handler) postPushComment(
+	ctx context.Context,
+	remoteRepo forge.Repository,
+	changeID forge.ChangeID,
+	prev, cur git.CommitSpan,
+	version int,
+) {
+	// Commits of the previous push may have been garbage collected
+	// if it was force-pushed over a while ago.
+	for _, hash := range []git.Hash{prev.Base, prev.Head} {
+		if _, err := h.Repository.PeelToCommit(ctx, hash.String()); err != nil {
+			h.Log.Warn("Previous push is no longer in the repository: not posting changes since then",
+				"change", changeID, "commit", hash.Short())
+			return
+		}
+	}
+
+	var rangeDiff bytes.Buffer
+	if err := h.Repository.RangeDiff(ctx, git.RangeDiffRequest{
+		Old:     prev,
+		New:     cur,
+		NoPatch: true,
+		Stdout:  &rangeDiff,
+	}); err != nil {
+		h.Log.Warn("Could not compare with the previous push", "change", changeID, "error", err)
+		return
+	}
+
+	body := pushCommentBody(version, rangeDiff.String())
+	if _, err := remoteRepo.PostChangeComment(ctx, changeID, body); err != nil {
+		h.Log.Warn("Could not post changes since last push", "change", changeID, "error", err)
+		return
+	}
+	h.Log.Infof("%v: Posted changes since v%d", changeID, version-1)
+}
+
+// pushCommentBody builds the body of a comment
+// listing changes in version of a branch since the previous version.
+// rangeDiff is the output of 'git range-diff --no-patch'.
+func pushCommentBody(version int, rangeDiff string) string {
+	var body strings.Builder
+	fmt.Fprintf(&body, "**Changes since last push** (v%d → v%d)\n\n", version-1, version)
+	body.WriteString("```\n")
+	body.WriteString(strings.TrimRight(rangeDiff, "\n"))
+	body.WriteString("\n```\n")
+	return body.String()
+}
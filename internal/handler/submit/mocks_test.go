@@ -81,6 +81,45 @@ func (c *MockServiceListChangeTemplatesCall) DoAndReturn(f func(context.Context,
 	return c
 }
 
+// ListUpstack mocks base method.
+func (m *MockService) ListUpstack(ctx context.Context, start string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUpstack", ctx, start)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUpstack indicates an expected call of ListUpstack.
+func (mr *MockServiceMockRecorder) ListUpstack(ctx, start any) *MockServiceListUpstackCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUpstack", reflect.TypeOf((*MockService)(nil).ListUpstack), ctx, start)
+	return &MockServiceListUpstackCall{Call: call}
+}
+
+// MockServiceListUpstackCall wrap *gomock.Call
+type MockServiceListUpstackCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceListUpstackCall) Return(arg0 []string, arg1 error) *MockServiceListUpstackCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceListUpstackCall) Do(f func(context.Context, string) ([]string, error)) *MockServiceListUpstackCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceListUpstackCall) DoAndReturn(f func(context.Context, string) ([]string, error)) *MockServiceListUpstackCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // LoadBranches mocks base method.
 func (m *MockService) LoadBranches(arg0 context.Context) ([]spice.LoadBranchItem, error) {
 	m.ctrl.T.Helper()
@@ -106,6 +106,12 @@ func updateNavigationComments(
 		return fmt.Errorf("get remote repository: %w", err)
 	}
 
+	// Forges that create changes from pushed commits
+	// already show how those changes are stacked.
+	if _, ok := remoteRepo.(forge.ChangePusher); ok {
+		return nil
+	}
+
 	// Look up branch graph once, and share between all syncs.
 	trackedBranches, err := svc.LoadBranches(ctx)
 	if err != nil {
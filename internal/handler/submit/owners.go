@@ -0,0 +1,186 @@
+package submit
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"strings"
+
+	"go.abhg.dev/gs/internal/codeowners"
+	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/spice/state"
+)
+
+// codeOwnerReviewers returns the reviewers to request for a branch
+// from the owners of the files it changes.
+//
+// Owners are read from the CODEOWNERS file at the base of the branch,
+// and adjusted with the branch's overrides.
+// Teams are expanded into their members if the forge supports it,
+// and the authenticated user is never included.
+//
+// Failures are logged and otherwise ignored.
+func (h *Handler) codeOwnerReviewers(
+	ctx context.Context,
+	branch string,
+	span git.CommitSpan,
+	enabled bool,
+) []string {
+	log := h.Log
+
+	overrides, err := h.Store.LoadBranchOwners(ctx, branch)
+	if err != nil {
+		log.Warn("Could not load code owner overrides", "branch", branch, "error", err)
+	}
+	if overrides == nil {
+		overrides = new(state.BranchOwners)
+	}
+	if overrides.Enabled != nil {
+		enabled = *overrides.Enabled
+	}
+	if !enabled {
+		return nil
+	}
+
+	var owners []string
+	if file, path := h.loadCodeOwners(ctx, span.Base); file != nil {
+		for f, err := range h.Repository.DiffTree(ctx, span.Base.String(), span.Head.String()) {
+			if err != nil {
+				log.Warn("Could not list changed files", "branch", branch, "error", err)
+				return nil
+			}
+
+			for _, p := range []string{f.Path, f.OldPath} {
+				if p == "" {
+					continue
+				}
+				for _, o := range file.Owners(p) {
+					if !slices.Contains(owners, o) {
+						owners = append(owners, o)
+					}
+				}
+			}
+		}
+		log.Debug("Found code owners", "branch", branch, "file", path, "owners", owners)
+	}
+
+	remoteRepo, err := h.RemoteRepository(ctx)
+	if err != nil {
+		log.Warn("Could not open remote repository", "error", err)
+		return nil
+	}
+
+	var reviewers []string
+	for _, owner := range owners {
+		name, ok := strings.CutPrefix(owner, "@")
+		if !ok || strings.HasPrefix(name, "@") {
+			// Email addresses and GitLab roles ("@@developer")
+			// can't be requested as reviewers.
+			log.Debug("Skipping code owner that is not a user or team", "owner", owner)
+			continue
+		}
+		if containsOwner(overrides.Skip, name) {
+			continue
+		}
+
+		if !strings.Contains(name, "/") {
+			reviewers = append(reviewers, name)
+			continue
+		}
+
+		members, err := h.teamMembers(ctx, remoteRepo, name)
+		if err != nil {
+			log.Warn("Could not list team members; requesting review from the team", "team", name, "error", err)
+			reviewers = append(reviewers, name)
+			continue
+		}
+		for _, m := range members {
+			if !containsOwner(overrides.Skip, m) {
+				reviewers = append(reviewers, m)
+			}
+		}
+	}
+	reviewers = append(reviewers, overrides.Add...)
+
+	if viewer, ok := remoteRepo.(forge.ViewerIdentifier); ok {
+		login, err := h.viewerLogin.Get(func() (string, error) {
+			return viewer.ViewerLogin(ctx)
+		})
+		if err != nil {
+			log.Warn("Could not identify authenticated user", "error", err)
+		} else {
+			reviewers = slices.DeleteFunc(reviewers, func(r string) bool {
+				return strings.EqualFold(r, login)
+			})
+		}
+	}
+
+	reviewers = mergeConfiguredValues(reviewers, nil)
+	if len(reviewers) > 0 {
+		log.Infof("%v: Requesting reviews from code owners: %v", branch, strings.Join(reviewers, ", "))
+	}
+	return reviewers
+}
+
+// loadCodeOwners reads the CODEOWNERS file at the given commit.
+// It returns nil if the commit doesn't have a valid CODEOWNERS file.
+func (h *Handler) loadCodeOwners(ctx context.Context, commit git.Hash) (*codeowners.File, string) {
+	for _, path := range codeowners.Paths {
+		hash, err := h.Repository.HashAt(ctx, commit.String(), path)
+		if err != nil {
+			continue // no such file
+		}
+
+		var buf bytes.Buffer
+		if err := h.Repository.ReadObject(ctx, git.BlobType, hash, &buf); err != nil {
+			h.Log.Warn("Could not read CODEOWNERS file", "path", path, "error", err)
+			return nil, ""
+		}
+
+		file, err := codeowners.Parse(&buf)
+		if err != nil {
+			h.Log.Warn("Ignoring invalid CODEOWNERS file", "path", path, "error", err)
+			return nil, ""
+		}
+		return file, path
+	}
+
+	h.Log.Debug("No CODEOWNERS file found", "commit", commit.Short())
+	return nil, ""
+}
+
+// teamMembers lists the members of a team on the forge,
+// memoizing the result for the lifetime of the handler.
+//
+// If the forge can't list team members,
+// the team itself is returned so that its review is requested.
+func (h *Handler) teamMembers(ctx context.Context, remoteRepo forge.Repository, team string) ([]string, error) {
+	if members, ok := h.teams[team]; ok {
+		return members, nil
+	}
+
+	lister, ok := remoteRepo.(forge.TeamMemberLister)
+	if !ok {
+		return []string{team}, nil
+	}
+
+	members, err := lister.ListTeamMembers(ctx, team)
+	if err != nil {
+		return nil, err
+	}
+
+	if h.teams == nil {
+		h.teams = make(map[string][]string)
+	}
+	h.teams[team] = members
+	return members, nil
+}
+
+// containsOwner reports whether owners lists the given user or team,
+// with or without a leading "@".
+func containsOwner(owners []string, name string) bool {
+	return slices.ContainsFunc(owners, func(o string) bool {
+		return strings.EqualFold(strings.TrimPrefix(o, "@"), name)
+	})
+}
@@ -0,0 +1,146 @@
+package submit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/forge/forgetest"
+	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/git/gittest"
+	"go.abhg.dev/gs/internal/silog/silogtest"
+	"go.abhg.dev/gs/internal/spice/state"
+	"go.abhg.dev/gs/internal/spice/state/storage"
+	"go.abhg.dev/gs/internal/text"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// teamForgeRepository is a forge repository
+// that can list team members and identify the viewer.
+type teamForgeRepository struct {
+	*forgetest.MockRepository
+	*forgetest.MockTeamMemberLister
+	*forgetest.MockViewerIdentifier
+}
+
+func TestCodeOwnerReviewers(t *testing.T) {
+	fixture, err := gittest.LoadFixtureScript([]byte(text.Dedent(`
+		as 'Test <test@example.com>'
+		at '2026-10-18T12:00:00Z'
+
+		git init
+		git add .github/CODEOWNERS
+		git commit -m 'Add CODEOWNERS'
+
+		git checkout -b feature
+		git add api/server.go docs/guide.md
+		git commit -m 'Change api and docs'
+
+		-- .github/CODEOWNERS --
+		*           @lead
+		*.md        @@developer
+		/api/       @acme/backend @lead
+		/docs/      @acme/writers docs@example.com
+
+		-- api/server.go --
+		package api
+		-- docs/guide.md --
+		# Guide
+	`)))
+	require.NoError(t, err)
+	t.Cleanup(fixture.Cleanup)
+
+	ctx := t.Context()
+	log := silogtest.New(t)
+	repo, err := git.Open(ctx, fixture.Dir(), git.OpenOptions{Log: log})
+	require.NoError(t, err)
+
+	var span git.CommitSpan
+	span.Base, err = repo.PeelToCommit(ctx, "main")
+	require.NoError(t, err)
+	span.Head, err = repo.PeelToCommit(ctx, "feature")
+	require.NoError(t, err)
+
+	newHandler := func(t *testing.T) (*Handler, *state.Store, *teamForgeRepository) {
+		mockCtrl := gomock.NewController(t)
+		remoteRepo := &teamForgeRepository{
+			MockRepository:       forgetest.NewMockRepository(mockCtrl),
+			MockTeamMemberLister: forgetest.NewMockTeamMemberLister(mockCtrl),
+			MockViewerIdentifier: forgetest.NewMockViewerIdentifier(mockCtrl),
+		}
+
+		store, err := state.InitStore(ctx, state.InitStoreRequest{
+			DB:    storage.NewDB(make(storage.MapBackend)),
+			Trunk: "main",
+			Log:   log,
+		})
+		require.NoError(t, err)
+
+		return &Handler{
+			Log:        log,
+			Repository: repo,
+			Store:      store,
+			OpenRemoteRepository: func(ctx context.Context, remote string) (forge.Repository, error) {
+				return remoteRepo, nil
+			},
+			FindRemote: func(ctx context.Context) (string, error) {
+				return "origin", nil
+			},
+		}, store, remoteRepo
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		h, _, _ := newHandler(t)
+		assert.Empty(t, h.codeOwnerReviewers(ctx, "feature", span, false))
+	})
+
+	t.Run("ExpandTeams", func(t *testing.T) {
+		h, _, remoteRepo := newHandler(t)
+		remoteRepo.MockTeamMemberLister.EXPECT().
+			ListTeamMembers(gomock.Any(), "acme/backend").
+			Return([]string{"alice", "bob"}, nil)
+		remoteRepo.MockTeamMemberLister.EXPECT().
+			ListTeamMembers(gomock.Any(), "acme/writers").
+			Return(nil, errors.New("great sadness"))
+		remoteRepo.MockViewerIdentifier.EXPECT().
+			ViewerLogin(gomock.Any()).
+			Return("Bob", nil)
+
+		got := h.codeOwnerReviewers(ctx, "feature", span, true)
+		assert.Equal(t, []string{"alice", "lead", "acme/writers"}, got)
+	})
+
+	t.Run("Overrides", func(t *testing.T) {
+		h, store, remoteRepo := newHandler(t)
+		remoteRepo.MockTeamMemberLister.EXPECT().
+			ListTeamMembers(gomock.Any(), "acme/backend").
+			Return([]string{"alice", "bob"}, nil)
+		remoteRepo.MockViewerIdentifier.EXPECT().
+			ViewerLogin(gomock.Any()).
+			Return("carol", nil)
+
+		enabled := true
+		require.NoError(t, store.SaveBranchOwners(ctx, "feature", &state.BranchOwners{
+			Enabled: &enabled,
+			Add:     []string{"dave"},
+			Skip:    []string{"@acme/writers", "alice"},
+		}))
+
+		got := h.codeOwnerReviewers(ctx, "feature", span, false)
+		assert.Equal(t, []string{"bob", "lead", "dave"}, got)
+	})
+
+	t.Run("DisabledForBranch", func(t *testing.T) {
+		h, store, _ := newHandler(t)
+
+		enabled := false
+		require.NoError(t, store.SaveBranchOwners(ctx, "feature", &state.BranchOwners{
+			Enabled: &enabled,
+		}))
+
+		assert.Empty(t, h.codeOwnerReviewers(ctx, "feature", span, true))
+	})
+}
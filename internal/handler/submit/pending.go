@@ -0,0 +1,320 @@
+package submit
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/spice"
+	"go.abhg.dev/gs/internal/spice/state"
+	"go.abhg.dev/gs/internal/tracing"
+)
+
+// queueSubmit records a submit operation for a branch
+// without contacting the remote or the forge.
+// The operation is replayed later by Flush,
+// or by the next submit that isn't offline.
+func (h *Handler) queueSubmit(
+	ctx context.Context,
+	branchToSubmit string,
+	branch *spice.LookupBranchResponse,
+	opts *submitOptions,
+) error {
+	head, err := h.Repository.PeelToCommit(ctx, branchToSubmit)
+	if err != nil {
+		return fmt.Errorf("peel to commit: %w", err)
+	}
+
+	// If the branch was pushed before,
+	// remember where its remote-tracking branch was
+	// so that we can tell if someone else pushed to it
+	// by the time the operation is replayed.
+	remoteHead := git.ZeroHash
+	if branch.UpstreamBranch != "" {
+		remote, err := h.Remote(ctx)
+		if err != nil {
+			return fmt.Errorf("get remote: %w", err)
+		}
+
+		if hash, err := h.Repository.PeelToCommit(ctx, remote+"/"+branch.UpstreamBranch); err == nil {
+			remoteHead = hash
+		}
+	}
+
+	if opts.DryRun {
+		h.Log.Infof("WOULD queue submit of %s", branchToSubmit)
+		return nil
+	}
+
+	pending := &state.PendingSubmit{
+		Branch:       branchToSubmit,
+		Head:         head,
+		RemoteHead:   remoteHead,
+		Queued:       time.Now(),
+		Title:        opts.Title,
+		Body:         opts.Body,
+		Base:         opts.Base,
+		Draft:        opts.Draft,
+		Fill:         opts.Fill,
+		NoPublish:    !opts.Publish,
+		Force:        opts.Force,
+		NoVerify:     opts.NoVerify,
+		Labels:       opts.Labels,
+		Reviewers:    opts.Reviewers,
+		Assignees:    opts.Assignees,
+		UpdateOnly:   opts.UpdateOnly != nil && *opts.UpdateOnly,
+		DraftDefault: opts.DraftDefault,
+		CodeOwners:   opts.CodeOwners,
+		PushComment:  opts.PushComment,
+		Copilot:      opts.Copilot,
+
+		NavComment:          opts.NavComment.String(),
+		NavCommentSync:      opts.NavCommentSync.String(),
+		NavCommentDownstack: opts.NavCommentDownstack.String(),
+		NavCommentMarker:    opts.NavCommentMarker,
+	}
+	if err := h.Store.SavePendingSubmit(ctx, pending); err != nil {
+		return fmt.Errorf("queue submit: %w", err)
+	}
+
+	h.Log.Infof("%v: Queued for submission. Run 'gs repo flush' when back online.", branchToSubmit)
+	return nil
+}
+
+// pendingOptions reconstructs the submit options
+// recorded for a queued operation.
+func pendingOptions(p *state.PendingSubmit) *submitOptions {
+	opts := Options{
+		Fill:                p.Fill,
+		Draft:               p.Draft,
+		Publish:             !p.NoPublish,
+		NavCommentMarker:    p.NavCommentMarker,
+		PushComment:         p.PushComment,
+		Force:               p.Force,
+		NoVerify:            p.NoVerify,
+		DraftDefault:        p.DraftDefault,
+		Labels:              p.Labels,
+		Reviewers:           p.Reviewers,
+		CodeOwners:          p.CodeOwners,
+		Copilot:             p.Copilot,
+		Assignees:           p.Assignees,
+		UpdateOnly:          &p.UpdateOnly,
+		NavComment:          NavCommentAlways,
+		NavCommentSync:      NavCommentSyncBranch,
+		NavCommentDownstack: NavCommentDownstackAll,
+	}
+
+	// These were recorded with their String forms,
+	// so invalid values can only come from a hand-edited store.
+	// Those fall back to the defaults.
+	_ = opts.NavComment.UnmarshalText([]byte(p.NavComment))
+	_ = opts.NavCommentSync.UnmarshalText([]byte(p.NavCommentSync))
+	_ = opts.NavCommentDownstack.UnmarshalText([]byte(p.NavCommentDownstack))
+
+	return &submitOptions{
+		Options: &opts,
+		Title:   p.Title,
+		Body:    p.Body,
+		Base:    p.Base,
+	}
+}
+
+// FlushRequest is a request to replay submit operations
+// that were queued while offline.
+type FlushRequest struct {
+	// DryRun reports the queued operations without replaying them.
+	DryRun bool
+}
+
+// errFlushConflict indicates that some queued operations
+// were not replayed because their remote branches changed.
+var errFlushConflict = errors.New("remote branches changed since submit was queued")
+
+// Flush replays submit operations that were queued with --offline.
+//
+// Each operation is replayed with the regular submit flow,
+// so branches that were already pushed or have change requests
+// are updated in place instead of being submitted again.
+// Operations are removed from the queue once they've been replayed.
+//
+// Operations for branches whose remote branch changed
+// since the operation was queued are left in the queue.
+func (h *Handler) Flush(ctx context.Context, req *FlushRequest) error {
+	ctx, span := tracing.Start(ctx, tracing.CategoryHandler, "flush")
+	defer span.End()
+
+	req = cmp.Or(req, &FlushRequest{})
+	pending, err := h.Store.ListPendingSubmits(ctx)
+	if err != nil {
+		return fmt.Errorf("list pending submits: %w", err)
+	}
+
+	if len(pending) == 0 {
+		h.Log.Infof("Nothing to flush: no queued submits")
+		return nil
+	}
+
+	if req.DryRun {
+		for _, p := range pending {
+			h.Log.Infof("WOULD submit %v (queued %v)", p.Branch, p.Queued.Local().Format(time.DateTime))
+		}
+		return nil
+	}
+
+	return h.replayPending(ctx, pending)
+}
+
+// flushBeforeSubmit replays queued submit operations
+// before an online submit.
+// Operations for branches that are about to be submitted
+// are left for that submit to supersede.
+//
+// Failures are reported as warnings
+// so that they don't block the submit.
+func (h *Handler) flushBeforeSubmit(ctx context.Context, branches []string) {
+	pending, err := h.Store.ListPendingSubmits(ctx)
+	if err != nil {
+		h.Log.Warn("Could not list queued submits", "error", err)
+		return
+	}
+
+	pending = slices.DeleteFunc(pending, func(p *state.PendingSubmit) bool {
+		return slices.Contains(branches, p.Branch)
+	})
+	if len(pending) == 0 {
+		return
+	}
+
+	h.Log.Infof("Replaying %d queued submit(s)", len(pending))
+	if err := h.replayPending(ctx, pending); err != nil {
+		h.Log.Warn("Could not replay queued submits", "error", err)
+		h.Log.Warn("Run 'gs repo flush' to try again.")
+	}
+}
+
+// clearPending removes the queued submit operation for a branch
+// after the branch was submitted online.
+func (h *Handler) clearPending(ctx context.Context, branch string) {
+	if err := h.Store.ClearPendingSubmit(ctx, branch); err != nil {
+		h.Log.Warn("Could not clear queued submit", "branch", branch, "error", err)
+	}
+}
+
+// navCommentOptions are the options that control
+// how navigation comments are updated.
+// Queued operations are grouped by these
+// when updating navigation comments after a replay.
+type navCommentOptions struct {
+	When      NavCommentWhen
+	Sync      NavCommentSync
+	Downstack NavCommentDownstack
+	Marker    string
+}
+
+func (h *Handler) replayPending(ctx context.Context, pending []*state.PendingSubmit) error {
+	remote, err := h.Remote(ctx)
+	if err != nil {
+		return fmt.Errorf("get remote: %w", err)
+	}
+
+	// Learn about anything pushed while we were offline
+	// so that we don't overwrite it.
+	if err := h.Repository.Fetch(ctx, git.FetchOptions{Remote: remote}); err != nil {
+		return fmt.Errorf("fetch %v: %w", remote, err)
+	}
+
+	var (
+		conflicts   []string
+		navGroups   []navCommentOptions
+		navBranches = make(map[navCommentOptions][]string)
+		submitErr   error
+	)
+	for _, p := range pending {
+		branch, err := h.Service.LookupBranch(ctx, p.Branch)
+		if err != nil {
+			if errors.Is(err, state.ErrNotExist) || errors.Is(err, git.ErrNotExist) {
+				h.Log.Infof("%v: Dropping queued submit: branch no longer exists or is not tracked", p.Branch)
+				h.clearPending(ctx, p.Branch)
+				continue
+			}
+			return fmt.Errorf("lookup branch %v: %w", p.Branch, err)
+		}
+
+		if branch.Head != p.Head {
+			h.Log.Infof("%v: Branch changed after the submit was queued. Submitting its current state.", p.Branch)
+		}
+
+		if !p.Force && branch.UpstreamBranch != "" {
+			remoteBranch := remote + "/" + branch.UpstreamBranch
+			remoteHead, err := h.Repository.PeelToCommit(ctx, remoteBranch)
+			if err != nil {
+				remoteHead = git.ZeroHash
+			}
+
+			// The remote branch moved since the submit was queued.
+			// That's fine only if we already have those commits
+			// (e.g. a previous flush pushed them).
+			if remoteHead != p.RemoteHead && remoteHead != git.ZeroHash &&
+				!h.Repository.IsAncestor(ctx, remoteHead, branch.Head) {
+				h.Log.Errorf("%v: %v changed after the submit was queued (%v -> %v).",
+					p.Branch, remoteBranch, p.RemoteHead.Short(), remoteHead.Short())
+				h.Log.Errorf("%v: Incorporate the remote changes and submit again, or resubmit with --force to overwrite them:", p.Branch)
+				h.Log.Errorf("  gs branch submit --branch=%s --force", p.Branch)
+				conflicts = append(conflicts, p.Branch)
+				continue
+			}
+		}
+
+		opts := pendingOptions(p)
+		status, err := h.submitBranch(ctx, p.Branch, opts)
+		if err != nil {
+			// Stop at the first failure:
+			// queued branches are often stacked on each other,
+			// and the forge may still be unreachable.
+			submitErr = fmt.Errorf("submit %v: %w", p.Branch, err)
+			break
+		}
+		h.clearPending(ctx, p.Branch)
+
+		if status.Submitted {
+			nav := navCommentOptions{
+				When:      opts.NavComment,
+				Sync:      opts.NavCommentSync,
+				Downstack: opts.NavCommentDownstack,
+				Marker:    opts.NavCommentMarker,
+			}
+			if _, ok := navBranches[nav]; !ok {
+				navGroups = append(navGroups, nav)
+			}
+			navBranches[nav] = append(navBranches[nav], p.Branch)
+		}
+	}
+
+	for _, nav := range navGroups {
+		if err := updateNavigationComments(
+			ctx,
+			h.Store, h.Service, h.Log,
+			nav.When,
+			nav.Sync,
+			nav.Downstack,
+			nav.Marker,
+			navBranches[nav],
+			h.RemoteRepository,
+		); err != nil {
+			return fmt.Errorf("update navigation comments: %w", err)
+		}
+	}
+
+	if submitErr != nil {
+		return submitErr
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("%v: %w", strings.Join(conflicts, ", "), errFlushConflict)
+	}
+	return nil
+}
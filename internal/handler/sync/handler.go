@@ -17,10 +17,12 @@ import (
 	"go.abhg.dev/gs/internal/git"
 	"go.abhg.dev/gs/internal/graph"
 	branchdel "go.abhg.dev/gs/internal/handler/delete"
+	"go.abhg.dev/gs/internal/hook"
 	"go.abhg.dev/gs/internal/must"
 	"go.abhg.dev/gs/internal/silog"
 	"go.abhg.dev/gs/internal/spice"
 	"go.abhg.dev/gs/internal/spice/state"
+	"go.abhg.dev/gs/internal/tracing"
 	"go.abhg.dev/gs/internal/ui"
 )
 
@@ -91,6 +93,10 @@ type Handler struct {
 	// SkipRebaseOnDelete updates upstack branches to new bases
 	// without rebasing while deleting merged branches.
 	SkipRebaseOnDelete bool
+
+	// Hooks runs hooks after syncing.
+	// If nil, no hooks run.
+	Hooks *hook.Runner
 }
 
 // ClosedChanges specifies how to handle closed Change Requests.
@@ -162,6 +168,9 @@ type TrunkOptions struct {
 // It also detects other tracked branches that have been merged upstream
 // and deletes them. (TODO: this should not be separated out.)
 func (h *Handler) SyncTrunk(ctx context.Context, opts *TrunkOptions) error {
+	ctx, span := tracing.Start(ctx, tracing.CategoryHandler, "sync trunk")
+	defer span.End()
+
 	log := h.Log
 	opts = cmp.Or(opts, &TrunkOptions{})
 	currentBranch, err := h.Worktree.CurrentBranch(ctx)
@@ -353,6 +362,17 @@ func (h *Handler) SyncTrunk(ctx context.Context, opts *TrunkOptions) error {
 		}
 	}
 
+	// Finished branches must be described for hooks
+	// before they're deleted.
+	var finished []hook.Branch
+	if h.Hooks.Enabled(hook.PostSync) {
+		names := make([]string, len(branchesToDelete))
+		for i, b := range branchesToDelete {
+			names[i] = b.BranchName
+		}
+		finished = h.Hooks.Describe(ctx, names)
+	}
+
 	if err := h.deleteBranches(ctx, branchesToDelete); err != nil {
 		return err
 	}
@@ -366,11 +386,13 @@ func (h *Handler) SyncTrunk(ctx context.Context, opts *TrunkOptions) error {
 		} else {
 			// TODO: if the merged branch leaves us on trunk
 			// --restack will end up restacking all known branches.
-			return h.Restack.RestackStack(ctx, currentBranch)
+			if err := h.Restack.RestackStack(ctx, currentBranch); err != nil {
+				return err
+			}
 		}
 	}
 
-	return nil
+	return h.Hooks.RunBranches(ctx, hook.PostSync, finished)
 }
 
 // findLocalMergedBranches finds branches that have been merged
@@ -383,6 +405,9 @@ func (h *Handler) findLocalMergedBranches(
 	knownBranches []spice.LoadBranchItem,
 	trunkHash git.Hash,
 ) ([]branchDeletion, error) {
+	ctx, span := tracing.Start(ctx, tracing.CategoryHandler, "find merged branches")
+	defer span.End()
+
 	// Find branches that have been merged by checking
 	// if they are reachable from the trunk.
 	var branchesToDelete []branchDeletion
@@ -404,6 +429,9 @@ func (h *Handler) findForgeFinishedBranches(
 	knownBranches []spice.LoadBranchItem,
 	closedChangeHandling ClosedChanges,
 ) ([]branchDeletion, error) {
+	ctx, span := tracing.Start(ctx, tracing.CategoryHandler, "find finished changes")
+	defer span.End()
+
 	type submittedBranch struct {
 		Name string
 
@@ -814,6 +842,9 @@ type branchDeletion struct {
 }
 
 func (h *Handler) deleteBranches(ctx context.Context, branchesToDelete []branchDeletion) error {
+	ctx, span := tracing.Start(ctx, tracing.CategoryHandler, "delete branches", "count", len(branchesToDelete))
+	defer span.End()
+
 	if len(branchesToDelete) == 0 {
 		return nil
 	}
@@ -0,0 +1,309 @@
+// Package hook runs user-configured commands
+// before and after git-spice operations.
+//
+// Hooks are found in two places:
+//
+//   - commands configured with 'spice.hook.<event>' in git-config,
+//     run with 'sh -c'; and
+//   - executables named after the event in the repository's
+//     .gitspice/hooks directory, if enabled.
+//
+// Each hook receives a JSON [Payload] on stdin
+// describing the branches affected by the operation.
+package hook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/silog"
+	"go.abhg.dev/gs/internal/spice"
+	"go.abhg.dev/gs/internal/xec"
+)
+
+// Dir is the directory, relative to the root of the worktree,
+// that holds repository hooks.
+const Dir = ".gitspice/hooks"
+
+// Event identifies the point in an operation at which hooks run.
+type Event string
+
+const (
+	// PreSubmit runs before branches are submitted.
+	// If it fails, nothing is submitted.
+	PreSubmit Event = "pre-submit"
+
+	// PostSubmit runs after branches were submitted.
+	PostSubmit Event = "post-submit"
+
+	// PreRestack runs before branches are restacked.
+	// If it fails, nothing is restacked.
+	PreRestack Event = "pre-restack"
+
+	// PostRestack runs after branches were restacked.
+	PostRestack Event = "post-restack"
+
+	// PostSync runs after the trunk branch was synced with the remote.
+	PostSync Event = "post-sync"
+
+	// BranchCreated runs after a branch was created.
+	BranchCreated Event = "branch-created"
+
+	// BranchDeleted runs after branches were deleted.
+	BranchDeleted Event = "branch-deleted"
+)
+
+// String returns the name of the event.
+func (e Event) String() string { return string(e) }
+
+// CanAbort reports whether hooks for this event
+// can abort the operation by failing.
+func (e Event) CanAbort() bool {
+	return e == PreSubmit || e == PreRestack
+}
+
+// Payload is the JSON object written to a hook's stdin.
+type Payload struct {
+	// Event is the event that the hook is running for.
+	Event Event `json:"event"`
+
+	// Branches are the branches affected by the operation.
+	Branches []Branch `json:"branches"`
+}
+
+// Branch is a branch affected by an operation.
+type Branch struct {
+	// Name is the name of the branch.
+	Name string `json:"name"`
+
+	// Base is the branch that this branch is stacked on.
+	// It's empty if the branch isn't tracked (e.g. after deletion).
+	Base string `json:"base,omitempty"`
+
+	// Head is the commit at the tip of the branch.
+	Head string `json:"head,omitempty"`
+
+	// Change is the change request submitted for the branch,
+	// or nil if the branch hasn't been submitted.
+	Change *Change `json:"change,omitempty"`
+}
+
+// Change is a change request submitted for a branch.
+type Change struct {
+	// Forge is the ID of the forge hosting the change.
+	Forge string `json:"forge"`
+
+	// ID identifies the change on the forge (e.g. "#123").
+	ID string `json:"id"`
+}
+
+// AbortError indicates that a hook failed
+// and the operation it ran before was not started.
+type AbortError struct {
+	Event Event  // required
+	Hook  string // required
+	Err   error  // required
+}
+
+func (e *AbortError) Error() string {
+	return fmt.Sprintf("%v hook %v: %v", e.Event, e.Hook, e.Err)
+}
+
+func (e *AbortError) Unwrap() error { return e.Err }
+
+// Config provides hook commands configured by the user.
+type Config interface {
+	// HookCommands returns shell commands to run for the given event
+	// in the order they should run.
+	HookCommands(event string) []string
+}
+
+var _ Config = (*spice.Config)(nil)
+
+// BranchLookup retrieves information about tracked branches.
+type BranchLookup interface {
+	LookupBranch(ctx context.Context, name string) (*spice.LookupBranchResponse, error)
+}
+
+var _ BranchLookup = (*spice.Service)(nil)
+
+// Runner runs hooks for events.
+//
+// A nil Runner runs no hooks.
+type Runner struct {
+	Log      *silog.Logger // required
+	Branches BranchLookup  // required
+
+	// RootDir is the root directory of the worktree.
+	// Hooks run from this directory.
+	RootDir string // required
+
+	// Config provides hook commands from configuration.
+	// If nil, no configured hooks run.
+	Config Config
+
+	// RepoHooks specifies whether to run hooks
+	// from the repository's .gitspice/hooks directory.
+	RepoHooks bool
+
+	// Stderr receives output from hooks.
+	// Defaults to os.Stderr.
+	Stderr io.Writer
+}
+
+// hookCommand is a single hook to run for an event.
+type hookCommand struct {
+	name string   // for error messages
+	args []string // command and arguments
+}
+
+func (r *Runner) hooks(event Event) []hookCommand {
+	if r == nil {
+		return nil
+	}
+
+	var hooks []hookCommand
+	if r.RepoHooks {
+		path := filepath.Join(r.RootDir, filepath.FromSlash(Dir), string(event))
+		if isExecutable(path) {
+			hooks = append(hooks, hookCommand{
+				name: filepath.ToSlash(filepath.Join(Dir, string(event))),
+				args: []string{path},
+			})
+		}
+	}
+
+	if r.Config != nil {
+		for _, cmd := range r.Config.HookCommands(string(event)) {
+			hooks = append(hooks, hookCommand{
+				name: fmt.Sprintf("%q", cmd),
+				args: []string{"sh", "-c", cmd},
+			})
+		}
+	}
+
+	return hooks
+}
+
+// Enabled reports whether any hooks will run for the given event.
+// Use this to skip expensive work to build a payload.
+func (r *Runner) Enabled(event Event) bool {
+	return len(r.hooks(event)) > 0
+}
+
+// Run runs hooks for the given event
+// with a payload describing the named branches.
+//
+// For events that can abort operations,
+// Run returns an [*AbortError] if a hook fails,
+// and doesn't run the remaining hooks.
+// Failures of other hooks are logged and otherwise ignored.
+func (r *Runner) Run(ctx context.Context, event Event, branches []string) error {
+	if !r.Enabled(event) {
+		return nil
+	}
+	return r.RunBranches(ctx, event, r.Describe(ctx, branches))
+}
+
+// RunBranches is a variant of [Runner.Run]
+// for branches that have already been described,
+// e.g. because they no longer exist.
+func (r *Runner) RunBranches(ctx context.Context, event Event, branches []Branch) error {
+	hooks := r.hooks(event)
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(Payload{
+		Event:    event,
+		Branches: append([]Branch{}, branches...), // never null
+	})
+	if err != nil {
+		return fmt.Errorf("encode hook payload: %w", err)
+	}
+
+	stderr := r.Stderr
+	if stderr == nil {
+		stderr = os.Stderr
+	}
+
+	for _, h := range hooks {
+		r.Log.Debug("Running hook", "event", event, "hook", h.name)
+		err := xec.Command(ctx, r.Log, h.args[0], h.args[1:]...).
+			WithDir(r.RootDir).
+			WithStdin(bytes.NewReader(payload)).
+			WithStdout(stderr).
+			WithStderr(stderr).
+			AppendEnv("GIT_SPICE_HOOK=" + string(event)).
+			Run()
+		if err == nil {
+			continue
+		}
+
+		if event.CanAbort() {
+			return &AbortError{Event: event, Hook: h.name, Err: err}
+		}
+		r.Log.Warn("Hook failed", "event", event, "hook", h.name, "error", err)
+	}
+
+	return nil
+}
+
+// Describe builds descriptions of the named branches for a payload.
+// Branches that aren't tracked are described by name only.
+func (r *Runner) Describe(ctx context.Context, names []string) []Branch {
+	if r == nil {
+		return nil
+	}
+
+	branches := make([]Branch, 0, len(names))
+	for _, name := range names {
+		b := Branch{Name: name}
+		res, err := r.Branches.LookupBranch(ctx, name)
+		if err != nil {
+			r.Log.Debug("Could not describe branch for hook", "branch", name, "error", err)
+			branches = append(branches, b)
+			continue
+		}
+
+		b.Base = res.Base
+		b.Head = res.Head.String()
+		b.Change = NewChange(res.Change)
+		branches = append(branches, b)
+	}
+	return branches
+}
+
+// NewChange describes a change for a payload.
+// It returns nil if the change is nil.
+func NewChange(md forge.ChangeMetadata) *Change {
+	if md == nil {
+		return nil
+	}
+	return &Change{
+		Forge: md.ForgeID(),
+		ID:    md.ChangeID().String(),
+	}
+}
+
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return false
+	}
+	return runtime.GOOS == "windows" || info.Mode().Perm()&0o111 != 0
+}
+
+// IsAbort reports whether err was caused by a hook aborting an operation.
+func IsAbort(err error) bool {
+	var abortErr *AbortError
+	return errors.As(err, &abortErr)
+}
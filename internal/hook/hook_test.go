@@ -0,0 +1,173 @@
+package hook_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/hook"
+	"go.abhg.dev/gs/internal/silog/silogtest"
+	"go.abhg.dev/gs/internal/spice"
+)
+
+type mapConfig map[string][]string
+
+func (c mapConfig) HookCommands(event string) []string {
+	return c[event]
+}
+
+type fakeBranches map[string]*spice.LookupBranchResponse
+
+func (b fakeBranches) LookupBranch(_ context.Context, name string) (*spice.LookupBranchResponse, error) {
+	if res, ok := b[name]; ok {
+		return res, nil
+	}
+	return nil, errors.New("not tracked")
+}
+
+func skipOnWindows(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook tests use sh")
+	}
+}
+
+func TestRunner_payload(t *testing.T) {
+	skipOnWindows(t)
+
+	dir := t.TempDir()
+	runner := &hook.Runner{
+		Log: silogtest.New(t),
+		Branches: fakeBranches{
+			"feat1": {Base: "main", Head: git.Hash("abc")},
+		},
+		RootDir: dir,
+		Config: mapConfig{
+			"post-restack": {`cat > payload.json; echo "$GIT_SPICE_HOOK" > event`},
+		},
+	}
+
+	require.True(t, runner.Enabled(hook.PostRestack))
+	assert.False(t, runner.Enabled(hook.PostSubmit))
+
+	err := runner.Run(t.Context(), hook.PostRestack, []string{"feat1", "feat2"})
+	require.NoError(t, err)
+
+	event, err := os.ReadFile(filepath.Join(dir, "event"))
+	require.NoError(t, err)
+	assert.Equal(t, "post-restack\n", string(event))
+
+	data, err := os.ReadFile(filepath.Join(dir, "payload.json"))
+	require.NoError(t, err)
+
+	var got hook.Payload
+	require.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, hook.Payload{
+		Event: hook.PostRestack,
+		Branches: []hook.Branch{
+			{Name: "feat1", Base: "main", Head: "abc"},
+			{Name: "feat2"},
+		},
+	}, got)
+}
+
+func TestRunner_abort(t *testing.T) {
+	skipOnWindows(t)
+
+	dir := t.TempDir()
+	var stderr bytes.Buffer
+	runner := &hook.Runner{
+		Log:      silogtest.New(t),
+		Branches: fakeBranches{},
+		RootDir:  dir,
+		Config: mapConfig{
+			"pre-submit": {"echo nope >&2; exit 1", "touch ran"},
+		},
+		Stderr: &stderr,
+	}
+
+	err := runner.Run(t.Context(), hook.PreSubmit, []string{"feat1"})
+	require.Error(t, err)
+	assert.True(t, hook.IsAbort(err))
+	assert.ErrorContains(t, err, "pre-submit hook")
+	assert.Equal(t, "nope\n", stderr.String())
+
+	assert.NoFileExists(t, filepath.Join(dir, "ran"),
+		"hooks after a failed pre- hook must not run")
+}
+
+func TestRunner_postFailureIgnored(t *testing.T) {
+	skipOnWindows(t)
+
+	dir := t.TempDir()
+	runner := &hook.Runner{
+		Log:      silogtest.New(t),
+		Branches: fakeBranches{},
+		RootDir:  dir,
+		Config: mapConfig{
+			"post-submit": {"exit 1", "touch ran"},
+		},
+	}
+
+	err := runner.RunBranches(t.Context(), hook.PostSubmit, []hook.Branch{{Name: "feat1"}})
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(dir, "ran"))
+}
+
+func TestRunner_repoHooks(t *testing.T) {
+	skipOnWindows(t)
+
+	dir := t.TempDir()
+	hooksDir := filepath.Join(dir, filepath.FromSlash(hook.Dir))
+	require.NoError(t, os.MkdirAll(hooksDir, 0o755))
+	require.NoError(t, os.WriteFile(
+		filepath.Join(hooksDir, "branch-created"),
+		[]byte("#!/bin/sh\necho repo >> order\n"),
+		0o755,
+	))
+
+	newRunner := func(repoHooks bool) *hook.Runner {
+		return &hook.Runner{
+			Log:      silogtest.New(t),
+			Branches: fakeBranches{},
+			RootDir:  dir,
+			Config: mapConfig{
+				"branch-created": {"echo config >> order"},
+			},
+			RepoHooks: repoHooks,
+		}
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		require.NoError(t, newRunner(false).Run(t.Context(), hook.BranchCreated, []string{"feat1"}))
+
+		got, err := os.ReadFile(filepath.Join(dir, "order"))
+		require.NoError(t, err)
+		assert.Equal(t, "config\n", string(got))
+		require.NoError(t, os.Remove(filepath.Join(dir, "order")))
+	})
+
+	t.Run("Enabled", func(t *testing.T) {
+		require.NoError(t, newRunner(true).Run(t.Context(), hook.BranchCreated, []string{"feat1"}))
+
+		got, err := os.ReadFile(filepath.Join(dir, "order"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"repo", "config"}, strings.Fields(string(got)))
+	})
+}
+
+func TestRunner_nil(t *testing.T) {
+	var runner *hook.Runner
+	assert.False(t, runner.Enabled(hook.PreSubmit))
+	assert.NoError(t, runner.Run(t.Context(), hook.PreSubmit, []string{"feat1"}))
+	assert.NoError(t, runner.RunBranches(t.Context(), hook.PostSync, nil))
+	assert.Nil(t, runner.Describe(t.Context(), []string{"feat1"}))
+}
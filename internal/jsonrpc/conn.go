@@ -0,0 +1,286 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+
+	"go.abhg.dev/gs/internal/silog"
+)
+
+// Handler handles requests and notifications received by a [Conn].
+//
+// For requests, the returned value is marshaled into the response.
+// For notifications, it's discarded.
+type Handler interface {
+	Handle(ctx context.Context, method string, params json.RawMessage) (any, error)
+}
+
+// HandlerFunc adapts a function into a [Handler].
+type HandlerFunc func(ctx context.Context, method string, params json.RawMessage) (any, error)
+
+var _ Handler = HandlerFunc(nil)
+
+// Handle calls f.
+func (f HandlerFunc) Handle(ctx context.Context, method string, params json.RawMessage) (any, error) {
+	return f(ctx, method, params)
+}
+
+// ErrClosed is returned by [Conn.Call] if the connection was closed
+// before a response was received.
+var ErrClosed = errors.New("connection closed")
+
+// ConnOptions customizes a [Conn].
+type ConnOptions struct {
+	// Log receives debug information about the connection.
+	// Defaults to discarding all messages.
+	Log *silog.Logger
+}
+
+// Conn is a JSON-RPC connection.
+//
+// Incoming requests are handled one at a time, in the order they arrive.
+// While a request is being handled,
+// the handler may issue requests to the other end with [Conn.Call]:
+// their responses are read concurrently.
+type Conn struct {
+	r   *messageReader
+	w   *messageWriter
+	log *silog.Logger
+
+	mu      sync.Mutex
+	nextID  int64
+	pending map[string]chan *message // by request ID
+	closed  bool
+}
+
+// NewConn builds a connection that reads messages from r
+// and writes messages to w.
+func NewConn(r io.Reader, w io.Writer, opts *ConnOptions) *Conn {
+	if opts == nil {
+		opts = &ConnOptions{}
+	}
+	log := opts.Log
+	if log == nil {
+		log = silog.Nop()
+	}
+
+	return &Conn{
+		r:       newMessageReader(r),
+		w:       newMessageWriter(w),
+		log:     log,
+		pending: make(map[string]chan *message),
+	}
+}
+
+// Serve reads messages from the connection and dispatches them
+// until the input is exhausted or the context is canceled.
+//
+// Serve returns nil if the input ended cleanly,
+// or if the context was canceled.
+func (c *Conn) Serve(ctx context.Context, h Handler) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Requests are queued for a single worker
+	// so that the reader can keep dispatching responses
+	// to calls made by the handler.
+	requests := make(chan *message, 16)
+	readErr := make(chan error, 1)
+	go func() {
+		defer close(requests)
+		err := c.readLoop(ctx, requests)
+		// Fail calls waiting for responses that will never arrive
+		// so that the handler making them can return.
+		c.close()
+		readErr <- err
+	}()
+
+	for {
+		// Stop as soon as the context is canceled,
+		// even if there are requests waiting to be handled.
+		if ctx.Err() != nil {
+			c.close()
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			c.close()
+			return nil
+
+		case req, ok := <-requests:
+			if !ok {
+				err := <-readErr
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+				return err
+			}
+			c.handle(ctx, h, req)
+		}
+	}
+}
+
+func (c *Conn) readLoop(ctx context.Context, requests chan<- *message) error {
+	for {
+		msg, err := c.r.Read()
+		if err != nil {
+			var rpcErr *Error
+			if errors.As(err, &rpcErr) {
+				// The message was framed correctly but isn't valid JSON.
+				// Report it and keep going.
+				c.log.Debug("Dropping malformed message", "error", err)
+				_ = c.w.Write(&message{ID: json.RawMessage("null"), Error: rpcErr})
+				continue
+			}
+			return err
+		}
+
+		if !msg.isRequest() {
+			c.dispatchResponse(msg)
+			continue
+		}
+
+		select {
+		case requests <- msg:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (c *Conn) dispatchResponse(msg *message) {
+	key := string(msg.ID)
+	c.mu.Lock()
+	ch, ok := c.pending[key]
+	delete(c.pending, key)
+	c.mu.Unlock()
+
+	if !ok {
+		c.log.Debug("Dropping response to unknown request", "id", key)
+		return
+	}
+	ch <- msg
+}
+
+func (c *Conn) handle(ctx context.Context, h Handler, req *message) {
+	result, err := h.Handle(ctx, req.Method, req.Params)
+	if req.isNotification() {
+		if err != nil {
+			c.log.Debug("Notification handler failed", "method", req.Method, "error", err)
+		}
+		return
+	}
+
+	res := message{ID: req.ID}
+	if err != nil {
+		var rpcErr *Error
+		if !errors.As(err, &rpcErr) {
+			rpcErr = &Error{Code: CodeInternalError, Message: err.Error()}
+		}
+		res.Error = rpcErr
+	} else {
+		res.Result, err = json.Marshal(result)
+		if err != nil {
+			res.Result = nil
+			res.Error = Errorf(CodeInternalError, "marshal result: %v", err)
+		}
+	}
+
+	if err := c.w.Write(&res); err != nil {
+		c.log.Debug("Could not write response", "method", req.Method, "error", err)
+	}
+}
+
+// Notify sends a notification to the other end.
+func (c *Conn) Notify(method string, params any) error {
+	bs, err := marshalParams(params)
+	if err != nil {
+		return err
+	}
+	return c.w.Write(&message{Method: method, Params: bs})
+}
+
+// Call sends a request to the other end and waits for its response.
+// The result is unmarshaled into result if it's non-nil.
+//
+// If the other end responds with an error, Call returns an *Error.
+func (c *Conn) Call(ctx context.Context, method string, params, result any) error {
+	bs, err := marshalParams(params)
+	if err != nil {
+		return err
+	}
+
+	ch := make(chan *message, 1)
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return ErrClosed
+	}
+	c.nextID++
+	id := json.RawMessage(strconv.FormatInt(c.nextID, 10))
+	c.pending[string(id)] = ch
+	c.mu.Unlock()
+
+	if err := c.w.Write(&message{ID: id, Method: method, Params: bs}); err != nil {
+		c.forget(id)
+		return fmt.Errorf("send request: %w", err)
+	}
+
+	select {
+	case <-ctx.Done():
+		c.forget(id)
+		return ctx.Err()
+
+	case res, ok := <-ch:
+		if !ok {
+			return ErrClosed
+		}
+		if res.Error != nil {
+			return res.Error
+		}
+		if result == nil || len(res.Result) == 0 {
+			return nil
+		}
+		if err := json.Unmarshal(res.Result, result); err != nil {
+			return fmt.Errorf("unmarshal result: %w", err)
+		}
+		return nil
+	}
+}
+
+// marshalParams marshals request parameters.
+// Nil parameters are omitted from the request.
+func marshalParams(params any) (json.RawMessage, error) {
+	if params == nil {
+		return nil, nil
+	}
+	bs, err := json.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("marshal params: %w", err)
+	}
+	return bs, nil
+}
+
+func (c *Conn) forget(id json.RawMessage) {
+	c.mu.Lock()
+	delete(c.pending, string(id))
+	c.mu.Unlock()
+}
+
+// close fails all pending calls.
+func (c *Conn) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closed = true
+	for id, ch := range c.pending {
+		close(ch)
+		delete(c.pending, id)
+	}
+}
@@ -0,0 +1,251 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testPeer is the other end of a connection under test.
+type testPeer struct {
+	t *testing.T
+	r *messageReader
+	w *messageWriter
+
+	in io.Closer // closes the server's input
+}
+
+// newTestConn starts serving h on a connection
+// and returns the client end of it.
+// The returned channel receives the result of Serve.
+func newTestConn(t *testing.T, h Handler) (*testPeer, <-chan error) {
+	serverIn, clientOut := io.Pipe()
+	clientIn, serverOut := io.Pipe()
+	t.Cleanup(func() {
+		_ = clientOut.Close()
+		_ = serverOut.Close()
+	})
+
+	conn := NewConn(serverIn, serverOut, nil)
+	done := make(chan error, 1)
+	go func() {
+		done <- conn.Serve(t.Context(), h)
+	}()
+
+	return &testPeer{
+		t:  t,
+		r:  newMessageReader(clientIn),
+		w:  newMessageWriter(clientOut),
+		in: clientOut,
+	}, done
+}
+
+func (p *testPeer) send(msg string) {
+	var m message
+	require.NoError(p.t, json.Unmarshal([]byte(msg), &m))
+	require.NoError(p.t, p.w.Write(&m))
+}
+
+func (p *testPeer) recv() *message {
+	msg, err := p.r.Read()
+	require.NoError(p.t, err)
+	return msg
+}
+
+func TestConn_Request(t *testing.T) {
+	peer, done := newTestConn(t, HandlerFunc(func(_ context.Context, method string, params json.RawMessage) (any, error) {
+		switch method {
+		case "add":
+			var args []int
+			if err := json.Unmarshal(params, &args); err != nil {
+				return nil, Errorf(CodeInvalidParams, "bad params: %v", err)
+			}
+			return args[0] + args[1], nil
+		case "fail":
+			return nil, errors.New("great sadness")
+		default:
+			return nil, Errorf(CodeMethodNotFound, "unknown method: %v", method)
+		}
+	}))
+
+	peer.send(`{"id": 1, "method": "add", "params": [1, 2]}`)
+	res := peer.recv()
+	assert.JSONEq(t, `1`, string(res.ID))
+	assert.JSONEq(t, `3`, string(res.Result))
+	assert.Nil(t, res.Error)
+
+	peer.send(`{"id": "two", "method": "nope"}`)
+	res = peer.recv()
+	assert.JSONEq(t, `"two"`, string(res.ID))
+	require.NotNil(t, res.Error)
+	assert.Equal(t, CodeMethodNotFound, res.Error.Code)
+
+	peer.send(`{"id": 3, "method": "fail"}`)
+	res = peer.recv()
+	require.NotNil(t, res.Error)
+	assert.Equal(t, CodeInternalError, res.Error.Code)
+	assert.Equal(t, "great sadness", res.Error.Message)
+
+	require.NoError(t, peer.in.Close())
+	require.NoError(t, <-done)
+}
+
+func TestConn_Notification(t *testing.T) {
+	got := make(chan string, 1)
+	peer, done := newTestConn(t, HandlerFunc(func(_ context.Context, method string, _ json.RawMessage) (any, error) {
+		got <- method
+		return "ignored", nil
+	}))
+
+	peer.send(`{"method": "ping"}`)
+	assert.Equal(t, "ping", <-got)
+
+	// No response is sent for notifications,
+	// so the next message is the response to this request.
+	peer.send(`{"id": 1, "method": "pong"}`)
+	assert.Equal(t, "pong", <-got)
+	res := peer.recv()
+	assert.JSONEq(t, `1`, string(res.ID))
+
+	require.NoError(t, peer.in.Close())
+	require.NoError(t, <-done)
+}
+
+func TestConn_CallDuringRequest(t *testing.T) {
+	var conn *Conn
+	serverIn, clientOut := io.Pipe()
+	clientIn, serverOut := io.Pipe()
+	t.Cleanup(func() {
+		_ = clientOut.Close()
+		_ = serverOut.Close()
+	})
+	conn = NewConn(serverIn, serverOut, nil)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- conn.Serve(t.Context(), HandlerFunc(func(ctx context.Context, _ string, _ json.RawMessage) (any, error) {
+			var name string
+			if err := conn.Call(ctx, "askName", map[string]string{"prompt": "Name?"}, &name); err != nil {
+				return nil, err
+			}
+			return "hello " + name, nil
+		}))
+	}()
+
+	peer := &testPeer{t: t, r: newMessageReader(clientIn), w: newMessageWriter(clientOut)}
+	peer.send(`{"id": 1, "method": "greet"}`)
+
+	call := peer.recv()
+	assert.Equal(t, "askName", call.Method)
+	assert.JSONEq(t, `{"prompt": "Name?"}`, string(call.Params))
+	peer.send(fmt.Sprintf(`{"id": %s, "result": "alice"}`, call.ID))
+
+	res := peer.recv()
+	assert.JSONEq(t, `"hello alice"`, string(res.Result))
+
+	require.NoError(t, clientOut.Close())
+	require.NoError(t, <-done)
+}
+
+func TestConn_CallErrorResponse(t *testing.T) {
+	var conn *Conn
+	serverIn, clientOut := io.Pipe()
+	clientIn, serverOut := io.Pipe()
+	t.Cleanup(func() {
+		_ = clientOut.Close()
+		_ = serverOut.Close()
+	})
+	conn = NewConn(serverIn, serverOut, nil)
+
+	callErr := make(chan error, 1)
+	go func() {
+		_ = conn.Serve(t.Context(), HandlerFunc(func(ctx context.Context, _ string, _ json.RawMessage) (any, error) {
+			err := conn.Call(ctx, "confirm", nil, nil)
+			callErr <- err
+			return nil, err
+		}))
+	}()
+
+	peer := &testPeer{t: t, r: newMessageReader(clientIn), w: newMessageWriter(clientOut)}
+	peer.send(`{"id": 1, "method": "work"}`)
+	call := peer.recv()
+	assert.Empty(t, call.Params, "nil params should be omitted")
+	peer.send(fmt.Sprintf(`{"id": %s, "error": {"code": 1, "message": "user canceled"}}`, call.ID))
+
+	err := <-callErr
+	var rpcErr *Error
+	require.ErrorAs(t, err, &rpcErr)
+	assert.Equal(t, "user canceled", rpcErr.Message)
+
+	res := peer.recv()
+	require.NotNil(t, res.Error)
+	assert.Equal(t, "user canceled", res.Error.Message)
+}
+
+func TestConn_CallAfterInputEnds(t *testing.T) {
+	var conn *Conn
+	serverIn, clientOut := io.Pipe()
+	clientIn, serverOut := io.Pipe()
+	t.Cleanup(func() {
+		_ = serverOut.Close()
+	})
+	go func() {
+		// Drain output so that writes don't block.
+		_, _ = io.Copy(io.Discard, clientIn)
+	}()
+	conn = NewConn(serverIn, serverOut, nil)
+
+	callErr := make(chan error, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- conn.Serve(t.Context(), HandlerFunc(func(ctx context.Context, _ string, _ json.RawMessage) (any, error) {
+			err := conn.Call(ctx, "confirm", nil, nil)
+			callErr <- err
+			return nil, err
+		}))
+	}()
+
+	w := newMessageWriter(clientOut)
+	require.NoError(t, w.Write(&message{ID: json.RawMessage("1"), Method: "work"}))
+	require.NoError(t, clientOut.Close())
+
+	// The client went away without answering.
+	assert.ErrorIs(t, <-callErr, ErrClosed)
+	require.NoError(t, <-done)
+}
+
+func TestConn_MalformedMessage(t *testing.T) {
+	input := "Content-Length: 5\r\n\r\n{oops" +
+		"Content-Length: 30\r\n\r\n" + `{"id": 1, "method": "hello"}  `
+	var out strings.Builder
+	conn := NewConn(strings.NewReader(input), &out, nil)
+	err := conn.Serve(t.Context(), HandlerFunc(func(context.Context, string, json.RawMessage) (any, error) {
+		return "world", nil
+	}))
+	require.NoError(t, err)
+
+	r := newMessageReader(strings.NewReader(out.String()))
+	parseErr, err := r.Read()
+	require.NoError(t, err)
+	require.NotNil(t, parseErr.Error)
+	assert.Equal(t, CodeParseError, parseErr.Error.Code)
+
+	res, err := r.Read()
+	require.NoError(t, err)
+	assert.JSONEq(t, `"world"`, string(res.Result))
+}
+
+func TestConn_BadFraming(t *testing.T) {
+	conn := NewConn(strings.NewReader("Content-Length: nope\r\n\r\n"), io.Discard, nil)
+	err := conn.Serve(t.Context(), HandlerFunc(func(context.Context, string, json.RawMessage) (any, error) {
+		return nil, nil
+	}))
+	assert.ErrorContains(t, err, "bad Content-Length")
+}
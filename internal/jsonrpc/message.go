@@ -0,0 +1,72 @@
+// Package jsonrpc implements a JSON-RPC 2.0 connection
+// over a pair of streams.
+//
+// Messages are framed with Content-Length headers
+// like the Language Server Protocol,
+// so clients that already speak LSP can talk to the server
+// without additional plumbing.
+//
+// Either end of a connection may send requests to the other:
+// servers may call back into the client while handling a request.
+package jsonrpc
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Version is the JSON-RPC version implemented by this package.
+const Version = "2.0"
+
+// Standard JSON-RPC error codes.
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+)
+
+// message is a single JSON-RPC message on the wire.
+// It may be a request, a notification, or a response.
+type message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// isRequest reports whether the message is a request or a notification.
+func (m *message) isRequest() bool {
+	return m.Method != ""
+}
+
+// isNotification reports whether the message is a request
+// that doesn't expect a response.
+func (m *message) isNotification() bool {
+	return m.Method != "" && len(m.ID) == 0
+}
+
+// Error is an error reported in a JSON-RPC response.
+//
+// Handlers may return an *Error to control the code
+// reported to the other end.
+// Other errors are reported as [CodeInternalError].
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    any    `json:"data,omitempty"`
+}
+
+var _ error = (*Error)(nil)
+
+// Errorf builds an *Error with the given code and message.
+func Errorf(code int, format string, args ...any) *Error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%v (code %d)", e.Message, e.Code)
+}
@@ -0,0 +1,86 @@
+package jsonrpc
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// _maxMessageSize is the largest message that will be read.
+// This guards against misbehaving clients.
+const _maxMessageSize = 64 << 20
+
+// messageReader reads Content-Length framed messages.
+type messageReader struct {
+	r *textproto.Reader
+}
+
+func newMessageReader(r io.Reader) *messageReader {
+	return &messageReader{r: textproto.NewReader(bufio.NewReader(r))}
+}
+
+// Read reads the next message.
+// It returns io.EOF if the stream ends between messages.
+func (mr *messageReader) Read() (*message, error) {
+	header, err := mr.r.ReadMIMEHeader()
+	if err != nil {
+		if errors.Is(err, io.EOF) && len(header) == 0 {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+
+	length, err := strconv.Atoi(strings.TrimSpace(header.Get("Content-Length")))
+	if err != nil {
+		return nil, fmt.Errorf("bad Content-Length %q: %w", header.Get("Content-Length"), err)
+	}
+	if length < 0 || length > _maxMessageSize {
+		return nil, fmt.Errorf("bad Content-Length: %d", length)
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(mr.r.R, body); err != nil {
+		return nil, fmt.Errorf("read body: %w", err)
+	}
+
+	var msg message
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return nil, &Error{Code: CodeParseError, Message: err.Error()}
+	}
+	return &msg, nil
+}
+
+// messageWriter writes Content-Length framed messages.
+// It's safe for concurrent use.
+type messageWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func newMessageWriter(w io.Writer) *messageWriter {
+	return &messageWriter{w: w}
+}
+
+func (mw *messageWriter) Write(msg *message) error {
+	msg.JSONRPC = Version
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("marshal message: %w", err)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "Content-Length: %d\r\n\r\n", len(body))
+	buf.Write(body)
+
+	mw.mu.Lock()
+	defer mw.mu.Unlock()
+	_, err = mw.w.Write(buf.Bytes())
+	return err
+}
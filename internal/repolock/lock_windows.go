@@ -0,0 +1,37 @@
+//go:build windows
+
+package repolock
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// Windows byte-range locks are mandatory:
+// other processes can't read a locked range.
+// Lock a byte far past the end of the file
+// so that the holder information stays readable.
+const (
+	_lockOffsetHigh = 0x7fffffff
+	_lockLen        = 1
+)
+
+func tryLock(f *os.File) error {
+	ol := windows.Overlapped{OffsetHigh: _lockOffsetHigh}
+	err := windows.LockFileEx(
+		windows.Handle(f.Fd()),
+		windows.LOCKFILE_EXCLUSIVE_LOCK|windows.LOCKFILE_FAIL_IMMEDIATELY,
+		0, _lockLen, 0, &ol,
+	)
+	if errors.Is(err, windows.ERROR_LOCK_VIOLATION) {
+		return errLocked
+	}
+	return err
+}
+
+func unlock(f *os.File) error {
+	ol := windows.Overlapped{OffsetHigh: _lockOffsetHigh}
+	return windows.UnlockFileEx(windows.Handle(f.Fd()), 0, _lockLen, 0, &ol)
+}
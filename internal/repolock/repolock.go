@@ -0,0 +1,164 @@
+// Package repolock implements an advisory, cross-process lock
+// on a repository.
+//
+// git-spice commands that change branches or their state
+// (e.g. restacking or submitting a stack, or amending a commit)
+// hold the lock for their duration
+// so that concurrent invocations (e.g. from an editor and a terminal,
+// possibly in different worktrees) don't interleave their changes.
+//
+// The lock is held with an OS-level file lock,
+// so it's released automatically if the holding process dies.
+package repolock
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// FileName is the name of the lock file
+// inside the repository's common Git directory.
+const FileName = "spice.lock"
+
+// Holder describes the process that holds the lock.
+type Holder struct {
+	// PID is the process ID of the holder.
+	PID int `json:"pid"`
+
+	// Command is the command that the holder is running,
+	// e.g. "gs repo sync".
+	Command string `json:"command"`
+
+	// Since is the time at which the lock was acquired.
+	Since time.Time `json:"since"`
+}
+
+// HeldError is returned by [Acquire]
+// if another process holds the lock.
+type HeldError struct {
+	// Holder is the process holding the lock.
+	// It is nil if the holder could not be determined.
+	Holder *Holder
+}
+
+func (e *HeldError) Error() string {
+	h := e.Holder
+	if h == nil || h.Command == "" {
+		return "repository is locked by another git-spice command"
+	}
+
+	msg := fmt.Sprintf("repository is locked by another git-spice command: %q (pid %d", h.Command, h.PID)
+	if !h.Since.IsZero() {
+		msg += fmt.Sprintf(", running for %v", time.Since(h.Since).Round(time.Second))
+	}
+	return msg + ")"
+}
+
+// errLocked is returned by tryLock if the file is locked
+// by another process.
+var errLocked = errors.New("file is locked")
+
+// Options configures [Acquire].
+type Options struct {
+	// Holder identifies this process to others that try to take the lock.
+	Holder Holder
+
+	// Wait is how long to wait for the lock
+	// if another process holds it.
+	// If zero, Acquire fails immediately.
+	Wait time.Duration
+
+	// PollInterval is how often to retry while waiting.
+	// Defaults to 50ms.
+	PollInterval time.Duration
+}
+
+// Lock is an acquired repository lock.
+type Lock struct {
+	f *os.File
+}
+
+// Acquire acquires the lock at the given path,
+// creating the file if needed.
+//
+// If another process holds the lock
+// and it isn't released within opts.Wait,
+// Acquire returns a [*HeldError] describing the holder.
+func Acquire(ctx context.Context, path string, opts Options) (*Lock, error) {
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = 50 * time.Millisecond
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open lock file: %w", err)
+	}
+
+	deadline := time.Now().Add(opts.Wait)
+	for {
+		err := tryLock(f)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, errLocked) {
+			return nil, errors.Join(fmt.Errorf("lock %v: %w", path, err), f.Close())
+		}
+
+		if !time.Now().Before(deadline) {
+			_ = f.Close()
+			return nil, &HeldError{Holder: readHolder(path)}
+		}
+
+		select {
+		case <-ctx.Done():
+			_ = f.Close()
+			return nil, ctx.Err()
+		case <-time.After(opts.PollInterval):
+		}
+	}
+
+	// The holder information is informational only,
+	// so failure to record it isn't fatal.
+	if bs, err := json.Marshal(opts.Holder); err == nil {
+		if err := f.Truncate(0); err == nil {
+			_, _ = f.WriteAt(bs, 0)
+		}
+	}
+
+	return &Lock{f: f}, nil
+}
+
+// Release releases the lock.
+// It is safe to call Release more than once.
+func (l *Lock) Release() error {
+	if l == nil || l.f == nil {
+		return nil
+	}
+
+	f := l.f
+	l.f = nil
+
+	// Clear the holder information before unlocking
+	// so that it isn't attributed to a stale holder.
+	_ = f.Truncate(0)
+	return errors.Join(unlock(f), f.Close())
+}
+
+// readHolder reads the holder information from the lock file.
+// It returns nil if the information is missing or malformed.
+func readHolder(path string) *Holder {
+	bs, err := os.ReadFile(path)
+	if err != nil || len(bs) == 0 {
+		return nil
+	}
+
+	var h Holder
+	if err := json.Unmarshal(bs, &h); err != nil {
+		return nil
+	}
+	return &h
+}
@@ -0,0 +1,76 @@
+package repolock
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcquire(t *testing.T) {
+	ctx := t.Context()
+	path := filepath.Join(t.TempDir(), FileName)
+
+	holder := Holder{
+		PID:     42,
+		Command: "gs repo sync",
+		Since:   time.Now(),
+	}
+	lock, err := Acquire(ctx, path, Options{Holder: holder})
+	require.NoError(t, err)
+
+	t.Run("Held", func(t *testing.T) {
+		_, err := Acquire(ctx, path, Options{})
+		require.Error(t, err)
+
+		var heldErr *HeldError
+		require.True(t, errors.As(err, &heldErr), "want HeldError, got %v", err)
+		require.NotNil(t, heldErr.Holder)
+		assert.Equal(t, 42, heldErr.Holder.PID)
+		assert.Equal(t, "gs repo sync", heldErr.Holder.Command)
+		assert.ErrorContains(t, err, `"gs repo sync" (pid 42`)
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+
+		_, err := Acquire(ctx, path, Options{Wait: time.Minute})
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	require.NoError(t, lock.Release())
+	require.NoError(t, lock.Release(), "release is idempotent")
+
+	lock, err = Acquire(ctx, path, Options{})
+	require.NoError(t, err)
+	require.NoError(t, lock.Release())
+}
+
+func TestAcquire_wait(t *testing.T) {
+	ctx := t.Context()
+	path := filepath.Join(t.TempDir(), FileName)
+
+	lock, err := Acquire(ctx, path, Options{})
+	require.NoError(t, err)
+
+	time.AfterFunc(100*time.Millisecond, func() {
+		assert.NoError(t, lock.Release())
+	})
+
+	second, err := Acquire(ctx, path, Options{
+		Wait:         10 * time.Second,
+		PollInterval: 10 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	assert.NoError(t, second.Release())
+}
+
+func TestHeldError_unknownHolder(t *testing.T) {
+	err := &HeldError{}
+	assert.Equal(t, "repository is locked by another git-spice command", err.Error())
+}
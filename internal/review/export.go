@@ -0,0 +1,229 @@
+package review
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// _sarifSchema is the JSON schema of the SARIF version we write.
+const _sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+// WriteJSON writes items to w as a stream of JSON objects,
+// one per line.
+func WriteJSON(w io.Writer, items []*Item) (retErr error) {
+	bufw := bufio.NewWriter(w)
+	defer func() {
+		if err := bufw.Flush(); err != nil && retErr == nil {
+			retErr = err
+		}
+	}()
+
+	enc := json.NewEncoder(bufw)
+	for _, it := range items {
+		v := jsonItem{
+			Kind:     it.Kind,
+			Branch:   it.Branch,
+			File:     it.File,
+			Level:    it.Level,
+			Outdated: it.Outdated,
+			Author:   it.Author,
+			Check:    it.Check,
+			Body:     it.Body,
+			URL:      it.URL,
+		}
+		if it.LineRange != [2]int{} {
+			v.StartLine = it.LineRange[0]
+			v.EndLine = it.LineRange[1]
+		}
+		if err := enc.Encode(v); err != nil {
+			return fmt.Errorf("encode item: %w", err)
+		}
+	}
+	return nil
+}
+
+type jsonItem struct {
+	Kind      Kind   `json:"kind"`
+	Branch    string `json:"branch,omitempty"`
+	File      string `json:"file,omitempty"`
+	StartLine int    `json:"startLine,omitempty"`
+	EndLine   int    `json:"endLine,omitempty"`
+	Outdated  bool   `json:"outdated,omitempty"`
+	Level     Level  `json:"level"`
+	Author    string `json:"author,omitempty"`
+	Check     string `json:"check,omitempty"`
+	Body      string `json:"body"`
+	URL       string `json:"url,omitempty"`
+}
+
+// WriteQuickfix writes items to w in the "file:line: message" format
+// understood by the quickfix lists of editors like Vim and Emacs.
+//
+// Items that are not attached to a file are skipped.
+// Items not anchored to lines are reported on line 1.
+func WriteQuickfix(w io.Writer, items []*Item) (retErr error) {
+	bufw := bufio.NewWriter(w)
+	defer func() {
+		if err := bufw.Flush(); err != nil && retErr == nil {
+			retErr = err
+		}
+	}()
+
+	for _, it := range items {
+		if it.File == "" {
+			continue
+		}
+
+		var msg strings.Builder
+		msg.WriteString(string(it.Level))
+		msg.WriteString(": ")
+		if source := itemSource(it); source != "" {
+			msg.WriteString("[" + source + "] ")
+		}
+		msg.WriteString(summarize(it))
+		if it.Outdated {
+			msg.WriteString(" (outdated)")
+		}
+
+		if _, err := fmt.Fprintf(bufw, "%s:%d: %s\n", it.File, max(it.LineRange[0], 1), msg.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// itemSource returns who reported an item:
+// the author of a review thread or the name of a check.
+func itemSource(it *Item) string {
+	if it.Check != "" {
+		return it.Check
+	}
+	return it.Author
+}
+
+// WriteSARIF writes items to w as a SARIF 2.1.0 log
+// that can be loaded into editors and code scanning tools.
+//
+// Review threads use the rule "review-thread",
+// and check annotations use the name of their check as the rule.
+func WriteSARIF(w io.Writer, items []*Item) error {
+	results := make([]sarifResult, 0, len(items))
+	for _, it := range items {
+		ruleID := "review-thread"
+		if it.Kind == KindCheckAnnotation {
+			ruleID = it.Check
+		}
+
+		result := sarifResult{
+			RuleID:          ruleID,
+			Level:           it.Level,
+			Message:         sarifMessage{Text: it.Body},
+			HostedViewerURI: it.URL,
+			Properties: &sarifProperties{
+				Branch:   it.Branch,
+				Author:   it.Author,
+				Outdated: it.Outdated,
+			},
+		}
+		if it.File != "" {
+			loc := sarifLocation{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: it.File},
+				},
+			}
+			if it.LineRange != [2]int{} {
+				loc.PhysicalLocation.Region = &sarifRegion{
+					StartLine: it.LineRange[0],
+					EndLine:   it.LineRange[1],
+				}
+			}
+			result.Locations = []sarifLocation{loc}
+		}
+		results = append(results, result)
+	}
+
+	log := sarifLog{
+		Schema:  _sarifSchema,
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool: sarifTool{
+				Driver: sarifDriver{
+					Name:           "git-spice",
+					InformationURI: "https://abhinav.github.io/git-spice/",
+				},
+			},
+			Results: results,
+		}},
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(log); err != nil {
+		return fmt.Errorf("encode SARIF: %w", err)
+	}
+	return nil
+}
+
+// Subset of the SARIF 2.1.0 format used by WriteSARIF.
+// See https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html.
+type (
+	sarifLog struct {
+		Schema  string     `json:"$schema"`
+		Version string     `json:"version"`
+		Runs    []sarifRun `json:"runs"`
+	}
+
+	sarifRun struct {
+		Tool    sarifTool     `json:"tool"`
+		Results []sarifResult `json:"results"`
+	}
+
+	sarifTool struct {
+		Driver sarifDriver `json:"driver"`
+	}
+
+	sarifDriver struct {
+		Name           string `json:"name"`
+		InformationURI string `json:"informationUri,omitempty"`
+	}
+
+	sarifResult struct {
+		RuleID          string           `json:"ruleId,omitempty"`
+		Level           Level            `json:"level"`
+		Message         sarifMessage     `json:"message"`
+		Locations       []sarifLocation  `json:"locations,omitempty"`
+		HostedViewerURI string           `json:"hostedViewerUri,omitempty"`
+		Properties      *sarifProperties `json:"properties,omitempty"`
+	}
+
+	sarifMessage struct {
+		Text string `json:"text"`
+	}
+
+	sarifLocation struct {
+		PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+	}
+
+	sarifPhysicalLocation struct {
+		ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+		Region           *sarifRegion          `json:"region,omitempty"`
+	}
+
+	sarifArtifactLocation struct {
+		URI string `json:"uri"`
+	}
+
+	sarifRegion struct {
+		StartLine int `json:"startLine"`
+		EndLine   int `json:"endLine,omitempty"`
+	}
+
+	sarifProperties struct {
+		Branch   string `json:"branch,omitempty"`
+		Author   string `json:"author,omitempty"`
+		Outdated bool   `json:"outdated,omitempty"`
+	}
+)
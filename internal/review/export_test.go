@@ -0,0 +1,120 @@
+package review_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/review"
+	"go.abhg.dev/gs/internal/text"
+)
+
+func testItems() []*review.Item {
+	return []*review.Item{
+		{
+			Kind:      review.KindReviewThread,
+			Branch:    "feature",
+			File:      "main.go",
+			LineRange: [2]int{10, 12},
+			Level:     review.LevelWarning,
+			Author:    "alice",
+			Body:      "Please rename\nthis function.",
+			URL:       "https://example.com/pr/1#thread-1",
+		},
+		{
+			Kind:      review.KindCheckAnnotation,
+			Branch:    "feature",
+			File:      "util.go",
+			LineRange: [2]int{3, 3},
+			Outdated:  true,
+			Level:     review.LevelError,
+			Check:     "lint",
+			Body:      "unused variable",
+		},
+		{
+			Kind:   review.KindReviewThread,
+			Branch: "feature",
+			Level:  review.LevelWarning,
+			Author: "bob",
+			Body:   "General comment.",
+		},
+	}
+}
+
+func TestWriteQuickfix(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, review.WriteQuickfix(&buf, testItems()))
+
+	assert.Equal(t, text.Dedent(`
+		main.go:10: warning: [alice] Please rename this function.
+		util.go:3: error: [lint] unused variable (outdated)
+	`)+"\n", buf.String())
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, review.WriteJSON(&buf, testItems()))
+
+	assert.Equal(t, text.Dedent(`
+		{"kind":"review","branch":"feature","file":"main.go","startLine":10,"endLine":12,"level":"warning","author":"alice","body":"Please rename\nthis function.","url":"https://example.com/pr/1#thread-1"}
+		{"kind":"check","branch":"feature","file":"util.go","startLine":3,"endLine":3,"outdated":true,"level":"error","check":"lint","body":"unused variable"}
+		{"kind":"review","branch":"feature","level":"warning","author":"bob","body":"General comment."}
+	`)+"\n", buf.String())
+}
+
+func TestWriteSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, review.WriteSARIF(&buf, testItems()))
+
+	var got struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Name string `json:"name"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []json.RawMessage `json:"results"`
+		} `json:"runs"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+	assert.Equal(t, "2.1.0", got.Version)
+	require.Len(t, got.Runs, 1)
+	assert.Equal(t, "git-spice", got.Runs[0].Tool.Driver.Name)
+
+	results := got.Runs[0].Results
+	require.Len(t, results, 3)
+	assert.JSONEq(t, `{
+		"ruleId": "review-thread",
+		"level": "warning",
+		"message": {"text": "Please rename\nthis function."},
+		"locations": [{
+			"physicalLocation": {
+				"artifactLocation": {"uri": "main.go"},
+				"region": {"startLine": 10, "endLine": 12}
+			}
+		}],
+		"hostedViewerUri": "https://example.com/pr/1#thread-1",
+		"properties": {"branch": "feature", "author": "alice"}
+	}`, string(results[0]))
+	assert.JSONEq(t, `{
+		"ruleId": "lint",
+		"level": "error",
+		"message": {"text": "unused variable"},
+		"locations": [{
+			"physicalLocation": {
+				"artifactLocation": {"uri": "util.go"},
+				"region": {"startLine": 3, "endLine": 3}
+			}
+		}],
+		"properties": {"branch": "feature", "outdated": true}
+	}`, string(results[1]))
+	assert.JSONEq(t, `{
+		"ruleId": "review-thread",
+		"level": "warning",
+		"message": {"text": "General comment."},
+		"properties": {"branch": "feature", "author": "bob"}
+	}`, string(results[2]))
+}
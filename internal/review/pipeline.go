@@ -1,5 +1,5 @@
-// Package review provides helpers for fetching and summarizing
-// PR review threads — used by `gs branch reviews`.
+// Package review provides helpers for fetching, summarizing,
+// and exporting PR review threads — used by `gs branch reviews`.
 package review
 
 import (
@@ -8,17 +8,62 @@ import (
 	"strings"
 
 	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/git"
 )
 
 // addressedRE permissively matches an "Addressed in <sha>" marker,
 // with or without a trailing ": <subject>".
 var addressedRE = regexp.MustCompile(`Addressed in [0-9a-f]{7,}\b`)
 
-// Item describes one open review thread for display purposes.
+// Kind is the kind of thing an Item describes.
+type Kind string
+
+// Supported item kinds.
+const (
+	KindReviewThread    Kind = "review"
+	KindCheckAnnotation Kind = "check"
+)
+
+// Level is the severity of an item.
+// The values match SARIF result levels.
+type Level string
+
+// Supported item levels.
+const (
+	LevelError   Level = "error"
+	LevelWarning Level = "warning"
+	LevelNote    Level = "note"
+)
+
+// Item describes one open review thread
+// or check annotation for display purposes.
 type Item struct {
-	File   string
+	Kind Kind
+
+	// Branch is the branch whose change the item was reported on.
+	Branch string
+
+	File string
+
+	// LineRange is the inclusive [start, end] line range in File.
+	// Both elements are 0 when the item is not anchored to lines.
+	LineRange [2]int
+
+	// Outdated reports whether the lines the item refers to
+	// were changed or removed locally,
+	// so LineRange is only an approximation.
+	Outdated bool
+
+	Level Level
+
+	// Author is the login of the reviewer who opened a review thread.
 	Author string
-	Body   string
+
+	// Check is the name of the check run that reported an annotation.
+	Check string
+
+	Body string
+	URL  string
 }
 
 // IsAlreadyAddressed reports whether the most recent reply on the
@@ -52,10 +97,80 @@ func PipelineForThreads(
 			continue
 		}
 		out = append(out, &Item{
-			File:   t.File,
-			Author: t.Author,
-			Body:   t.Body,
+			Kind:      KindReviewThread,
+			File:      t.File,
+			LineRange: t.LineRange,
+			Level:     LevelWarning,
+			Author:    t.Author,
+			Body:      t.Body,
+			URL:       t.URL,
 		})
 	}
 	return out
 }
+
+// ItemsForChecks returns the annotations of the given check runs as Items.
+func ItemsForChecks(checks []*forge.ChangeCheckItem) []*Item {
+	var out []*Item
+	for _, c := range checks {
+		for _, a := range c.Annotations {
+			body := a.Message
+			if a.Title != "" {
+				body = a.Title + ": " + body
+			}
+
+			level := LevelNote
+			switch a.Level {
+			case forge.CheckAnnotationFailure:
+				level = LevelError
+			case forge.CheckAnnotationWarning:
+				level = LevelWarning
+			}
+
+			out = append(out, &Item{
+				Kind:      KindCheckAnnotation,
+				File:      a.File,
+				LineRange: a.LineRange,
+				Level:     level,
+				Check:     c.Name,
+				Body:      body,
+				URL:       c.URL,
+			})
+		}
+	}
+	return out
+}
+
+// Remap updates the locations of items
+// from the version of the code they were reported on
+// to the version described by m.
+//
+// Items on lines that were changed or removed are marked Outdated
+// and moved to the closest line.
+// Items on deleted files are marked Outdated and left as-is.
+func Remap(items []*Item, m *git.LineMap) {
+	for _, it := range items {
+		if it.File == "" {
+			continue
+		}
+
+		if it.LineRange == [2]int{} {
+			if path, _, _ := m.MapLine(it.File, 1); path != "" {
+				it.File = path
+			} else {
+				it.Outdated = true
+			}
+			continue
+		}
+
+		path, start, startExact := m.MapLine(it.File, it.LineRange[0])
+		if path == "" {
+			it.Outdated = true
+			continue
+		}
+		_, end, endExact := m.MapLine(it.File, it.LineRange[1])
+		it.File = path
+		it.LineRange = [2]int{start, max(start, end)}
+		it.Outdated = it.Outdated || !startExact || !endExact
+	}
+}
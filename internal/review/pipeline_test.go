@@ -0,0 +1,117 @@
+package review_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/git/gittest"
+	"go.abhg.dev/gs/internal/review"
+	"go.abhg.dev/gs/internal/silog/silogtest"
+	"go.abhg.dev/gs/internal/text"
+)
+
+func TestItemsForChecks(t *testing.T) {
+	items := review.ItemsForChecks([]*forge.ChangeCheckItem{
+		{Name: "build"}, // no annotations
+		{
+			Name: "lint",
+			URL:  "https://example.com/checks/1",
+			Annotations: []*forge.CheckAnnotation{
+				{
+					File:      "a.go",
+					LineRange: [2]int{1, 2},
+					Level:     forge.CheckAnnotationFailure,
+					Title:     "errcheck",
+					Message:   "unchecked error",
+				},
+				{
+					File:    "b.go",
+					Level:   forge.CheckAnnotationNotice,
+					Message: "consider simplifying",
+				},
+			},
+		},
+	})
+
+	assert.Equal(t, []*review.Item{
+		{
+			Kind:      review.KindCheckAnnotation,
+			File:      "a.go",
+			LineRange: [2]int{1, 2},
+			Level:     review.LevelError,
+			Check:     "lint",
+			Body:      "errcheck: unchecked error",
+			URL:       "https://example.com/checks/1",
+		},
+		{
+			Kind:  review.KindCheckAnnotation,
+			File:  "b.go",
+			Level: review.LevelNote,
+			Check: "lint",
+			Body:  "consider simplifying",
+			URL:   "https://example.com/checks/1",
+		},
+	}, items)
+}
+
+func TestRemap(t *testing.T) {
+	fixture, err := gittest.LoadFixtureScript([]byte(text.Dedent(`
+		as 'Test <test@example.com>'
+		at '2025-06-21T10:00:00Z'
+
+		git init
+		git add main.go gone.go
+		git commit -m 'Initial commit'
+		git tag reviewed
+
+		cp $WORK/extra/main.go main.go
+		git rm gone.go
+		git commit -am 'Address comments'
+
+		-- main.go --
+		one
+		two
+		three
+		four
+		-- gone.go --
+		gone
+		-- extra/main.go --
+		zero
+		one
+		two
+		3
+		four
+	`)))
+	require.NoError(t, err)
+	t.Cleanup(fixture.Cleanup)
+
+	repo, err := git.Open(t.Context(), fixture.Dir(), git.OpenOptions{
+		Log: silogtest.New(t),
+	})
+	require.NoError(t, err)
+
+	lineMap, err := repo.DiffLineMap(t.Context(), "reviewed", "HEAD")
+	require.NoError(t, err)
+
+	items := []*review.Item{
+		{File: "main.go", LineRange: [2]int{1, 2}},
+		{File: "main.go", LineRange: [2]int{3, 3}},
+		{File: "main.go", LineRange: [2]int{4, 4}},
+		{File: "main.go"},
+		{File: "gone.go", LineRange: [2]int{1, 1}},
+		{Body: "no file"},
+	}
+	review.Remap(items, lineMap)
+
+	assert.Equal(t, []*review.Item{
+		{File: "main.go", LineRange: [2]int{2, 3}},
+		{File: "main.go", LineRange: [2]int{4, 4}, Outdated: true},
+		{File: "main.go", LineRange: [2]int{5, 5}},
+		{File: "main.go"},
+		{File: "gone.go", LineRange: [2]int{1, 1}, Outdated: true},
+		{Body: "no file"},
+	}, items)
+}
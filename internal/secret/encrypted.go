@@ -0,0 +1,321 @@
+package secret
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"go.abhg.dev/gs/internal/silog"
+)
+
+const (
+	_encryptedStashVersion = 1
+	_encryptedStashKDF     = "pbkdf2-sha256"
+	_encryptedStashKeySize = 32 // AES-256
+	_encryptedStashSaltLen = 16
+)
+
+// _encryptedStashIterations is the number of PBKDF2 iterations
+// used when creating a new encrypted secrets file.
+//
+// Existing files record the iteration count they were written with.
+// This is overridden in tests to keep them fast.
+var _encryptedStashIterations = 600_000
+
+// EncryptedStash is a secrets stash that stores secrets in a file
+// encrypted with a key derived from a passphrase or a key file.
+//
+// It is intended for systems where a system keyring is not available,
+// but secrets should not be stored in plain text.
+type EncryptedStash struct {
+	// Destination path to the encrypted secrets file.
+	Path string // required
+
+	// Passphrase is used to derive the encryption key.
+	//
+	// Exactly one of Passphrase or KeyFile must be set.
+	Passphrase string
+
+	// KeyFile is the path to a file holding the secret key
+	// used to derive the encryption key.
+	//
+	// Blank lines and lines starting with '#' are ignored,
+	// so age-style identity files may be used as-is.
+	//
+	// Exactly one of Passphrase or KeyFile must be set.
+	KeyFile string
+
+	// Log is the logger used by the stash.
+	Log *silog.Logger // required
+
+	mu   sync.Mutex
+	keys map[string][]byte // salt => derived key
+}
+
+var _ Stash = (*EncryptedStash)(nil)
+
+// encryptedStashFile is the on-disk representation
+// of the encrypted secrets file.
+type encryptedStashFile struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// keyMaterial returns the secret material
+// from which the encryption key is derived.
+func (f *EncryptedStash) keyMaterial() (string, error) {
+	switch {
+	case f.Passphrase != "" && f.KeyFile != "":
+		return "", errors.New("only one of passphrase or key file may be set")
+
+	case f.Passphrase != "":
+		return f.Passphrase, nil
+
+	case f.KeyFile != "":
+		bs, err := os.ReadFile(f.KeyFile)
+		if err != nil {
+			return "", fmt.Errorf("read key file: %w", err)
+		}
+
+		var key strings.Builder
+		for line := range strings.Lines(string(bs)) {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			key.WriteString(line)
+		}
+		if key.Len() == 0 {
+			return "", fmt.Errorf("key file %v is empty", f.KeyFile)
+		}
+		return key.String(), nil
+
+	default:
+		return "", errors.New("no passphrase or key file specified")
+	}
+}
+
+// deriveKey derives the encryption key for the given file parameters.
+// Derived keys are cached by salt for the lifetime of the stash.
+func (f *EncryptedStash) deriveKey(kdf string, iterations int, salt []byte) ([]byte, error) {
+	if kdf != _encryptedStashKDF {
+		return nil, fmt.Errorf("unsupported key derivation function: %q", kdf)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if key, ok := f.keys[string(salt)]; ok {
+		return key, nil
+	}
+
+	material, err := f.keyMaterial()
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := pbkdf2.Key(sha256.New, material, salt, iterations, _encryptedStashKeySize)
+	if err != nil {
+		return nil, fmt.Errorf("derive key: %w", err)
+	}
+
+	if f.keys == nil {
+		f.keys = make(map[string][]byte)
+	}
+	f.keys[string(salt)] = key
+	return key, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// load reads and decrypts the secrets file.
+// It returns the decrypted data and the file header
+// so that save can re-use the key derivation parameters.
+func (f *EncryptedStash) load() (*insecureStashData, *encryptedStashFile, error) {
+	bs, err := os.ReadFile(f.Path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return new(insecureStashData), nil, nil
+		}
+
+		return nil, nil, fmt.Errorf("read: %w", err)
+	}
+
+	var file encryptedStashFile
+	if err := json.Unmarshal(bs, &file); err != nil {
+		return nil, nil, fmt.Errorf("unmarshal: %w", err)
+	}
+	if file.Version != _encryptedStashVersion {
+		return nil, nil, fmt.Errorf("unsupported encrypted stash version: %d", file.Version)
+	}
+
+	key, err := f.deriveKey(file.KDF, file.Iterations, file.Salt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	plain, err := gcm.Open(nil, file.Nonce, file.Ciphertext, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("decrypt %v: wrong passphrase or key file?", f.Path)
+	}
+
+	var data insecureStashData
+	if err := json.Unmarshal(plain, &data); err != nil {
+		return nil, nil, fmt.Errorf("unmarshal secrets: %w", err)
+	}
+
+	return &data, &file, nil
+}
+
+// save encrypts and writes the secrets file.
+// prev holds the header of the existing file, if any.
+func (f *EncryptedStash) save(data *insecureStashData, prev *encryptedStashFile) error {
+	if data.empty() {
+		if err := os.Remove(f.Path); err != nil {
+			if !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("remove: %w", err)
+			}
+		}
+
+		return nil
+	}
+
+	file := encryptedStashFile{
+		Version:    _encryptedStashVersion,
+		KDF:        _encryptedStashKDF,
+		Iterations: _encryptedStashIterations,
+	}
+	if prev != nil {
+		// Re-use the existing salt and parameters
+		// so that we don't need to derive the key again.
+		file.KDF = prev.KDF
+		file.Iterations = prev.Iterations
+		file.Salt = prev.Salt
+	} else {
+		file.Salt = make([]byte, _encryptedStashSaltLen)
+		if _, err := rand.Read(file.Salt); err != nil {
+			return fmt.Errorf("generate salt: %w", err)
+		}
+	}
+
+	key, err := f.deriveKey(file.KDF, file.Iterations, file.Salt)
+	if err != nil {
+		return err
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+
+	plain, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("marshal secrets: %w", err)
+	}
+
+	file.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(file.Nonce); err != nil {
+		return fmt.Errorf("generate nonce: %w", err)
+	}
+	file.Ciphertext = gcm.Seal(nil, file.Nonce, plain, nil)
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(file); err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(f.Path), 0o700); err != nil {
+		return fmt.Errorf("mkdir: %w", err)
+	}
+
+	if prev == nil {
+		f.Log.Infof("Storing encrypted secrets at %s", f.Path)
+	}
+
+	if err := os.WriteFile(f.Path, buf.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("write: %w", err)
+	}
+
+	return nil
+}
+
+// SaveSecret stores a secret in the stash.
+func (f *EncryptedStash) SaveSecret(service, key, secret string) error {
+	data, file, err := f.load()
+	if err != nil {
+		return err
+	}
+
+	svc, ok := data.services()[service]
+	if !ok {
+		svc = new(insecureStashService)
+		data.services()[service] = svc
+	}
+	svc.secrets()[key] = &insecureStashSecret{Value: secret}
+
+	return f.save(data, file)
+}
+
+// LoadSecret retrieves a secret from the stash.
+// It returns ErrNotFound if the secret does not exist.
+func (f *EncryptedStash) LoadSecret(service, key string) (string, error) {
+	data, _, err := f.load()
+	if err != nil {
+		return "", err
+	}
+
+	svc, ok := data.services()[service]
+	if !ok {
+		return "", ErrNotFound
+	}
+
+	secret, ok := svc.secrets()[key]
+	if !ok {
+		return "", ErrNotFound
+	}
+
+	return secret.Value, nil
+}
+
+// DeleteSecret deletes a secret from the stash.
+// It is a no-op if the secret does not exist.
+func (f *EncryptedStash) DeleteSecret(service, key string) error {
+	data, file, err := f.load()
+	if err != nil {
+		return err
+	}
+
+	if svc, ok := data.services()[service]; ok {
+		delete(svc.secrets(), key)
+		return f.save(data, file)
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package secret
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/silog/silogtest"
+)
+
+func TestEncryptedStash(t *testing.T) {
+	defer func(old int) { _encryptedStashIterations = old }(_encryptedStashIterations)
+	_encryptedStashIterations = 1000
+
+	t.Run("NotPlainText", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "secrets.enc")
+		stash := EncryptedStash{
+			Path:       file,
+			Passphrase: "hunter2",
+			Log:        silogtest.New(t),
+		}
+		require.NoError(t, stash.SaveSecret("service", "key", "very-secret-value"))
+
+		bs, err := os.ReadFile(file)
+		require.NoError(t, err)
+		assert.NotContains(t, string(bs), "very-secret-value")
+		assert.NotContains(t, string(bs), "service")
+	})
+
+	t.Run("WrongPassphrase", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "secrets.enc")
+		require.NoError(t, (&EncryptedStash{
+			Path:       file,
+			Passphrase: "hunter2",
+			Log:        silogtest.New(t),
+		}).SaveSecret("service", "key", "secret"))
+
+		_, err := (&EncryptedStash{
+			Path:       file,
+			Passphrase: "hunter3",
+			Log:        silogtest.New(t),
+		}).LoadSecret("service", "key")
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "wrong passphrase")
+	})
+
+	t.Run("ReadAcrossInstances", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "secrets.enc")
+		require.NoError(t, (&EncryptedStash{
+			Path:       file,
+			Passphrase: "hunter2",
+			Log:        silogtest.New(t),
+		}).SaveSecret("service", "key", "secret"))
+
+		got, err := (&EncryptedStash{
+			Path:       file,
+			Passphrase: "hunter2",
+			Log:        silogtest.New(t),
+		}).LoadSecret("service", "key")
+		require.NoError(t, err)
+		assert.Equal(t, "secret", got)
+	})
+
+	t.Run("NoKey", func(t *testing.T) {
+		stash := EncryptedStash{
+			Path: filepath.Join(t.TempDir(), "secrets.enc"),
+			Log:  silogtest.New(t),
+		}
+		err := stash.SaveSecret("service", "key", "secret")
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "no passphrase or key file")
+	})
+
+	t.Run("EmptyKeyFile", func(t *testing.T) {
+		dir := t.TempDir()
+		keyFile := filepath.Join(dir, "key.txt")
+		require.NoError(t, os.WriteFile(keyFile, []byte("# nothing here\n\n"), 0o600))
+
+		stash := EncryptedStash{
+			Path:    filepath.Join(dir, "secrets.enc"),
+			KeyFile: keyFile,
+			Log:     silogtest.New(t),
+		}
+		err := stash.SaveSecret("service", "key", "secret")
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "is empty")
+	})
+
+	t.Run("DeleteLastRemovesFile", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "secrets.enc")
+		stash := EncryptedStash{
+			Path:       file,
+			Passphrase: "hunter2",
+			Log:        silogtest.New(t),
+		}
+		require.NoError(t, stash.SaveSecret("service", "key", "secret"))
+		assert.FileExists(t, file)
+
+		require.NoError(t, stash.DeleteSecret("service", "key"))
+		assert.NoFileExists(t, file)
+	})
+}
+
+func TestHelperStash_failure(t *testing.T) {
+	stash := HelperStash{
+		Command: "exit 1 #",
+		Log:     silogtest.New(t),
+	}
+
+	_, err := stash.LoadSecret("service", "key")
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "secret helper get")
+
+	require.Error(t, stash.SaveSecret("service", "key", "secret"))
+	require.Error(t, stash.DeleteSecret("service", "key"))
+}
+
+func TestHelperStash_rejectsNewlines(t *testing.T) {
+	stash := HelperStash{
+		Command: "true",
+		Log:     silogtest.New(t),
+	}
+
+	err := stash.SaveSecret("service", "key", "multi\nline")
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "newline")
+}
@@ -0,0 +1,124 @@
+package secret
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+
+	"go.abhg.dev/gs/internal/silog"
+	"go.abhg.dev/gs/internal/xec"
+)
+
+// HelperStash is a secrets stash that delegates to an external program,
+// similar to Git credential helpers.
+//
+// The helper is invoked with 'sh -c' as:
+//
+//	<command> <action>
+//
+// Where action is one of "get", "store", or "erase".
+// The request is written to the helper's stdin
+// as "name=value" lines terminated by a blank line:
+//
+//	service=<service>
+//	key=<key>
+//	secret=<secret>  (store only)
+//
+// For "get", the helper must print "secret=<value>" to stdout
+// if it knows the secret, and print nothing if it does not.
+// Output for other actions is ignored.
+//
+// A helper that exits with a non-zero status is considered to have failed.
+//
+// Helpers require a POSIX shell and are not supported on Windows.
+type HelperStash struct {
+	// Command is the shell command that runs the helper.
+	// The action is appended to it as an argument.
+	Command string // required
+
+	// Log is the logger used by the stash.
+	Log *silog.Logger // required
+}
+
+var _ Stash = (*HelperStash)(nil)
+
+func (h *HelperStash) run(action string, attrs [][2]string) ([]byte, error) {
+	if runtime.GOOS == "windows" {
+		return nil, errors.New("secret helpers are not supported on Windows")
+	}
+
+	var stdin bytes.Buffer
+	for _, attr := range attrs {
+		name, value := attr[0], attr[1]
+		if strings.ContainsAny(value, "\n\x00") {
+			return nil, fmt.Errorf("%v: value contains a newline or NUL byte", name)
+		}
+		fmt.Fprintf(&stdin, "%s=%s\n", name, value)
+	}
+	stdin.WriteString("\n")
+
+	// Pass the action as a positional argument
+	// so that the command may be an arbitrary shell snippet.
+	out, err := xec.Command(context.Background(), h.Log,
+		"sh", "-c", h.Command+` "$@"`, h.Command, action).
+		WithLogPrefix("secret-helper").
+		WithStdin(&stdin).
+		Output()
+	if err != nil {
+		return nil, fmt.Errorf("secret helper %v: %w", action, err)
+	}
+	return out, nil
+}
+
+// SaveSecret stores a secret with the helper.
+func (h *HelperStash) SaveSecret(service, key, secret string) error {
+	_, err := h.run("store", [][2]string{
+		{"service", service},
+		{"key", key},
+		{"secret", secret},
+	})
+	return err
+}
+
+// LoadSecret retrieves a secret from the helper.
+// It returns ErrNotFound if the helper does not report a secret.
+func (h *HelperStash) LoadSecret(service, key string) (string, error) {
+	out, err := h.run("get", [][2]string{
+		{"service", service},
+		{"key", key},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			break
+		}
+
+		if value, ok := strings.CutPrefix(line, "secret="); ok {
+			return value, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("read secret helper output: %w", err)
+	}
+
+	return "", ErrNotFound
+}
+
+// DeleteSecret asks the helper to erase a secret.
+// Helpers should treat erasing an unknown secret as a no-op.
+func (h *HelperStash) DeleteSecret(service, key string) error {
+	_, err := h.run("erase", [][2]string{
+		{"service", service},
+		{"key", key},
+	})
+	return err
+}
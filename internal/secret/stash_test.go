@@ -4,6 +4,7 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"runtime"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -49,6 +50,40 @@ func TestStash(t *testing.T) {
 		testStash(t, &stash)
 	})
 
+	t.Run("Encrypted/Passphrase", func(t *testing.T) {
+		stash := secret.EncryptedStash{
+			Path:       filepath.Join(t.TempDir(), "secrets.enc"),
+			Passphrase: "hunter2",
+			Log:        silogtest.New(t),
+		}
+		testStash(t, &stash)
+	})
+
+	t.Run("Encrypted/KeyFile", func(t *testing.T) {
+		dir := t.TempDir()
+		keyFile := filepath.Join(dir, "key.txt")
+		require.NoError(t, os.WriteFile(keyFile,
+			[]byte("# created: today\nAGE-SECRET-KEY-1QQQQQQQQQQQQ\n"), 0o600))
+
+		stash := secret.EncryptedStash{
+			Path:    filepath.Join(dir, "secrets.enc"),
+			KeyFile: keyFile,
+			Log:     silogtest.New(t),
+		}
+		testStash(t, &stash)
+	})
+
+	t.Run("Helper", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("secret helpers are not supported on Windows")
+		}
+
+		testStash(t, &secret.HelperStash{
+			Command: fileHelperScript(t),
+			Log:     silogtest.New(t),
+		})
+	})
+
 	t.Run("Fallback/PrimaryBroken", func(t *testing.T) {
 		testStash(t, &secret.FallbackStash{
 			Primary: &brokenStash{
@@ -109,6 +144,32 @@ func testStash(t *testing.T, stash secret.Stash) {
 	})
 }
 
+// fileHelperScript writes a secret helper script
+// that stores each secret in a separate file in a temporary directory,
+// and returns the command to run it.
+func fileHelperScript(t *testing.T) string {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "helper.sh")
+	require.NoError(t, os.WriteFile(script, []byte(`#!/bin/sh
+set -eu
+while IFS='=' read -r name value; do
+	[ -z "$name" ] && break
+	case "$name" in
+		service) service="$value" ;;
+		key) key="$value" ;;
+		secret) secret="$value" ;;
+	esac
+done
+file="`+dir+`/$service.$key"
+case "$1" in
+	get) [ -f "$file" ] && printf 'secret=%s\n' "$(cat "$file")" || true ;;
+	store) printf '%s' "$secret" > "$file" ;;
+	erase) rm -f "$file" ;;
+esac
+`), 0o700))
+	return script
+}
+
 // brokenStash is a Stash that always returns an error.
 type brokenStash struct {
 	err error
@@ -15,6 +15,7 @@ import (
 	"go.abhg.dev/gs/internal/git"
 	"go.abhg.dev/gs/internal/must"
 	"go.abhg.dev/gs/internal/spice/state"
+	"go.abhg.dev/gs/internal/tracing"
 )
 
 // GenerateBranchName generates a branch name from a commit message subject.
@@ -74,6 +75,14 @@ type LookupBranchResponse struct {
 	// or an empty string if the branch is not tracking an upstream branch.
 	UpstreamBranch string
 
+	// Issue is the issue tracker reference associated with the branch,
+	// or an empty string if the branch is not linked to an issue.
+	Issue string
+
+	// ReadOnly reports whether the branch must not be rewritten
+	// by restacking.
+	ReadOnly bool
+
 	// Head is the commit at the head of the branch.
 	Head git.Hash
 
@@ -144,6 +153,8 @@ func (s *Service) LookupBranch(ctx context.Context, name string) (*LookupBranchR
 			Base:            resp.Base,
 			BaseHash:        resp.BaseHash,
 			UpstreamBranch:  resp.UpstreamBranch,
+			Issue:           resp.Issue,
+			ReadOnly:        resp.ReadOnly,
 			Head:            head,
 			MergedDownstack: resp.MergedDownstack,
 		}
@@ -344,6 +355,8 @@ func (s *Service) RenameBranch(ctx context.Context, oldName, newName string) err
 		ChangeForge:    changeForge,
 		ChangeMetadata: changeMetadata,
 		UpstreamBranch: &oldBranch.UpstreamBranch,
+		Issue:          &oldBranch.Issue,
+		ReadOnly:       &oldBranch.ReadOnly,
 	}); err != nil {
 		return fmt.Errorf("create branch with name %v: %w", newName, err)
 	}
@@ -363,6 +376,7 @@ func (s *Service) RenameBranch(ctx context.Context, oldName, newName string) err
 	if err := tx.Delete(ctx, oldName); err != nil {
 		return fmt.Errorf("delete branch %v: %w", oldName, err)
 	}
+	tx.MoveHistory(oldName, newName)
 
 	// If we get here, the change will be committed successfully.
 	// We can perform the Git rename and commit.
@@ -404,6 +418,13 @@ type LoadBranchItem struct {
 	// was pushed to the upstream repository.
 	UpstreamBranch string
 
+	// Issue is the issue tracker reference associated with the branch.
+	Issue string
+
+	// ReadOnly reports whether the branch must not be rewritten
+	// by restacking.
+	ReadOnly bool
+
 	// MergedDownstack contains information about any branches,
 	// which this one was based on, that have already been merged into trunk.
 	MergedDownstack []json.RawMessage
@@ -412,8 +433,15 @@ type LoadBranchItem struct {
 // LoadBranches loads all tracked branches
 // and all their information as a single operation.
 //
+// Branch state and heads are read through the repository's
+// long-lived object reader,
+// so this doesn't spawn a Git process per branch.
+//
 // The returned branches are sorted by name.
 func (s *Service) LoadBranches(ctx context.Context) ([]LoadBranchItem, error) {
+	ctx, span := tracing.Start(ctx, tracing.CategoryHandler, "load branches")
+	defer span.End()
+
 	var (
 		wg sync.WaitGroup
 
@@ -453,6 +481,8 @@ func (s *Service) LoadBranches(ctx context.Context) ([]LoadBranchItem, error) {
 					Base:            resp.Base,
 					BaseHash:        resp.BaseHash,
 					UpstreamBranch:  resp.UpstreamBranch,
+					Issue:           resp.Issue,
+					ReadOnly:        resp.ReadOnly,
 					Change:          resp.Change,
 					MergedDownstack: resp.MergedDownstack,
 				})
@@ -0,0 +1,259 @@
+package spice
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultTicketPattern is the default regular expression
+// used to find ticket IDs in commit messages and branch names.
+// It matches JIRA-style IDs like "ABC-123".
+const DefaultTicketPattern = `[A-Z][A-Z0-9]+-[0-9]+`
+
+// BranchNamePolicy defines how branch names are generated
+// and the rules that all branch names must follow.
+//
+// The zero value generates names from the commit subject
+// and accepts all names.
+type BranchNamePolicy struct {
+	// Template is the template used to generate branch names.
+	// It may reference the following variables in braces:
+	//
+	//   - {user}: name of the current user
+	//   - {date}: current date in YYYY-MM-DD format
+	//   - {ticket}: ticket ID extracted from the commit message
+	//   - {stack}: name of the bottom-most branch in the stack
+	//   - {slug}: slugified commit subject
+	//
+	// Variables that expand to an empty string
+	// are removed along with a single separator (-, _, /, or .)
+	// that follows them.
+	//
+	// Defaults to "{slug}".
+	Template string
+
+	// TicketPattern matches ticket IDs in commit messages.
+	// If the pattern has a capture group, the first group is the ticket ID.
+	// Otherwise, the entire match is used.
+	//
+	// Defaults to DefaultTicketPattern.
+	TicketPattern *regexp.Regexp
+
+	// MaxLength is the maximum length of a branch name.
+	// Generated names are shortened to fit.
+	// Zero means no limit.
+	MaxLength int
+
+	// AllowedChars is a regular expression that matches
+	// a single character allowed in branch names,
+	// e.g. "[a-z0-9/_-]".
+	// If nil, all characters allowed by Git are accepted.
+	AllowedChars *regexp.Regexp
+
+	// RequireTicket specifies that all branch names
+	// must contain a ticket ID matching TicketPattern.
+	RequireTicket bool
+}
+
+// BranchNameVars are the values available to a [BranchNamePolicy] template.
+type BranchNameVars struct {
+	User  string
+	Date  string
+	Stack string
+
+	// Message is the full commit message.
+	// The ticket ID and slug are derived from it.
+	Message string
+}
+
+var _templateVarRe = regexp.MustCompile(`\{([a-z]+)\}`)
+
+// ParseBranchNameTemplate verifies that the given template
+// references only known variables.
+func ParseBranchNameTemplate(tmpl string) error {
+	for _, m := range _templateVarRe.FindAllStringSubmatch(tmpl, -1) {
+		switch m[1] {
+		case "user", "date", "ticket", "stack", "slug":
+		default:
+			return fmt.Errorf("unknown variable %q in branch name template %q", m[0], tmpl)
+		}
+	}
+	return nil
+}
+
+var _defaultTicketRe = regexp.MustCompile(DefaultTicketPattern)
+
+func (p *BranchNamePolicy) ticketPattern() *regexp.Regexp {
+	if p.TicketPattern != nil {
+		return p.TicketPattern
+	}
+	return _defaultTicketRe
+}
+
+// FindTicket returns the first ticket ID found in the given text,
+// or an empty string if there isn't one.
+func (p *BranchNamePolicy) FindTicket(s string) string {
+	m := p.ticketPattern().FindStringSubmatch(s)
+	switch {
+	case m == nil:
+		return ""
+	case len(m) > 1 && m[1] != "":
+		return m[1]
+	default:
+		return m[0]
+	}
+}
+
+// Generate generates a branch name from the given variables.
+// slugLimit is the maximum length of the slug.
+// reserved is the number of characters the caller will add to the name,
+// e.g. a prefix or a suffix to make it unique.
+// These count against MaxLength.
+//
+// The result is not guaranteed to satisfy the policy;
+// use Validate to check it.
+func (p *BranchNamePolicy) Generate(vars BranchNameVars, slugLimit, reserved int) string {
+	tmpl := p.Template
+	if tmpl == "" {
+		tmpl = "{slug}"
+	}
+
+	subject, _, _ := strings.Cut(strings.TrimSpace(vars.Message), "\n")
+	ticket := p.FindTicket(vars.Message)
+	if ticket != "" && strings.Contains(tmpl, "{ticket}") {
+		// Don't repeat the ticket ID in the slug
+		// if the template already includes it.
+		subject = strings.Replace(subject, ticket, "", 1)
+	}
+
+	render := func(slug string) string {
+		values := map[string]string{
+			"user":   sanitizeRefComponent(vars.User),
+			"date":   vars.Date,
+			"ticket": ticket,
+			"stack":  vars.Stack,
+			"slug":   slug,
+		}
+		return expandBranchNameTemplate(tmpl, values)
+	}
+
+	for limit := slugLimit; ; limit-- {
+		var slug string
+		if limit > 0 && hasAlnum(subject) {
+			slug = GenerateBranchName(subject, limit)
+		}
+
+		name := render(slug)
+		if p.MaxLength <= 0 || reserved+utf8.RuneCountInString(name) <= p.MaxLength || limit <= 0 {
+			return name
+		}
+	}
+}
+
+// expandBranchNameTemplate expands {var} references in tmpl.
+// Empty variables consume a single separator that follows them.
+func expandBranchNameTemplate(tmpl string, values map[string]string) string {
+	var out strings.Builder
+	for len(tmpl) > 0 {
+		loc := _templateVarRe.FindStringSubmatchIndex(tmpl)
+		if loc == nil {
+			out.WriteString(tmpl)
+			break
+		}
+
+		out.WriteString(tmpl[:loc[0]])
+		value := values[tmpl[loc[2]:loc[3]]]
+		out.WriteString(value)
+		tmpl = tmpl[loc[1]:]
+
+		if value == "" && len(tmpl) > 0 && strings.ContainsRune("-_/.", rune(tmpl[0])) {
+			tmpl = tmpl[1:]
+		}
+	}
+
+	// Empty trailing variables may leave a dangling separator.
+	return strings.TrimRight(out.String(), "-_/.")
+}
+
+// Validate reports whether the given branch name satisfies the policy.
+// The returned error lists all violations.
+func (p *BranchNamePolicy) Validate(name string) error {
+	var errs []error
+	if p.MaxLength > 0 {
+		if n := utf8.RuneCountInString(name); n > p.MaxLength {
+			errs = append(errs, fmt.Errorf("must be at most %d characters long, got %d", p.MaxLength, n))
+		}
+	}
+
+	if p.AllowedChars != nil {
+		var bad []string
+		seen := make(map[rune]struct{})
+		for _, r := range name {
+			if _, ok := seen[r]; ok {
+				continue
+			}
+			seen[r] = struct{}{}
+
+			if !p.AllowedChars.MatchString(string(r)) {
+				bad = append(bad, fmt.Sprintf("%q", r))
+			}
+		}
+		if len(bad) > 0 {
+			errs = append(errs, fmt.Errorf("contains disallowed characters: %v", strings.Join(bad, ", ")))
+		}
+	}
+
+	if p.RequireTicket && p.FindTicket(name) == "" {
+		errs = append(errs, fmt.Errorf("must contain a ticket ID matching %v", p.ticketPattern()))
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return &BranchNameError{Name: name, Err: errors.Join(errs...)}
+}
+
+// BranchNameError is returned when a branch name
+// does not satisfy the branch naming policy.
+type BranchNameError struct {
+	Name string
+	Err  error
+}
+
+func (e *BranchNameError) Error() string {
+	msg := strings.ReplaceAll(e.Err.Error(), "\n", "; ")
+	return fmt.Sprintf("branch name %q does not follow naming policy: %v", e.Name, msg)
+}
+
+func (e *BranchNameError) Unwrap() error {
+	return e.Err
+}
+
+// sanitizeRefComponent turns s into something
+// that can be safely used as part of a branch name.
+func sanitizeRefComponent(s string) string {
+	s = strings.TrimSpace(s)
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == ' ':
+			return '-'
+		case r < 0x20 || r == 0x7f || strings.ContainsRune(`~^:?*[\@{}/.`, r):
+			return -1
+		default:
+			return r
+		}
+	}, s)
+}
+
+// hasAlnum reports whether s has any letters or numbers,
+// i.e. whether GenerateBranchName can generate a name from it.
+func hasAlnum(s string) bool {
+	return strings.IndexFunc(s, func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.IsNumber(r)
+	}) >= 0
+}
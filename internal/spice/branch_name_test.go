@@ -0,0 +1,169 @@
+package spice
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBranchNamePolicy_Generate(t *testing.T) {
+	vars := BranchNameVars{
+		User:    "alice",
+		Date:    "2026-01-02",
+		Stack:   "feature",
+		Message: "ABC-123: Add a new widget\n\nSome details.",
+	}
+
+	tests := []struct {
+		name     string
+		policy   BranchNamePolicy
+		vars     BranchNameVars
+		limit    int
+		reserved int
+		want     string
+	}{
+		{
+			name:  "Default",
+			vars:  vars,
+			limit: 32,
+			want:  "abc-123-add-a-new-widget",
+		},
+		{
+			name:   "UserTicketSlug",
+			policy: BranchNamePolicy{Template: "{user}/{ticket}-{slug}"},
+			vars:   vars,
+			limit:  32,
+			want:   "alice/ABC-123-add-a-new-widget",
+		},
+		{
+			name:   "DateStack",
+			policy: BranchNamePolicy{Template: "{stack}/{date}-{slug}"},
+			vars:   vars,
+			limit:  32,
+			want:   "feature/2026-01-02-abc-123-add-a-new-widget",
+		},
+		{
+			name:   "EmptyVariables",
+			policy: BranchNamePolicy{Template: "{user}/{stack}/{ticket}-{slug}"},
+			vars:   BranchNameVars{Message: "Add a new widget"},
+			limit:  32,
+			want:   "add-a-new-widget",
+		},
+		{
+			name:   "TicketInBody",
+			policy: BranchNamePolicy{Template: "{ticket}-{slug}"},
+			vars:   BranchNameVars{Message: "Add a new widget\n\nFixes: XY-9"},
+			limit:  32,
+			want:   "XY-9-add-a-new-widget",
+		},
+		{
+			name:   "OnlyTicket",
+			policy: BranchNamePolicy{Template: "{ticket}-{slug}"},
+			vars:   BranchNameVars{Message: "ABC-123"},
+			limit:  32,
+			want:   "ABC-123",
+		},
+		{
+			name: "TicketPatternGroup",
+			policy: BranchNamePolicy{
+				Template:      "{ticket}-{slug}",
+				TicketPattern: regexp.MustCompile(`#(\d+)`),
+			},
+			vars:  BranchNameVars{Message: "Fix crash (#42)"},
+			limit: 32,
+			want:  "42-fix-crash",
+		},
+		{
+			name:   "UserSanitized",
+			policy: BranchNamePolicy{Template: "{user}/{slug}"},
+			vars:   BranchNameVars{User: "Jane Q. Doe", Message: "Fix"},
+			limit:  32,
+			want:   "Jane-Q-Doe/fix",
+		},
+		{
+			name: "MaxLength",
+			policy: BranchNamePolicy{
+				Template:  "{user}/{slug}",
+				MaxLength: 16,
+			},
+			vars:  BranchNameVars{User: "alice", Message: "Add a new widget to the page"},
+			limit: 32,
+			want:  "alice/add-a-new",
+		},
+		{
+			name: "MaxLengthReserved",
+			policy: BranchNamePolicy{
+				Template:  "{user}/{slug}",
+				MaxLength: 16,
+			},
+			vars:     BranchNameVars{User: "alice", Message: "Add a new widget to the page"},
+			limit:    32,
+			reserved: 4, // e.g. "bug/"
+			want:     "alice/add-a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.policy.Generate(tt.vars, tt.limit, tt.reserved)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBranchNamePolicy_Validate(t *testing.T) {
+	policy := BranchNamePolicy{
+		MaxLength:     20,
+		AllowedChars:  regexp.MustCompile(`^[a-zA-Z0-9/-]$`),
+		RequireTicket: true,
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		assert.NoError(t, policy.Validate("alice/ABC-1-fix"))
+	})
+
+	t.Run("TooLong", func(t *testing.T) {
+		err := policy.Validate("alice/ABC-1-fix-the-thing")
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "must be at most 20 characters long, got 25")
+
+		var nameErr *BranchNameError
+		require.ErrorAs(t, err, &nameErr)
+		assert.Equal(t, "alice/ABC-1-fix-the-thing", nameErr.Name)
+	})
+
+	t.Run("DisallowedChars", func(t *testing.T) {
+		err := policy.Validate("ABC-1_fix.it_")
+		require.Error(t, err)
+		assert.ErrorContains(t, err, `contains disallowed characters: '_', '.'`)
+	})
+
+	t.Run("MissingTicket", func(t *testing.T) {
+		err := policy.Validate("fix")
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "must contain a ticket ID")
+	})
+
+	t.Run("MultipleViolations", func(t *testing.T) {
+		err := policy.Validate("this_branch_name_is_too_long")
+		require.Error(t, err)
+		assert.Equal(t,
+			`branch name "this_branch_name_is_too_long" does not follow naming policy: `+
+				`must be at most 20 characters long, got 28; `+
+				`contains disallowed characters: '_'; `+
+				`must contain a ticket ID matching [A-Z][A-Z0-9]+-[0-9]+`,
+			err.Error())
+	})
+
+	t.Run("ZeroValue", func(t *testing.T) {
+		var policy BranchNamePolicy
+		assert.NoError(t, policy.Validate("anything goes"))
+	})
+}
+
+func TestParseBranchNameTemplate(t *testing.T) {
+	assert.NoError(t, ParseBranchNameTemplate("{user}/{date}/{ticket}-{stack}-{slug}"))
+	assert.ErrorContains(t, ParseBranchNameTemplate("{user}/{branch}"), `unknown variable "{branch}"`)
+}
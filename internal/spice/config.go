@@ -11,6 +11,7 @@ import (
 
 	"github.com/alecthomas/kong"
 	"github.com/buildkite/shellwords"
+	"go.abhg.dev/gs/internal/cli/shorthand"
 	"go.abhg.dev/gs/internal/git"
 	"go.abhg.dev/gs/internal/silog"
 )
@@ -20,6 +21,7 @@ const (
 	_spiceSection         = "spice"
 	_shorthandSubsection  = "shorthand"
 	_experimentSubsection = "experiment"
+	_hookSubsection       = "hook"
 )
 
 // GitSections is a list of Git-owned sections
@@ -27,6 +29,7 @@ const (
 var GitSections = []string{
 	"core",
 	"commit",
+	"user",
 }
 
 // GitConfigLister provides access to git-config output.
@@ -89,8 +92,22 @@ type Config struct {
 	// allowing shorthands to call any shell command.
 	shellCommands map[string]string
 
+	// macros is a map from shorthand to macros:
+	// shorthands that reference their arguments
+	// or run multiple commands.
+	macros map[string]*shorthand.Macro
+
+	// definitions is a map from all user-defined shorthands
+	// (including shell commands and macros)
+	// to their raw configured values.
+	definitions map[string]string
+
 	// experiments is a set of enabled experimental features.
 	experiments map[string]struct{}
+
+	// hooks is a map from event name to shell commands
+	// to run for that event.
+	hooks map[string][]string
 }
 
 // ConfigOptions specifies options for the [Config].
@@ -109,7 +126,10 @@ func LoadConfig(ctx context.Context, cfg GitConfigLister, opts ConfigOptions) (*
 	items := make(map[git.ConfigKey][]string)
 	shorthands := make(map[string][]string)
 	shellCommands := make(map[string]string)
+	macros := make(map[string]*shorthand.Macro)
+	definitions := make(map[string]string)
 	experiments := make(map[string]struct{})
+	hooks := make(map[string][]string)
 
 	sectionNames := make(map[string]struct{})
 	sectionNames[_spiceSection] = struct{}{}
@@ -141,9 +161,16 @@ func LoadConfig(ctx context.Context, cfg GitConfigLister, opts ConfigOptions) (*
 			// Everything under "spice.shorthand.*" defines a shorthand.
 			short := name
 
-			// "!foo" is used for shell commands.
-			if cmd, ok := strings.CutPrefix(entry.Value, "!"); ok {
+			// "!foo" is used for shell commands,
+			// unless it chains more external commands with "&&":
+			// that's a macro that starts with an external command.
+			// "!" is meaningless at the start of a shell command,
+			// so "!foo && !bar" can't be a shell command.
+			if cmd, ok := strings.CutPrefix(entry.Value, "!"); ok && !chainsExternalCommands(cmd) {
 				shellCommands[short] = cmd
+				delete(shorthands, short)
+				delete(macros, short)
+				definitions[short] = entry.Value
 				continue
 			}
 
@@ -158,7 +185,29 @@ func LoadConfig(ctx context.Context, cfg GitConfigLister, opts ConfigOptions) (*
 				continue
 			}
 
-			shorthands[short] = longform
+			// Shorthands that reference their arguments
+			// or chain multiple commands are macros.
+			// Shorthands that get here with a leading "!"
+			// always chain external commands.
+			if shorthand.IsMacro(longform) {
+				macro, err := shorthand.ParseMacro(longform)
+				if err != nil {
+					opts.Log.Warn("skipping shorthand with invalid macro",
+						"shorthand", short,
+						"value", entry.Value,
+						"error", err,
+					)
+					continue
+				}
+
+				macros[short] = macro
+				delete(shorthands, short)
+			} else {
+				shorthands[short] = longform
+				delete(macros, short)
+			}
+			delete(shellCommands, short)
+			definitions[short] = entry.Value
 
 		case section == _spiceSection && subsection == _experimentSubsection:
 			// Everything under "spice.experiment.*"
@@ -182,6 +231,13 @@ func LoadConfig(ctx context.Context, cfg GitConfigLister, opts ConfigOptions) (*
 				delete(experiments, experiment)
 			}
 
+		case section == _spiceSection && subsection == _hookSubsection:
+			// Everything under "spice.hook.*" is a command
+			// to run for an event.
+			// Events may have multiple hooks.
+			event := strings.ToLower(name)
+			hooks[event] = append(hooks[event], entry.Value)
+
 		default:
 			items[key] = append(items[key], entry.Value)
 		}
@@ -191,7 +247,10 @@ func LoadConfig(ctx context.Context, cfg GitConfigLister, opts ConfigOptions) (*
 		items:         items,
 		shorthands:    shorthands,
 		shellCommands: shellCommands,
+		macros:        macros,
+		definitions:   definitions,
 		experiments:   experiments,
+		hooks:         hooks,
 	}, nil
 }
 
@@ -201,6 +260,12 @@ func (c *Config) ExperimentEnabled(name string) bool {
 	return ok
 }
 
+// HookCommands returns the shell commands configured
+// to run for the given event, in the order they were defined.
+func (c *Config) HookCommands(event string) []string {
+	return c.hooks[strings.ToLower(event)]
+}
+
 // ExpandShorthand returns the long form of a custom shorthand command.
 // Returns false if the shorthand is not defined.
 func (c *Config) ExpandShorthand(name string) ([]string, bool) {
@@ -215,11 +280,44 @@ func (c *Config) ShellCommand(name string) (string, bool) {
 	return cmd, ok
 }
 
+// Macro returns a custom shorthand macro, if defined.
+// Returns false if the macro is not defined.
+func (c *Config) Macro(name string) (*shorthand.Macro, bool) {
+	m, ok := c.macros[name]
+	return m, ok
+}
+
 // Shorthands returns a sorted list of all defined shorthands.
+//
+// This does not include shell commands or macros.
 func (c *Config) Shorthands() []string {
 	return slices.Sorted(maps.Keys(c.shorthands))
 }
 
+// ShorthandDefinition is a user-defined shorthand
+// and the value it was configured with.
+type ShorthandDefinition struct {
+	// Name is the name of the shorthand.
+	Name string
+
+	// Value is the configured value of the shorthand
+	// as it appears in git-config.
+	Value string
+}
+
+// ShorthandDefinitions returns all user-defined shorthands,
+// including shell commands and macros, sorted by name.
+func (c *Config) ShorthandDefinitions() []ShorthandDefinition {
+	defs := make([]ShorthandDefinition, 0, len(c.definitions))
+	for _, name := range slices.Sorted(maps.Keys(c.definitions)) {
+		defs = append(defs, ShorthandDefinition{
+			Name:  name,
+			Value: c.definitions[name],
+		})
+	}
+	return defs
+}
+
 // Validate checks if the configuration is valid for the given application.
 // This is a no-op, as we allow unknown configuration keys.
 func (*Config) Validate(*kong.Application) error { return nil }
@@ -264,3 +362,20 @@ func (c *Config) Resolve(_ *kong.Context, _ *kong.Path, flag *kong.Flag) (any, e
 		return values[len(values)-1], nil
 	}
 }
+
+// chainsExternalCommands reports whether the given shell command
+// is actually a macro that chains external commands with "&&",
+// e.g. "make test && !make lint".
+func chainsExternalCommands(cmd string) bool {
+	words, err := shellwords.SplitPosix(cmd)
+	if err != nil {
+		return false // not a macro
+	}
+
+	for i, w := range words[:max(len(words)-1, 0)] {
+		if w == shorthand.MacroSeparator && strings.HasPrefix(words[i+1], "!") {
+			return true
+		}
+	}
+	return false
+}
@@ -343,6 +343,140 @@ func TestConfig_ShellCommand(t *testing.T) {
 	}
 }
 
+func TestConfig_Macro(t *testing.T) {
+	home := t.TempDir()
+	require.NoError(t, os.WriteFile(
+		filepath.Join(home, ".gitconfig"),
+		[]byte(text.Dedent(`
+			[spice.shorthand]
+			can = commit amend --no-edit
+			ship = repo sync && stack submit
+			bcm = branch create "$1" -m "$2"
+			cb = !git branch --show-current
+			bad = repo sync &&
+			two = !echo a && !echo b $1
+			sh = !make test && make lint
+		`)),
+		0o600,
+	), "write configuration file")
+
+	gitCfg := git.NewConfig(git.ConfigOptions{
+		Log: silogtest.New(t),
+		Dir: home,
+		Env: []string{
+			"HOME=" + home,
+			"USER=testuser",
+			"GIT_CONFIG_NOSYSTEM=1",
+		},
+	})
+	spicecfg, err := spice.LoadConfig(t.Context(), gitCfg, spice.ConfigOptions{
+		Log: silogtest.New(t),
+	})
+	require.NoError(t, err, "load configuration")
+
+	t.Run("Chain", func(t *testing.T) {
+		macro, ok := spicecfg.Macro("ship")
+		require.True(t, ok)
+
+		got, err := macro.Expand([]string{"--fill"})
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{
+			{"repo", "sync"},
+			{"stack", "submit", "--fill"},
+		}, got)
+	})
+
+	t.Run("Positional", func(t *testing.T) {
+		macro, ok := spicecfg.Macro("bcm")
+		require.True(t, ok)
+
+		got, err := macro.Expand([]string{"feat", "Add feature"})
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{
+			{"branch", "create", "feat", "-m", "Add feature"},
+		}, got)
+	})
+
+	t.Run("NotMacros", func(t *testing.T) {
+		for _, name := range []string{"can", "cb", "bad", "sh", "unknown"} {
+			_, ok := spicecfg.Macro(name)
+			assert.False(t, ok, "Macro(%q)", name)
+		}
+
+		// Macros are not plain shorthands.
+		assert.Equal(t, []string{"can"}, spicecfg.Shorthands())
+	})
+
+	// A macro starting with an external command
+	// is not run as a shell command.
+	t.Run("ExternalFirst", func(t *testing.T) {
+		_, ok := spicecfg.ShellCommand("two")
+		assert.False(t, ok)
+
+		macro, ok := spicecfg.Macro("two")
+		require.True(t, ok)
+
+		got, err := macro.Expand([]string{"x"})
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{
+			{"!echo", "a"},
+			{"!echo", "b", "x"},
+		}, got)
+	})
+
+	t.Run("ShellChain", func(t *testing.T) {
+		_, ok := spicecfg.Macro("sh")
+		assert.False(t, ok)
+
+		cmd, ok := spicecfg.ShellCommand("sh")
+		require.True(t, ok)
+		assert.Equal(t, "make test && make lint", cmd)
+	})
+
+	t.Run("Definitions", func(t *testing.T) {
+		assert.Equal(t, []spice.ShorthandDefinition{
+			{Name: "bcm", Value: "branch create $1 -m $2"},
+			{Name: "can", Value: "commit amend --no-edit"},
+			{Name: "cb", Value: "!git branch --show-current"},
+			{Name: "sh", Value: "!make test && make lint"},
+			{Name: "ship", Value: "repo sync && stack submit"},
+			{Name: "two", Value: "!echo a && !echo b $1"},
+		}, spicecfg.ShorthandDefinitions())
+	})
+}
+
+func TestConfig_HookCommands(t *testing.T) {
+	home := t.TempDir()
+	require.NoError(t, os.WriteFile(
+		filepath.Join(home, ".gitconfig"),
+		[]byte(text.Dedent(`
+			[spice.hook]
+			pre-submit = make lint
+			post-submit = ./notify.sh
+			pre-submit = make test
+		`)),
+		0o600,
+	), "write configuration file")
+
+	gitCfg := git.NewConfig(git.ConfigOptions{
+		Log: silogtest.New(t),
+		Dir: home,
+		Env: []string{
+			"HOME=" + home,
+			"USER=testuser",
+			"GIT_CONFIG_NOSYSTEM=1",
+		},
+	})
+	spicecfg, err := spice.LoadConfig(t.Context(), gitCfg, spice.ConfigOptions{
+		Log: silogtest.New(t),
+	})
+	require.NoError(t, err, "load configuration")
+
+	assert.Equal(t, []string{"make lint", "make test"}, spicecfg.HookCommands("pre-submit"))
+	assert.Equal(t, []string{"./notify.sh"}, spicecfg.HookCommands("post-submit"))
+	assert.Empty(t, spicecfg.HookCommands("post-sync"))
+}
+
 func TestIntegrationConfig_gitConfigReferences(t *testing.T) {
 	t.Setenv("HOME", "")
 	t.Setenv("XDG_CONFIG_HOME", "")
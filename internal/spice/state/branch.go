@@ -65,6 +65,8 @@ type branchState struct {
 	Base     branchStateBase      `json:"base"`
 	Upstream *branchUpstreamState `json:"upstream,omitempty"`
 	Change   *branchChangeState   `json:"change,omitempty"`
+	Issue    string               `json:"issue,omitempty"`
+	ReadOnly bool                 `json:"readOnly,omitempty"`
 
 	MergedDownstack []json.RawMessage `json:"merged,omitempty"`
 }
@@ -99,6 +101,14 @@ type LookupResponse struct {
 	// or an empty string if the branch is not tracking an upstream branch.
 	UpstreamBranch string
 
+	// Issue is the issue tracker reference associated with the branch,
+	// or an empty string if the branch is not linked to an issue.
+	Issue string
+
+	// ReadOnly reports whether the branch must not be rewritten
+	// by restacking.
+	ReadOnly bool
+
 	// MergedDownstack holds information about branches
 	// that were previously downstack from this branch
 	// that have since been merged into trunk.
@@ -120,6 +130,8 @@ func (s *Store) LookupBranch(ctx context.Context, name string) (*LookupResponse,
 	res := &LookupResponse{
 		Base:            state.Base.Name,
 		BaseHash:        git.Hash(state.Base.Hash),
+		Issue:           state.Issue,
+		ReadOnly:        state.ReadOnly,
 		MergedDownstack: state.MergedDownstack,
 	}
 
@@ -177,19 +189,23 @@ func (s *Store) listBranches(ctx context.Context) iter.Seq2[string, error] {
 type BranchTx struct {
 	store *Store
 
-	states map[string]*branchState // cached states with changes
-	sets   map[string]struct{}     // branches to set
-	dels   map[string]struct{}     // branches to delete
+	states  map[string]*branchState // cached states with changes
+	sets    map[string]struct{}     // branches to set
+	dels    map[string]struct{}     // branches to delete
+	creates map[string]struct{}     // branches that weren't tracked before
+	moves   map[string]string       // old name -> new name for push history
 }
 
 // BeginBranchTx starts a new transaction for updating the branch graph.
 // Changes are not persisted until Commit is called.
 func (s *Store) BeginBranchTx() *BranchTx {
 	return &BranchTx{
-		store:  s,
-		states: make(map[string]*branchState),
-		sets:   make(map[string]struct{}),
-		dels:   make(map[string]struct{}),
+		store:   s,
+		states:  make(map[string]*branchState),
+		sets:    make(map[string]struct{}),
+		dels:    make(map[string]struct{}),
+		creates: make(map[string]struct{}),
+		moves:   make(map[string]string),
 	}
 }
 
@@ -229,6 +245,15 @@ type UpsertRequest struct {
 	// Leave nil to leave it unchanged, or set to an empty string to clear it.
 	UpstreamBranch *string
 
+	// Issue is the issue tracker reference to associate with the branch.
+	// Leave nil to leave it unchanged, or set to an empty string to clear it.
+	Issue *string
+
+	// ReadOnly marks the branch as one that must not be rewritten
+	// by restacking, e.g. because it belongs to someone else.
+	// Leave nil to leave it unchanged.
+	ReadOnly *bool
+
 	// MergedDownstack is a list of branches that were previously
 	// downstack from this branch that have since been merged into trunk.
 	MergedDownstack *[]json.RawMessage
@@ -257,6 +282,7 @@ func (tx *BranchTx) Upsert(ctx context.Context, req UpsertRequest) error {
 		}
 
 		state = &branchState{Base: branchStateBase{Name: req.Base}}
+		tx.creates[req.Name] = struct{}{}
 		// Note:
 		// Don't persist the state here until the rest
 		// of the request is validated.
@@ -310,6 +336,14 @@ func (tx *BranchTx) Upsert(ctx context.Context, req UpsertRequest) error {
 		}
 	}
 
+	if req.Issue != nil {
+		state.Issue = *req.Issue
+	}
+
+	if req.ReadOnly != nil {
+		state.ReadOnly = *req.ReadOnly
+	}
+
 	if req.MergedDownstack != nil {
 		state.MergedDownstack = *req.MergedDownstack
 	}
@@ -347,16 +381,30 @@ func (tx *BranchTx) Delete(ctx context.Context, name string) error {
 	tx.dels[name] = struct{}{}
 	delete(tx.sets, name)
 	delete(tx.states, name)
+	delete(tx.creates, name)
 	return nil
 }
 
+// MoveHistory moves the push history of a branch deleted in this transaction
+// to a branch added in it.
+// Use this when renaming a branch
+// so that versions pushed under the old name aren't lost.
+//
+// Otherwise, push history is discarded for deleted branches,
+// and for branches that weren't tracked before the transaction.
+func (tx *BranchTx) MoveHistory(from, to string) {
+	tx.moves[from] = to
+}
+
 // Commit persists all planned changes to the store.
 // If there are no changes, this is a no-op.
 func (tx *BranchTx) Commit(ctx context.Context, msg string) error {
 	req := updateBranchesRequest{
-		Sets:    make([]setBranchStateRequest, 0, len(tx.sets)),
-		Deletes: slices.Collect(maps.Keys(tx.dels)),
-		Message: msg,
+		Sets:         make([]setBranchStateRequest, 0, len(tx.sets)),
+		Deletes:      slices.Collect(maps.Keys(tx.dels)),
+		Creates:      slices.Collect(maps.Keys(tx.creates)),
+		HistoryMoves: tx.moves,
+		Message:      msg,
 	}
 
 	for branch := range tx.sets {
@@ -375,6 +423,8 @@ func (tx *BranchTx) Commit(ctx context.Context, msg string) error {
 	clear(tx.sets)
 	clear(tx.dels)
 	clear(tx.states)
+	clear(tx.creates)
+	clear(tx.moves)
 	return nil
 }
 
@@ -500,6 +550,13 @@ type updateBranchesRequest struct {
 	Sets    []setBranchStateRequest
 	Deletes []string
 	Message string // required
+
+	// Creates lists branches in Sets that weren't tracked before.
+	Creates []string
+
+	// HistoryMoves maps branches in Deletes to branches in Sets
+	// that their push history should be moved to.
+	HistoryMoves map[string]string
 }
 
 // updateBranches atomically updates the state of multiple branches in the store.
@@ -525,9 +582,16 @@ func (s *Store) updateBranches(ctx context.Context, req updateBranchesRequest) e
 		dels[idx] = branchKey(del)
 	}
 
+	// Push history belongs to the branch that was pushed,
+	// so it must not outlive it or be inherited by a new branch.
+	histReq, err := s.branchHistoryUpdate(ctx, req.Deletes, req.Creates, req.HistoryMoves)
+	if err != nil {
+		return fmt.Errorf("update branch history: %w", err)
+	}
+
 	updReq := storage.UpdateRequest{
-		Sets:    sets,
-		Deletes: dels,
+		Sets:    append(sets, histReq.Sets...),
+		Deletes: append(dels, histReq.Deletes...),
 		Message: req.Message,
 	}
 	if err := s.db.Update(ctx, updReq); err != nil {
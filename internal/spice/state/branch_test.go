@@ -287,6 +287,106 @@ func TestBranchTxUpsert_canClearUpstream(t *testing.T) {
 	assert.Equal(t, "", foo.UpstreamBranch)
 }
 
+func TestBranchTxUpsert_issue(t *testing.T) {
+	ctx := t.Context()
+	db := storage.NewDB(make(storage.MapBackend))
+	store, err := state.InitStore(ctx, state.InitStoreRequest{
+		DB:    db,
+		Trunk: "main",
+	})
+	require.NoError(t, err)
+
+	issue := "ABC-123"
+	require.NoError(t, statetest.UpdateBranch(ctx, store, &statetest.UpdateRequest{
+		Upserts: []state.UpsertRequest{
+			{
+				Name:  "foo",
+				Base:  "main",
+				Issue: &issue,
+			},
+		},
+		Message: "add foo",
+	}))
+
+	foo, err := store.LookupBranch(ctx, "foo")
+	require.NoError(t, err)
+	assert.Equal(t, "ABC-123", foo.Issue)
+
+	// Unset Issue leaves it unchanged.
+	require.NoError(t, statetest.UpdateBranch(ctx, store, &statetest.UpdateRequest{
+		Upserts: []state.UpsertRequest{
+			{Name: "foo", BaseHash: "abc"},
+		},
+		Message: "update foo",
+	}))
+
+	foo, err = store.LookupBranch(ctx, "foo")
+	require.NoError(t, err)
+	assert.Equal(t, "ABC-123", foo.Issue)
+
+	var empty string
+	require.NoError(t, statetest.UpdateBranch(ctx, store, &statetest.UpdateRequest{
+		Upserts: []state.UpsertRequest{
+			{Name: "foo", Issue: &empty},
+		},
+		Message: "clear foo",
+	}))
+
+	foo, err = store.LookupBranch(ctx, "foo")
+	require.NoError(t, err)
+	assert.Empty(t, foo.Issue)
+}
+
+func TestBranchTxUpsert_readOnly(t *testing.T) {
+	ctx := t.Context()
+	db := storage.NewDB(make(storage.MapBackend))
+	store, err := state.InitStore(ctx, state.InitStoreRequest{
+		DB:    db,
+		Trunk: "main",
+	})
+	require.NoError(t, err)
+
+	readOnly := true
+	require.NoError(t, statetest.UpdateBranch(ctx, store, &statetest.UpdateRequest{
+		Upserts: []state.UpsertRequest{
+			{
+				Name:     "foo",
+				Base:     "main",
+				ReadOnly: &readOnly,
+			},
+		},
+		Message: "add foo",
+	}))
+
+	foo, err := store.LookupBranch(ctx, "foo")
+	require.NoError(t, err)
+	assert.True(t, foo.ReadOnly)
+
+	// Unset ReadOnly leaves it unchanged.
+	require.NoError(t, statetest.UpdateBranch(ctx, store, &statetest.UpdateRequest{
+		Upserts: []state.UpsertRequest{
+			{Name: "foo", BaseHash: "abc"},
+		},
+		Message: "update foo",
+	}))
+
+	foo, err = store.LookupBranch(ctx, "foo")
+	require.NoError(t, err)
+	assert.True(t, foo.ReadOnly)
+
+	readOnly = false
+	require.NoError(t, statetest.UpdateBranch(ctx, store, &statetest.UpdateRequest{
+		Upserts: []state.UpsertRequest{
+			{Name: "foo", ReadOnly: &readOnly},
+		},
+		Message: "clear foo",
+	}))
+
+	foo, err = store.LookupBranch(ctx, "foo")
+	require.NoError(t, err)
+	assert.False(t, foo.ReadOnly)
+}
+
 // Uses rapid to run randomized scenarios on the branch state
 // to ensure we never leave it in a corrupted state.
 func TestBranchStateUncorruptible(t *testing.T) {
@@ -0,0 +1,156 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"time"
+
+	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/spice/state/storage"
+)
+
+// _historyDir is the directory holding the versions of branches
+// that were pushed by 'branch submit'.
+//
+// This is used to compare versions of a submitted branch
+// with 'branch interdiff'.
+const _historyDir = "history"
+
+type branchHistoryState struct {
+	Versions []branchVersionState `json:"versions"`
+}
+
+type branchVersionState struct {
+	Base string    `json:"base"`
+	Head string    `json:"head"`
+	Time time.Time `json:"time"`
+}
+
+func (s *Store) branchHistoryJSON(branch string) string {
+	return path.Join(_historyDir, branch)
+}
+
+// BranchVersion is a version of a branch that was pushed to the remote.
+type BranchVersion struct {
+	// Span is the range of commits in the branch.
+	// Base is the head of the base branch at the time of the push.
+	Span git.CommitSpan
+
+	// Time is when the version was pushed.
+	Time time.Time
+}
+
+// AppendBranchVersion records a new version of a branch
+// at the end of its history,
+// and returns the number of the new version.
+// Versions are numbered from 1.
+//
+// If the head of the branch hasn't changed since the last version,
+// nothing is recorded and the number of the last version is returned.
+func (s *Store) AppendBranchVersion(ctx context.Context, branch string, v BranchVersion) (int, error) {
+	var state branchHistoryState
+	if err := s.db.Get(ctx, s.branchHistoryJSON(branch), &state); err != nil && !errors.Is(err, storage.ErrNotExist) {
+		return 0, fmt.Errorf("get branch history: %w", err)
+	}
+
+	if n := len(state.Versions); n > 0 && state.Versions[n-1].Head == v.Span.Head.String() {
+		return n, nil
+	}
+
+	state.Versions = append(state.Versions, branchVersionState{
+		Base: v.Span.Base.String(),
+		Head: v.Span.Head.String(),
+		Time: v.Time,
+	})
+	n := len(state.Versions)
+	err := s.db.Set(ctx, s.branchHistoryJSON(branch), state,
+		fmt.Sprintf("%v: record v%d at %v", branch, n, v.Span.Head.Short()))
+	if err != nil {
+		return 0, fmt.Errorf("set branch history: %w", err)
+	}
+	return n, nil
+}
+
+// LoadBranchHistory retrieves the versions of a branch
+// recorded with AppendBranchVersion, oldest first.
+// Versions are numbered from 1, so the result's [0] is version 1.
+//
+// It returns an empty list if no versions were recorded for the branch.
+func (s *Store) LoadBranchHistory(ctx context.Context, branch string) ([]BranchVersion, error) {
+	var state branchHistoryState
+	if err := s.db.Get(ctx, s.branchHistoryJSON(branch), &state); err != nil {
+		if errors.Is(err, storage.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get branch history: %w", err)
+	}
+
+	versions := make([]BranchVersion, len(state.Versions))
+	for i, v := range state.Versions {
+		versions[i] = BranchVersion{
+			Span: git.CommitSpan{
+				Base: git.Hash(v.Base),
+				Head: git.Hash(v.Head),
+			},
+			Time: v.Time,
+		}
+	}
+	return versions, nil
+}
+
+// branchHistoryUpdate returns changes to the push history of branches
+// that are being deleted or added:
+// history of deleted branches is discarded or moved as requested,
+// and stale history left behind for new branches is discarded.
+func (s *Store) branchHistoryUpdate(
+	ctx context.Context,
+	deletes, creates []string,
+	moves map[string]string,
+) (storage.UpdateRequest, error) {
+	var req storage.UpdateRequest
+	if len(deletes) == 0 && len(creates) == 0 {
+		return req, nil
+	}
+
+	keys, err := s.db.Keys(ctx, _historyDir)
+	if err != nil {
+		return req, fmt.Errorf("list branch histories: %w", err)
+	}
+	hasHistory := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		hasHistory[key] = struct{}{}
+	}
+
+	moved := make(map[string]struct{}, len(moves))
+	for _, branch := range deletes {
+		if _, ok := hasHistory[branch]; !ok {
+			continue
+		}
+
+		if to, ok := moves[branch]; ok {
+			var state branchHistoryState
+			if err := s.db.Get(ctx, s.branchHistoryJSON(branch), &state); err != nil {
+				return req, fmt.Errorf("get history of %v: %w", branch, err)
+			}
+			req.Sets = append(req.Sets, storage.SetRequest{
+				Key:   s.branchHistoryJSON(to),
+				Value: state,
+			})
+			moved[to] = struct{}{}
+		}
+		req.Deletes = append(req.Deletes, s.branchHistoryJSON(branch))
+	}
+
+	for _, branch := range creates {
+		if _, ok := moved[branch]; ok {
+			continue
+		}
+		if _, ok := hasHistory[branch]; ok {
+			req.Deletes = append(req.Deletes, s.branchHistoryJSON(branch))
+		}
+	}
+
+	return req, nil
+}
@@ -0,0 +1,117 @@
+package state_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/silog/silogtest"
+	"go.abhg.dev/gs/internal/spice/state"
+	"go.abhg.dev/gs/internal/spice/state/storage"
+)
+
+func TestStore_branchHistory(t *testing.T) {
+	ctx := t.Context()
+	store, err := state.InitStore(ctx, state.InitStoreRequest{
+		DB:    storage.NewDB(make(storage.MapBackend)),
+		Trunk: "main",
+		Log:   silogtest.New(t),
+	})
+	require.NoError(t, err)
+
+	t.Run("DoesNotExist", func(t *testing.T) {
+		versions, err := store.LoadBranchHistory(ctx, "feature")
+		require.NoError(t, err)
+		assert.Empty(t, versions)
+	})
+
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	v1 := state.BranchVersion{
+		Span: git.CommitSpan{Base: "abc", Head: "def"},
+		Time: now,
+	}
+	v2 := state.BranchVersion{
+		Span: git.CommitSpan{Base: "abc", Head: "123"},
+		Time: now.Add(time.Hour),
+	}
+
+	n, err := store.AppendBranchVersion(ctx, "user/feature", v1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	n, err = store.AppendBranchVersion(ctx, "user/feature", v2)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	// Pushing the same head again doesn't add a version.
+	n, err = store.AppendBranchVersion(ctx, "user/feature", state.BranchVersion{
+		Span: v2.Span,
+		Time: now.Add(2 * time.Hour),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	got, err := store.LoadBranchHistory(ctx, "user/feature")
+	require.NoError(t, err)
+	assert.Equal(t, []state.BranchVersion{v1, v2}, got)
+}
+
+func TestStore_branchHistoryFollowsBranch(t *testing.T) {
+	ctx := t.Context()
+	store, err := state.InitStore(ctx, state.InitStoreRequest{
+		DB:    storage.NewDB(make(storage.MapBackend)),
+		Trunk: "main",
+		Log:   silogtest.New(t),
+	})
+	require.NoError(t, err)
+
+	v1 := state.BranchVersion{
+		Span: git.CommitSpan{Base: "abc", Head: "def"},
+		Time: time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC),
+	}
+	history := func(t *testing.T, branch string) []state.BranchVersion {
+		t.Helper()
+		got, err := store.LoadBranchHistory(ctx, branch)
+		require.NoError(t, err)
+		return got
+	}
+
+	tx := store.BeginBranchTx()
+	require.NoError(t, tx.Upsert(ctx, state.UpsertRequest{Name: "feat1", Base: "main"}))
+	require.NoError(t, tx.Commit(ctx, "track feat1"))
+	_, err = store.AppendBranchVersion(ctx, "feat1", v1)
+	require.NoError(t, err)
+
+	t.Run("Rename", func(t *testing.T) {
+		tx := store.BeginBranchTx()
+		require.NoError(t, tx.Upsert(ctx, state.UpsertRequest{Name: "feat2", Base: "main"}))
+		require.NoError(t, tx.Delete(ctx, "feat1"))
+		tx.MoveHistory("feat1", "feat2")
+		require.NoError(t, tx.Commit(ctx, "rename feat1 to feat2"))
+
+		assert.Empty(t, history(t, "feat1"))
+		assert.Equal(t, []state.BranchVersion{v1}, history(t, "feat2"))
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		tx := store.BeginBranchTx()
+		require.NoError(t, tx.Delete(ctx, "feat2"))
+		require.NoError(t, tx.Commit(ctx, "delete feat2"))
+
+		assert.Empty(t, history(t, "feat2"))
+	})
+
+	t.Run("StaleHistoryNotInherited", func(t *testing.T) {
+		// Left behind by an older version of git-spice.
+		_, err := store.AppendBranchVersion(ctx, "feat3", v1)
+		require.NoError(t, err)
+
+		tx := store.BeginBranchTx()
+		require.NoError(t, tx.Upsert(ctx, state.UpsertRequest{Name: "feat3", Base: "main"}))
+		require.NoError(t, tx.Commit(ctx, "track feat3"))
+
+		assert.Empty(t, history(t, "feat3"))
+	})
+}
@@ -0,0 +1,88 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+
+	"go.abhg.dev/gs/internal/spice/state/storage"
+)
+
+// _ownersDir is the directory holding per-branch overrides
+// for reviewers requested from code owners.
+//
+// This is used by 'branch submit' and friends
+// when requesting reviews from owners listed in CODEOWNERS files.
+const _ownersDir = "owners"
+
+type branchOwnersState struct {
+	Enabled *bool    `json:"enabled,omitempty"`
+	Add     []string `json:"add,omitempty"`
+	Skip    []string `json:"skip,omitempty"`
+}
+
+func (s *Store) branchOwnersJSON(branch string) string {
+	return path.Join(_ownersDir, branch)
+}
+
+// BranchOwners holds per-branch overrides for reviewers
+// requested from code owners when a branch is submitted.
+type BranchOwners struct {
+	// Enabled overrides whether reviews are requested
+	// from code owners for this branch.
+	// If nil, the repository's configuration is used.
+	Enabled *bool
+
+	// Add lists reviewers to request
+	// in addition to the code owners.
+	Add []string
+
+	// Skip lists code owners, users or teams,
+	// that reviews should not be requested from.
+	Skip []string
+}
+
+// SaveBranchOwners saves the code owner overrides for a branch,
+// overwriting any existing overrides.
+func (s *Store) SaveBranchOwners(ctx context.Context, branch string, owners *BranchOwners) error {
+	err := s.db.Set(ctx, s.branchOwnersJSON(branch), branchOwnersState{
+		Enabled: owners.Enabled,
+		Add:     owners.Add,
+		Skip:    owners.Skip,
+	}, fmt.Sprintf("%v: save code owner overrides", branch))
+	if err != nil {
+		return fmt.Errorf("set branch owners state: %w", err)
+	}
+	return nil
+}
+
+// LoadBranchOwners retrieves the code owner overrides for a branch
+// that were previously saved with SaveBranchOwners.
+// If there are no overrides for the branch, it returns nil.
+func (s *Store) LoadBranchOwners(ctx context.Context, branch string) (*BranchOwners, error) {
+	var state branchOwnersState
+	if err := s.db.Get(ctx, s.branchOwnersJSON(branch), &state); err != nil {
+		if errors.Is(err, storage.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get branch owners state: %w", err)
+	}
+
+	return &BranchOwners{
+		Enabled: state.Enabled,
+		Add:     state.Add,
+		Skip:    state.Skip,
+	}, nil
+}
+
+// ClearBranchOwners removes the code owner overrides for a branch.
+// This is a no-op if the branch has no overrides.
+func (s *Store) ClearBranchOwners(ctx context.Context, branch string) error {
+	err := s.db.Delete(ctx, s.branchOwnersJSON(branch),
+		fmt.Sprintf("%v: clear code owner overrides", branch))
+	if err != nil {
+		return fmt.Errorf("delete branch owners state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package state_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/silog/silogtest"
+	"go.abhg.dev/gs/internal/spice/state"
+	"go.abhg.dev/gs/internal/spice/state/storage"
+)
+
+func TestStore_branchOwners(t *testing.T) {
+	ctx := t.Context()
+	store, err := state.InitStore(ctx, state.InitStoreRequest{
+		DB:    storage.NewDB(make(storage.MapBackend)),
+		Trunk: "main",
+		Log:   silogtest.New(t),
+	})
+	require.NoError(t, err)
+
+	t.Run("DoesNotExist", func(t *testing.T) {
+		owners, err := store.LoadBranchOwners(ctx, "feature")
+		require.NoError(t, err)
+		assert.Nil(t, owners)
+	})
+
+	enabled := false
+	want := &state.BranchOwners{
+		Enabled: &enabled,
+		Add:     []string{"alice"},
+		Skip:    []string{"@org/team"},
+	}
+	require.NoError(t, store.SaveBranchOwners(ctx, "user/feature", want))
+
+	got, err := store.LoadBranchOwners(ctx, "user/feature")
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	require.NoError(t, store.ClearBranchOwners(ctx, "user/feature"))
+	got, err = store.LoadBranchOwners(ctx, "user/feature")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	// Clearing again is a no-op.
+	require.NoError(t, store.ClearBranchOwners(ctx, "user/feature"))
+}
@@ -0,0 +1,86 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+
+	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/spice/state/storage"
+)
+
+// _patchSeriesDir is the directory holding information about
+// stacks that were exported as email patch series.
+//
+// This is used by the 'stack format-patch' command
+// to number new versions of a series
+// and compare them against the previous version.
+const _patchSeriesDir = "patches"
+
+type patchSeriesState struct {
+	Versions []patchSeriesVersionState `json:"versions"`
+}
+
+type patchSeriesVersionState struct {
+	Base string `json:"base"`
+	Head string `json:"head"`
+}
+
+func (s *Store) patchSeriesJSON(name string) string {
+	return path.Join(_patchSeriesDir, name)
+}
+
+// PatchSeries records the versions of a stack
+// that were exported as email patch series.
+type PatchSeries struct {
+	// Name identifies the series.
+	Name string
+
+	// Versions lists the commits exported in each version of the series.
+	// Versions are numbered from 1,
+	// so Versions[0] is version 1.
+	Versions []git.CommitSpan
+}
+
+// SavePatchSeries saves information about a patch series,
+// overwriting any existing information about it.
+func (s *Store) SavePatchSeries(ctx context.Context, series *PatchSeries) error {
+	var state patchSeriesState
+	for _, v := range series.Versions {
+		state.Versions = append(state.Versions, patchSeriesVersionState{
+			Base: v.Base.String(),
+			Head: v.Head.String(),
+		})
+	}
+
+	err := s.db.Set(ctx, s.patchSeriesJSON(series.Name), state,
+		fmt.Sprintf("%v: save patch series v%d", series.Name, len(series.Versions)))
+	if err != nil {
+		return fmt.Errorf("set patch series state: %w", err)
+	}
+
+	return nil
+}
+
+// LoadPatchSeries retrieves information about a patch series
+// that was previously saved with SavePatchSeries.
+// If there's no information saved, it returns nil.
+func (s *Store) LoadPatchSeries(ctx context.Context, name string) (*PatchSeries, error) {
+	var state patchSeriesState
+	if err := s.db.Get(ctx, s.patchSeriesJSON(name), &state); err != nil {
+		if errors.Is(err, storage.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get patch series state: %w", err)
+	}
+
+	series := &PatchSeries{Name: name}
+	for _, v := range state.Versions {
+		series.Versions = append(series.Versions, git.CommitSpan{
+			Base: git.Hash(v.Base),
+			Head: git.Hash(v.Head),
+		})
+	}
+	return series, nil
+}
@@ -0,0 +1,41 @@
+package state_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/silog/silogtest"
+	"go.abhg.dev/gs/internal/spice/state"
+	"go.abhg.dev/gs/internal/spice/state/storage"
+)
+
+func TestStore_patchSeries(t *testing.T) {
+	ctx := t.Context()
+	store, err := state.InitStore(ctx, state.InitStoreRequest{
+		DB:    storage.NewDB(make(storage.MapBackend)),
+		Trunk: "main",
+		Log:   silogtest.New(t),
+	})
+	require.NoError(t, err)
+
+	t.Run("DoesNotExist", func(t *testing.T) {
+		series, err := store.LoadPatchSeries(ctx, "feature")
+		require.NoError(t, err)
+		assert.Nil(t, series)
+	})
+
+	want := &state.PatchSeries{
+		Name: "user/feature",
+		Versions: []git.CommitSpan{
+			{Base: "abc", Head: "def"},
+			{Base: "abc", Head: "123"},
+		},
+	}
+	require.NoError(t, store.SavePatchSeries(ctx, want))
+
+	got, err := store.LoadPatchSeries(ctx, "user/feature")
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
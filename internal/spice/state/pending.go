@@ -0,0 +1,237 @@
+package state
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"time"
+
+	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/spice/state/storage"
+)
+
+// _pendingDir is the directory holding submit operations
+// that were queued while offline.
+//
+// This is used by 'branch submit --offline' and friends
+// to record pushes and change request updates,
+// and by 'repo flush' to replay them later.
+const _pendingDir = "pending"
+
+type pendingSubmitState struct {
+	Head       string    `json:"head"`
+	RemoteHead string    `json:"remoteHead,omitempty"`
+	Queued     time.Time `json:"queued"`
+
+	Title     string   `json:"title,omitempty"`
+	Body      string   `json:"body,omitempty"`
+	Base      string   `json:"base,omitempty"`
+	Draft     *bool    `json:"draft,omitempty"`
+	Fill      bool     `json:"fill,omitempty"`
+	NoPublish bool     `json:"noPublish,omitempty"`
+	Force     bool     `json:"force,omitempty"`
+	NoVerify  bool     `json:"noVerify,omitempty"`
+	Labels    []string `json:"labels,omitempty"`
+	Reviewers []string `json:"reviewers,omitempty"`
+	Assignees []string `json:"assignees,omitempty"`
+
+	UpdateOnly   bool `json:"updateOnly,omitempty"`
+	DraftDefault bool `json:"draftDefault,omitempty"`
+	CodeOwners   bool `json:"codeOwners,omitempty"`
+	PushComment  bool `json:"pushComment,omitempty"`
+	Copilot      bool `json:"copilot,omitempty"`
+
+	NavComment          string `json:"navComment,omitempty"`
+	NavCommentSync      string `json:"navCommentSync,omitempty"`
+	NavCommentDownstack string `json:"navCommentDownstack,omitempty"`
+	NavCommentMarker    string `json:"navCommentMarker,omitempty"`
+}
+
+func (s *Store) pendingSubmitJSON(branch string) string {
+	return path.Join(_pendingDir, branch)
+}
+
+// PendingSubmit is a submit operation for a branch
+// that was queued while offline,
+// to be replayed when the forge is reachable again.
+type PendingSubmit struct {
+	// Branch is the name of the branch to submit.
+	Branch string
+
+	// Head is the commit the branch pointed to
+	// when the operation was queued.
+	Head git.Hash
+
+	// RemoteHead is the commit the branch's remote-tracking branch
+	// pointed to when the operation was queued.
+	// It's used to detect whether someone else pushed to the branch
+	// before the operation was replayed.
+	//
+	// This is [git.ZeroHash] if the branch had not been pushed.
+	RemoteHead git.Hash
+
+	// Queued is the time at which the operation was queued.
+	Queued time.Time
+
+	// Title and Body are the change request title and body
+	// if they were provided when the operation was queued.
+	Title, Body string
+
+	// Base is the base branch override, if any.
+	Base string
+
+	// Draft is the requested draft status, if any.
+	Draft *bool
+
+	// Fill requests that the title and body be filled
+	// from commit messages.
+	Fill bool
+
+	// NoPublish indicates that the branch should only be pushed,
+	// without creating a change request.
+	NoPublish bool
+
+	// Force and NoVerify are the push options
+	// requested when the operation was queued.
+	Force, NoVerify bool
+
+	// Labels, Reviewers, and Assignees to add to the change request.
+	Labels, Reviewers, Assignees []string
+
+	// UpdateOnly skips the branch if it doesn't have a change request.
+	UpdateOnly bool
+
+	// DraftDefault is the draft status for new change requests
+	// if Draft is not set.
+	DraftDefault bool
+
+	// CodeOwners, PushComment, and Copilot record
+	// whether those submit features were enabled.
+	CodeOwners, PushComment, Copilot bool
+
+	// NavComment, NavCommentSync, NavCommentDownstack,
+	// and NavCommentMarker record how navigation comments
+	// should be updated once the branch is submitted.
+	// These hold the textual form of the submit options.
+	NavComment          string
+	NavCommentSync      string
+	NavCommentDownstack string
+	NavCommentMarker    string
+}
+
+// SavePendingSubmit queues a submit operation for a branch.
+// If the branch already has a pending operation, it will be overwritten.
+func (s *Store) SavePendingSubmit(ctx context.Context, p *PendingSubmit) error {
+	state := pendingSubmitState{
+		Head:      p.Head.String(),
+		Queued:    p.Queued,
+		Title:     p.Title,
+		Body:      p.Body,
+		Base:      p.Base,
+		Draft:     p.Draft,
+		Fill:      p.Fill,
+		NoPublish: p.NoPublish,
+		Force:     p.Force,
+		NoVerify:  p.NoVerify,
+		Labels:    p.Labels,
+		Reviewers: p.Reviewers,
+		Assignees: p.Assignees,
+
+		UpdateOnly:   p.UpdateOnly,
+		DraftDefault: p.DraftDefault,
+		CodeOwners:   p.CodeOwners,
+		PushComment:  p.PushComment,
+		Copilot:      p.Copilot,
+
+		NavComment:          p.NavComment,
+		NavCommentSync:      p.NavCommentSync,
+		NavCommentDownstack: p.NavCommentDownstack,
+		NavCommentMarker:    p.NavCommentMarker,
+	}
+	if p.RemoteHead != git.ZeroHash {
+		state.RemoteHead = p.RemoteHead.String()
+	}
+
+	err := s.db.Set(ctx, s.pendingSubmitJSON(p.Branch), state,
+		fmt.Sprintf("%v: queue submit", p.Branch))
+	if err != nil {
+		return fmt.Errorf("set pending submit state: %w", err)
+	}
+	return nil
+}
+
+// ListPendingSubmits reports all queued submit operations
+// in the order they were queued.
+func (s *Store) ListPendingSubmits(ctx context.Context) ([]*PendingSubmit, error) {
+	branches, err := s.db.Keys(ctx, _pendingDir)
+	if err != nil {
+		return nil, fmt.Errorf("list pending submits: %w", err)
+	}
+
+	pending := make([]*PendingSubmit, 0, len(branches))
+	for _, branch := range branches {
+		var state pendingSubmitState
+		if err := s.db.Get(ctx, s.pendingSubmitJSON(branch), &state); err != nil {
+			if errors.Is(err, storage.ErrNotExist) {
+				continue
+			}
+			return nil, fmt.Errorf("get pending submit %v: %w", branch, err)
+		}
+
+		remoteHead := git.ZeroHash
+		if state.RemoteHead != "" {
+			remoteHead = git.Hash(state.RemoteHead)
+		}
+
+		pending = append(pending, &PendingSubmit{
+			Branch:     branch,
+			Head:       git.Hash(state.Head),
+			RemoteHead: remoteHead,
+			Queued:     state.Queued,
+			Title:      state.Title,
+			Body:       state.Body,
+			Base:       state.Base,
+			Draft:      state.Draft,
+			Fill:       state.Fill,
+			NoPublish:  state.NoPublish,
+			Force:      state.Force,
+			NoVerify:   state.NoVerify,
+			Labels:     state.Labels,
+			Reviewers:  state.Reviewers,
+			Assignees:  state.Assignees,
+
+			UpdateOnly:   state.UpdateOnly,
+			DraftDefault: state.DraftDefault,
+			CodeOwners:   state.CodeOwners,
+			PushComment:  state.PushComment,
+			Copilot:      state.Copilot,
+
+			NavComment:          state.NavComment,
+			NavCommentSync:      state.NavCommentSync,
+			NavCommentDownstack: state.NavCommentDownstack,
+			NavCommentMarker:    state.NavCommentMarker,
+		})
+	}
+
+	slices.SortStableFunc(pending, func(a, b *PendingSubmit) int {
+		return cmp.Or(
+			a.Queued.Compare(b.Queued),
+			cmp.Compare(a.Branch, b.Branch),
+		)
+	})
+	return pending, nil
+}
+
+// ClearPendingSubmit removes the queued submit operation for a branch.
+// This is a no-op if the branch has no pending operation.
+func (s *Store) ClearPendingSubmit(ctx context.Context, branch string) error {
+	err := s.db.Delete(ctx, s.pendingSubmitJSON(branch),
+		fmt.Sprintf("%v: clear pending submit", branch))
+	if err != nil {
+		return fmt.Errorf("delete pending submit state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package state_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/silog/silogtest"
+	"go.abhg.dev/gs/internal/spice/state"
+	"go.abhg.dev/gs/internal/spice/state/storage"
+)
+
+func TestStore_pendingSubmits(t *testing.T) {
+	ctx := t.Context()
+	store, err := state.InitStore(ctx, state.InitStoreRequest{
+		DB:    storage.NewDB(make(storage.MapBackend)),
+		Trunk: "main",
+		Log:   silogtest.New(t),
+	})
+	require.NoError(t, err)
+
+	t.Run("Empty", func(t *testing.T) {
+		pending, err := store.ListPendingSubmits(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, pending)
+	})
+
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	draft := true
+	feat2 := &state.PendingSubmit{
+		Branch:     "user/feat2",
+		Head:       "2222222222222222222222222222222222222222",
+		RemoteHead: git.ZeroHash,
+		Queued:     now.Add(time.Second),
+		Fill:       true,
+		Labels:     []string{"bug"},
+	}
+	feat1 := &state.PendingSubmit{
+		Branch:     "feat1",
+		Head:       "1111111111111111111111111111111111111111",
+		RemoteHead: "3333333333333333333333333333333333333333",
+		Queued:     now,
+		Title:      "Add feature",
+		Body:       "Feature body",
+		Draft:      &draft,
+		Reviewers:  []string{"bob"},
+		CodeOwners: true,
+		NavComment: "multiple",
+	}
+	require.NoError(t, store.SavePendingSubmit(ctx, feat2))
+	require.NoError(t, store.SavePendingSubmit(ctx, feat1))
+
+	pending, err := store.ListPendingSubmits(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []*state.PendingSubmit{feat1, feat2}, pending,
+		"should be listed in the order they were queued")
+
+	require.NoError(t, store.ClearPendingSubmit(ctx, "user/feat2"))
+	pending, err = store.ListPendingSubmits(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []*state.PendingSubmit{feat1}, pending)
+
+	// Clearing again is a no-op.
+	require.NoError(t, store.ClearPendingSubmit(ctx, "user/feat2"))
+}
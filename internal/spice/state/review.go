@@ -0,0 +1,92 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+
+	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/spice/state/storage"
+)
+
+// _reviewsDir is the directory holding results of Claude reviews.
+//
+// This is used by the 'claude review' command
+// to avoid reviewing unchanged code again.
+const _reviewsDir = "reviews"
+
+type cachedReviewState struct {
+	Response string `json:"response"`
+}
+
+type lastReviewState struct {
+	Base string `json:"base"`
+	Head string `json:"head"`
+}
+
+func (s *Store) cachedReviewJSON(key string) string {
+	return path.Join(_reviewsDir, "cache", key)
+}
+
+func (s *Store) lastReviewJSON(branch string) string {
+	return path.Join(_reviewsDir, "branches", branch)
+}
+
+// SaveCachedReview saves the response to a review prompt
+// under a key identifying the prompt.
+func (s *Store) SaveCachedReview(ctx context.Context, key, response string) error {
+	err := s.db.Set(ctx, s.cachedReviewJSON(key), cachedReviewState{
+		Response: response,
+	}, "cache review "+key)
+	if err != nil {
+		return fmt.Errorf("set cached review: %w", err)
+	}
+	return nil
+}
+
+// LoadCachedReview retrieves the response to a review prompt
+// that was previously saved with SaveCachedReview.
+// It reports false if there's no response saved for the key.
+func (s *Store) LoadCachedReview(ctx context.Context, key string) (string, bool, error) {
+	var state cachedReviewState
+	if err := s.db.Get(ctx, s.cachedReviewJSON(key), &state); err != nil {
+		if errors.Is(err, storage.ErrNotExist) {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("get cached review: %w", err)
+	}
+	return state.Response, true, nil
+}
+
+// SaveLastReview records the commits of a branch
+// that were reviewed most recently,
+// overwriting the previous record for the branch.
+func (s *Store) SaveLastReview(ctx context.Context, branch string, span git.CommitSpan) error {
+	err := s.db.Set(ctx, s.lastReviewJSON(branch), lastReviewState{
+		Base: span.Base.String(),
+		Head: span.Head.String(),
+	}, fmt.Sprintf("%v: record review of %v", branch, span.Head.Short()))
+	if err != nil {
+		return fmt.Errorf("set last review: %w", err)
+	}
+	return nil
+}
+
+// LoadLastReview retrieves the commits of a branch
+// that were recorded with SaveLastReview.
+// If there's no record for the branch, it returns nil.
+func (s *Store) LoadLastReview(ctx context.Context, branch string) (*git.CommitSpan, error) {
+	var state lastReviewState
+	if err := s.db.Get(ctx, s.lastReviewJSON(branch), &state); err != nil {
+		if errors.Is(err, storage.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get last review: %w", err)
+	}
+
+	return &git.CommitSpan{
+		Base: git.Hash(state.Base),
+		Head: git.Hash(state.Head),
+	}, nil
+}
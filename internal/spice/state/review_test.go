@@ -0,0 +1,54 @@
+package state_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/silog/silogtest"
+	"go.abhg.dev/gs/internal/spice/state"
+	"go.abhg.dev/gs/internal/spice/state/storage"
+)
+
+func TestStore_cachedReview(t *testing.T) {
+	ctx := t.Context()
+	store, err := state.InitStore(ctx, state.InitStoreRequest{
+		DB:    storage.NewDB(make(storage.MapBackend)),
+		Trunk: "main",
+		Log:   silogtest.New(t),
+	})
+	require.NoError(t, err)
+
+	_, ok, err := store.LoadCachedReview(ctx, "abc")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, store.SaveCachedReview(ctx, "abc", "looks good"))
+
+	got, ok, err := store.LoadCachedReview(ctx, "abc")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "looks good", got)
+}
+
+func TestStore_lastReview(t *testing.T) {
+	ctx := t.Context()
+	store, err := state.InitStore(ctx, state.InitStoreRequest{
+		DB:    storage.NewDB(make(storage.MapBackend)),
+		Trunk: "main",
+		Log:   silogtest.New(t),
+	})
+	require.NoError(t, err)
+
+	got, err := store.LoadLastReview(ctx, "user/feature")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	want := git.CommitSpan{Base: "abc", Head: "def"}
+	require.NoError(t, store.SaveLastReview(ctx, "user/feature", want))
+
+	got, err = store.LoadLastReview(ctx, "user/feature")
+	require.NoError(t, err)
+	assert.Equal(t, &want, got)
+}
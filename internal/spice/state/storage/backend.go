@@ -33,6 +33,12 @@ type SetRequest struct {
 // ErrNotExist indicates that a key that was expected to exist does not exist.
 var ErrNotExist = errors.New("does not exist in store")
 
+// ErrConflict indicates that a write could not be applied
+// because the store was changed concurrently:
+// either the same keys were written,
+// or the store kept changing until the write gave up.
+var ErrConflict = errors.New("store was modified concurrently")
+
 // Backend defines the primitive operations for the key-value store.
 type Backend interface {
 	// Get retrieves a value from the store
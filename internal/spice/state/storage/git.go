@@ -8,7 +8,11 @@ import (
 	"fmt"
 	"io"
 	"iter"
+	"math/rand/v2"
+	"path"
+	"strings"
 	"sync"
+	"time"
 
 	"go.abhg.dev/gs/internal/git"
 	"go.abhg.dev/gs/internal/must"
@@ -19,12 +23,11 @@ import (
 type GitRepository interface {
 	PeelToCommit(ctx context.Context, ref string) (git.Hash, error)
 	PeelToTree(ctx context.Context, ref string) (git.Hash, error)
-	HashAt(ctx context.Context, commitish, path string) (git.Hash, error)
 
-	ReadObject(ctx context.Context, typ git.Type, hash git.Hash, dst io.Writer) error
+	ReadObjectAt(ctx context.Context, treeish, path string, dst io.Writer) error
 	WriteObject(ctx context.Context, typ git.Type, src io.Reader) (git.Hash, error)
 
-	ListTree(ctx context.Context, tree git.Hash, opts git.ListTreeOptions) iter.Seq2[git.TreeEntry, error]
+	ReadTree(ctx context.Context, treeish string, opts git.ListTreeOptions) iter.Seq2[git.TreeEntry, error]
 	CommitTree(ctx context.Context, req git.CommitTreeRequest) (git.Hash, error)
 	UpdateTree(ctx context.Context, req git.UpdateTreeRequest) (git.Hash, error)
 	MakeTree(ctx context.Context, ents iter.Seq2[git.TreeEntry, error]) (git.Hash, int, error)
@@ -78,25 +81,17 @@ func (g *GitBackend) Keys(ctx context.Context, dir string) ([]string, error) {
 	g.mu.RLock()
 	defer g.mu.RUnlock()
 
-	var (
-		treeHash git.Hash
-		err      error
-	)
-	if dir == "" {
-		treeHash, err = g.repo.PeelToTree(ctx, g.ref)
-	} else {
-		treeHash, err = g.repo.HashAt(ctx, g.ref, dir)
-	}
-	if err != nil {
-		if errors.Is(err, git.ErrNotExist) {
-			return nil, nil // no keys
-		}
-		return nil, fmt.Errorf("get tree hash: %w", err)
+	treeish := g.ref + "^{tree}"
+	if dir != "" {
+		treeish = g.ref + ":" + dir
 	}
 
 	var keys []string
-	for ent, err := range g.repo.ListTree(ctx, treeHash, git.ListTreeOptions{Recurse: true}) {
+	for ent, err := range g.repo.ReadTree(ctx, treeish, git.ListTreeOptions{Recurse: true}) {
 		if err != nil {
+			if errors.Is(err, git.ErrNotExist) {
+				return nil, nil // no keys
+			}
 			return nil, fmt.Errorf("list tree: %w", err)
 		}
 
@@ -115,13 +110,11 @@ func (g *GitBackend) Get(ctx context.Context, key string, v any) error {
 	g.mu.RLock()
 	defer g.mu.RUnlock()
 
-	blobHash, err := g.repo.HashAt(ctx, g.ref, key)
-	if err != nil {
-		return ErrNotExist
-	}
-
 	var buf bytes.Buffer
-	if err := g.repo.ReadObject(ctx, git.BlobType, blobHash, &buf); err != nil {
+	if err := g.repo.ReadObjectAt(ctx, g.ref, key, &buf); err != nil {
+		if errors.Is(err, git.ErrNotExist) {
+			return ErrNotExist
+		}
 		return fmt.Errorf("read object: %w", err)
 	}
 
@@ -137,39 +130,16 @@ func (g *GitBackend) Clear(ctx context.Context, msg string) error {
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
-	prevCommit, err := g.repo.PeelToCommit(ctx, g.ref)
-	if err != nil {
-		prevCommit = "" // not initialized
-	}
-
 	tree, _, err := g.repo.MakeTree(ctx, func(func(git.TreeEntry, error) bool) {})
 	if err != nil {
 		return fmt.Errorf("make tree: %w", err)
 	}
 
-	commitReq := git.CommitTreeRequest{
-		Tree:      tree,
-		Message:   msg,
-		Author:    &g.sig,
-		Committer: &g.sig,
-	}
-	if prevCommit != "" {
-		commitReq.Parents = []git.Hash{prevCommit}
-	}
-	newCommit, err := g.repo.CommitTree(ctx, commitReq)
-	if err != nil {
-		return fmt.Errorf("commit: %w", err)
-	}
-
-	if err := g.repo.SetRef(ctx, git.SetRefRequest{
-		Ref:     g.ref,
-		Hash:    newCommit,
-		OldHash: prevCommit,
-	}); err != nil {
-		return fmt.Errorf("update ref: %w", err)
-	}
-
-	return nil
+	// Clearing discards everything, including concurrent writes,
+	// so there are no keys to check for conflicts.
+	return g.commit(ctx, msg, nil, func(git.Hash) (git.Hash, error) {
+		return tree, nil
+	})
 }
 
 // Update applies a batch of changes to the store.
@@ -196,36 +166,93 @@ func (g *GitBackend) Update(ctx context.Context, req UpdateRequest) error {
 		setBlobs[i] = blobHash
 	}
 
-	var updateErr error
-	for range 5 {
-		var prevTree git.Hash
-		prevCommit, err := g.repo.PeelToCommit(ctx, g.ref)
+	writes := make([]git.BlobInfo, len(req.Sets))
+	for i, set := range req.Sets {
+		writes[i] = git.BlobInfo{
+			Mode: git.RegularMode,
+			Path: set.Key,
+			Hash: setBlobs[i],
+		}
+	}
+
+	keys := make([]string, 0, len(req.Sets)+len(req.Deletes))
+	for _, set := range req.Sets {
+		keys = append(keys, set.Key)
+	}
+	keys = append(keys, req.Deletes...)
+
+	return g.commit(ctx, req.Message, keys, func(prevTree git.Hash) (git.Hash, error) {
+		newTree, err := g.repo.UpdateTree(ctx, git.UpdateTreeRequest{
+			Tree:    prevTree,
+			Writes:  writes,
+			Deletes: req.Deletes,
+		})
 		if err != nil {
-			prevCommit = ""
-			prevTree = ""
-		} else {
+			return "", fmt.Errorf("update tree: %w", err)
+		}
+		return newTree, nil
+	})
+}
+
+// _maxCommitAttempts is the number of times commit will try
+// to update the ref before giving up.
+const _maxCommitAttempts = 10
+
+// commit records a new state commit on top of the current one.
+//
+// makeTree is called with the tree of the current commit
+// (empty if the ref doesn't exist yet)
+// and returns the tree for the new commit.
+// No commit is made if the tree doesn't change.
+//
+// The ref is updated with a compare-and-swap
+// so that concurrent writers (in this or other processes)
+// never overwrite each other's changes.
+// If the ref changes between reading and updating it,
+// the new state is re-read, makeTree is re-applied to it,
+// and the update is retried.
+// The retry fails with ErrConflict instead
+// if any of the given keys were changed by the concurrent writer:
+// makeTree would overwrite those changes.
+func (g *GitBackend) commit(
+	ctx context.Context,
+	msg string,
+	keys []string,
+	makeTree func(prevTree git.Hash) (git.Hash, error),
+) error {
+	var (
+		baseTree  git.Hash // tree read by the first attempt
+		updateErr error
+	)
+	for attempt := range _maxCommitAttempts {
+		if attempt > 0 {
+			if err := sleepBackoff(ctx, attempt); err != nil {
+				return err
+			}
+		}
+
+		prevCommit, err := g.head(ctx)
+		if err != nil {
+			return err
+		}
+
+		var prevTree git.Hash
+		if prevCommit != "" {
 			prevTree, err = g.repo.PeelToTree(ctx, prevCommit.String())
 			if err != nil {
 				return fmt.Errorf("get tree for %v: %w", prevCommit, err)
 			}
 		}
 
-		writes := make([]git.BlobInfo, len(req.Sets))
-		for i, req := range req.Sets {
-			writes[i] = git.BlobInfo{
-				Mode: git.RegularMode,
-				Path: req.Key,
-				Hash: setBlobs[i],
-			}
+		if attempt == 0 {
+			baseTree = prevTree
+		} else if err := g.checkUnchanged(ctx, baseTree, prevTree, keys); err != nil {
+			return err
 		}
 
-		newTree, err := g.repo.UpdateTree(ctx, git.UpdateTreeRequest{
-			Tree:    prevTree,
-			Writes:  writes,
-			Deletes: req.Deletes,
-		})
+		newTree, err := makeTree(prevTree)
 		if err != nil {
-			return fmt.Errorf("update tree: %w", err)
+			return err
 		}
 
 		// The tree didn't change, so we don't need to commit.
@@ -235,30 +262,118 @@ func (g *GitBackend) Update(ctx context.Context, req UpdateRequest) error {
 
 		commitReq := git.CommitTreeRequest{
 			Tree:      newTree,
-			Message:   req.Message,
+			Message:   msg,
 			Author:    &g.sig,
 			Committer: &g.sig,
 		}
+		oldHash := git.ZeroHash // ref must not exist yet
 		if prevCommit != "" {
 			commitReq.Parents = []git.Hash{prevCommit}
+			oldHash = prevCommit
 		}
 		newCommit, err := g.repo.CommitTree(ctx, commitReq)
 		if err != nil {
 			return fmt.Errorf("commit: %w", err)
 		}
 
-		if err := g.repo.SetRef(ctx, git.SetRefRequest{
+		err = g.repo.SetRef(ctx, git.SetRefRequest{
 			Ref:     g.ref,
 			Hash:    newCommit,
-			OldHash: prevCommit,
-		}); err != nil {
-			updateErr = err
-			g.log.Warn("could not update ref: retrying", "error", err)
-			continue
+			OldHash: oldHash,
+		})
+		if err == nil {
+			return nil
 		}
 
-		return nil
+		// If the ref still points to what we read,
+		// the update failed for a reason other than a conflict,
+		// and retrying won't help.
+		if cur, headErr := g.head(ctx); headErr != nil || cur == prevCommit {
+			return fmt.Errorf("set ref: %w", err)
+		}
+
+		updateErr = err
+		g.log.Debug("State was updated concurrently: retrying", "attempt", attempt+1, "error", err)
+	}
+
+	return fmt.Errorf("set ref: %w: %w", ErrConflict, updateErr)
+}
+
+// checkUnchanged returns ErrConflict
+// if any of the given keys differ between the two trees.
+func (g *GitBackend) checkUnchanged(ctx context.Context, oldTree, newTree git.Hash, keys []string) error {
+	for _, key := range keys {
+		oldHash, err := g.blobAt(ctx, oldTree, key)
+		if err != nil {
+			return err
+		}
+
+		newHash, err := g.blobAt(ctx, newTree, key)
+		if err != nil {
+			return err
+		}
+
+		if oldHash != newHash {
+			return fmt.Errorf("%v: %w", key, ErrConflict)
+		}
+	}
+	return nil
+}
+
+// blobAt returns the hash of the object at the given key in a tree,
+// or an empty hash if the tree or key doesn't exist.
+func (g *GitBackend) blobAt(ctx context.Context, tree git.Hash, key string) (git.Hash, error) {
+	if tree == "" {
+		return "", nil
+	}
+
+	treeish := tree.String()
+	dir, name := path.Split(key)
+	if dir != "" {
+		treeish += ":" + strings.TrimSuffix(dir, "/")
 	}
 
-	return fmt.Errorf("set ref: %w", updateErr)
+	for ent, err := range g.repo.ReadTree(ctx, treeish, git.ListTreeOptions{}) {
+		if err != nil {
+			if errors.Is(err, git.ErrNotExist) {
+				return "", nil
+			}
+			return "", fmt.Errorf("list tree: %w", err)
+		}
+
+		if ent.Name == name {
+			return ent.Hash, nil
+		}
+	}
+	return "", nil
+}
+
+// head returns the commit that the state ref points to,
+// or an empty hash if the ref doesn't exist.
+func (g *GitBackend) head(ctx context.Context) (git.Hash, error) {
+	hash, err := g.repo.PeelToCommit(ctx, g.ref)
+	if err != nil {
+		if errors.Is(err, git.ErrNotExist) {
+			return "", nil
+		}
+		return "", fmt.Errorf("resolve %v: %w", g.ref, err)
+	}
+	return hash, nil
+}
+
+// sleepBackoff sleeps before the given retry attempt,
+// waiting longer with each attempt.
+// Jitter keeps concurrent writers from retrying in lockstep.
+func sleepBackoff(ctx context.Context, attempt int) error {
+	d := time.Duration(attempt) * 10 * time.Millisecond
+	d += rand.N(d) // jitter
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
 }
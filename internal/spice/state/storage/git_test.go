@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"context"
 	cryptorand "crypto/rand"
 	"encoding/hex"
 	"errors"
@@ -187,6 +188,142 @@ func TestGitBackend_ConcurrentOperations(t *testing.T) {
 	assert.Equal(t, "final_value", finalValue)
 }
 
+// Backends that don't share a mutex,
+// like git-spice invocations in different worktrees,
+// must not lose each other's updates.
+func TestGitBackend_ConcurrentBackends(t *testing.T) {
+	ctx := t.Context()
+	dir := t.TempDir()
+	_, _, err := git.Init(ctx, dir, git.InitOptions{
+		Log: silog.Nop(),
+	})
+	require.NoError(t, err)
+
+	const (
+		NumBackends      = 5
+		UpdatesPerWorker = 10
+	)
+
+	var wg sync.WaitGroup
+	for backendIdx := range NumBackends {
+		repo, err := git.Open(ctx, dir, git.OpenOptions{Log: silog.Nop()})
+		require.NoError(t, err)
+		t.Cleanup(func() { assert.NoError(t, repo.Close()) })
+
+		backend := NewGitBackend(GitConfig{
+			Repo:        repo,
+			Ref:         "refs/data",
+			AuthorName:  "Test Author",
+			AuthorEmail: "test@example.com",
+			Log:         silogtest.New(t),
+		})
+
+		wg.Go(func() {
+			for i := range UpdatesPerWorker {
+				key := fmt.Sprintf("backend%d/key%d", backendIdx, i)
+				assert.NoError(t, NewDB(backend).Set(ctx, key, i, "set "+key))
+			}
+		})
+	}
+	wg.Wait()
+
+	repo, err := git.Open(ctx, dir, git.OpenOptions{Log: silog.Nop()})
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, repo.Close()) })
+
+	keys, err := NewGitBackend(GitConfig{
+		Repo: repo,
+		Ref:  "refs/data",
+	}).Keys(ctx, "")
+	require.NoError(t, err)
+	assert.Len(t, keys, NumBackends*UpdatesPerWorker)
+}
+
+// A write that races with another backend
+// must not overwrite the keys that backend wrote.
+func TestGitBackend_ConflictingKeys(t *testing.T) {
+	tests := []struct {
+		name string
+
+		// theirKey is the key written concurrently.
+		theirKey string
+		wantErr  bool
+	}{
+		{name: "SameKey", theirKey: "dir/foo", wantErr: true},
+		{name: "OtherKey", theirKey: "dir/bar"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := t.Context()
+			repo, _, err := git.Init(ctx, t.TempDir(), git.InitOptions{
+				Log: silogtest.New(t),
+			})
+			require.NoError(t, err)
+
+			theirs := NewDB(NewGitBackend(GitConfig{
+				Repo:        repo,
+				Ref:         "refs/data",
+				AuthorName:  "Test Author",
+				AuthorEmail: "test@example.com",
+				Log:         silogtest.New(t),
+			}))
+			require.NoError(t, theirs.Set(ctx, "dir/foo", "initial", "initial set"))
+
+			// Write to the store right before our first ref update.
+			var raced bool
+			ours := NewDB(NewGitBackend(GitConfig{
+				Repo: &setRefHookRepo{
+					GitRepository: repo,
+					beforeSetRef: func() {
+						if raced {
+							return
+						}
+						raced = true
+						require.NoError(t, theirs.Set(ctx, tt.theirKey, "theirs", "concurrent set"))
+					},
+				},
+				Ref:         "refs/data",
+				AuthorName:  "Test Author",
+				AuthorEmail: "test@example.com",
+				Log:         silogtest.New(t),
+			}))
+
+			err = ours.Set(ctx, "dir/foo", "ours", "our set")
+			require.True(t, raced, "concurrent write did not happen")
+
+			var got string
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, ErrConflict)
+
+				require.NoError(t, ours.Get(ctx, "dir/foo", &got))
+				assert.Equal(t, "theirs", got)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NoError(t, ours.Get(ctx, "dir/foo", &got))
+			assert.Equal(t, "ours", got)
+			require.NoError(t, ours.Get(ctx, tt.theirKey, &got))
+			assert.Equal(t, "theirs", got)
+		})
+	}
+}
+
+// setRefHookRepo is a GitRepository
+// that calls a function before every ref update.
+type setRefHookRepo struct {
+	GitRepository
+
+	beforeSetRef func()
+}
+
+func (r *setRefHookRepo) SetRef(ctx context.Context, req git.SetRefRequest) error {
+	r.beforeSetRef()
+	return r.GitRepository.SetRef(ctx, req)
+}
+
 func TestGitBackend_SpecialCharacterKeys(t *testing.T) {
 	ctx := t.Context()
 	repo, _, err := git.Init(ctx, t.TempDir(), git.InitOptions{
@@ -253,3 +390,53 @@ func TestGitBackend_SpecialCharacterKeys(t *testing.T) {
 		}
 	})
 }
+
+// BenchmarkGitBackend_loadAll measures reading every branch
+// from a store with many tracked branches,
+// as done when loading the branch graph.
+func BenchmarkGitBackend_loadAll(b *testing.B) {
+	ctx := b.Context()
+	repo, _, err := git.Init(ctx, b.TempDir(), git.InitOptions{
+		Log: silog.Nop(),
+	})
+	require.NoError(b, err)
+	b.Cleanup(func() { assert.NoError(b, repo.Close()) })
+
+	backend := NewGitBackend(GitConfig{
+		Repo:        repo,
+		Ref:         "refs/data",
+		AuthorName:  "Test Author",
+		AuthorEmail: "test@example.com",
+		Log:         silog.Nop(),
+	})
+
+	const numBranches = 300
+	sets := make([]SetRequest, numBranches)
+	for i := range sets {
+		sets[i] = SetRequest{
+			Key:   fmt.Sprintf("branches/feature-%03d", i),
+			Value: map[string]string{"base": "main"},
+		}
+	}
+	require.NoError(b, backend.Update(ctx, UpdateRequest{
+		Sets:    sets,
+		Message: "add branches",
+	}))
+
+	for b.Loop() {
+		keys, err := backend.Keys(ctx, "branches")
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(keys) != numBranches {
+			b.Fatalf("expected %d keys, got %d", numBranches, len(keys))
+		}
+
+		for _, key := range keys {
+			var v map[string]string
+			if err := backend.Get(ctx, "branches/"+key, &v); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,303 @@
+// Package tracing records spans of work done by git-spice,
+// such as Git commands and forge requests,
+// and writes them in the Chrome trace event format.
+//
+// Traces may be opened in https://ui.perfetto.dev
+// or chrome://tracing.
+//
+// Spans are recorded only if a [Recorder] is attached to the context
+// with [WithRecorder].
+// Otherwise, [Start] returns a nil [*Span], which is safe to use.
+//
+// See https://docs.google.com/document/d/1CvAClvFfyA5R-PhYUmn5OOQtYMH4h6I0nSsKchNAySU
+// for the format.
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Categories of spans.
+const (
+	CategoryExec    = "exec"
+	CategoryHTTP    = "http"
+	CategoryGraphQL = "graphql"
+	CategoryHandler = "handler"
+)
+
+// Recorder records spans and writes them to a writer
+// as a JSON array of trace events.
+//
+// Events are written as spans end,
+// so the output is usable even if the program exits
+// before the recorder is closed.
+type Recorder struct {
+	mu    sync.Mutex
+	w     io.Writer
+	start time.Time
+	pid   int
+	err   error // first write error
+	count int   // number of events written
+
+	// lanes holds the open spans on each lane (thread ID) of the trace.
+	// Spans on a lane must strictly nest,
+	// so concurrent spans are placed on separate lanes.
+	lanes [][]*Span
+
+	now func() time.Time // for testing
+}
+
+// NewRecorder builds a Recorder that writes events to w.
+// name is shown as the process name in trace viewers.
+//
+// The caller must call Close to finish the trace.
+func NewRecorder(w io.Writer, name string) *Recorder {
+	r := &Recorder{
+		w:   w,
+		pid: os.Getpid(),
+		now: time.Now,
+	}
+	r.start = r.now()
+
+	r.write([]byte("[\n"))
+	r.writeEvent(event{
+		Name:  "process_name",
+		Phase: "M",
+		PID:   r.pid,
+		Args:  map[string]any{"name": name},
+	})
+	return r
+}
+
+// Close finishes the trace.
+// Spans that are still open are not recorded.
+//
+// It returns the first error encountered while writing the trace.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.write([]byte("\n]\n"))
+	return r.err
+}
+
+// event is a single trace event.
+type event struct {
+	Name     string         `json:"name"`
+	Category string         `json:"cat,omitempty"`
+	Phase    string         `json:"ph"`
+	Time     int64          `json:"ts"`            // microseconds
+	Duration int64          `json:"dur,omitempty"` // microseconds
+	PID      int            `json:"pid"`
+	TID      int            `json:"tid"`
+	Args     map[string]any `json:"args,omitempty"`
+}
+
+// writeEvent writes an event,
+// separating it from the previous one with a comma.
+// Trace viewers accept an array without the closing bracket,
+// so the output is valid after every event.
+// mu must be held.
+func (r *Recorder) writeEvent(ev event) {
+	bs, err := json.Marshal(ev)
+	if err != nil {
+		if r.err == nil {
+			r.err = fmt.Errorf("encode event %q: %w", ev.Name, err)
+		}
+		return
+	}
+	if r.count > 0 {
+		r.write([]byte(",\n"))
+	}
+	r.write(bs)
+	r.count++
+}
+
+// write writes bs to the output, recording the first error.
+// mu must be held.
+func (r *Recorder) write(bs []byte) {
+	if r.err != nil {
+		return
+	}
+	if _, err := r.w.Write(bs); err != nil {
+		r.err = err
+	}
+}
+
+// startSpan places a new span on a lane.
+func (r *Recorder) startSpan(parent *Span, category, name string, args map[string]any) *Span {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s := &Span{
+		r:        r,
+		category: category,
+		name:     name,
+		args:     args,
+		start:    r.now(),
+		lane:     -1,
+	}
+
+	// Stay on the parent's lane if the parent is innermost there.
+	// Otherwise, a sibling is running concurrently,
+	// so use the first idle lane.
+	if parent != nil && parent.lane >= 0 {
+		if open := r.lanes[parent.lane]; len(open) > 0 && open[len(open)-1] == parent {
+			s.lane = parent.lane
+		}
+	}
+	if s.lane < 0 {
+		for i, open := range r.lanes {
+			if len(open) == 0 {
+				s.lane = i
+				break
+			}
+		}
+	}
+	if s.lane < 0 {
+		s.lane = len(r.lanes)
+		r.lanes = append(r.lanes, nil)
+	}
+
+	r.lanes[s.lane] = append(r.lanes[s.lane], s)
+	return s
+}
+
+// endSpan removes a span from its lane and records it.
+func (r *Recorder) endSpan(s *Span, args map[string]any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	end := r.now()
+	open := r.lanes[s.lane]
+	for i := len(open) - 1; i >= 0; i-- {
+		if open[i] == s {
+			r.lanes[s.lane] = append(open[:i], open[i+1:]...)
+			break
+		}
+	}
+
+	r.writeEvent(event{
+		Name:     s.name,
+		Category: s.category,
+		Phase:    "X",
+		Time:     s.start.Sub(r.start).Microseconds(),
+		Duration: max(end.Sub(s.start).Microseconds(), 1),
+		PID:      r.pid,
+		TID:      s.lane + 1,
+		Args:     args,
+	})
+}
+
+// Span is a unit of work being recorded.
+//
+// All methods are safe to call on a nil Span.
+type Span struct {
+	r        *Recorder
+	category string
+	name     string
+	start    time.Time
+	lane     int
+
+	mu    sync.Mutex
+	args  map[string]any
+	ended bool
+}
+
+// SetArgs attaches key-value pairs to the span.
+// Keys must be strings.
+// It has no effect after the span has ended.
+func (s *Span) SetArgs(kvs ...any) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.ended {
+		s.args = appendArgs(s.args, kvs)
+	}
+}
+
+// End ends the span and records it.
+// Only the first call to End has an effect.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	args := s.args
+	s.mu.Unlock()
+
+	s.r.endSpan(s, args)
+}
+
+type recorderKey struct{}
+
+type spanKey struct{}
+
+// WithRecorder returns a context that records spans to r.
+func WithRecorder(ctx context.Context, r *Recorder) context.Context {
+	return context.WithValue(ctx, recorderKey{}, r)
+}
+
+// WithoutRecorder returns a context that doesn't record spans.
+//
+// Use this for work that outlives the span that started it,
+// such as long-lived background processes,
+// and record individual operations on it instead.
+func WithoutRecorder(ctx context.Context) context.Context {
+	return context.WithValue(ctx, recorderKey{}, (*Recorder)(nil))
+}
+
+// Start starts a span with the given category and name.
+// kvs are key-value pairs attached to the span; keys must be strings.
+//
+// The returned context holds the span,
+// so spans started from it are recorded as its children.
+// If ctx doesn't have a recorder, Start returns ctx and a nil span.
+//
+// The caller must call End on the span.
+func Start(ctx context.Context, category, name string, kvs ...any) (context.Context, *Span) {
+	r, _ := ctx.Value(recorderKey{}).(*Recorder)
+	if r == nil {
+		return ctx, nil
+	}
+
+	parent, _ := ctx.Value(spanKey{}).(*Span)
+	s := r.startSpan(parent, category, name, appendArgs(nil, kvs))
+	return context.WithValue(ctx, spanKey{}, s), s
+}
+
+// Enabled reports whether spans started from ctx are recorded.
+// Use this to avoid expensive work for span arguments.
+func Enabled(ctx context.Context) bool {
+	r, _ := ctx.Value(recorderKey{}).(*Recorder)
+	return r != nil
+}
+
+func appendArgs(args map[string]any, kvs []any) map[string]any {
+	for i := 0; i+1 < len(kvs); i += 2 {
+		key, ok := kvs[i].(string)
+		if !ok {
+			key = fmt.Sprint(kvs[i])
+		}
+		if args == nil {
+			args = make(map[string]any, len(kvs)/2)
+		}
+		args[key] = kvs[i+1]
+	}
+	return args
+}
@@ -0,0 +1,124 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRecorder returns a recorder with a clock
+// that advances by a millisecond on every reading.
+func newTestRecorder(t *testing.T) (*Recorder, *bytes.Buffer) {
+	var buf bytes.Buffer
+	r := NewRecorder(&buf, "test")
+
+	now := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	r.start = now
+	r.now = func() time.Time {
+		now = now.Add(time.Millisecond)
+		return now
+	}
+	return r, &buf
+}
+
+func decodeEvents(t *testing.T, bs []byte) []event {
+	var events []event
+	require.NoError(t, json.Unmarshal(bs, &events), "trace:\n%s", bs)
+
+	// Drop metadata events.
+	spans := events[:0]
+	for _, ev := range events {
+		if ev.Phase != "M" {
+			spans = append(spans, ev)
+		}
+	}
+	return spans
+}
+
+func TestRecorder(t *testing.T) {
+	r, buf := newTestRecorder(t)
+	ctx := WithRecorder(t.Context(), r)
+
+	ctx, root := Start(ctx, "handler", "sync", "trunk", "main")
+	_, child := Start(ctx, "exec", "git fetch")
+	child.SetArgs("exit", 0)
+	child.End()
+	root.End()
+	root.End() // no-op
+	require.NoError(t, r.Close())
+
+	events := decodeEvents(t, buf.Bytes())
+	require.Len(t, events, 2)
+
+	assert.Equal(t, "git fetch", events[0].Name)
+	assert.Equal(t, "exec", events[0].Category)
+	assert.Equal(t, "X", events[0].Phase)
+	assert.Equal(t, map[string]any{"exit": float64(0)}, events[0].Args)
+
+	assert.Equal(t, "sync", events[1].Name)
+	assert.Equal(t, map[string]any{"trunk": "main"}, events[1].Args)
+
+	// Child is nested inside the parent on the same lane.
+	assert.Equal(t, events[1].TID, events[0].TID)
+	assert.GreaterOrEqual(t, events[0].Time, events[1].Time)
+	assert.LessOrEqual(t,
+		events[0].Time+events[0].Duration,
+		events[1].Time+events[1].Duration)
+}
+
+func TestRecorder_concurrentSpansUseSeparateLanes(t *testing.T) {
+	r, buf := newTestRecorder(t)
+	ctx := WithRecorder(t.Context(), r)
+
+	ctx, root := Start(ctx, "handler", "load")
+	_, a := Start(ctx, "exec", "a")
+	_, b := Start(ctx, "exec", "b") // while a is running
+	a.End()
+	_, c := Start(ctx, "exec", "c") // a's lane is free again
+	b.End()
+	c.End()
+	root.End()
+	require.NoError(t, r.Close())
+
+	lanes := make(map[string]int)
+	for _, ev := range decodeEvents(t, buf.Bytes()) {
+		lanes[ev.Name] = ev.TID
+	}
+	assert.Equal(t, lanes["load"], lanes["a"])
+	assert.NotEqual(t, lanes["a"], lanes["b"])
+	assert.Equal(t, lanes["load"], lanes["c"])
+}
+
+func TestRecorder_unterminated(t *testing.T) {
+	r, buf := newTestRecorder(t)
+	ctx := WithRecorder(t.Context(), r)
+
+	_, span := Start(ctx, "exec", "git status")
+	span.End()
+
+	// Without Close, the output is missing the closing bracket,
+	// which trace viewers accept.
+	out := buf.String()
+	assert.NotContains(t, out, "]")
+	events := decodeEvents(t, []byte(out+"]"))
+	require.Len(t, events, 1)
+	assert.Equal(t, "git status", events[0].Name)
+}
+
+func TestStart_noRecorder(t *testing.T) {
+	ctx := context.Background()
+	assert.False(t, Enabled(ctx))
+
+	got, span := Start(ctx, "exec", "git status")
+	assert.Equal(t, ctx, got)
+	assert.Nil(t, span)
+
+	// Nil spans are safe to use.
+	span.SetArgs("key", "value")
+	span.End()
+}
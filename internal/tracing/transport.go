@@ -0,0 +1,130 @@
+package tracing
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// WrapTransport wraps an HTTP transport
+// to record a span for each request
+// made with a context that has a recorder.
+//
+// GraphQL requests are recorded with the operation type
+// and the first field they select (e.g. "mutation createPullRequest")
+// and the names of their variables.
+// Other requests are recorded with their method and path.
+// Credentials in URLs are redacted,
+// and headers and bodies are never recorded.
+//
+// If t is nil, [http.DefaultTransport] is used.
+func WrapTransport(t http.RoundTripper) http.RoundTripper {
+	if t == nil {
+		t = http.DefaultTransport
+	}
+	return &transport{t: t}
+}
+
+type transport struct{ t http.RoundTripper }
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	if !Enabled(ctx) {
+		return t.t.RoundTrip(req)
+	}
+
+	category, name := CategoryHTTP, req.Method+" "+req.URL.Path
+	args := []any{"url", RedactURL(req.URL.String())}
+	if op, vars, ok := graphQLOperation(req); ok {
+		category, name = CategoryGraphQL, op
+		if len(vars) > 0 {
+			args = append(args, "variables", vars)
+		}
+	}
+
+	_, span := Start(ctx, category, name, args...)
+	defer span.End()
+
+	res, err := t.t.RoundTrip(req)
+	if err != nil {
+		span.SetArgs("error", err.Error())
+		return res, err
+	}
+	span.SetArgs("status", res.StatusCode)
+	return res, nil
+}
+
+// graphQLOperation reports the operation name and sorted variable names
+// of a GraphQL request.
+// ok is false if the request isn't a GraphQL request.
+func graphQLOperation(req *http.Request) (op string, vars []string, ok bool) {
+	if req.Method != http.MethodPost || req.GetBody == nil {
+		return "", nil, false
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return "", nil, false
+	}
+	defer func() { _ = body.Close() }()
+
+	var payload struct {
+		Query     string                     `json:"query"`
+		Variables map[string]json.RawMessage `json:"variables"`
+	}
+	if err := json.NewDecoder(io.LimitReader(body, 1<<20)).Decode(&payload); err != nil || payload.Query == "" {
+		return "", nil, false
+	}
+
+	for name := range payload.Variables {
+		vars = append(vars, name)
+	}
+	slices.Sort(vars)
+	return operationName(payload.Query), vars, true
+}
+
+// operationName describes a GraphQL query
+// by its operation type and the first field it selects.
+// For example:
+//
+//	query($owner: String!){repository(owner: $owner){...}}
+//
+// is described as "query repository".
+func operationName(query string) string {
+	query = strings.TrimSpace(query)
+
+	kind := "query"
+	for _, k := range []string{"query", "mutation", "subscription"} {
+		if strings.HasPrefix(query, k) {
+			kind = k
+			break
+		}
+	}
+
+	// Skip the variable definitions, which may contain braces
+	// in default values, by finding the selection set
+	// after the closing parenthesis.
+	if open := strings.IndexByte(query, '('); open >= 0 && open < strings.IndexByte(query, '{') {
+		if end := strings.IndexByte(query[open:], ')'); end >= 0 {
+			query = query[open+end+1:]
+		}
+	}
+
+	_, sel, ok := strings.Cut(query, "{")
+	if !ok {
+		return kind
+	}
+	sel = strings.TrimSpace(sel)
+	end := strings.IndexFunc(sel, func(r rune) bool {
+		return !(r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z')
+	})
+	if end >= 0 {
+		sel = sel[:end]
+	}
+	if sel == "" {
+		return kind
+	}
+	return kind + " " + sel
+}
@@ -0,0 +1,77 @@
+package tracing
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrapTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		_, _ = w.Write([]byte("{}"))
+	}))
+	t.Cleanup(srv.Close)
+
+	r, buf := newTestRecorder(t)
+	ctx := WithRecorder(t.Context(), r)
+	client := &http.Client{Transport: WrapTransport(nil)}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/missing?token=abc", nil)
+	require.NoError(t, err)
+	res, err := client.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, res.Body.Close())
+
+	query := `{"query":"mutation($input:CreatePullRequestInput!){createPullRequest(input:$input){pullRequest{id}}}","variables":{"input":{"title":"secret title"}}}`
+	req, err = http.NewRequestWithContext(ctx, http.MethodPost, srv.URL+"/graphql", strings.NewReader(query))
+	require.NoError(t, err)
+	res, err = client.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, res.Body.Close())
+
+	// Requests without a recorder are not recorded.
+	req, err = http.NewRequestWithContext(t.Context(), http.MethodGet, srv.URL+"/untraced", nil)
+	require.NoError(t, err)
+	res, err = client.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, res.Body.Close())
+
+	require.NoError(t, r.Close())
+	events := decodeEvents(t, buf.Bytes())
+	require.Len(t, events, 2)
+
+	assert.Equal(t, "GET /missing", events[0].Name)
+	assert.Equal(t, CategoryHTTP, events[0].Category)
+	assert.Equal(t, srv.URL+"/missing?token=REDACTED", events[0].Args["url"])
+	assert.Equal(t, float64(http.StatusNotFound), events[0].Args["status"])
+
+	assert.Equal(t, "mutation createPullRequest", events[1].Name)
+	assert.Equal(t, CategoryGraphQL, events[1].Category)
+	assert.Equal(t, []any{"input"}, events[1].Args["variables"])
+	assert.False(t, bytes.Contains(buf.Bytes(), []byte("secret title")),
+		"variable values must not be recorded")
+}
+
+func TestOperationName(t *testing.T) {
+	tests := []struct {
+		give string
+		want string
+	}{
+		{`query($owner:String!$name:String!){repository(owner:$owner, name:$name){id}}`, "query repository"},
+		{`{viewer{login}}`, "query viewer"},
+		{`mutation{addComment(input:{}){clientMutationId}}`, "mutation addComment"},
+		{`query FindPR { repository { id } }`, "query repository"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, operationName(tt.give), "operationName(%q)", tt.give)
+	}
+}
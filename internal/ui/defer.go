@@ -33,6 +33,12 @@ func (d *Deferred) Init() tea.Cmd {
 	return d.f.Init()
 }
 
+// Unwrap returns the constructed field.
+// It returns nil if the field hasn't been initialized yet.
+func (d *Deferred) Unwrap() Field {
+	return d.f
+}
+
 // Title returns the title of the deferred field.
 func (d *Deferred) Title() string {
 	if d.f == nil {
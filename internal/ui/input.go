@@ -81,6 +81,13 @@ func (i *Input) WithValue(value *string) *Input {
 	return i
 }
 
+// WithMask hides the entered text behind a mask character.
+// Use this for secrets such as passwords.
+func (i *Input) WithMask() *Input {
+	i.model.EchoMode = textinput.EchoPassword
+	return i
+}
+
 // WithOptions sets the list of options
 // that can be cycled through with arrow keys.
 // The options are cycled-through in order with wrap-around.
@@ -39,9 +39,11 @@ import (
 	"iter"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 
 	"go.abhg.dev/gs/internal/silog"
+	"go.abhg.dev/gs/internal/tracing"
 )
 
 const _gitSpiceEnv = "GIT_SPICE=1"
@@ -54,6 +56,9 @@ type Cmd struct {
 	log     *prefixLogger
 	_execer Execer
 
+	ctx  context.Context // for tracing
+	span *tracing.Span   // set between Start and Wait
+
 	// Wraps an error with stderr output.
 	wrap func(error) error
 }
@@ -78,6 +83,7 @@ func Command(ctx context.Context, log *silog.Logger, name string, args ...string
 		cmd:     cmd,
 		log:     logger,
 		wrap:    wrap,
+		ctx:     ctx,
 		_execer: DefaultExecer,
 	}
 }
@@ -100,30 +106,101 @@ func (c *Cmd) execer() Execer {
 //
 // It returns an error if the command fails with a non-zero exit code.
 func (c *Cmd) Run() error {
-	return c.wrap(c.execer().Run(c.cmd))
+	span := c.startSpan()
+	err := c.execer().Run(c.cmd)
+	endSpan(span, err)
+	return c.wrap(err)
 }
 
 // Start starts the command, returning immediately.
 // It returns an error if the command fails to start.
 func (c *Cmd) Start() error {
-	return c.wrap(c.execer().Start(c.cmd))
+	c.span = c.startSpan()
+	err := c.execer().Start(c.cmd)
+	if err != nil {
+		endSpan(c.span, err)
+		c.span = nil
+	}
+	return c.wrap(err)
 }
 
 // Wait waits for a command started with Start to complete.
 // It returns an error if the command fails with a non-zero exit code.
 func (c *Cmd) Wait() error {
-	return c.wrap(c.execer().Wait(c.cmd))
+	err := c.execer().Wait(c.cmd)
+	endSpan(c.span, err)
+	c.span = nil
+	return c.wrap(err)
 }
 
 // Kill kills a command started with Start.
 func (c *Cmd) Kill() error {
+	c.span.SetArgs("killed", true)
+	endSpan(c.span, nil)
+	c.span = nil
 	return c.wrap(c.execer().Kill(c.cmd))
 }
 
 // Output runs the command and returns its stdout.
 // It returns an error if the command fails with a non-zero exit code.
 func (c *Cmd) Output() ([]byte, error) {
-	return c.execer().Output(c.cmd)
+	span := c.startSpan()
+	out, err := c.execer().Output(c.cmd)
+	endSpan(span, err)
+	return out, err
+}
+
+// startSpan starts a trace span for the command
+// if the command's context is being traced.
+func (c *Cmd) startSpan() *tracing.Span {
+	if c.ctx == nil || !tracing.Enabled(c.ctx) {
+		return nil
+	}
+
+	args := tracing.RedactArgs(c.Args())
+	kvs := []any{"args", args}
+	if c.cmd.Dir != "" {
+		kvs = append(kvs, "dir", c.cmd.Dir)
+	}
+	_, span := tracing.Start(c.ctx, tracing.CategoryExec, spanName(c.cmd.Args[0], args), kvs...)
+	return span
+}
+
+// endSpan ends a command's trace span,
+// recording the exit code if the command failed.
+func endSpan(span *tracing.Span, err error) {
+	if span == nil {
+		return
+	}
+
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			span.SetArgs("exit", exitErr.ExitCode())
+		} else {
+			span.SetArgs("error", err.Error())
+		}
+	}
+	span.End()
+}
+
+// spanName names a command's trace span
+// after the program and its subcommand, e.g. "git rebase".
+// args must already be redacted.
+func spanName(name string, args []string) string {
+	name = filepath.Base(name)
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "-c" || arg == "-C":
+			i++ // skip the option's value
+		case strings.HasPrefix(arg, "-"):
+			// skip flags
+		default:
+			return name + " " + arg
+		}
+	}
+	return name
 }
 
 // Args returns the arguments passed to the command,
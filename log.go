@@ -16,6 +16,7 @@ import (
 	"github.com/alecthomas/kong"
 	"github.com/charmbracelet/lipgloss"
 	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/forge/forgecache"
 	"go.abhg.dev/gs/internal/git"
 	"go.abhg.dev/gs/internal/handler/list"
 	"go.abhg.dev/gs/internal/must"
@@ -41,6 +42,7 @@ func (*logCmd) AfterApply(kctx *kong.Context) error {
 		svc *spice.Service,
 		forges *forge.Registry,
 		stash secret.Stash,
+		cache *forgecache.Cache,
 	) (ListHandler, error) {
 		return &list.Handler{
 			Log:        log,
@@ -49,8 +51,9 @@ func (*logCmd) AfterApply(kctx *kong.Context) error {
 			Service:    svc,
 			Forges:     forges,
 			OpenRemoteRepository: func(ctx context.Context, f forge.Forge, repo forge.RepositoryID) (forge.Repository, error) {
-				return openForgeRepository(ctx, stash, f, repo)
+				return openForgeRepository(ctx, log, stash, cache, f, repo)
 			},
+			Cache: cache,
 		}, nil
 	})
 }
@@ -78,6 +81,8 @@ var (
 
 	_worktreeStyle = ui.NewStyle().Faint(true)
 
+	_issueStyle = ui.NewStyle().Foreground(ui.Magenta)
+
 	_markerStyle = ui.NewStyle().
 			Foreground(ui.Yellow).
 			Bold(true).
@@ -277,6 +282,11 @@ func (p *graphLogPresenter) Present(res *list.BranchesResponse, currentBranch st
 				o.WriteString(crText.String())
 			}
 
+			if b.Issue != "" {
+				o.WriteString(" ")
+				o.WriteString(_issueStyle.Render("[" + b.Issue + "]"))
+			}
+
 			// TODO: share this logic with branch_select.
 			if wt := b.Worktree; wt != "" && wt != p.CurrentWorktree {
 				// If the path is relative to the user's home directory
@@ -347,6 +357,7 @@ func (p *jsonLogPresenter) Present(res *list.BranchesResponse, currentBranch str
 		logBranch := jsonLogBranch{
 			Name:    branch.Name,
 			Current: branch.Name == currentBranch,
+			Issue:   branch.Issue,
 		}
 
 		if branch.Base != "" {
@@ -446,6 +457,10 @@ type jsonLogBranch struct {
 	// This is unset if this branch has not been published.
 	Change *jsonLogChange `json:"change,omitempty"`
 
+	// Issue is the issue tracker reference linked to this branch.
+	// This is unset if the branch is not linked to an issue.
+	Issue string `json:"issue,omitempty"`
+
 	// Push indicates the push status of this branch,
 	// if the branch has been pushed to a remote.
 	// This is unset if the branch has not been pushed
@@ -292,6 +292,33 @@ func TestJSONLogPresenter_Present(t *testing.T) {
 		assert.Equal(t, `{"name":"main"}
 {"name":"feature1","current":true,"down":{"name":"main"}}
 {"name":"feature2","down":{"name":"main"},"worktree":"/home/user/other-worktree"}
+`, buf.String())
+	})
+
+	t.Run("Issue", func(t *testing.T) {
+		var buf bytes.Buffer
+		presenter := &jsonLogPresenter{
+			Stdout:          &buf,
+			CurrentWorktree: "/home/user/repo",
+		}
+
+		res := &list.BranchesResponse{
+			Branches: []*list.BranchItem{
+				{Name: "main"},
+				{
+					Name:  "feature",
+					Base:  "main",
+					Issue: "ABC-123",
+				},
+			},
+			TrunkIdx: 0,
+		}
+
+		err := presenter.Present(res, "feature")
+		require.NoError(t, err)
+
+		assert.Equal(t, `{"name":"main"}
+{"name":"feature","current":true,"down":{"name":"main"},"issue":"ABC-123"}
 `, buf.String())
 	})
 }
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+
+	"github.com/alecthomas/kong"
+	"go.abhg.dev/gs/internal/cli/shorthand"
+	"go.abhg.dev/gs/internal/silog"
+	"go.abhg.dev/gs/internal/spice"
+	"go.abhg.dev/gs/internal/xec"
+)
+
+const (
+	// _shorthandDepthLimit is the maximum nesting depth
+	// of shell command aliases and macros that invoke gs.
+	_shorthandDepthLimit = 10
+
+	// _shorthandDepthEnvVar tracks the nesting depth
+	// across gs invocations.
+	_shorthandDepthEnvVar = "__GS_SHELL_COMMAND_DEPTH"
+)
+
+// shorthandDepthEnv returns the environment variable to set
+// for commands spawned by a shell command alias or macro.
+//
+// It returns an error if the recursion depth limit has been exceeded.
+func shorthandDepthEnv(kind, name string) (string, error) {
+	var depth int
+	if depthStr := os.Getenv(_shorthandDepthEnvVar); depthStr != "" {
+		// Prevent infinite loops by limiting recursion depth.
+		var err error
+		depth, err = strconv.Atoi(depthStr)
+		if err != nil {
+			// Assume depth is 0 if invalid.
+			depth = 0
+		}
+	}
+	if depth >= _shorthandDepthLimit {
+		return "", fmt.Errorf("%v recursion depth limit exceeded (%d): %v",
+			kind, _shorthandDepthLimit, name)
+	}
+
+	return fmt.Sprintf("%v=%d", _shorthandDepthEnvVar, depth+1), nil
+}
+
+// runMacro runs the expanded commands of a macro in order,
+// stopping at the first failure.
+//
+// git-spice commands are run with a new gs process,
+// and external commands (prefixed with "!") are run directly.
+func runMacro(ctx context.Context, log *silog.Logger, name string, cmds [][]string) error {
+	depthEnv, err := shorthandDepthEnv("macro", name)
+	if err != nil {
+		return err
+	}
+
+	for _, args := range cmds {
+		exe := os.Args[0]
+		if ext, ok := shorthand.ExternalCommand(args); ok {
+			exe, args = ext[0], ext[1:]
+		}
+
+		if err := xec.Command(ctx, log, exe, args...).
+			WithStdin(os.Stdin).
+			WithStdout(os.Stdout).
+			WithStderr(os.Stderr).
+			AppendEnv(depthEnv).
+			Run(); err != nil {
+			return fmt.Errorf("%v: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// addShorthandCommands adds user-defined shorthands to the CLI model
+// so that they're listed in help output and shell completion.
+//
+// Shorthands are always expanded before arguments are parsed,
+// so these commands are never run directly.
+// Shorthands that conflict with an existing command are skipped.
+func addShorthandCommands(app *kong.Application, defs []spice.ShorthandDefinition) {
+	if len(defs) == 0 {
+		return
+	}
+
+	taken := make(map[string]struct{})
+	for _, child := range app.Children {
+		taken[child.Name] = struct{}{}
+		for _, alias := range child.Aliases {
+			taken[alias] = struct{}{}
+		}
+	}
+
+	group := &kong.Group{
+		Key:   "shorthands",
+		Title: "Custom shorthands:",
+	}
+	for _, def := range defs {
+		if _, ok := taken[def.Name]; ok {
+			continue
+		}
+
+		app.Children = append(app.Children, &kong.Node{
+			Type:   kong.CommandNode,
+			Parent: app.Node,
+			Name:   def.Name,
+			Help:   def.Value,
+			Group:  group,
+			Tag:    &kong.Tag{},
+		})
+	}
+}
+
+// completionArgs transforms the completed arguments
+// for shell completion by expanding shorthands and macros.
+func completionArgs(cfg *spice.Config, src shorthand.Source, args []string) []string {
+	if len(args) > 0 {
+		if macro, ok := cfg.Macro(args[0]); ok {
+			args = macro.CompletionArgs(slices.Clone(args[1:]))
+		}
+	}
+	return shorthand.Expand(src, args)
+}
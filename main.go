@@ -17,6 +17,8 @@ import (
 	"go.abhg.dev/gs/internal/cli/experiment"
 	"go.abhg.dev/gs/internal/cli/shorthand"
 	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/forge/forgecache"
+	"go.abhg.dev/gs/internal/forge/gerrit"
 	"go.abhg.dev/gs/internal/forge/github"
 	"go.abhg.dev/gs/internal/forge/gitlab"
 	"go.abhg.dev/gs/internal/git"
@@ -30,6 +32,7 @@ import (
 	"go.abhg.dev/gs/internal/handler/submit"
 	"go.abhg.dev/gs/internal/handler/sync"
 	"go.abhg.dev/gs/internal/handler/track"
+	"go.abhg.dev/gs/internal/hook"
 	"go.abhg.dev/gs/internal/secret"
 	"go.abhg.dev/gs/internal/silog"
 	"go.abhg.dev/gs/internal/spice"
@@ -69,6 +72,7 @@ func main() {
 	var forges forge.Registry
 	forges.Register(&github.Forge{Log: logger})
 	forges.Register(&gitlab.Forge{Log: logger})
+	forges.Register(&gerrit.Forge{Log: logger})
 	for _, f := range _extraForges {
 		forges.Register(f)
 	}
@@ -109,14 +113,6 @@ func main() {
 		logger.Error("Error loading spice configuration; continuing without it.", "error", err)
 	}
 
-	secretStash := &secret.FallbackStash{
-		Primary: _secretStash,
-		Secondary: &secret.InsecureStash{
-			Path: filepath.Join(filepath.Join(userConfigDir, "git-spice"), "secrets.json"),
-			Log:  logger,
-		},
-	}
-
 	var cmd mainCmd
 
 	// Forges may register additional command line flags
@@ -128,21 +124,20 @@ func main() {
 	}
 
 	cmdName := filepath.Base(os.Args[0])
-	parser, err := kong.New(&cmd,
+	parser, err := kong.New(&cmd, append([]kong.Option{
 		kong.Name(cmdName),
 		kong.Description("gs (gritt-spice) is a command line tool for stacking Git branches."),
 		kong.Resolvers(spiceConfig),
-		kong.Bind(logger, &forges),
+		kong.Bind(logger, &forges, spiceConfig),
 		kong.BindTo(ctx, (*context.Context)(nil)),
 		kong.BindTo(spiceConfig, (*experiment.Enabler)(nil)),
-		kong.BindTo(secretStash, (*secret.Stash)(nil)),
 		kong.Vars{
 			// Default to prompting only when the terminal is interactive.
 			"defaultPrompt": strconv.FormatBool(isatty.IsTerminal(os.Stdin.Fd())),
 		},
 		kong.UsageOnError(),
 		kong.Help(helpPrinter),
-	)
+	}, pluginCommands(findPlugins(os.Getenv("PATH")))...)...)
 	if err != nil {
 		panic(err)
 	}
@@ -154,10 +149,16 @@ func main() {
 
 	// user-configured shorthands take precedence over builtins.
 	shorthands := shorthand.Sources{spiceConfig, builtinShorthands}
+	plugins := takePluginCommands(parser.Model)
+	addShorthandCommands(parser.Model, spiceConfig.ShorthandDefinitions())
+
+	// Plugins are added after shorthands so that both
+	// built-in commands and shorthands take precedence over them.
+	addPluginCommands(parser.Model, plugins)
 
 	komplete.Run(parser,
 		komplete.WithTransformCompleted(func(args []string) []string {
-			return shorthand.Expand(shorthands, args)
+			return completionArgs(spiceConfig, shorthands, args)
 		}),
 		komplete.WithPredictor("branches", komplete.PredictFunc(predictBranches)),
 		komplete.WithPredictor("trackedBranches", komplete.PredictFunc(predictTrackedBranches)),
@@ -176,24 +177,9 @@ func main() {
 			logger.Fatalf("%v: please provide a command", cmdName)
 		}
 	} else if shellCmd, ok := spiceConfig.ShellCommand(args[0]); ok {
-		const (
-			recursionDepthLimit  = 10
-			recursionDepthEnvVar = "__GS_SHELL_COMMAND_DEPTH"
-		)
-
-		var depth int
-		if depthStr := os.Getenv(recursionDepthEnvVar); depthStr != "" {
-			// Prevent infinite loops by limiting recursion depth.
-			var err error
-			depth, err = strconv.Atoi(depthStr)
-			if err != nil {
-				// Assume depth is 0 if invalid.
-				depth = 0
-			}
-		}
-		if depth >= recursionDepthLimit {
-			logger.Fatalf("%v: shell command recursion depth limit exceeded (%d): %v",
-				cmdName, recursionDepthLimit, args[0])
+		depthEnv, err := shorthandDepthEnv("shell command", args[0])
+		if err != nil {
+			logger.Fatalf("%v: %v", cmdName, err)
 		}
 
 		// The first argument might be a shell command alias.
@@ -207,11 +193,29 @@ func main() {
 		if err := xec.Command(ctx, logger, "sh", shArgs...).
 			WithStdout(os.Stdout).
 			WithStderr(os.Stderr).
-			AppendEnv(fmt.Sprintf("%v=%d", recursionDepthEnvVar, depth+1)).
+			AppendEnv(depthEnv).
 			Run(); err != nil {
 			logger.Fatalf("%v: %v", cmdName, err)
 		}
 		return
+	} else if macro, ok := spiceConfig.Macro(args[0]); ok {
+		cmds, err := macro.Expand(args[1:])
+		if err != nil {
+			logger.Fatalf("%v: %v: %v", cmdName, args[0], err)
+		}
+
+		// Macros that run multiple commands
+		// run each command in a separate process.
+		if len(cmds) > 1 {
+			if err := runMacro(ctx, logger, args[0], cmds); err != nil {
+				logger.Fatalf("%v: %v", cmdName, err)
+			}
+			return
+		}
+
+		// Single-command macros only substitute arguments,
+		// so they can proceed as usual.
+		args = shorthand.Expand(shorthands, cmds[0])
 	} else {
 		// Otherwise, expand the shorthand,
 		// parse the arguments, and proceed as usual.
@@ -222,15 +226,54 @@ func main() {
 	if err != nil {
 		logger.Fatalf("%v: %v", cmdName, err)
 	}
+	if node := kctx.Selected(); node != nil && !node.Target.IsValid() {
+		// Shorthands are expanded only if they're the first argument.
+		// Otherwise, their placeholder command is selected.
+		logger.Fatalf("%v: %v: shorthands must be the first argument", cmdName, node.Name)
+	}
+
+	// The secret stash is built lazily
+	// so that misconfiguration only affects commands that need it.
+	if err := kctx.BindSingletonProvider(func(view ui.View) (secret.Stash, error) {
+		return cmd.SecretStash.Build(filepath.Join(userConfigDir, "git-spice"), logger, view)
+	}); err != nil {
+		panic(err)
+	}
 
 	if err := cmd.Profile.Start(); err != nil {
 		logger.Error("Error creating trace file", "error", err)
 	}
 
-	if err := kctx.Run(builtinShorthands); err != nil {
+	// The lock is released automatically if we exit early.
+	lock, err := acquireRepoLock(ctx, kctx)
+	if err != nil {
 		logger.Fatalf("%v: %v", cmdName, err)
 	}
 
+	runErr := kctx.Run(builtinShorthands)
+	if err := cmd.forgeCache.Flush(); err != nil {
+		logger.Warn("Could not save forge cache", "error", err)
+	}
+	if cmd.repo != nil {
+		if err := cmd.repo.Close(); err != nil {
+			logger.Warn("Could not close repository", "error", err)
+		}
+	}
+	if err := cmd.stopTrace(runErr); err != nil {
+		logger.Error("Error writing trace file", "error", err)
+	}
+	if runErr != nil {
+		var pluginErr *pluginExitError
+		if errors.As(runErr, &pluginErr) {
+			os.Exit(pluginErr.code)
+		}
+		logger.Fatalf("%v: %v", cmdName, runErr)
+	}
+
+	if err := lock.Release(); err != nil {
+		logger.Error("Error releasing repository lock", "error", err)
+	}
+
 	if err := cmd.Profile.Stop(); err != nil {
 		logger.Error("Error closing trace file", "error", err)
 	}
@@ -240,7 +283,20 @@ type mainCmd struct {
 	kong.Plugins
 	experiment.Check
 
-	Profile ProfileFlags `embed:""`
+	Profile     ProfileFlags     `embed:""`
+	SecretStash SecretStashFlags `embed:""`
+
+	// finishTrace finishes the trace started with --trace-file.
+	// It is nil if tracing is disabled.
+	finishTrace func(error) error
+
+	// forgeCache is the cache of forge metadata.
+	// It is nil if the command didn't use it.
+	forgeCache *forgecache.Cache
+
+	// repo is the Git repository the command ran against.
+	// It is nil if the command didn't use it.
+	repo *git.Repository
 
 	// Global options that are never accessed directly by subcommands.
 	Globals struct {
@@ -250,6 +306,8 @@ type mainCmd struct {
 		Dir           kong.ChangeDirFlag `short:"C" placeholder:"DIR" help:"Change to DIR before doing anything" predictor:"dirs"`
 		Prompt        bool               `name:"prompt" negatable:"" default:"${defaultPrompt}" help:"Whether to prompt for missing information"`
 		RestackMethod string             `config:"restack.method" default:"merge" enum:"rebase,merge" help:"Method to use for restacking (rebase or merge)"`
+		TraceFile     string             `name:"trace-file" type:"path" placeholder:"FILE" released:"unreleased" help:"Write a trace of Git commands and forge requests to FILE"`
+		RepoHooks     bool               `name:"repo-hooks" hidden:"" config:"repoHooks" help:"Run hooks from the repository's .gitspice/hooks directory"`
 	} `embed:"" group:"globals"`
 
 	Shell shellCmd `cmd:"" group:"Shell"`
@@ -279,7 +337,9 @@ type mainCmd struct {
 	Bottom bottomCmd `cmd:"" aliases:"D" group:"Navigation" help:"Move to the bottom of the stack"`
 	Trunk  trunkCmd  `cmd:"" group:"Navigation" help:"Move to the trunk branch"`
 
-	Version versionCmd `cmd:"" help:"Print version information and quit"`
+	Serve     serveCmd     `cmd:"" released:"unreleased" help:"Run a JSON-RPC server for editor integrations"`
+	Dashboard dashboardCmd `cmd:"" released:"unreleased" help:"Serve a web dashboard of tracked stacks"`
+	Version   versionCmd   `cmd:"" help:"Print version information and quit"`
 
 	Internal internalCmd `cmd:"" hidden:"" help:"For internal use only."`
 
@@ -292,7 +352,26 @@ func (cmd *mainCmd) AfterApply(ctx context.Context, kctx *kong.Context, logger *
 		logger.SetLevel(silog.LevelDebug)
 	}
 
-	view, err := _buildView(os.Stdin, kctx.Stderr, cmd.Globals.Prompt)
+	if path := cmd.Globals.TraceFile; path != "" {
+		var err error
+		ctx, cmd.finishTrace, err = startTrace(ctx, path, commandPath(kctx.Selected()))
+		if err != nil {
+			return err
+		}
+
+		// Commands and the providers below must use the traced context.
+		kctx.BindTo(ctx, (*context.Context)(nil))
+	}
+
+	var (
+		view ui.View
+		err  error
+	)
+	if vb, ok := selectedTarget(kctx).(viewBuilder); ok {
+		view, err = vb.buildView(ctx, logger)
+	} else {
+		view, err = _buildView(os.Stdin, kctx.Stderr, cmd.Globals.Prompt)
+	}
 	if err != nil {
 		return fmt.Errorf("build view: %w", err)
 	}
@@ -311,11 +390,18 @@ func (cmd *mainCmd) AfterApply(ctx context.Context, kctx *kong.Context, logger *
 			})
 		}),
 		kctx.BindSingletonProvider(func(wt *git.Worktree) (*git.Repository, error) {
-			return wt.Repository(), nil
+			// Closed once the command finishes.
+			cmd.repo = wt.Repository()
+			return cmd.repo, nil
 		}),
 		kctx.BindSingletonProvider(func(repo *git.Repository, wt *git.Worktree) (*state.Store, error) {
 			return ensureStore(ctx, repo, wt, logger, view)
 		}),
+		kctx.BindSingletonProvider(func(repo *git.Repository) (*forgecache.Cache, error) {
+			// Written back to disk once the command finishes.
+			cmd.forgeCache = forgecache.New(filepath.Join(repo.CommonDir(), forgecache.FileName), logger)
+			return cmd.forgeCache, nil
+		}),
 		kctx.BindSingletonProvider(func(
 			repo *git.Repository,
 			wt *git.Worktree,
@@ -335,6 +421,20 @@ func (cmd *mainCmd) AfterApply(ctx context.Context, kctx *kong.Context, logger *
 
 			return spice.NewServiceWithRestackMethod(repo, wt, store, forges, logger, restackMethod), nil
 		}),
+		kctx.BindSingletonProvider(func(
+			log *silog.Logger,
+			wt *git.Worktree,
+			svc *spice.Service,
+			config *spice.Config,
+		) (*hook.Runner, error) {
+			return &hook.Runner{
+				Log:       log,
+				Branches:  svc,
+				RootDir:   wt.RootDir(),
+				Config:    config,
+				RepoHooks: cmd.Globals.RepoHooks,
+			}, nil
+		}),
 		kctx.BindSingletonProvider(func(
 			log *silog.Logger,
 			wt *git.Worktree,
@@ -385,7 +485,9 @@ func (cmd *mainCmd) AfterApply(ctx context.Context, kctx *kong.Context, logger *
 			wt *git.Worktree,
 			svc *spice.Service,
 			secretStash secret.Stash,
+			cache *forgecache.Cache,
 			forges *forge.Registry,
+			hooks *hook.Runner,
 		) (SubmitHandler, error) {
 			restackMethod := spice.RestackMethodRebase
 			if cmd.Globals.RestackMethod == "merge" {
@@ -401,11 +503,12 @@ func (cmd *mainCmd) AfterApply(ctx context.Context, kctx *kong.Context, logger *
 				Service:       svc,
 				Browser:       _browserLauncher,
 				RestackMethod: restackMethod,
+				Hooks:         hooks,
 				FindRemote: func(ctx context.Context) (string, error) {
 					return ensureRemote(ctx, wt.Repository(), store, log, view)
 				},
 				OpenRemoteRepository: func(ctx context.Context, remote string) (forge.Repository, error) {
-					return openRemoteRepository(ctx, log, secretStash, forges, wt.Repository(), remote)
+					return openRemoteRepository(ctx, log, secretStash, cache, forges, wt.Repository(), remote)
 				},
 			}, nil
 		}),
@@ -414,12 +517,14 @@ func (cmd *mainCmd) AfterApply(ctx context.Context, kctx *kong.Context, logger *
 			worktree *git.Worktree,
 			store *state.Store,
 			svc *spice.Service,
+			hooks *hook.Runner,
 		) (RestackHandler, error) {
 			return &restack.Handler{
 				Log:      log,
 				Worktree: worktree,
 				Store:    store,
 				Service:  svc,
+				Hooks:    hooks,
 			}, nil
 		}),
 		kctx.BindSingletonProvider(func(
@@ -427,6 +532,7 @@ func (cmd *mainCmd) AfterApply(ctx context.Context, kctx *kong.Context, logger *
 			store *state.Store,
 			wt *git.Worktree,
 			svc *spice.Service,
+			hooks *hook.Runner,
 		) (DeleteHandler, error) {
 			return &delete.Handler{
 				Log:        log,
@@ -435,6 +541,7 @@ func (cmd *mainCmd) AfterApply(ctx context.Context, kctx *kong.Context, logger *
 				Worktree:   wt,
 				Store:      store,
 				Service:    svc,
+				Hooks:      hooks,
 			}, nil
 		}),
 		kctx.BindSingletonProvider(func(
@@ -495,9 +602,11 @@ func (cmd *mainCmd) AfterApply(ctx context.Context, kctx *kong.Context, logger *
 			store *state.Store,
 			svc *spice.Service,
 			secretStash secret.Stash,
+			cache *forgecache.Cache,
 			forges *forge.Registry,
 			deleteHandler DeleteHandler,
 			restackHandler RestackHandler,
+			hooks *hook.Runner,
 		) (SyncHandler, error) {
 			remote, err := ensureRemote(ctx, repo, store, log, view)
 			// TODO: move ensure remote to Service
@@ -505,7 +614,7 @@ func (cmd *mainCmd) AfterApply(ctx context.Context, kctx *kong.Context, logger *
 				return nil, err
 			}
 
-			remoteRepo, err := openRemoteRepositorySilent(ctx, secretStash, forges, repo, remote)
+			remoteRepo, err := openRemoteRepositorySilent(ctx, log, secretStash, cache, forges, repo, remote)
 			if err != nil {
 				var unsupported *unsupportedForgeError
 				if !errors.As(err, &unsupported) {
@@ -526,11 +635,19 @@ func (cmd *mainCmd) AfterApply(ctx context.Context, kctx *kong.Context, logger *
 				Remote:             remote,
 				RemoteRepository:   remoteRepo,
 				SkipRebaseOnDelete: cmd.Globals.RestackMethod == "merge",
+				Hooks:              hooks,
 			}, nil
 		}),
 	)
 }
 
+// viewBuilder is implemented by commands that talk to the user
+// through something other than the terminal (e.g. 'gs serve').
+// The view they build is used in place of the default one.
+type viewBuilder interface {
+	buildView(ctx context.Context, log *silog.Logger) (ui.View, error)
+}
+
 type AutostashHandler interface {
 	BeginAutostash(ctx context.Context, opts *autostash.Options) (func(*error), error)
 	RestoreAutostash(ctx context.Context, stashHash string) error
@@ -547,3 +664,16 @@ var _buildView = func(stdin io.Reader, stderr io.Writer, interactive bool) (ui.V
 	}
 	return &ui.FileView{W: stderr}, nil
 }
+
+// selectedTarget returns a pointer to the selected command's struct,
+// or nil if no command was selected.
+//
+// Commands added to list user-defined shorthands don't have a struct,
+// and are selected only if a shorthand isn't the first argument.
+func selectedTarget(kctx *kong.Context) any {
+	node := kctx.Selected()
+	if node == nil || !node.Target.IsValid() {
+		return nil
+	}
+	return node.Target.Addr().Interface()
+}
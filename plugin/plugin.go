@@ -0,0 +1,204 @@
+// Package plugin helps write external git-spice subcommands.
+//
+// When git-spice is invoked as 'gs <name>'
+// and <name> isn't a git-spice command or shorthand,
+// it looks for an executable named 'git-spice-<name>' on $PATH
+// and runs it with the remaining arguments.
+//
+// Before running a plugin, git-spice writes information about
+// the repository it was invoked in to a JSON file,
+// and sets the GIT_SPICE_PLUGIN_CONTEXT environment variable
+// to the path of that file.
+// Use [Load] to read it.
+//
+//	func main() {
+//		pctx, err := plugin.Load()
+//		if err != nil {
+//			log.Fatal(err)
+//		}
+//
+//		for _, name := range pctx.Ups(pctx.CurrentBranch) {
+//			fmt.Println(name)
+//		}
+//	}
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+)
+
+// ContextEnv is the environment variable holding the path
+// to the plugin context file.
+const ContextEnv = "GIT_SPICE_PLUGIN_CONTEXT"
+
+// ContextVersion is the version of the plugin context format
+// understood by this package.
+//
+// Fields may be added to the context without changing the version.
+// The version changes only if existing fields change meaning.
+const ContextVersion = 1
+
+// ErrNotPlugin indicates that the program was not run as a plugin
+// by git-spice.
+var ErrNotPlugin = errors.New(ContextEnv + " is not set; not running as a git-spice plugin")
+
+// Context is information about the repository
+// that git-spice ran a plugin in.
+//
+// Only Version, Executable, and Name are always set.
+// Other fields are empty if the plugin was run outside a Git repository,
+// or if the repository hasn't been initialized with 'gs repo init'.
+type Context struct {
+	// Version is the version of the context format.
+	Version int `json:"version"`
+
+	// Executable is the git-spice executable that ran the plugin.
+	// Use it to run git-spice commands from the plugin.
+	Executable string `json:"executable"`
+
+	// Name is the name of the plugin,
+	// i.e. the part of the command after 'git-spice-'.
+	Name string `json:"name"`
+
+	// RepoRoot is the root directory of the current worktree.
+	RepoRoot string `json:"repoRoot,omitempty"`
+
+	// Trunk is the name of the trunk branch.
+	Trunk string `json:"trunk,omitempty"`
+
+	// Remote is the name of the Git remote
+	// that branches are pushed to.
+	Remote string `json:"remote,omitempty"`
+
+	// CurrentBranch is the branch checked out in the current worktree.
+	// It's empty if HEAD is detached.
+	CurrentBranch string `json:"currentBranch,omitempty"`
+
+	// Forge is the ID of the forge that the remote is hosted on
+	// (e.g. "github", "gitlab").
+	// It's empty if the remote doesn't belong to a known forge.
+	Forge string `json:"forge,omitempty"`
+
+	// RepositoryID identifies the repository on the forge
+	// (e.g. "abhinav/git-spice").
+	RepositoryID string `json:"repositoryID,omitempty"`
+
+	// Branches lists branches tracked by git-spice.
+	// Trunk is not included.
+	Branches []*Branch `json:"branches,omitempty"`
+}
+
+// Branch is a branch tracked by git-spice.
+type Branch struct {
+	// Name is the name of the branch.
+	Name string `json:"name"`
+
+	// Head is the commit at the tip of the branch.
+	Head string `json:"head"`
+
+	// Base is the name of the branch that this branch is stacked on.
+	Base string `json:"base"`
+
+	// BaseHash is the last known commit of the base branch.
+	// If this doesn't match the base branch's current head,
+	// the branch needs to be restacked.
+	BaseHash string `json:"baseHash"`
+
+	// Upstream is the name of the branch on the remote
+	// if the branch has been pushed.
+	Upstream string `json:"upstream,omitempty"`
+
+	// Change is the change request submitted for the branch,
+	// or nil if the branch hasn't been submitted.
+	Change *Change `json:"change,omitempty"`
+}
+
+// Change is a change request submitted for a branch.
+type Change struct {
+	// Forge is the ID of the forge hosting the change.
+	Forge string `json:"forge"`
+
+	// ID identifies the change on the forge (e.g. "#123").
+	ID string `json:"id"`
+}
+
+// Load reads the plugin context that git-spice provided
+// for the current process.
+//
+// It returns [ErrNotPlugin] if the process wasn't run by git-spice.
+func Load() (*Context, error) {
+	path := os.Getenv(ContextEnv)
+	if path == "" {
+		return nil, ErrNotPlugin
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open plugin context: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	return Decode(f)
+}
+
+// Decode reads a plugin context from the given reader.
+//
+// It returns an error if the context uses a newer format version
+// than this package understands.
+func Decode(r io.Reader) (*Context, error) {
+	var pctx Context
+	if err := json.NewDecoder(r).Decode(&pctx); err != nil {
+		return nil, fmt.Errorf("decode plugin context: %w", err)
+	}
+
+	if pctx.Version > ContextVersion {
+		return nil, fmt.Errorf("unsupported plugin context version %d (want <= %d)",
+			pctx.Version, ContextVersion)
+	}
+
+	return &pctx, nil
+}
+
+// Branch returns information about the tracked branch with the given name,
+// or false if it isn't tracked.
+func (c *Context) Branch(name string) (*Branch, bool) {
+	for _, b := range c.Branches {
+		if b.Name == name {
+			return b, true
+		}
+	}
+	return nil, false
+}
+
+// Ups returns the names of branches stacked directly on top of
+// the given branch.
+func (c *Context) Ups(name string) []string {
+	var ups []string
+	for _, b := range c.Branches {
+		if b.Base == name {
+			ups = append(ups, b.Name)
+		}
+	}
+	return ups
+}
+
+// Command builds a command that runs git-spice with the given arguments.
+// Its standard streams are connected to those of the current process.
+func (c *Context) Command(ctx context.Context, args ...string) *exec.Cmd {
+	exe := c.Executable
+	if exe == "" {
+		exe = "gs"
+	}
+
+	cmd := exec.CommandContext(ctx, exe, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd
+}
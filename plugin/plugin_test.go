@@ -0,0 +1,60 @@
+package plugin_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/plugin"
+)
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "context.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"version": 1,
+		"executable": "gs",
+		"name": "hello",
+		"trunk": "main",
+		"currentBranch": "feat1",
+		"branches": [
+			{"name": "feat1", "base": "main", "head": "abc", "baseHash": "def",
+			 "change": {"forge": "github", "id": "#1"}},
+			{"name": "feat2", "base": "feat1", "head": "123", "baseHash": "abc"},
+			{"name": "feat3", "base": "feat1", "head": "456", "baseHash": "abc"}
+		]
+	}`), 0o644))
+	t.Setenv(plugin.ContextEnv, path)
+
+	pctx, err := plugin.Load()
+	require.NoError(t, err)
+
+	assert.Equal(t, "hello", pctx.Name)
+	assert.Equal(t, "main", pctx.Trunk)
+	assert.Equal(t, "feat1", pctx.CurrentBranch)
+
+	feat1, ok := pctx.Branch("feat1")
+	require.True(t, ok)
+	assert.Equal(t, &plugin.Change{Forge: "github", ID: "#1"}, feat1.Change)
+
+	_, ok = pctx.Branch("main")
+	assert.False(t, ok)
+
+	assert.Equal(t, []string{"feat1"}, pctx.Ups("main"))
+	assert.Equal(t, []string{"feat2", "feat3"}, pctx.Ups("feat1"))
+	assert.Empty(t, pctx.Ups("feat2"))
+}
+
+func TestLoad_notPlugin(t *testing.T) {
+	t.Setenv(plugin.ContextEnv, "")
+
+	_, err := plugin.Load()
+	assert.ErrorIs(t, err, plugin.ErrNotPlugin)
+}
+
+func TestDecode_newerVersion(t *testing.T) {
+	_, err := plugin.Decode(strings.NewReader(`{"version": 2}`))
+	assert.ErrorContains(t, err, "unsupported plugin context version 2")
+}
@@ -0,0 +1,276 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/alecthomas/kong"
+	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/silog"
+	"go.abhg.dev/gs/internal/spice"
+	"go.abhg.dev/gs/internal/spice/state"
+	"go.abhg.dev/gs/internal/xec"
+	"go.abhg.dev/gs/plugin"
+)
+
+// _pluginPrefix is the prefix of executables on $PATH
+// that are run as git-spice subcommands.
+const _pluginPrefix = "git-spice-"
+
+// findPlugins searches the directories in the given $PATH-style list
+// for plugin executables.
+// It returns a map from plugin name to executable path.
+//
+// If multiple directories contain a plugin with the same name,
+// the first one wins, matching how the shell resolves commands.
+func findPlugins(pathList string) map[string]string {
+	plugins := make(map[string]string)
+	for _, dir := range filepath.SplitList(pathList) {
+		if dir == "" {
+			continue
+		}
+
+		// Missing or unreadable directories on $PATH are not an error.
+		entries, _ := os.ReadDir(dir)
+		for _, ent := range entries {
+			name, ok := pluginName(ent.Name())
+			if !ok {
+				continue
+			}
+			if _, ok := plugins[name]; ok {
+				continue
+			}
+
+			path := filepath.Join(dir, ent.Name())
+			if !isExecutable(path) {
+				continue
+			}
+			plugins[name] = path
+		}
+	}
+	return plugins
+}
+
+// pluginName returns the name of the plugin
+// provided by an executable with the given file name,
+// or false if the file is not a plugin.
+func pluginName(file string) (string, bool) {
+	if runtime.GOOS == "windows" {
+		var ok bool
+		file, ok = strings.CutSuffix(strings.ToLower(file), ".exe")
+		if !ok {
+			return "", false
+		}
+	}
+
+	name, ok := strings.CutPrefix(file, _pluginPrefix)
+	if !ok || name == "" || strings.HasPrefix(name, "-") {
+		return "", false
+	}
+	return name, true
+}
+
+func isExecutable(path string) bool {
+	info, err := os.Stat(path) // follow symlinks
+	if err != nil || !info.Mode().IsRegular() {
+		return false
+	}
+	return runtime.GOOS == "windows" || info.Mode().Perm()&0o111 != 0
+}
+
+// pluginCommands returns options that add the given plugins
+// as commands to the CLI.
+//
+// Plugin commands are parsed like any other command,
+// so global flags may precede the plugin name.
+// All arguments after the name are passed to the plugin as-is.
+func pluginCommands(plugins map[string]string) []kong.Option {
+	opts := make([]kong.Option, 0, len(plugins))
+	for _, name := range slices.Sorted(maps.Keys(plugins)) {
+		path := plugins[name]
+		opts = append(opts, kong.DynamicCommand(
+			name, "Run "+path, _pluginGroup.Key,
+			&pluginCmd{name: name, path: path},
+			`cmd:"" passthrough:""`,
+		))
+	}
+	return opts
+}
+
+var _pluginGroup = &kong.Group{
+	Key:   "plugins",
+	Title: "Plugins:",
+}
+
+// takePluginCommands removes the commands added by [pluginCommands]
+// from the CLI model and returns them.
+func takePluginCommands(app *kong.Application) []*kong.Node {
+	var nodes []*kong.Node
+	app.Children = slices.DeleteFunc(app.Children, func(child *kong.Node) bool {
+		if child.Target.IsValid() && child.Target.Type() == reflect.TypeFor[pluginCmd]() {
+			nodes = append(nodes, child)
+			return true
+		}
+		return false
+	})
+	return nodes
+}
+
+// addPluginCommands adds plugin commands taken with [takePluginCommands]
+// back to the CLI model.
+// Plugins that conflict with an existing command, alias, or shorthand
+// are dropped.
+func addPluginCommands(app *kong.Application, nodes []*kong.Node) {
+	taken := make(map[string]struct{})
+	for _, child := range app.Children {
+		taken[child.Name] = struct{}{}
+		for _, alias := range child.Aliases {
+			taken[alias] = struct{}{}
+		}
+	}
+
+	for _, node := range nodes {
+		if _, ok := taken[node.Name]; ok {
+			continue
+		}
+		node.Group = _pluginGroup
+		app.Children = append(app.Children, node)
+	}
+}
+
+// pluginCmd runs a git-spice-<name> executable found on $PATH.
+type pluginCmd struct {
+	Args []string `arg:"" optional:"" help:"Arguments for the plugin"`
+
+	name string
+	path string
+}
+
+func (cmd *pluginCmd) Run(ctx context.Context, log *silog.Logger, forges *forge.Registry) error {
+	err := runPlugin(ctx, log, forges, cmd.name, cmd.path, cmd.Args)
+
+	// The plugin is responsible for reporting its own failures.
+	var exitErr *xec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+		return &pluginExitError{code: exitErr.ExitCode()}
+	}
+	return err
+}
+
+// pluginExitError indicates that a plugin exited with a non-zero status.
+// git-spice exits with the same status without reporting anything further.
+type pluginExitError struct{ code int }
+
+func (e *pluginExitError) Error() string {
+	return fmt.Sprintf("plugin exited with status %d", e.code)
+}
+
+// runPlugin runs the plugin executable at the given path
+// with the given arguments,
+// providing it information about the current repository.
+func runPlugin(
+	ctx context.Context,
+	log *silog.Logger,
+	forges *forge.Registry,
+	name, path string,
+	args []string,
+) error {
+	pctx := buildPluginContext(ctx, log, forges)
+	pctx.Executable = os.Args[0]
+	pctx.Name = name
+
+	f, err := os.CreateTemp("", "git-spice-plugin-*.json")
+	if err != nil {
+		return fmt.Errorf("create plugin context: %w", err)
+	}
+	defer func() { _ = os.Remove(f.Name()) }()
+
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	if err := errors.Join(enc.Encode(pctx), f.Close()); err != nil {
+		return fmt.Errorf("write plugin context: %w", err)
+	}
+
+	return xec.Command(ctx, log, path, args...).
+		WithStdin(os.Stdin).
+		WithStdout(os.Stdout).
+		WithStderr(os.Stderr).
+		AppendEnv(plugin.ContextEnv + "=" + f.Name()).
+		Run()
+}
+
+// buildPluginContext builds a plugin context for the current directory.
+// This is the directory specified with -C, if any,
+// because it's applied while parsing arguments.
+//
+// Plugins may be run outside a repository,
+// or in one that git-spice hasn't been initialized in,
+// so parts of the context that can't be determined are left empty.
+func buildPluginContext(ctx context.Context, log *silog.Logger, forges *forge.Registry) *plugin.Context {
+	pctx := &plugin.Context{Version: plugin.ContextVersion}
+
+	wt, err := git.OpenWorktree(ctx, ".", git.OpenOptions{Log: log})
+	if err != nil {
+		log.Debug("Not in a Git repository", "error", err)
+		return pctx
+	}
+	repo := wt.Repository()
+	pctx.RepoRoot = wt.RootDir()
+
+	if current, err := wt.CurrentBranch(ctx); err == nil {
+		pctx.CurrentBranch = current
+	}
+
+	store, err := state.OpenStore(ctx, newRepoStorage(repo, log), log)
+	if err != nil {
+		log.Debug("Could not open git-spice state", "error", err)
+		return pctx
+	}
+	pctx.Trunk = store.Trunk()
+
+	if remote, err := store.Remote(); err == nil {
+		pctx.Remote = remote
+		if remoteURL, err := repo.RemoteURL(ctx, remote); err == nil {
+			if f, repoID, ok := forge.MatchRemoteURL(forges, remoteURL); ok {
+				pctx.Forge = f.ID()
+				pctx.RepositoryID = repoID.String()
+			}
+		}
+	}
+
+	svc := spice.NewService(repo, wt, store, forges, log)
+	graph, err := svc.BranchGraph(ctx, nil)
+	if err != nil {
+		log.Warn("Could not load branches for plugin", "error", err)
+		return pctx
+	}
+
+	for item := range graph.All() {
+		branch := &plugin.Branch{
+			Name:     item.Name,
+			Head:     item.Head.String(),
+			Base:     item.Base,
+			BaseHash: item.BaseHash.String(),
+			Upstream: item.UpstreamBranch,
+		}
+		if item.Change != nil {
+			branch.Change = &plugin.Change{
+				Forge: item.Change.ForgeID(),
+				ID:    item.Change.ChangeID().String(),
+			}
+		}
+		pctx.Branches = append(pctx.Branches, branch)
+	}
+
+	return pctx
+}
@@ -14,6 +14,8 @@ import (
 
 type rebaseAbortCmd struct{}
 
+func (*rebaseAbortCmd) locksRepository() {}
+
 func (*rebaseAbortCmd) Help() string {
 	return text.Dedent(`
 		Cancels an ongoing git-spice operation that was interrupted by
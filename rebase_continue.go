@@ -17,6 +17,8 @@ type rebaseContinueCmd struct {
 	Edit bool `default:"true" negatable:"" config:"rebaseContinue.edit" help:"Whether to open an editor to edit the commit message."`
 }
 
+func (*rebaseContinueCmd) locksRepository() {}
+
 func (*rebaseContinueCmd) Help() string {
 	return text.Dedent(`
 		Continues an ongoing git-spice operation interrupted by
@@ -4,13 +4,53 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 
 	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/forge/forgecache"
 	"go.abhg.dev/gs/internal/git"
 	"go.abhg.dev/gs/internal/secret"
 	"go.abhg.dev/gs/internal/silog"
 )
 
+// _openedRepositories remembers repositories opened by openForgeRepository.
+//
+// It's nil for most commands as they open each repository at most once.
+// Long-running commands (e.g. 'gs serve') set it
+// to reuse forge clients between operations.
+var _openedRepositories *openedRepositories
+
+// openedRepositories is a set of opened forge repositories
+// keyed by forge and repository ID.
+type openedRepositories struct {
+	mu    sync.Mutex
+	repos map[string]forge.Repository
+}
+
+func (o *openedRepositories) get(key string) (forge.Repository, bool) {
+	if o == nil {
+		return nil, false
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	repo, ok := o.repos[key]
+	return repo, ok
+}
+
+func (o *openedRepositories) put(key string, repo forge.Repository) {
+	if o == nil {
+		return
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.repos == nil {
+		o.repos = make(map[string]forge.Repository)
+	}
+	o.repos[key] = repo
+}
+
 type unsupportedForgeError struct {
 	Remote    string // required
 	RemoteURL string // required
@@ -38,7 +78,9 @@ func (e *notLoggedInError) Error() string {
 //   - notLoggedInError if the user is not authenticated with the forge.
 func openRemoteRepositorySilent(
 	ctx context.Context,
+	log *silog.Logger,
 	stash secret.Stash,
+	cache *forgecache.Cache,
 	forges *forge.Registry,
 	gitRepo *git.Repository,
 	remote string,
@@ -56,15 +98,26 @@ func openRemoteRepositorySilent(
 		}
 	}
 
-	return openForgeRepository(ctx, stash, f, repoID)
+	return openForgeRepository(ctx, log, stash, cache, f, repoID)
 }
 
+// openForgeRepository opens a repository on the given forge.
+//
+// If the forge supports it, repository metadata is kept in cache
+// between invocations.
 func openForgeRepository(
 	ctx context.Context,
+	log *silog.Logger,
 	stash secret.Stash,
+	cache *forgecache.Cache,
 	f forge.Forge,
 	repoID forge.RepositoryID,
 ) (forge.Repository, error) {
+	key := f.ID() + "/" + repoID.String()
+	if repo, ok := _openedRepositories.get(key); ok {
+		return repo, nil
+	}
+
 	tok, err := f.LoadAuthenticationToken(stash)
 	if err != nil {
 		if errors.Is(err, secret.ErrNotFound) {
@@ -73,18 +126,30 @@ func openForgeRepository(
 		return nil, fmt.Errorf("load authentication token: %w", err)
 	}
 
-	return f.OpenRepository(ctx, tok, repoID)
+	var repo forge.Repository
+	if opener, ok := f.(forge.CachedRepositoryOpener); ok && cache != nil {
+		repo, err = opener.OpenCachedRepository(ctx, tok, repoID, cache.Scope(f.ID(), repoID.String()))
+	} else {
+		repo, err = f.OpenRepository(ctx, tok, repoID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	_openedRepositories.put(key, repo)
+	return repo, nil
 }
 
 func openRemoteRepository(
 	ctx context.Context,
 	log *silog.Logger,
 	stash secret.Stash,
+	cache *forgecache.Cache,
 	forges *forge.Registry,
 	gitRepo *git.Repository,
 	remote string,
 ) (forge.Repository, error) {
-	forgeRepo, err := openRemoteRepositorySilent(ctx, stash, forges, gitRepo, remote)
+	forgeRepo, err := openRemoteRepositorySilent(ctx, log, stash, cache, forges, gitRepo, remote)
 
 	var (
 		unsupportedErr *unsupportedForgeError
@@ -4,4 +4,5 @@ type repoCmd struct {
 	Init    repoInitCmd    `cmd:"" aliases:"i" help:"Initialize a repository"`
 	Sync    repoSyncCmd    `cmd:"" aliases:"s" help:"Pull latest changes from the remote"`
 	Restack repoRestackCmd `cmd:"" aliases:"r" help:"Restack all tracked branches" released:"v0.16.0"`
+	Flush   repoFlushCmd   `cmd:"" aliases:"f" help:"Replay submits queued while offline" released:"unreleased"`
 }
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+
+	"go.abhg.dev/gs/internal/handler/submit"
+	"go.abhg.dev/gs/internal/text"
+)
+
+type repoFlushCmd struct {
+	DryRun bool `short:"n" help:"List queued submits without replaying them"`
+}
+
+func (*repoFlushCmd) locksRepository() {}
+
+func (*repoFlushCmd) Help() string {
+	return text.Dedent(`
+		Submits queued with 'gs branch submit --offline'
+		(or the other submit commands) are pushed
+		and their Change Requests created or updated,
+		in the order they were queued.
+		Navigation comments are updated afterwards.
+
+		Queued submits are also replayed by the next submit
+		that isn't offline.
+
+		A queued submit is left in the queue
+		if its branch was pushed to by someone else
+		after the submit was queued.
+		Resolve the conflict and submit the branch again.
+
+		Use --dry-run to list queued submits without replaying them.
+	`)
+}
+
+func (cmd *repoFlushCmd) Run(ctx context.Context, submitHandler SubmitHandler) error {
+	return submitHandler.Flush(ctx, &submit.FlushRequest{
+		DryRun: cmd.DryRun,
+	})
+}
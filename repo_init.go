@@ -23,6 +23,8 @@ type repoInitCmd struct {
 	Reset bool `help:"Forget all information about the repository"`
 }
 
+func (*repoInitCmd) locksRepository() {}
+
 func (*repoInitCmd) Help() string {
 	return text.Dedent(`
 		A trunk branch is required.
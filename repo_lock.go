@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alecthomas/kong"
+	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/repolock"
+)
+
+// repoLocker is implemented by commands that restack branches
+// or otherwise change branches and their state
+// (e.g. restack, submit, branch create, commit amend),
+// and must hold the repository lock while they run.
+//
+// Commands that are run from inside another command
+// (e.g. rebase continuations) are covered by the outer command's lock.
+type repoLocker interface {
+	locksRepository()
+}
+
+// _repoLockWait is how long to wait for another git-spice command
+// to release the repository lock before giving up.
+var _repoLockWait = 2 * time.Second
+
+// _repoLockEnv is set in the environment of a process holding the lock.
+// git-spice commands run by that process (e.g. from Git hooks)
+// are covered by its lock and must not try to take it again.
+const _repoLockEnv = "GIT_SPICE_REPO_LOCK_PID"
+
+// acquireRepoLock acquires the repository lock
+// if the selected command requires it.
+// The returned lock is nil if no lock was taken.
+func acquireRepoLock(ctx context.Context, kctx *kong.Context) (*repolock.Lock, error) {
+	node := kctx.Selected()
+	if node == nil {
+		return nil, nil
+	}
+	if _, ok := selectedTarget(kctx).(repoLocker); !ok {
+		return nil, nil
+	}
+	if os.Getenv(_repoLockEnv) != "" {
+		return nil, nil
+	}
+
+	// kong.Context.Call doesn't report errors returned by the function,
+	// so they're captured separately.
+	var (
+		lock    *repolock.Lock
+		lockErr error
+	)
+	if _, err := kctx.Call(func(repo *git.Repository) {
+		lock, lockErr = lockRepository(ctx, repo, commandPath(node))
+	}); err != nil {
+		return nil, err
+	}
+	return lock, lockErr
+}
+
+// lockRepository acquires the repository lock on behalf of the given command
+// and marks this process as its holder
+// for git-spice commands run by it.
+func lockRepository(ctx context.Context, repo *git.Repository, command string) (*repolock.Lock, error) {
+	path := filepath.Join(repo.CommonDir(), repolock.FileName)
+	lock, err := repolock.Acquire(ctx, path, repolock.Options{
+		Holder: repolock.Holder{
+			PID:     os.Getpid(),
+			Command: command,
+			Since:   time.Now(),
+		},
+		Wait: _repoLockWait,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.Setenv(_repoLockEnv, strconv.Itoa(os.Getpid())); err != nil {
+		return nil, errors.Join(
+			fmt.Errorf("set %v: %w", _repoLockEnv, err),
+			lock.Release(),
+		)
+	}
+	return lock, nil
+}
+
+// commandPath returns the full name of the command at node,
+// e.g. "gs repo sync", without aliases.
+func commandPath(node *kong.Node) string {
+	var names []string
+	for n := node; n != nil; n = n.Parent {
+		names = append(names, n.Name)
+	}
+	slices.Reverse(names)
+	return strings.Join(names, " ")
+}
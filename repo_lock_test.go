@@ -0,0 +1,79 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Commands that change branches or their state must take the repository lock.
+// Update this list when adding such a command.
+func TestRepoLockers(t *testing.T) {
+	var cmd mainCmd
+	parser, err := kong.New(&cmd,
+		kong.Name("gs"),
+		kong.Exit(func(int) {}),
+		kong.Vars{
+			"defaultPrompt": "true",
+		},
+	)
+	require.NoError(t, err)
+
+	var got []string
+	for _, node := range parser.Model.Leaves(true) {
+		if !node.Target.IsValid() {
+			continue
+		}
+		if _, ok := node.Target.Addr().Interface().(repoLocker); !ok {
+			continue
+		}
+
+		var path []string
+		for n := node; n != nil && n.Type == kong.CommandNode; n = n.Parent {
+			path = append([]string{n.Name}, path...)
+		}
+		got = append(got, strings.Join(path, " "))
+	}
+
+	assert.ElementsMatch(t, []string{
+		"abort",
+		"branch create",
+		"branch delete",
+		"branch fold",
+		"branch onto",
+		"branch rename",
+		"branch restack",
+		"branch split",
+		"branch split-files",
+		"branch squash",
+		"branch submit",
+		"branch track",
+		"branch untrack",
+		"commit amend",
+		"commit create",
+		"commit fixup",
+		"commit pick",
+		"commit split",
+		"continue",
+		"downstack edit",
+		"downstack submit",
+		"downstack track",
+		"rebase abort",
+		"rebase continue",
+		"repo flush",
+		"repo init",
+		"repo restack",
+		"repo sync",
+		"stack delete",
+		"stack edit",
+		"stack restack",
+		"stack submit",
+		"upstack delete",
+		"upstack onto",
+		"upstack restack",
+		"upstack submit",
+	}, got)
+}
@@ -14,6 +14,8 @@ import (
 
 type repoRestackCmd struct{}
 
+func (*repoRestackCmd) locksRepository() {}
+
 func (*repoRestackCmd) Help() string {
 	return text.Dedent(`
 		All tracked branches in the repository are rebased on top of their
@@ -11,6 +11,8 @@ type repoSyncCmd struct {
 	sync.TrunkOptions
 }
 
+func (*repoSyncCmd) locksRepository() {}
+
 func (*repoSyncCmd) Help() string {
 	return text.Dedent(`
 		Branches with merged Change Requests
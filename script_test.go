@@ -3,6 +3,8 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/json"
 	"flag"
 	"fmt"
@@ -11,6 +13,7 @@ import (
 	"path/filepath"
 	"reflect"
 	"runtime"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -181,6 +184,7 @@ func TestScript(t *testing.T) {
 				ts.Setenv("GIT_SPICE_NOW", ts.Getenv("GIT_COMMITTER_DATE"))
 			},
 			"cmpenvJSON": cmdCmpenvJSON,
+			"jsonrpc":    cmdJSONRPC,
 			"shamhub":    shamhubCmd.Run,
 			"home": func(ts *testscript.TestScript, _ bool, args []string) {
 				if len(args) != 1 {
@@ -236,3 +240,76 @@ func cmdCmpenvJSON(ts *testscript.TestScript, neg bool, args []string) {
 	ts.Logf("%s", unifiedDiff)
 	ts.Fatalf("%s and %s differ", name1, name2)
 }
+
+// cmdJSONRPC converts between JSON values, one per line,
+// and Content-Length framed JSON-RPC messages.
+//
+//	jsonrpc frame src dst
+//	jsonrpc unframe src dst
+//
+// frame adds the JSON-RPC version to each message.
+func cmdJSONRPC(ts *testscript.TestScript, neg bool, args []string) {
+	if neg {
+		ts.Fatalf("usage: jsonrpc does not support negation")
+	}
+	if len(args) != 3 {
+		ts.Fatalf("usage: jsonrpc frame|unframe src dst")
+	}
+	src := []byte(ts.ReadFile(args[1]))
+
+	var out bytes.Buffer
+	switch args[0] {
+	case "frame":
+		for line := range bytes.Lines(src) {
+			line = bytes.TrimSpace(line)
+			if len(line) == 0 {
+				continue
+			}
+
+			var msg map[string]any
+			ts.Check(json.Unmarshal(line, &msg))
+			msg["jsonrpc"] = "2.0"
+			body, err := json.Marshal(msg)
+			ts.Check(err)
+
+			fmt.Fprintf(&out, "Content-Length: %d\r\n\r\n%s", len(body), body)
+		}
+
+	case "unframe":
+		r := bufio.NewReader(bytes.NewReader(src))
+		for {
+			length := -1
+			for {
+				line, err := r.ReadString('\n')
+				if err == io.EOF && line == "" && length < 0 {
+					ts.Check(os.WriteFile(ts.MkAbs(args[2]), out.Bytes(), 0o644))
+					return
+				}
+				ts.Check(err)
+
+				line = strings.TrimSpace(line)
+				if line == "" {
+					break
+				}
+				if v, ok := strings.CutPrefix(line, "Content-Length: "); ok {
+					length, err = strconv.Atoi(v)
+					ts.Check(err)
+				}
+			}
+			if length < 0 {
+				ts.Fatalf("message without Content-Length")
+			}
+
+			body := make([]byte, length)
+			_, err := io.ReadFull(r, body)
+			ts.Check(err)
+			out.Write(body)
+			out.WriteByte('\n')
+		}
+
+	default:
+		ts.Fatalf("unknown subcommand: %q", args[0])
+	}
+
+	ts.Check(os.WriteFile(ts.MkAbs(args[2]), out.Bytes(), 0o644))
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"go.abhg.dev/gs/internal/secret"
+	"go.abhg.dev/gs/internal/silog"
+	"go.abhg.dev/gs/internal/ui"
+)
+
+// _secretPassphraseEnv is the environment variable
+// holding the passphrase for the encrypted secret stash.
+//
+// There is intentionally no flag for this
+// so that the passphrase doesn't end up in shell history
+// or process listings.
+const _secretPassphraseEnv = "GIT_SPICE_SECRET_PASSPHRASE"
+
+// SecretStashFlags configures where authentication tokens are stored.
+//
+// These are hidden in the CLI,
+// and are expected to be set via git-config or environment variables.
+// The passphrase for the encrypted stash is read separately
+// from $GIT_SPICE_SECRET_PASSPHRASE or prompted for.
+type SecretStashFlags struct {
+	SecretStash   string `name:"secret-stash" hidden:"" config:"secret.stash" env:"GIT_SPICE_SECRET_STASH" default:"keyring" enum:"keyring,encrypted,helper,insecure" help:"Where to store authentication tokens"`
+	SecretKeyFile string `name:"secret-key-file" hidden:"" type:"path" config:"secret.keyFile" env:"GIT_SPICE_SECRET_KEY_FILE" help:"Key file for the encrypted secret stash"`
+	SecretHelper  string `name:"secret-helper" hidden:"" config:"secret.helper" env:"GIT_SPICE_SECRET_HELPER" help:"External secret helper command"`
+}
+
+// Build builds the secret stash selected by the flags.
+// configDir is the git-spice configuration directory
+// where file-based stashes are stored.
+// view is used to prompt for a passphrase if one is needed.
+func (f *SecretStashFlags) Build(configDir string, log *silog.Logger, view ui.View) (secret.Stash, error) {
+	switch f.SecretStash {
+	case "", "keyring":
+		return &secret.FallbackStash{
+			Primary: _secretStash,
+			Secondary: &secret.InsecureStash{
+				Path: filepath.Join(configDir, "secrets.json"),
+				Log:  log,
+			},
+		}, nil
+
+	case "insecure":
+		return &secret.InsecureStash{
+			Path: filepath.Join(configDir, "secrets.json"),
+			Log:  log,
+		}, nil
+
+	case "encrypted":
+		passphrase := os.Getenv(_secretPassphraseEnv)
+		if passphrase != "" && f.SecretKeyFile != "" {
+			return nil, errors.New("encrypted secret stash: " +
+				"only one of $GIT_SPICE_SECRET_PASSPHRASE or spice.secret.keyFile may be set")
+		}
+		if passphrase == "" && f.SecretKeyFile == "" {
+			if !ui.Interactive(view) {
+				return nil, errors.New("encrypted secret stash requires " +
+					"$GIT_SPICE_SECRET_PASSPHRASE or spice.secret.keyFile to be set")
+			}
+
+			field := ui.NewInput().
+				WithTitle("Passphrase").
+				WithDescription("Passphrase for the encrypted secret stash").
+				WithMask().
+				WithValidate(func(s string) error {
+					if s == "" {
+						return errors.New("passphrase is required")
+					}
+					return nil
+				}).
+				WithValue(&passphrase)
+			if err := ui.Run(view, field); err != nil {
+				return nil, fmt.Errorf("prompt for passphrase: %w", err)
+			}
+		}
+
+		return &secret.EncryptedStash{
+			Path:       filepath.Join(configDir, "secrets.enc"),
+			Passphrase: passphrase,
+			KeyFile:    f.SecretKeyFile,
+			Log:        log,
+		}, nil
+
+	case "helper":
+		if f.SecretHelper == "" {
+			return nil, errors.New("helper secret stash requires spice.secret.helper to be set")
+		}
+
+		return &secret.HelperStash{
+			Command: f.SecretHelper,
+			Log:     log,
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown secret stash: %q", f.SecretStash)
+	}
+}
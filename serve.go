@@ -0,0 +1,692 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alecthomas/kong"
+	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/jsonrpc"
+	"go.abhg.dev/gs/internal/must"
+	"go.abhg.dev/gs/internal/silog"
+	"go.abhg.dev/gs/internal/spice"
+	"go.abhg.dev/gs/internal/text"
+	"go.abhg.dev/gs/internal/ui"
+)
+
+type serveCmd struct {
+	Stdio bool `required:"" help:"Communicate over stdin and stdout"`
+
+	WatchInterval time.Duration `hidden:"" default:"1s" help:"How often to check for changes made by other processes. Set to 0 to disable."`
+
+	// conn is the connection to the client.
+	// It's set when the view is built,
+	// before any other dependencies are resolved.
+	conn *jsonrpc.Conn
+}
+
+var _ viewBuilder = (*serveCmd)(nil)
+
+func (*serveCmd) Help() string {
+	return text.Dedent(`
+		Starts a long-running JSON-RPC 2.0 server for editor integrations.
+		Messages are exchanged over stdin and stdout
+		with Content-Length headers, like the Language Server Protocol.
+
+		The server exposes the branch graph, checkout, restack, onto,
+		submit, reviews, and checks operations.
+		Prompts are sent to the client as 'ui/prompt' requests,
+		and clients are notified when branches or state change.
+
+		See https://abhinav.github.io/git-spice/cli/serve/ for details.
+	`)
+}
+
+func (cmd *serveCmd) buildView(ctx context.Context, log *silog.Logger) (ui.View, error) {
+	must.Bef(cmd.Stdio, "only --stdio is supported")
+
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		return nil, fmt.Errorf("open %v: %w", os.DevNull, err)
+	}
+
+	// stdin and stdout belong to the protocol from here on.
+	// Git and other programs we run must not read from or write to them.
+	in, out := os.Stdin, os.Stdout
+	os.Stdin, os.Stdout = devNull, os.Stderr
+
+	cmd.conn = jsonrpc.NewConn(in, out, &jsonrpc.ConnOptions{Log: log})
+	return &rpcView{ctx: ctx, conn: cmd.conn}, nil
+}
+
+func (cmd *serveCmd) Run(
+	ctx context.Context,
+	kctx *kong.Context,
+	log *silog.Logger,
+	config *spice.Config,
+	repo *git.Repository,
+	wt *git.Worktree,
+) error {
+	// Stop long-lived Git processes when the server shuts down,
+	// after the watcher has been canceled.
+	defer func() {
+		if err := repo.Close(); err != nil {
+			log.Warn("Could not close repository", "error", err)
+		}
+	}()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Reuse forge clients between requests.
+	_openedRepositories = new(openedRepositories)
+
+	srv := &server{
+		kctx:   kctx,
+		log:    log,
+		config: config,
+		repo:   repo,
+		stop:   cancel,
+		watcher: &repoWatcher{
+			log:  log,
+			conn: cmd.conn,
+			repo: repo,
+			wt:   wt,
+		},
+	}
+
+	// Commands write machine-readable output to kctx.Stdout.
+	// This is captured and returned in responses.
+	kctx.Stdout = &srv.stdout
+
+	// The logger is bound at the Kong level,
+	// so list handlers for branchGraph need to be bound up front.
+	if err := new(logCmd).AfterApply(kctx); err != nil {
+		return fmt.Errorf("bind log handlers: %w", err)
+	}
+
+	srv.watcher.Check(ctx)
+	if cmd.WatchInterval > 0 {
+		go srv.watcher.Run(ctx, cmd.WatchInterval)
+	}
+
+	log.Debug("Serving JSON-RPC over stdio")
+	return cmd.conn.Serve(ctx, srv)
+}
+
+// server handles JSON-RPC requests for 'gs serve'.
+//
+// Each operation is implemented by running the same command
+// that implements it on the command line,
+// with parameters filled from the request instead of flags.
+// These share the Git repository, state store, and forge clients
+// bound to the Kong context for the lifetime of the server.
+type server struct {
+	kctx    *kong.Context
+	log     *silog.Logger
+	config  *spice.Config
+	repo    *git.Repository
+	watcher *repoWatcher
+	stop    func()
+
+	stdout bytes.Buffer
+}
+
+var _ jsonrpc.Handler = (*server)(nil)
+
+// Handle dispatches a request to the matching operation.
+func (s *server) Handle(ctx context.Context, method string, params json.RawMessage) (any, error) {
+	s.log.Debug("Handling request", "method", method)
+
+	var (
+		result any
+		err    error
+	)
+	switch method {
+	case "branchGraph":
+		result, err = s.branchGraph(ctx, params)
+
+	case "checkout":
+		err = s.run(ctx, method, &branchCheckoutCmd{}, params)
+
+	case "restack":
+		err = s.runScoped(ctx, method, params, map[string]func() any{
+			"branch":  func() any { return &branchRestackCmd{} },
+			"upstack": func() any { return &upstackRestackCmd{} },
+			"stack":   func() any { return &stackRestackCmd{} },
+		})
+
+	case "onto":
+		err = s.runScoped(ctx, method, params, map[string]func() any{
+			"branch":  func() any { return &branchOntoCmd{} },
+			"upstack": func() any { return &upstackOntoCmd{} },
+		})
+
+	case "submit":
+		err = s.runScoped(ctx, method, params, map[string]func() any{
+			"branch":    func() any { return &branchSubmitCmd{} },
+			"upstack":   func() any { return &upstackSubmitCmd{} },
+			"downstack": func() any { return &downstackSubmitCmd{} },
+			"stack":     func() any { return &stackSubmitCmd{} },
+		})
+
+	case "reviews":
+		result, err = s.reviews(ctx, params)
+
+	case "checks":
+		result, err = s.checks(ctx, params)
+
+	case "shutdown":
+		s.stop()
+		return nil, nil
+
+	default:
+		return nil, jsonrpc.Errorf(jsonrpc.CodeMethodNotFound, "unknown method: %v", method)
+	}
+
+	// Operations may have changed branches or state.
+	// Let the client know before it sees the response.
+	s.watcher.Check(ctx)
+	return result, err
+}
+
+// branchGraphParams are the parameters of a 'branchGraph' request
+// besides the options of 'gs log short'.
+type branchGraphParams struct {
+	// Commits requests the commits of each branch
+	// as reported by 'gs log long'.
+	Commits bool `json:"commits"`
+}
+
+// branchGraphResult is the result of a 'branchGraph' request.
+type branchGraphResult struct {
+	// Branches holds an object for each branch
+	// in the format reported by 'gs log --json'.
+	Branches []json.RawMessage `json:"branches"`
+}
+
+func (s *server) branchGraph(ctx context.Context, params json.RawMessage) (*branchGraphResult, error) {
+	var req branchGraphParams
+	if err := unmarshalParams(params, &req); err != nil {
+		return nil, err
+	}
+
+	var (
+		cmd    any
+		logCmd *branchLogCmd
+	)
+	if req.Commits {
+		long := &logLongCmd{}
+		cmd, logCmd = long, &long.branchLogCmd
+	} else {
+		short := &logShortCmd{}
+		cmd, logCmd = short, &short.branchLogCmd
+	}
+
+	params, err := withoutParams(params, "commits")
+	if err != nil {
+		return nil, err
+	}
+
+	stdout, err := s.capture(ctx, "branchGraph", cmd, params, func() {
+		logCmd.JSON = true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	branches, err := decodeJSONStream(stdout)
+	if err != nil {
+		return nil, fmt.Errorf("read branches: %w", err)
+	}
+	return &branchGraphResult{Branches: branches}, nil
+}
+
+// reviewsResult is the result of a 'reviews' request.
+type reviewsResult struct {
+	// Items holds an object for each review thread or annotation
+	// in the format reported by 'gs branch reviews --format=json'.
+	Items []json.RawMessage `json:"items"`
+}
+
+func (s *server) reviews(ctx context.Context, params json.RawMessage) (*reviewsResult, error) {
+	cmd := &branchReviewsCmd{}
+	stdout, err := s.capture(ctx, "reviews", cmd, params, func() {
+		cmd.Format = "json"
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := decodeJSONStream(stdout)
+	if err != nil {
+		return nil, fmt.Errorf("read review items: %w", err)
+	}
+	return &reviewsResult{Items: items}, nil
+}
+
+// checksResult is the result of a 'checks' request.
+type checksResult struct {
+	Checks []serveCheck `json:"checks"`
+}
+
+// serveCheck is a single CI check run in a 'checks' response.
+type serveCheck struct {
+	Name       string    `json:"name"`
+	Status     string    `json:"status,omitempty"`
+	Conclusion string    `json:"conclusion,omitempty"`
+	URL        string    `json:"url,omitempty"`
+	StartedAt  time.Time `json:"startedAt,omitzero"`
+	EndedAt    time.Time `json:"endedAt,omitzero"`
+}
+
+func (s *server) checks(ctx context.Context, params json.RawMessage) (*checksResult, error) {
+	cmd := &branchChecksCmd{}
+	if err := s.prepare(cmd, params, nil); err != nil {
+		return nil, err
+	}
+
+	var items []*forge.ChangeCheckItem
+	if err := s.call(cmd.listChecks, &items); err != nil {
+		return nil, err
+	}
+
+	checks := make([]serveCheck, 0, len(items))
+	for _, item := range items {
+		checks = append(checks, serveCheck{
+			Name:       item.Name,
+			Status:     item.Status,
+			Conclusion: item.Conclusion,
+			URL:        item.URL,
+			StartedAt:  item.StartedAt,
+			EndedAt:    item.EndedAt,
+		})
+	}
+	return &checksResult{Checks: checks}, nil
+}
+
+// scopeParams selects which command handles a request
+// for operations that apply to different parts of a stack.
+type scopeParams struct {
+	// Scope is one of "branch", "upstack", "downstack", or "stack".
+	// Defaults to "branch".
+	Scope string `json:"scope"`
+}
+
+// runScoped runs the command for the request's scope.
+func (s *server) runScoped(
+	ctx context.Context,
+	method string,
+	params json.RawMessage,
+	scopes map[string]func() any,
+) error {
+	var req scopeParams
+	if err := unmarshalParams(params, &req); err != nil {
+		return err
+	}
+	if req.Scope == "" {
+		req.Scope = "branch"
+	}
+
+	newCmd, ok := scopes[req.Scope]
+	if !ok {
+		return jsonrpc.Errorf(jsonrpc.CodeInvalidParams, "%v: unsupported scope: %q", method, req.Scope)
+	}
+
+	params, err := withoutParams(params, "scope")
+	if err != nil {
+		return err
+	}
+	return s.run(ctx, method, newCmd(), params)
+}
+
+// run runs a command and discards its standard output.
+func (s *server) run(ctx context.Context, method string, cmd any, params json.RawMessage) error {
+	_, err := s.capture(ctx, method, cmd, params, nil)
+	return err
+}
+
+// capture runs a command with the given parameters
+// and returns what it wrote to standard output.
+//
+// override, if non-nil, is called after parameters are filled in
+// to set fields that clients may not change.
+func (s *server) capture(
+	ctx context.Context,
+	method string,
+	cmd any,
+	params json.RawMessage,
+	override func(),
+) ([]byte, error) {
+	if err := s.prepare(cmd, params, override); err != nil {
+		return nil, err
+	}
+
+	// Commands that must hold the repository lock on the command line
+	// hold it for the duration of the request.
+	if _, ok := cmd.(repoLocker); ok && os.Getenv(_repoLockEnv) == "" {
+		lock, err := lockRepository(ctx, s.repo, "gs serve "+method)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			if err := lock.Release(); err != nil {
+				s.log.Error("Error releasing repository lock", "error", err)
+			}
+			if err := os.Unsetenv(_repoLockEnv); err != nil {
+				s.log.Error("Error clearing lock holder", "error", err)
+			}
+		}()
+	}
+
+	s.stdout.Reset()
+	if err := s.callHooks(cmd, "AfterApply"); err != nil {
+		return nil, err
+	}
+	run := reflect.ValueOf(cmd).MethodByName("Run")
+	must.Bef(run.IsValid(), "%T does not have a Run method", cmd)
+	if err := s.call(run.Interface(), nil); err != nil {
+		return nil, err
+	}
+
+	return bytes.Clone(s.stdout.Bytes()), nil
+}
+
+// prepare fills a command with defaults, configuration,
+// and the request parameters.
+//
+// Parameters are turned into command line arguments
+// and parsed by Kong,
+// so they're validated exactly like flags and arguments would be.
+// See paramArgs for how they're matched.
+func (s *server) prepare(cmd any, params json.RawMessage, override func()) error {
+	app, err := kong.New(cmd,
+		kong.Resolvers(s.config),
+		kong.NoDefaultHelp(),
+	)
+	if err != nil {
+		return fmt.Errorf("build parser: %w", err)
+	}
+
+	args, err := paramArgs(app.Model.Node, params)
+	if err != nil {
+		return jsonrpc.Errorf(jsonrpc.CodeInvalidParams, "invalid params: %v", err)
+	}
+
+	// This follows the same steps as kong.Parse,
+	// except that hooks are called with the server's bindings.
+	pctx, err := kong.Trace(app, args)
+	if err == nil {
+		err = pctx.Error
+	}
+	if err == nil {
+		err = pctx.Reset()
+	}
+	if err == nil {
+		err = pctx.Resolve()
+	}
+	if err != nil {
+		return jsonrpc.Errorf(jsonrpc.CodeInvalidParams, "invalid params: %v", err)
+	}
+
+	// Some hooks bind providers that the command needs,
+	// so they must run before anything else like on the command line.
+	if err := s.callHooks(cmd, "BeforeApply"); err != nil {
+		return err
+	}
+
+	if _, err := pctx.Apply(); err != nil {
+		return jsonrpc.Errorf(jsonrpc.CodeInvalidParams, "invalid params: %v", err)
+	}
+	if err := pctx.Validate(); err != nil {
+		return jsonrpc.Errorf(jsonrpc.CodeInvalidParams, "invalid params: %v", err)
+	}
+	if override != nil {
+		override()
+	}
+	return nil
+}
+
+// paramArgs converts request parameters into command line arguments
+// for the flags and positional arguments of node.
+//
+// Parameters are named after flags in camelCase,
+// so {"noPublish": true} becomes --no-publish=true.
+// Lists become repeated flags or positional arguments,
+// and null values are ignored.
+// Parameters that don't match a flag or argument are rejected.
+func paramArgs(node *kong.Node, params json.RawMessage) ([]string, error) {
+	if len(params) == 0 {
+		return nil, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(params, &fields); err != nil {
+		return nil, err
+	}
+
+	var (
+		args        []string
+		positionals = make([][]string, len(node.Positional))
+	)
+	for _, name := range slices.Sorted(maps.Keys(fields)) {
+		values, err := paramValues(fields[name])
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", name, err)
+		}
+		if values == nil {
+			continue // null
+		}
+
+		if flag := findParamFlag(node, name); flag != nil {
+			if len(values) > 1 && !flag.IsSlice() && !flag.IsMap() {
+				return nil, fmt.Errorf("%v: expected a single value", name)
+			}
+			for _, v := range values {
+				args = append(args, "--"+flag.Name+"="+v)
+			}
+			continue
+		}
+
+		idx := slices.IndexFunc(node.Positional, func(v *kong.Value) bool {
+			return paramNameMatches(v.Name, name)
+		})
+		if idx < 0 {
+			return nil, fmt.Errorf("unknown parameter %q", name)
+		}
+		if len(values) > 1 && !node.Positional[idx].IsSlice() {
+			return nil, fmt.Errorf("%v: expected a single value", name)
+		}
+		positionals[idx] = values
+	}
+
+	// Positional arguments can only be omitted from the end.
+	var rest []string
+	for idx, values := range positionals {
+		if len(values) == 0 {
+			continue
+		}
+		for _, missing := range positionals[:idx] {
+			if len(missing) == 0 {
+				return nil, fmt.Errorf("%v: requires %v to be set",
+					node.Positional[idx].Name, node.Positional[idx-1].Name)
+			}
+		}
+		rest = append(rest, values...)
+	}
+	if len(rest) > 0 {
+		args = append(args, "--")
+		args = append(args, rest...)
+	}
+	return args, nil
+}
+
+// paramValues returns the command line representation of a parameter,
+// or nil if it's null.
+func paramValues(raw json.RawMessage) ([]string, error) {
+	var value any
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	items, isList := value.([]any)
+	if !isList {
+		if value == nil {
+			return nil, nil
+		}
+		items = []any{value}
+	}
+
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		switch item := item.(type) {
+		case string:
+			values = append(values, item)
+		case bool:
+			values = append(values, strconv.FormatBool(item))
+		case json.Number:
+			values = append(values, item.String())
+		default:
+			return nil, fmt.Errorf("unsupported value: %s", raw)
+		}
+	}
+	return values, nil
+}
+
+// findParamFlag returns the flag of node named by a request parameter,
+// or nil if there isn't one.
+func findParamFlag(node *kong.Node, name string) *kong.Flag {
+	for _, flags := range node.AllFlags(false) {
+		for _, flag := range flags {
+			if paramNameMatches(flag.Name, name) {
+				return flag
+			}
+		}
+	}
+	return nil
+}
+
+// paramNameMatches reports whether the parameter name
+// refers to the given flag or argument name,
+// e.g. "noPublish" and "no-publish".
+func paramNameMatches(argName, paramName string) bool {
+	return strings.EqualFold(strings.ReplaceAll(argName, "-", ""), paramName)
+}
+
+// callHooks calls Kong hooks with the given name on cmd
+// and the structs embedded in it.
+func (s *server) callHooks(cmd any, name string) error {
+	for _, hook := range hookMethods(reflect.ValueOf(cmd), name) {
+		if err := s.call(hook.Interface(), nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// call calls fn with arguments injected from the Kong context.
+// fn must return an error as its last result.
+// If result is non-nil, it's set to fn's first result.
+func (s *server) call(fn, result any) error {
+	out, err := s.kctx.Call(fn)
+	if err != nil {
+		return err
+	}
+	must.NotBeEmptyf(out, "%T must return an error", fn)
+
+	if err, _ := out[len(out)-1].(error); err != nil {
+		return err
+	}
+	if result != nil {
+		reflect.ValueOf(result).Elem().Set(reflect.ValueOf(out[0]))
+	}
+	return nil
+}
+
+// hookMethods finds methods with the given name on v,
+// a pointer to a struct, and the structs embedded in it.
+//
+// A method declared on the struct or promoted from a single embedded struct
+// is found directly.
+// Otherwise, each embedded struct is searched.
+func hookMethods(v reflect.Value, name string) []reflect.Value {
+	if m := v.MethodByName(name); m.IsValid() {
+		return []reflect.Value{m}
+	}
+
+	elem := v.Elem()
+	if elem.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var methods []reflect.Value
+	for i := range elem.NumField() {
+		field := elem.Type().Field(i)
+		if !field.Anonymous || !field.IsExported() {
+			continue
+		}
+
+		fieldValue := elem.Field(i)
+		if fieldValue.Kind() != reflect.Struct {
+			continue
+		}
+		methods = append(methods, hookMethods(fieldValue.Addr(), name)...)
+	}
+	return methods
+}
+
+// unmarshalParams decodes request parameters into dst.
+// Missing parameters leave dst unchanged.
+func unmarshalParams(params json.RawMessage, dst any) error {
+	if len(params) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(params, dst); err != nil {
+		return jsonrpc.Errorf(jsonrpc.CodeInvalidParams, "invalid params: %v", err)
+	}
+	return nil
+}
+
+// withoutParams returns params without the given keys.
+// These are parameters handled by the server itself
+// that don't correspond to flags of the command.
+func withoutParams(params json.RawMessage, keys ...string) (json.RawMessage, error) {
+	if len(params) == 0 {
+		return params, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(params, &fields); err != nil {
+		return nil, jsonrpc.Errorf(jsonrpc.CodeInvalidParams, "invalid params: %v", err)
+	}
+	for _, key := range keys {
+		delete(fields, key)
+	}
+	return json.Marshal(fields)
+}
+
+// decodeJSONStream splits a stream of JSON values into its values.
+func decodeJSONStream(bs []byte) ([]json.RawMessage, error) {
+	values := []json.RawMessage{} // never nil
+	dec := json.NewDecoder(bytes.NewReader(bs))
+	for dec.More() {
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
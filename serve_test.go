@@ -0,0 +1,96 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/alecthomas/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParamArgs(t *testing.T) {
+	var cmd struct {
+		NoPublish bool     `help:"Don't publish"`
+		Label     []string `short:"l" help:"Labels"`
+		Count     int      `help:"How many"`
+		Secret    string   `hidden:"" help:"Hidden flag"`
+
+		Base  string   `arg:"" optional:"" help:"Base branch"`
+		Heads []string `arg:"" optional:"" help:"Head branches"`
+	}
+	app, err := kong.New(&cmd, kong.NoDefaultHelp())
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		params  string
+		want    []string
+		wantErr string
+	}{
+		{name: "Empty"},
+		{name: "EmptyObject", params: `{}`},
+		{
+			name:   "Flags",
+			params: `{"noPublish": true, "count": 3, "secret": "x"}`,
+			want:   []string{"--count=3", "--no-publish=true", "--secret=x"},
+		},
+		{
+			name:   "ListFlag",
+			params: `{"label": ["a", "b"]}`,
+			want:   []string{"--label=a", "--label=b"},
+		},
+		{
+			name:   "Null",
+			params: `{"label": null}`,
+		},
+		{
+			name:   "Positionals",
+			params: `{"heads": ["feat1", "feat2"], "base": "main"}`,
+			want:   []string{"--", "main", "feat1", "feat2"},
+		},
+		{
+			name:   "PositionalWithDash",
+			params: `{"base": "-main"}`,
+			want:   []string{"--", "-main"},
+		},
+		{
+			name:    "UnknownParam",
+			params:  `{"frob": true}`,
+			wantErr: `unknown parameter "frob"`,
+		},
+		{
+			name:    "ListForScalar",
+			params:  `{"count": [1, 2]}`,
+			wantErr: "count: expected a single value",
+		},
+		{
+			name:    "Object",
+			params:  `{"count": {"n": 1}}`,
+			wantErr: "count: unsupported value",
+		},
+		{
+			name:    "PositionalGap",
+			params:  `{"heads": ["feat1"]}`,
+			wantErr: "heads: requires base to be set",
+		},
+		{
+			name:    "NotObject",
+			params:  `[1, 2]`,
+			wantErr: "cannot unmarshal",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := paramArgs(app.Model.Node, []byte(tt.params))
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"go.abhg.dev/gs/internal/jsonrpc"
+	"go.abhg.dev/gs/internal/ui"
+)
+
+// rpcCaller sends requests and notifications to a JSON-RPC client.
+type rpcCaller interface {
+	Notify(method string, params any) error
+	Call(ctx context.Context, method string, params, result any) error
+}
+
+var _ rpcCaller = (*jsonrpc.Conn)(nil)
+
+// rpcView is a [ui.View] for 'gs serve'.
+//
+// Messages are posted to the client as 'ui/output' notifications,
+// and prompts are sent to it as 'ui/prompt' requests, one per field.
+type rpcView struct {
+	ctx  context.Context
+	conn rpcCaller
+}
+
+var _ ui.InteractiveView = (*rpcView)(nil)
+
+// rpcOutputParams are the parameters of a 'ui/output' notification.
+type rpcOutputParams struct {
+	Text string `json:"text"`
+}
+
+func (v *rpcView) Write(p []byte) (int, error) {
+	if err := v.conn.Notify("ui/output", rpcOutputParams{Text: string(p)}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// rpcPromptParams are the parameters of a 'ui/prompt' request.
+//
+// The client responds with the value for the field
+// in the same JSON form accepted by the field's UnmarshalValue method.
+type rpcPromptParams struct {
+	// Kind is the kind of field, e.g. "confirm", "input", "select".
+	Kind string `json:"kind"`
+
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	// Rendered is a plain text rendering of the field
+	// as it would appear in a terminal.
+	Rendered string `json:"rendered"`
+}
+
+// Prompt asks the client to fill each field in order.
+func (v *rpcView) Prompt(fields ...ui.Field) error {
+	for idx, field := range fields {
+		if initRPCField(field) {
+			continue
+		}
+
+		var rendered strings.Builder
+		field.Render(&rendered)
+		params := rpcPromptParams{
+			Kind:        rpcFieldKind(field),
+			Title:       field.Title(),
+			Description: field.Description(),
+			Rendered:    rendered.String(),
+		}
+
+		var value json.RawMessage
+		if err := v.conn.Call(v.ctx, "ui/prompt", params, &value); err != nil {
+			return fmt.Errorf("field [%d]: %w", idx, err)
+		}
+
+		if err := field.UnmarshalValue(func(dst any) error {
+			return json.Unmarshal(value, dst)
+		}); err != nil {
+			return fmt.Errorf("field [%d]: bad input: %w", idx, err)
+		}
+		if err := field.Err(); err != nil {
+			return fmt.Errorf("field [%d]: %w", idx, err)
+		}
+	}
+	return nil
+}
+
+// initRPCField initializes a field the same way a form would
+// before it's presented to the user.
+// It reports whether the field asked to be skipped.
+func initRPCField(field ui.Field) (skip bool) {
+	cmd := tea.Sequence(
+		field.Init(),
+		field.Update(tea.WindowSizeMsg{Width: 80, Height: 40}),
+	)
+	if cmd == nil {
+		return false
+	}
+
+	msgs := []tea.Msg{cmd()}
+	for len(msgs) > 0 {
+		var msg tea.Msg
+		msg, msgs = msgs[0], msgs[1:]
+
+		switch msg := msg.(type) {
+		case nil:
+			// Commands may not produce a message.
+
+		case tea.BatchMsg:
+			for _, cmd := range msg {
+				if cmd != nil {
+					msgs = append(msgs, cmd())
+				}
+			}
+
+		case tea.Cmd:
+			if msg != nil {
+				msgs = append(msgs, msg())
+			}
+
+		default:
+			// tea.Sequence produces an unexported slice of commands.
+			if v := reflect.ValueOf(msg); v.Kind() == reflect.Slice {
+				for i := range v.Len() {
+					if cmd, ok := v.Index(i).Interface().(tea.Cmd); ok && cmd != nil {
+						msgs = append(msgs, cmd())
+					}
+				}
+				continue
+			}
+
+			if reflect.TypeOf(msg).Comparable() && msg == ui.SkipField() {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// rpcFieldKind reports the kind of a field for 'ui/prompt' requests
+// based on its type name, e.g. "confirm" for *ui.Confirm
+// and "multiSelect" for ui.MultiSelect[T].
+func rpcFieldKind(field ui.Field) string {
+	// Fields like ui.Deferred wrap the field that's actually shown.
+	for {
+		w, ok := field.(interface{ Unwrap() ui.Field })
+		if !ok || w.Unwrap() == nil {
+			break
+		}
+		field = w.Unwrap()
+	}
+
+	t := reflect.TypeOf(field)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	name, _, _ := strings.Cut(t.Name(), "[")
+	if name == "" {
+		return "unknown"
+	}
+
+	r := []rune(name)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
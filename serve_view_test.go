@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/gs/internal/jsonrpc"
+	"go.abhg.dev/gs/internal/ui"
+)
+
+// fakeRPCCaller records messages sent by an rpcView,
+// answering requests with the given responses in order.
+type fakeRPCCaller struct {
+	responses []string // JSON
+
+	notifications []string // "method: params"
+	calls         []rpcPromptParams
+}
+
+var _ rpcCaller = (*fakeRPCCaller)(nil)
+
+func (f *fakeRPCCaller) Notify(method string, params any) error {
+	bs, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	f.notifications = append(f.notifications, fmt.Sprintf("%v: %s", method, bs))
+	return nil
+}
+
+func (f *fakeRPCCaller) Call(_ context.Context, method string, params, result any) error {
+	if method != "ui/prompt" {
+		return fmt.Errorf("unexpected call: %v", method)
+	}
+	f.calls = append(f.calls, params.(rpcPromptParams))
+
+	if len(f.responses) == 0 {
+		return &jsonrpc.Error{Code: 1, Message: "canceled"}
+	}
+	var res string
+	res, f.responses = f.responses[0], f.responses[1:]
+	return json.Unmarshal([]byte(res), result)
+}
+
+func TestRPCView_Write(t *testing.T) {
+	caller := new(fakeRPCCaller)
+	view := &rpcView{ctx: t.Context(), conn: caller}
+
+	_, err := fmt.Fprintln(view, "hello")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{`ui/output: {"text":"hello\n"}`}, caller.notifications)
+}
+
+func TestRPCView_Prompt(t *testing.T) {
+	caller := &fakeRPCCaller{
+		responses: []string{`false`, `"feat1"`},
+	}
+	view := &rpcView{ctx: t.Context(), conn: caller}
+
+	var (
+		proceed = true
+		name    string
+	)
+	err := ui.Run(view,
+		ui.NewConfirm().
+			WithTitle("Proceed?").
+			WithDescription("Something will happen.").
+			WithValue(&proceed),
+		// Deferred fields that aren't built are skipped.
+		ui.Defer(func() ui.Field { return nil }),
+		ui.Defer(func() ui.Field {
+			return ui.NewInput().
+				WithTitle("Branch name").
+				WithValue(&name)
+		}),
+	)
+	require.NoError(t, err)
+
+	assert.False(t, proceed)
+	assert.Equal(t, "feat1", name)
+
+	require.Len(t, caller.calls, 2)
+	assert.Equal(t, rpcPromptParams{
+		Kind:        "confirm",
+		Title:       "Proceed?",
+		Description: "Something will happen.",
+		Rendered:    "[Y/n]",
+	}, caller.calls[0])
+	assert.Equal(t, "input", caller.calls[1].Kind)
+	assert.Equal(t, "Branch name", caller.calls[1].Title)
+}
+
+func TestRPCView_PromptCanceled(t *testing.T) {
+	caller := new(fakeRPCCaller)
+	view := &rpcView{ctx: t.Context(), conn: caller}
+
+	var proceed bool
+	err := ui.Run(view, ui.NewConfirm().WithTitle("Proceed?").WithValue(&proceed))
+	require.Error(t, err)
+
+	var rpcErr *jsonrpc.Error
+	require.ErrorAs(t, err, &rpcErr)
+	assert.Equal(t, "canceled", rpcErr.Message)
+}
+
+func TestRPCView_PromptBadValue(t *testing.T) {
+	caller := &fakeRPCCaller{responses: []string{`"yes"`}}
+	view := &rpcView{ctx: t.Context(), conn: caller}
+
+	var proceed bool
+	err := ui.Run(view, ui.NewConfirm().WithTitle("Proceed?").WithValue(&proceed))
+	assert.ErrorContains(t, err, "bad input")
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/silog"
+)
+
+// repoWatcher notifies clients of 'gs serve'
+// when branches or git-spice's state change,
+// whether by the server itself or by other processes.
+type repoWatcher struct {
+	log  *silog.Logger   // required
+	conn rpcCaller       // required
+	repo *git.Repository // required
+	wt   *git.Worktree   // required
+
+	mu   sync.Mutex
+	last *repoSnapshot // nil until the first check
+}
+
+// repoSnapshot is the observable state of a repository
+// at a point in time.
+type repoSnapshot struct {
+	branches map[string]git.Hash
+	current  string // empty if detached
+
+	// state holds the top-level entries of git-spice's state tree.
+	state map[string]git.Hash
+}
+
+// refsChangedParams are the parameters of a 'refs/didChange' notification.
+type refsChangedParams struct {
+	// Branches lists branches that were created, deleted, or moved.
+	Branches []string `json:"branches"`
+
+	// Current is the branch checked out in the worktree.
+	// It's empty if HEAD is detached.
+	Current string `json:"current"`
+}
+
+// Run checks for changes at the given interval
+// until the context is canceled.
+func (w *repoWatcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.Check(ctx)
+		}
+	}
+}
+
+// Check takes a new snapshot of the repository
+// and notifies the client of differences from the last one.
+// The first check only records the snapshot.
+func (w *repoWatcher) Check(ctx context.Context) {
+	snap, err := w.snapshot(ctx)
+	if err != nil {
+		// The repository may be in the middle of an update.
+		// Try again on the next check.
+		w.log.Debug("Could not inspect repository", "error", err)
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	last := w.last
+	w.last = snap
+	if last == nil {
+		return
+	}
+
+	changed := []string{} // never nil
+	for name, hash := range snap.branches {
+		if oldHash, ok := last.branches[name]; !ok || oldHash != hash {
+			changed = append(changed, name)
+		}
+	}
+	for name := range last.branches {
+		if _, ok := snap.branches[name]; !ok {
+			changed = append(changed, name)
+		}
+	}
+	slices.Sort(changed)
+
+	if len(changed) > 0 || snap.current != last.current {
+		if err := w.conn.Notify("refs/didChange", refsChangedParams{
+			Branches: changed,
+			Current:  snap.current,
+		}); err != nil {
+			w.log.Debug("Could not notify client", "error", err)
+		}
+	}
+
+	if !maps.Equal(snap.state, last.state) {
+		if err := w.conn.Notify("state/didChange", nil); err != nil {
+			w.log.Debug("Could not notify client", "error", err)
+		}
+	}
+}
+
+func (w *repoWatcher) snapshot(ctx context.Context) (*repoSnapshot, error) {
+	branches := make(map[string]git.Hash)
+	for branch, err := range w.repo.LocalBranches(ctx, nil) {
+		if err != nil {
+			return nil, err
+		}
+		branches[branch.Name] = branch.Hash
+	}
+
+	current, err := w.wt.CurrentBranch(ctx)
+	if err != nil {
+		if !errors.Is(err, git.ErrDetachedHead) {
+			return nil, err
+		}
+		current = ""
+	}
+
+	// The data ref doesn't exist until the repository is initialized.
+	stateEntries := make(map[string]git.Hash)
+	if tree, err := w.repo.PeelToTree(ctx, _dataRef); err == nil {
+		for ent, err := range w.repo.ListTree(ctx, tree, git.ListTreeOptions{}) {
+			if err != nil {
+				return nil, err
+			}
+
+			stateEntries[ent.Name] = ent.Hash
+		}
+	}
+
+	return &repoSnapshot{
+		branches: branches,
+		current:  current,
+		state:    stateEntries,
+	}, nil
+}
@@ -1,10 +1,11 @@
 package main
 
 type stackCmd struct {
-	Submit  stackSubmitCmd  `cmd:"" aliases:"s" help:"Submit a stack"`
-	Restack stackRestackCmd `cmd:"" aliases:"r" help:"Restack a stack"`
-	Edit    stackEditCmd    `cmd:"" aliases:"e" help:"Edit the order of branches in a stack"`
-	Delete  stackDeleteCmd  `cmd:"" aliases:"d" released:"v0.16.0" help:"Delete all branches in a stack"`
-	Reviews stackReviewsCmd `cmd:"" help:"Walk reviews on every PR in the stack"`
-	Checks  stackChecksCmd  `cmd:"" help:"Walk failing checks on every PR in the stack"`
+	Submit      stackSubmitCmd      `cmd:"" aliases:"s" help:"Submit a stack"`
+	Restack     stackRestackCmd     `cmd:"" aliases:"r" help:"Restack a stack"`
+	Edit        stackEditCmd        `cmd:"" aliases:"e" help:"Edit the order of branches in a stack"`
+	Delete      stackDeleteCmd      `cmd:"" aliases:"d" released:"v0.16.0" help:"Delete all branches in a stack"`
+	Reviews     stackReviewsCmd     `cmd:"" help:"Walk reviews on every PR in the stack"`
+	Checks      stackChecksCmd      `cmd:"" help:"Walk failing checks on every PR in the stack"`
+	FormatPatch stackFormatPatchCmd `cmd:"" released:"unreleased" help:"Export a stack as an email patch series"`
 }
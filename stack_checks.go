@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/forge/forgecache"
 	"go.abhg.dev/gs/internal/git"
 	"go.abhg.dev/gs/internal/secret"
 	"go.abhg.dev/gs/internal/silog"
@@ -40,6 +41,7 @@ func (c *stackChecksCmd) Run(
 	repo *git.Repository,
 	store *state.Store,
 	stash secret.Stash,
+	cache *forgecache.Cache,
 	forges *forge.Registry,
 	svc *spice.Service,
 ) error {
@@ -68,7 +70,7 @@ func (c *stackChecksCmd) Run(
 			Branch:         branch,
 			IncludePassing: c.IncludePassing,
 		}
-		if err := cmd.Run(ctx, log, view, wt, repo, store, stash, forges); err != nil {
+		if err := cmd.Run(ctx, log, view, wt, repo, store, stash, cache, forges); err != nil {
 			errs = append(errs, branchErr{branch, err})
 		}
 	}
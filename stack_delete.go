@@ -19,6 +19,8 @@ type stackDeleteCmd struct {
 	Force bool `help:"Force deletion of the branches"`
 }
 
+func (*stackDeleteCmd) locksRepository() {}
+
 func (*stackDeleteCmd) Help() string {
 	return text.Dedent(`
 		Deletes all branches in the current branch's stack.
@@ -20,6 +20,8 @@ type stackEditCmd struct {
 	Branch string `placeholder:"NAME" help:"Branch whose stack we're editing. Defaults to current branch." predictor:"trackedBranches"`
 }
 
+func (*stackEditCmd) locksRepository() {}
+
 func (*stackEditCmd) Help() string {
 	return text.Dedent(`
 		This operation requires a linear stack:
@@ -0,0 +1,273 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/alecthomas/kong"
+	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/silog"
+	"go.abhg.dev/gs/internal/spice"
+	"go.abhg.dev/gs/internal/spice/state"
+	"go.abhg.dev/gs/internal/text"
+)
+
+type stackFormatPatchCmd struct {
+	Branch string `placeholder:"NAME" help:"Branch whose stack to export. Defaults to current branch." predictor:"trackedBranches"`
+
+	OutputDir     string `short:"o" placeholder:"DIR" xor:"output" help:"Directory to write patch files to. Defaults to the current directory."`
+	Stdout        bool   `xor:"output" help:"Write all patches to stdout as an mbox"`
+	SubjectPrefix string `placeholder:"PREFIX" default:"PATCH" help:"Prefix to use in patch subjects instead of PATCH"`
+}
+
+func (*stackFormatPatchCmd) Help() string {
+	return text.Dedent(`
+		Exports the commits of the current stack as a numbered
+		email patch series that can be sent with 'git send-email'.
+		This operation requires a linear stack:
+		no branch can have multiple branches above it.
+
+		The series starts with a cover letter
+		listing the branches in the stack
+		and the commits in each.
+
+		git-spice remembers each version of the series it exports.
+		If the stack has changed since the last export,
+		the new series is numbered as the next version, e.g. [PATCH v2],
+		and its cover letter includes a range-diff
+		against the previous version.
+
+		Patch files are written to the current directory by default.
+		Use -o to pick a different directory,
+		or --stdout to write them as a single mbox.
+	`)
+}
+
+func (cmd *stackFormatPatchCmd) Run(
+	ctx context.Context,
+	kctx *kong.Context,
+	log *silog.Logger,
+	repo *git.Repository,
+	wt *git.Worktree,
+	store *state.Store,
+	svc *spice.Service,
+) error {
+	if cmd.Branch == "" {
+		currentBranch, err := wt.CurrentBranch(ctx)
+		if err != nil {
+			return fmt.Errorf("get current branch: %w", err)
+		}
+		cmd.Branch = currentBranch
+	}
+
+	stack, err := svc.ListStackLinear(ctx, cmd.Branch)
+	if err != nil {
+		var nonLinearErr *spice.NonLinearStackError
+		if errors.As(err, &nonLinearErr) {
+			log.Errorf("%v is part of a stack with a divergent upstack.", cmd.Branch)
+			log.Errorf("%v has multiple branches above it: %s", nonLinearErr.Branch, strings.Join(nonLinearErr.Aboves, ", "))
+			log.Errorf("Check out one of those branches and try again.")
+			return errors.New("current branch has ambiguous upstack")
+		}
+		return fmt.Errorf("list stack: %w", err)
+	}
+
+	// If current branch was trunk, it'll be at the bottom of the stack.
+	if len(stack) > 0 && stack[0] == store.Trunk() {
+		stack = stack[1:]
+	}
+	if len(stack) == 0 {
+		return errors.New("no branches in stack to export")
+	}
+
+	// Patches must apply on top of each other in order,
+	// so every branch must be restacked.
+	branches := make([]*formatPatchBranch, len(stack))
+	for i, name := range stack {
+		if err := svc.VerifyRestacked(ctx, name); err != nil {
+			var restackErr *spice.BranchNeedsRestackError
+			if errors.As(err, &restackErr) {
+				log.Errorf("%v: branch needs to be restacked.", name)
+				log.Errorf("Run the following command to fix this:")
+				log.Errorf("  gs stack restack")
+				return errors.New("stack is not restacked")
+			}
+			return fmt.Errorf("verify restacked %v: %w", name, err)
+		}
+
+		b, err := svc.LookupBranch(ctx, name)
+		if err != nil {
+			return fmt.Errorf("lookup branch %v: %w", name, err)
+		}
+
+		msgs, err := repo.CommitMessageRange(ctx, b.Head.String(), b.BaseHash.String())
+		if err != nil {
+			return fmt.Errorf("list commits of %v: %w", name, err)
+		}
+
+		branches[i] = &formatPatchBranch{
+			Name:     name,
+			Messages: msgs,
+		}
+	}
+
+	bottom, err := svc.LookupBranch(ctx, stack[0])
+	if err != nil {
+		return fmt.Errorf("lookup branch %v: %w", stack[0], err)
+	}
+	top, err := svc.LookupBranch(ctx, stack[len(stack)-1])
+	if err != nil {
+		return fmt.Errorf("lookup branch %v: %w", stack[len(stack)-1], err)
+	}
+	span := git.CommitSpan{Base: bottom.BaseHash, Head: top.Head}
+	if span.Base == span.Head {
+		return errors.New("stack has no commits to export")
+	}
+
+	// Merge commits can't be sent as patches,
+	// and merge-based restacks leave old versions of commits behind.
+	mergeCommits := git.CommitRangeFrom(span.Head).ExcludeFrom(span.Base).Merges().Limit(1)
+	for _, err := range repo.ListCommits(ctx, mergeCommits) {
+		if err != nil {
+			return fmt.Errorf("list merge commits: %w", err)
+		}
+
+		log.Errorf("Stack contains merge commits which cannot be exported as patches.")
+		log.Errorf("Rebase the affected branches to remove them and try again.")
+		log.Errorf("To avoid merge commits when restacking, run:")
+		log.Errorf("  git config spice.restack.method rebase")
+		return errors.New("stack is not linear")
+	}
+
+	// The series is identified by the bottom branch of the stack.
+	series, err := store.LoadPatchSeries(ctx, stack[0])
+	if err != nil {
+		return fmt.Errorf("load patch series: %w", err)
+	}
+	if series == nil {
+		series = &state.PatchSeries{Name: stack[0]}
+	}
+
+	// If nothing changed since the last export,
+	// re-export the same version instead of starting a new one.
+	var changed bool
+	if n := len(series.Versions); n == 0 || series.Versions[n-1] != span {
+		series.Versions = append(series.Versions, span)
+		changed = true
+	}
+	version := len(series.Versions)
+
+	var rangeDiff *git.CommitSpan
+	if version > 1 {
+		prev := series.Versions[version-2]
+		if _, err := repo.PeelToCommit(ctx, prev.Head.String()); err != nil {
+			log.Warnf("Commits for v%d of the series are no longer available. Skipping range-diff.", version-1)
+		} else {
+			rangeDiff = &prev
+		}
+	}
+
+	req := git.FormatPatchRequest{
+		Base:          span.Base,
+		Head:          span.Head,
+		SubjectPrefix: cmd.SubjectPrefix,
+		Version:       version,
+		CoverLetter:   formatPatchCoverLetter(branches),
+		RangeDiff:     rangeDiff,
+	}
+	if cmd.Stdout {
+		req.Stdout = kctx.Stdout
+	} else {
+		req.OutputDir, err = filepath.Abs(cmp.Or(cmd.OutputDir, "."))
+		if err != nil {
+			return fmt.Errorf("resolve output directory: %w", err)
+		}
+	}
+
+	files, err := repo.FormatPatch(ctx, req)
+	if err != nil {
+		return fmt.Errorf("format patches: %w", err)
+	}
+	for _, f := range files {
+		fmt.Fprintln(kctx.Stdout, f)
+	}
+
+	if changed {
+		if err := store.SavePatchSeries(ctx, series); err != nil {
+			return fmt.Errorf("save patch series: %w", err)
+		}
+	}
+
+	log.Infof("Exported v%d of the series for %v", version, stack[0])
+	return nil
+}
+
+// formatPatchBranch is a branch exported as part of a patch series.
+type formatPatchBranch struct {
+	Name string
+
+	// Messages are the messages of commits in the branch,
+	// newest first.
+	Messages []git.CommitMessage
+}
+
+// formatPatchCoverLetter generates a cover letter for a patch series
+// from the names and commit messages of its branches.
+func formatPatchCoverLetter(branches []*formatPatchBranch) *git.CoverLetter {
+	var body strings.Builder
+	body.WriteString("This series contains the following branches:\n")
+
+	var patchNum int
+	for _, b := range branches {
+		if len(b.Messages) == 0 {
+			continue
+		}
+
+		first := patchNum + 1
+		patchNum += len(b.Messages)
+		body.WriteString("\n")
+		if first == patchNum {
+			fmt.Fprintf(&body, "Patch %d: %v\n", first, b.Name)
+		} else {
+			fmt.Fprintf(&body, "Patches %d-%d: %v\n", first, patchNum, b.Name)
+		}
+
+		body.WriteString("\n")
+		for line := range strings.Lines(changeSummary(b.Messages)) {
+			if line = strings.TrimRight(line, "\n"); line == "" {
+				body.WriteString("\n")
+			} else {
+				body.WriteString("  " + line + "\n")
+			}
+		}
+	}
+
+	return &git.CoverLetter{
+		Subject: branches[len(branches)-1].Name,
+		Body:    body.String(),
+	}
+}
+
+// changeSummary describes a change made up of the given commits,
+// listed newest first.
+// It lists the subjects and bodies of the commits in the order they were made.
+func changeSummary(msgs []git.CommitMessage) string {
+	var sb strings.Builder
+	for i := len(msgs) - 1; i >= 0; i-- {
+		msg := msgs[i]
+		if sb.Len() > 0 {
+			sb.WriteString("\n\n")
+		}
+		sb.WriteString(msg.Subject)
+		if msg.Body != "" {
+			sb.WriteString("\n\n")
+			sb.WriteString(msg.Body)
+		}
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
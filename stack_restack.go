@@ -16,6 +16,8 @@ type stackRestackCmd struct {
 	Branch string `help:"Branch to restack the stack of" placeholder:"NAME" predictor:"trackedBranches"`
 }
 
+func (*stackRestackCmd) locksRepository() {}
+
 func (*stackRestackCmd) Help() string {
 	return text.Dedent(`
 		All branches in the current stack are rebased on top of their
@@ -5,8 +5,11 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/alecthomas/kong"
 	"go.abhg.dev/gs/internal/forge"
+	"go.abhg.dev/gs/internal/forge/forgecache"
 	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/review"
 	"go.abhg.dev/gs/internal/secret"
 	"go.abhg.dev/gs/internal/silog"
 	"go.abhg.dev/gs/internal/spice"
@@ -20,6 +23,8 @@ import (
 type stackReviewsCmd struct {
 	IncludeResolved bool   `help:"Include resolved threads"`
 	BotAllowlist    string `help:"Comma-separated bot logins to include" default:"copilot,claude,codex,github-advanced-security,copilot-pull-request-reviewer"`
+
+	Format string `enum:"text,sarif,quickfix,json" default:"text" released:"unreleased" help:"Output format. One of 'text', 'sarif', 'quickfix', or 'json'."`
 }
 
 func (*stackReviewsCmd) Help() string {
@@ -33,18 +38,24 @@ with branches that are checked out in other worktrees.
 Per-branch errors are collected and reported at the end rather than
 aborting the whole stack walk.
 
+Use --format to write threads and check annotations of all branches
+to stdout as a single SARIF log, quickfix list, or JSON stream.
+See 'gs branch reviews --help' for details.
+
 This command is read-only; addressing comments is the user's job.
 `
 }
 
 func (c *stackReviewsCmd) Run(
 	ctx context.Context,
+	kctx *kong.Context,
 	log *silog.Logger,
 	view ui.View,
 	wt *git.Worktree,
 	repo *git.Repository,
 	store *state.Store,
 	stash secret.Stash,
+	cache *forgecache.Cache,
 	forges *forge.Registry,
 	svc *spice.Service,
 ) error {
@@ -65,23 +76,44 @@ func (c *stackReviewsCmd) Run(
 		branch string
 		err    error
 	}
-	var errs []branchErr
+	var (
+		errs  []branchErr
+		items []*review.Item
+	)
 	for _, branch := range stack {
 		if branch == store.Trunk() {
 			continue
 		}
 
-		fmt.Fprintf(view, "\n=== %s ===\n", branch)
 		cmd := &branchReviewsCmd{
 			Branch:          branch,
 			IncludeResolved: c.IncludeResolved,
 			BotAllowlist:    c.BotAllowlist,
+			Format:          c.Format,
+		}
+
+		// Exports are written for the whole stack at once.
+		if c.Format != "text" {
+			branchItems, err := cmd.listItems(ctx, log, view, wt, repo, store, stash, cache, forges)
+			if err != nil {
+				errs = append(errs, branchErr{branch, err})
+			}
+			items = append(items, branchItems...)
+			continue
 		}
-		if err := cmd.Run(ctx, log, view, wt, repo, store, stash, forges); err != nil {
+
+		fmt.Fprintf(view, "\n=== %s ===\n", branch)
+		if err := cmd.Run(ctx, kctx, log, view, wt, repo, store, stash, cache, forges); err != nil {
 			errs = append(errs, branchErr{branch, err})
 		}
 	}
 
+	if c.Format != "text" {
+		if err := writeReviewItems(kctx.Stdout, c.Format, items); err != nil {
+			return err
+		}
+	}
+
 	if len(errs) > 0 {
 		fmt.Fprintln(view, "")
 		fmt.Fprintln(view, "Errors encountered:")
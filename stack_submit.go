@@ -16,6 +16,8 @@ type stackSubmitCmd struct {
 	submit.BatchOptions
 }
 
+func (*stackSubmitCmd) locksRepository() {}
+
 func (*stackSubmitCmd) Help() string {
 	return text.Dedent(`
 		Change Requests are created or updated
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"go.abhg.dev/gs/internal/tracing"
+)
+
+// startTrace starts recording a trace of the named command to path
+// in the Chrome trace event format.
+//
+// It returns a context that records spans to the trace,
+// and a function to finish the trace
+// given the error returned by the command, if any.
+func startTrace(ctx context.Context, path, command string) (context.Context, func(error) error, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return ctx, nil, fmt.Errorf("create trace file: %w", err)
+	}
+
+	rec := tracing.NewRecorder(f, command)
+	ctx, span := tracing.Start(tracing.WithRecorder(ctx, rec), "command", command)
+	return ctx, func(runErr error) error {
+		if runErr != nil {
+			span.SetArgs("error", runErr.Error())
+		}
+		span.End()
+		return errors.Join(rec.Close(), f.Close())
+	}, nil
+}
+
+// stopTrace finishes the trace started with --trace-file, if any.
+func (cmd *mainCmd) stopTrace(runErr error) error {
+	if cmd.finishTrace == nil {
+		return nil
+	}
+	return cmd.finishTrace(runErr)
+}
@@ -18,6 +18,8 @@ type upstackDeleteCmd struct {
 	Force bool `help:"Force deletion of the branches"`
 }
 
+func (*upstackDeleteCmd) locksRepository() {}
+
 func (*upstackDeleteCmd) Help() string {
 	return text.Dedent(`
 		Deletes all branches above the current branch in the stack,
@@ -21,6 +21,8 @@ type upstackOntoCmd struct {
 	Onto   string `arg:"" optional:"" help:"Destination branch" predictor:"trackedBranches"`
 }
 
+func (*upstackOntoCmd) locksRepository() {}
+
 func (*upstackOntoCmd) Help() string {
 	return text.Dedent(`
 		The current branch and its upstack will move onto the new base.
@@ -18,6 +18,8 @@ type upstackRestackCmd struct {
 	Branch string `help:"Branch to restack the upstack of" placeholder:"NAME" predictor:"trackedBranches"`
 }
 
+func (*upstackRestackCmd) locksRepository() {}
+
 func (*upstackRestackCmd) Help() string {
 	return text.Dedent(`
 		The current branch and all branches above it
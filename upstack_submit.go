@@ -20,6 +20,8 @@ type upstackSubmitCmd struct {
 	Branch string `placeholder:"NAME" help:"Branch to start at" predictor:"trackedBranches"`
 }
 
+func (*upstackSubmitCmd) locksRepository() {}
+
 func (*upstackSubmitCmd) Help() string {
 	return text.Dedent(`
 		Change Requests are created or updated